@@ -0,0 +1,29 @@
+package setup
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+var (
+	//go:embed 26.sql
+	addPayloadEncoding string
+)
+
+// AddPayloadEncodingToEvents adds the columns required to store compressed payloads,
+// existing events have no encoding and are read as JSON.
+type AddPayloadEncodingToEvents struct {
+	dbClient *database.DB
+}
+
+func (mig *AddPayloadEncodingToEvents) Execute(ctx context.Context, _ eventstore.Event) error {
+	_, err := mig.dbClient.ExecContext(ctx, addPayloadEncoding)
+	return err
+}
+
+func (mig *AddPayloadEncodingToEvents) String() string {
+	return "26_add_payload_encoding_to_events"
+}
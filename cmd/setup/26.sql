@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS eventstore.events2 ADD COLUMN IF NOT EXISTS payload_encoding SMALLINT;
+ALTER TABLE IF EXISTS eventstore.events2 ADD COLUMN IF NOT EXISTS compressed_payload BYTEA;
@@ -0,0 +1,29 @@
+package setup
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+var (
+	//go:embed 27.sql
+	addMetadata string
+)
+
+// AddMetadataToEvents adds the column storing the operational metadata of the events,
+// existing events have no metadata.
+type AddMetadataToEvents struct {
+	dbClient *database.DB
+}
+
+func (mig *AddMetadataToEvents) Execute(ctx context.Context, _ eventstore.Event) error {
+	_, err := mig.dbClient.ExecContext(ctx, addMetadata)
+	return err
+}
+
+func (mig *AddMetadataToEvents) String() string {
+	return "27_add_metadata_to_events"
+}
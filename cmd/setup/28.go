@@ -0,0 +1,29 @@
+package setup
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+var (
+	//go:embed 28.sql
+	addTargetExecutionTable string
+)
+
+// AddTargetExecutionTable adds the table storing the executions of the targets,
+// it is not a projection, so the executions are kept on a reset of the projections.
+type AddTargetExecutionTable struct {
+	dbClient *database.DB
+}
+
+func (mig *AddTargetExecutionTable) Execute(ctx context.Context, _ eventstore.Event) error {
+	_, err := mig.dbClient.ExecContext(ctx, addTargetExecutionTable)
+	return err
+}
+
+func (mig *AddTargetExecutionTable) String() string {
+	return "28_add_target_execution_table"
+}
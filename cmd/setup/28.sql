@@ -0,0 +1,17 @@
+CREATE SCHEMA IF NOT EXISTS execution;
+
+CREATE TABLE IF NOT EXISTS execution.target_executions (
+    instance_id TEXT NOT NULL
+    , target_id TEXT NOT NULL
+    , id TEXT NOT NULL
+    , execution_date TIMESTAMPTZ NOT NULL
+    , status_code INT NOT NULL
+    , latency BIGINT NOT NULL
+    , error TEXT NOT NULL
+    , succeeded BOOLEAN NOT NULL
+
+    , PRIMARY KEY (instance_id, target_id, id)
+);
+
+CREATE INDEX IF NOT EXISTS target_executions_date_idx ON execution.target_executions (instance_id, target_id, execution_date DESC);
+CREATE INDEX IF NOT EXISTS target_executions_retention_idx ON execution.target_executions (execution_date);
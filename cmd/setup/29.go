@@ -0,0 +1,29 @@
+package setup
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+var (
+	//go:embed 29.sql
+	addCausation string
+)
+
+// AddCausationToEvents adds the columns storing the causation and the correlation of the events,
+// existing events have neither.
+type AddCausationToEvents struct {
+	dbClient *database.DB
+}
+
+func (mig *AddCausationToEvents) Execute(ctx context.Context, _ eventstore.Event) error {
+	_, err := mig.dbClient.ExecContext(ctx, addCausation)
+	return err
+}
+
+func (mig *AddCausationToEvents) String() string {
+	return "29_add_causation_to_events"
+}
@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS eventstore.events2 ADD COLUMN IF NOT EXISTS causation_id TEXT;
+ALTER TABLE IF EXISTS eventstore.events2 ADD COLUMN IF NOT EXISTS correlation_id TEXT;
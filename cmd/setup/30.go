@@ -0,0 +1,29 @@
+package setup
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+var (
+	//go:embed 30.sql
+	addTargetDeliveryTables string
+)
+
+// AddTargetDeliveryTables adds the tables storing the payloads queued for paused targets and the amount of dropped payloads,
+// so the queued payloads survive a restart and are delivered by any node.
+type AddTargetDeliveryTables struct {
+	dbClient *database.DB
+}
+
+func (mig *AddTargetDeliveryTables) Execute(ctx context.Context, _ eventstore.Event) error {
+	_, err := mig.dbClient.ExecContext(ctx, addTargetDeliveryTables)
+	return err
+}
+
+func (mig *AddTargetDeliveryTables) String() string {
+	return "30_add_target_delivery_tables"
+}
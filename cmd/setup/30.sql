@@ -0,0 +1,20 @@
+CREATE SCHEMA IF NOT EXISTS execution;
+
+CREATE TABLE IF NOT EXISTS execution.target_deliveries (
+    instance_id TEXT NOT NULL
+    , target_id TEXT NOT NULL
+    , id TEXT NOT NULL
+    , created_at TIMESTAMPTZ NOT NULL
+    , payload BYTEA NOT NULL
+    , url_variables JSONB
+
+    , PRIMARY KEY (instance_id, target_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS execution.target_queues (
+    instance_id TEXT NOT NULL
+    , target_id TEXT NOT NULL
+    , dropped BIGINT NOT NULL DEFAULT 0
+
+    , PRIMARY KEY (instance_id, target_id)
+);
@@ -37,7 +37,9 @@ func Cleanup(config *Config) {
 	esPusherDBClient, err := database.Connect(config.Database, false, dialect.DBPurposeEventPusher)
 	logging.OnError(err).Fatal("unable to connect to database")
 
-	config.Eventstore.Pusher = new_es.NewEventstore(esPusherDBClient)
+	pushOptions, err := new_es.ConfigOptions(config.Eventstore.Push)
+	logging.OnError(err).Fatal("unable to configure eventstore")
+	config.Eventstore.Pusher = new_es.NewEventstore(esPusherDBClient, pushOptions...)
 	config.Eventstore.Querier = old_es.NewCRDB(queryDBClient)
 	es := eventstore.NewEventstore(config.Eventstore)
 
@@ -63,6 +63,7 @@ func MustNewConfig(v *viper.Viper) *Config {
 		viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
 			hooks.SliceTypeStringDecode[*domain.CustomMessageText],
 			hooks.SliceTypeStringDecode[internal_authz.RoleMapping],
+			hooks.SliceTypeStringDecode[eventstore.RedactionConfig],
 			hooks.MapTypeStringDecode[string, *internal_authz.SystemAPIUser],
 			hooks.MapHTTPHeaderStringDecode,
 			database.DecodeHook,
@@ -108,6 +109,11 @@ type Steps struct {
 	s23CorrectGlobalUniqueConstraints      *CorrectGlobalUniqueConstraints
 	s24AddActorToAuthTokens                *AddActorToAuthTokens
 	s25User11AddLowerFieldsToVerifiedEmail *User11AddLowerFieldsToVerifiedEmail
+	s26AddPayloadEncodingToEvents          *AddPayloadEncodingToEvents
+	s27AddMetadataToEvents                 *AddMetadataToEvents
+	s28AddTargetExecutionTable             *AddTargetExecutionTable
+	s29AddCausationToEvents                *AddCausationToEvents
+	s30AddTargetDeliveryTables             *AddTargetDeliveryTables
 }
 
 func MustNewSteps(v *viper.Viper) *Steps {
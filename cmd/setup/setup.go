@@ -99,7 +99,9 @@ func Setup(config *Config, steps *Steps, masterKey string) {
 	logging.OnError(err).Fatal("unable to connect to database")
 
 	config.Eventstore.Querier = old_es.NewCRDB(queryDBClient)
-	config.Eventstore.Pusher = new_es.NewEventstore(esPusherDBClient)
+	pushOptions, err := new_es.ConfigOptions(config.Eventstore.Push)
+	logging.OnError(err).Fatal("unable to configure eventstore")
+	config.Eventstore.Pusher = new_es.NewEventstore(esPusherDBClient, pushOptions...)
 	eventstoreClient := eventstore.NewEventstore(config.Eventstore)
 	logging.OnError(err).Fatal("unable to start eventstore")
 
@@ -138,6 +140,11 @@ func Setup(config *Config, steps *Steps, masterKey string) {
 	steps.s23CorrectGlobalUniqueConstraints = &CorrectGlobalUniqueConstraints{dbClient: esPusherDBClient}
 	steps.s24AddActorToAuthTokens = &AddActorToAuthTokens{dbClient: queryDBClient}
 	steps.s25User11AddLowerFieldsToVerifiedEmail = &User11AddLowerFieldsToVerifiedEmail{dbClient: esPusherDBClient}
+	steps.s26AddPayloadEncodingToEvents = &AddPayloadEncodingToEvents{dbClient: esPusherDBClient}
+	steps.s27AddMetadataToEvents = &AddMetadataToEvents{dbClient: esPusherDBClient}
+	steps.s28AddTargetExecutionTable = &AddTargetExecutionTable{dbClient: queryDBClient}
+	steps.s29AddCausationToEvents = &AddCausationToEvents{dbClient: esPusherDBClient}
+	steps.s30AddTargetDeliveryTables = &AddTargetDeliveryTables{dbClient: queryDBClient}
 
 	err = projection.Create(ctx, projectionDBClient, eventstoreClient, config.Projections, nil, nil, nil)
 	logging.OnError(err).Fatal("unable to start projections")
@@ -156,8 +163,24 @@ func Setup(config *Config, steps *Steps, masterKey string) {
 		},
 	}
 
+	// the steps changing the events table push their migration events without the columns they add
+	eventsTableClient := eventstore.NewEventstore(&eventstore.Config{
+		PushTimeout: config.Eventstore.PushTimeout,
+		MaxRetries:  config.Eventstore.MaxRetries,
+		RetryBudget: config.Eventstore.RetryBudget,
+		Pusher:      new_es.NewEventstore(esPusherDBClient, new_es.WithoutAddedColumns()),
+		Querier:     config.Eventstore.Querier,
+	})
 	for _, step := range []migration.Migration{
 		steps.s14NewEventsTable,
+		steps.s26AddPayloadEncodingToEvents,
+		steps.s27AddMetadataToEvents,
+		steps.s29AddCausationToEvents,
+	} {
+		mustExecuteMigration(ctx, eventsTableClient, step, "migration failed")
+	}
+
+	for _, step := range []migration.Migration{
 		steps.s1ProjectionTable,
 		steps.s2AssetsTable,
 		steps.FirstInstance,
@@ -175,6 +198,8 @@ func Setup(config *Config, steps *Steps, masterKey string) {
 		steps.s22ActiveInstancesIndex,
 		steps.s23CorrectGlobalUniqueConstraints,
 		steps.s24AddActorToAuthTokens,
+		steps.s28AddTargetExecutionTable,
+		steps.s30AddTargetDeliveryTables,
 	} {
 		mustExecuteMigration(ctx, eventstoreClient, step, "migration failed")
 	}
@@ -67,6 +67,7 @@ import (
 	"github.com/zitadel/zitadel/internal/eventstore"
 	old_es "github.com/zitadel/zitadel/internal/eventstore/repository/sql"
 	new_es "github.com/zitadel/zitadel/internal/eventstore/v3"
+	target_execution "github.com/zitadel/zitadel/internal/execution"
 	"github.com/zitadel/zitadel/internal/i18n"
 	"github.com/zitadel/zitadel/internal/id"
 	"github.com/zitadel/zitadel/internal/logstore"
@@ -150,7 +151,11 @@ func startZitadel(ctx context.Context, config *Config, masterKey string, server
 		return err
 	}
 
-	config.Eventstore.Pusher = new_es.NewEventstore(esPusherDBClient)
+	pushOptions, err := new_es.ConfigOptions(config.Eventstore.Push)
+	if err != nil {
+		return fmt.Errorf("cannot configure eventstore: %w", err)
+	}
+	config.Eventstore.Pusher = new_es.NewEventstore(esPusherDBClient, pushOptions...)
 	config.Eventstore.Querier = old_es.NewCRDB(queryDBClient)
 	eventstoreClient := eventstore.NewEventstore(config.Eventstore)
 
@@ -240,6 +245,8 @@ func startZitadel(ctx context.Context, config *Config, masterKey string, server
 
 	actionsLogstoreSvc := logstore.New(queries, actionsExecutionDBEmitter, actionsExecutionStdoutEmitter)
 	actions.SetLogstoreService(actionsLogstoreSvc)
+	target_execution.SetLogstoreService(actionsLogstoreSvc)
+	target_execution.Start(ctx, config.Executions.Queue, queryDBClient, queries)
 
 	notification.Register(
 		ctx,
@@ -19,13 +19,35 @@ import (
 var _ execution.Target = &mockExecutionTarget{}
 
 type mockExecutionTarget struct {
-	InstanceID       string
-	ExecutionID      string
-	TargetID         string
-	TargetType       domain.TargetType
-	Endpoint         string
-	Timeout          time.Duration
-	InterruptOnError bool
+	InstanceID             string
+	ExecutionID            string
+	TargetID               string
+	TargetType             domain.TargetType
+	Endpoint               string
+	Timeout                time.Duration
+	InterruptOnError       bool
+	SuccessCriteria        *domain.TargetSuccessCriteria
+	EventTypeFilter        []string
+	CertificateFingerprint string
+	CABundle               string
+	ResponseContentType    string
+	PhaseTimeouts          domain.TargetPhaseTimeouts
+	PayloadFormat          domain.TargetPayloadFormat
+	OAuth2Credentials      *domain.TargetOAuth2Credentials
+	BasicAuthCredentials   *domain.TargetBasicAuthCredentials
+	MirrorEndpoints        []string
+	ResponseSizeLimit      domain.TargetResponseSizeLimit
+	BatchDelivery          domain.TargetBatchDelivery
+	OrderedDelivery        bool
+	ExcludePayload         bool
+	JWTDelivery            *domain.TargetJWTDelivery
+	FailoverEndpoints      []string
+	MaxRedirects           int
+	RequestIDHeader        string
+	SigningKey             string
+	SecondarySigningKey    string
+	SecondaryValidUntil    time.Time
+	Paused                 bool
 }
 
 func (e *mockExecutionTarget) SetEndpoint(endpoint string) {
@@ -43,6 +65,69 @@ func (e *mockExecutionTarget) GetTargetType() domain.TargetType {
 func (e *mockExecutionTarget) GetTimeout() time.Duration {
 	return e.Timeout
 }
+func (e *mockExecutionTarget) GetSuccessCriteria() *domain.TargetSuccessCriteria {
+	return e.SuccessCriteria
+}
+func (e *mockExecutionTarget) GetEventTypeFilter() []string {
+	return e.EventTypeFilter
+}
+func (e *mockExecutionTarget) GetCertificateFingerprint() string {
+	return e.CertificateFingerprint
+}
+func (e *mockExecutionTarget) GetCABundle() string {
+	return e.CABundle
+}
+func (e *mockExecutionTarget) GetResponseContentType() string {
+	return e.ResponseContentType
+}
+func (e *mockExecutionTarget) GetPhaseTimeouts() domain.TargetPhaseTimeouts {
+	return e.PhaseTimeouts
+}
+func (e *mockExecutionTarget) GetPayloadFormat() domain.TargetPayloadFormat {
+	return e.PayloadFormat
+}
+func (e *mockExecutionTarget) GetOAuth2Credentials() *domain.TargetOAuth2Credentials {
+	return e.OAuth2Credentials
+}
+func (e *mockExecutionTarget) GetBasicAuthCredentials() *domain.TargetBasicAuthCredentials {
+	return e.BasicAuthCredentials
+}
+func (e *mockExecutionTarget) GetMirrorEndpoints() []string {
+	return e.MirrorEndpoints
+}
+func (e *mockExecutionTarget) GetResponseSizeLimit() domain.TargetResponseSizeLimit {
+	return e.ResponseSizeLimit
+}
+func (e *mockExecutionTarget) GetBatchDelivery() domain.TargetBatchDelivery {
+	return e.BatchDelivery
+}
+func (e *mockExecutionTarget) IsOrderedDelivery() bool {
+	return e.OrderedDelivery
+}
+func (e *mockExecutionTarget) IsIncludePayload() bool {
+	return !e.ExcludePayload
+}
+func (e *mockExecutionTarget) GetJWTDelivery() *domain.TargetJWTDelivery {
+	return e.JWTDelivery
+}
+func (e *mockExecutionTarget) GetFailoverEndpoints() []string {
+	return e.FailoverEndpoints
+}
+func (e *mockExecutionTarget) GetMaxRedirects() int {
+	return e.MaxRedirects
+}
+func (e *mockExecutionTarget) GetRequestIDHeader() string {
+	return e.RequestIDHeader
+}
+func (e *mockExecutionTarget) GetSigningKey() string {
+	return e.SigningKey
+}
+func (e *mockExecutionTarget) GetSecondarySigningKey() (string, time.Time) {
+	return e.SecondarySigningKey, e.SecondaryValidUntil
+}
+func (e *mockExecutionTarget) IsPaused() bool {
+	return e.Paused
+}
 func (e *mockExecutionTarget) GetTargetID() string {
 	return e.TargetID
 }
@@ -5,12 +5,16 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
+	"github.com/zitadel/zitadel/internal/execution"
 	"github.com/zitadel/zitadel/internal/repository/target"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
+// AddTarget is a new target, omitted values are set to the target defaults of the instance
 type AddTarget struct {
 	models.ObjectRoot
 
@@ -19,21 +23,151 @@ type AddTarget struct {
 	Endpoint         string
 	Timeout          time.Duration
 	InterruptOnError bool
+	SuccessCriteria  *domain.TargetSuccessCriteria
+	EventTypeFilter  []string
+	// CertificateFingerprint pins the certificate of the endpoint, empty to verify against the certificate authorities
+	CertificateFingerprint string
+	// CABundle are the PEM encoded certificate authorities the endpoint is verified against,
+	// empty to verify against the certificate authorities of the system
+	CABundle string
+	// ResponseContentType is the expected content type of the responses, empty for no expectation
+	ResponseContentType string
+	// PayloadFormat is the format the payload is serialized in, JSON by default
+	PayloadFormat domain.TargetPayloadFormat
+	// PhaseTimeouts limit the phases of the calls, unset phases are limited by the timeout
+	PhaseTimeouts *domain.TargetPhaseTimeouts
+	// OAuth2 are the client credentials used to authorize the calls, nil if the calls are not authorized
+	OAuth2 *TargetOAuth2
+	// BasicAuth are the credentials used to authorize the calls, nil if the calls are not authorized.
+	// It can't be combined with OAuth2.
+	BasicAuth *TargetBasicAuth
+	// MirrorEndpoints additionally receive the payload best-effort, failures never interrupt the execution
+	MirrorEndpoints []string
+	// ResponseSizeLimit limits the size of the response bodies, nil to read them completely
+	ResponseSizeLimit *domain.TargetResponseSizeLimit
+	// BatchDelivery coalesces the payloads delivered to an async target into batches, nil to deliver every payload on its own
+	BatchDelivery *domain.TargetBatchDelivery
+	// OrderedDelivery delivers the payloads of an async target one after the other
+	OrderedDelivery bool
+	// IncludePayload sends the payload of the events to the target, nil to include it.
+	// If false, only the metadata of the events is sent.
+	IncludePayload *bool
+	// JWTDelivery sends the payload as JWT signed with the signing key instead of in the payload format, nil to send it in the payload format
+	JWTDelivery *domain.TargetJWTDelivery
+	// FailoverEndpoints are called in order if the endpoint fails, until one of them succeeds
+	FailoverEndpoints []string
+	// MaxRedirects is the amount of redirects followed, redirects are not followed if zero
+	MaxRedirects int
+	// RequestIDHeader is the name of the header the ID of the originating request is sent in, empty if it is not sent
+	RequestIDHeader string
+	// SigningKey is the primary key the requests are signed with, empty if they are not signed.
+	// See [Commands.SetTargetSigningKey] and [Commands.RotateTargetSigningKey] to rotate it.
+	SigningKey string
+}
+
+// TargetOAuth2 are the client credentials of a target for the OAuth2 client credentials grant
+type TargetOAuth2 struct {
+	TokenEndpoint string
+	ClientID      string
+	// ClientSecret is stored encrypted, on change an empty secret keeps the stored one
+	ClientSecret string
+}
+
+func (o *TargetOAuth2) isZero() bool {
+	return o == nil || *o == TargetOAuth2{}
+}
+
+func (o *TargetOAuth2) IsValid() error {
+	if o.isZero() {
+		return nil
+	}
+	return execution.ValidateOAuth2(o.TokenEndpoint, o.ClientID)
+}
+
+// TargetBasicAuth are the credentials of a target for HTTP basic authentication
+type TargetBasicAuth struct {
+	Username string
+	// Password is stored encrypted, on change an empty password keeps the stored one
+	Password string
+}
+
+func (b *TargetBasicAuth) isZero() bool {
+	return b == nil || *b == TargetBasicAuth{}
+}
+
+func (b *TargetBasicAuth) IsValid() error {
+	if b.isZero() {
+		return nil
+	}
+	return execution.ValidateBasicAuth(b.Username)
 }
 
 func (a *AddTarget) IsValid() error {
+	if errs := a.validate(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// validate runs all checks of the target and returns every violation in the order of the checks
+func (a *AddTarget) validate() []error {
+	var errs []error
+	check := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
 	if a.Name == "" {
-		return zerrors.ThrowInvalidArgument(nil, "COMMAND-ddqbm9us5p", "Errors.Target.Invalid")
+		check(zerrors.ThrowInvalidArgument(nil, "COMMAND-ddqbm9us5p", "Errors.Target.Invalid"))
 	}
 	if a.Timeout == 0 {
-		return zerrors.ThrowInvalidArgument(nil, "COMMAND-39f35d8uri", "Errors.Target.NoTimeout")
+		check(zerrors.ThrowInvalidArgument(nil, "COMMAND-39f35d8uri", "Errors.Target.NoTimeout"))
+	}
+	if _, err := url.Parse(a.Endpoint); err != nil || a.Endpoint == "" {
+		check(zerrors.ThrowInvalidArgument(err, "COMMAND-1r2k6qo6wg", "Errors.Target.InvalidURL"))
+	} else {
+		// the checks of the endpoint are only meaningful for a parsable URL
+		check(execution.CheckTargetHost(a.Endpoint))
+		check(execution.ValidateEndpointTemplate(a.Endpoint))
+	}
+	check(execution.ValidateSuccessCriteria(a.SuccessCriteria))
+	check(execution.ValidateEventTypeFilter(a.EventTypeFilter))
+	check(execution.ValidateCertificateFingerprint(a.CertificateFingerprint))
+	check(execution.ValidateCABundle(a.CABundle))
+	check(execution.ValidateResponseContentType(a.ResponseContentType))
+	check(execution.ValidatePayloadFormat(a.PayloadFormat))
+	check(execution.ValidatePhaseTimeouts(a.PhaseTimeouts))
+	check(a.OAuth2.IsValid())
+	if !a.OAuth2.isZero() && a.OAuth2.ClientSecret == "" {
+		check(zerrors.ThrowInvalidArgument(nil, "COMMAND-Oa2Sc1", "Errors.Target.InvalidOAuth2"))
 	}
-	_, err := url.Parse(a.Endpoint)
-	if err != nil || a.Endpoint == "" {
-		return zerrors.ThrowInvalidArgument(err, "COMMAND-1r2k6qo6wg", "Errors.Target.InvalidURL")
+	check(a.BasicAuth.IsValid())
+	if !a.BasicAuth.isZero() && a.BasicAuth.Password == "" {
+		check(zerrors.ThrowInvalidArgument(nil, "COMMAND-Ba3Pw1", "Errors.Target.InvalidBasicAuth"))
 	}
+	if !a.OAuth2.isZero() && !a.BasicAuth.isZero() {
+		check(zerrors.ThrowInvalidArgument(nil, "COMMAND-Ba4Ex1", "Errors.Target.MultipleAuth"))
+	}
+	check(execution.ValidateMirrorEndpoints(a.MirrorEndpoints))
+	check(execution.ValidateResponseSizeLimit(a.ResponseSizeLimit))
+	check(execution.ValidateBatchDelivery(a.BatchDelivery))
+	check(execution.ValidateJWTDelivery(a.JWTDelivery))
+	if !a.JWTDelivery.IsZero() && a.SigningKey == "" {
+		check(zerrors.ThrowInvalidArgument(nil, "COMMAND-Jw1Ky1", "Errors.Target.NoSigningKey"))
+	}
+	check(execution.ValidateFailoverEndpoints(a.FailoverEndpoints))
+	check(execution.ValidateMaxRedirects(a.MaxRedirects))
+	check(execution.ValidateRequestIDHeader(a.RequestIDHeader))
+	check(execution.ValidateSigningKey(a.SigningKey))
+	return errs
+}
 
-	return nil
+// ValidateTarget runs all checks of the target without saving it and returns every violation at once,
+// nil if the target is valid.
+// The target is validated as passed, the target defaults of the instance are not applied
+// and the database is not accessed, so the name is not checked for uniqueness.
+func (c *Commands) ValidateTarget(ctx context.Context, add *AddTarget) []error {
+	return add.validate()
 }
 
 func (c *Commands) AddTarget(ctx context.Context, add *AddTarget, resourceOwner string) (_ *domain.ObjectDetails, err error) {
@@ -41,6 +175,9 @@ func (c *Commands) AddTarget(ctx context.Context, add *AddTarget, resourceOwner
 		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-brml926e2d", "Errors.IDMissing")
 	}
 
+	if err := c.applyTargetDefaults(ctx, add); err != nil {
+		return nil, err
+	}
 	if err := add.IsValid(); err != nil {
 		return nil, err
 	}
@@ -58,6 +195,18 @@ func (c *Commands) AddTarget(ctx context.Context, add *AddTarget, resourceOwner
 	if wm.State.Exists() {
 		return nil, zerrors.ThrowAlreadyExists(nil, "INSTANCE-9axkz0jvzm", "Errors.Target.AlreadyExists")
 	}
+	oauth2, err := encryptTargetOAuth2(add.OAuth2, c.idpConfigEncryption)
+	if err != nil {
+		return nil, err
+	}
+	basicAuth, err := encryptTargetBasicAuth(add.BasicAuth, c.idpConfigEncryption)
+	if err != nil {
+		return nil, err
+	}
+	signingKeys, err := encryptTargetSigningKey(add.SigningKey, c.idpConfigEncryption)
+	if err != nil {
+		return nil, err
+	}
 
 	pushedEvents, err := c.eventstore.Push(ctx, target.NewAddedEvent(
 		ctx,
@@ -67,6 +216,25 @@ func (c *Commands) AddTarget(ctx context.Context, add *AddTarget, resourceOwner
 		add.Endpoint,
 		add.Timeout,
 		add.InterruptOnError,
+		target.WithSuccessCriteria(add.SuccessCriteria),
+		target.WithEventTypeFilter(add.EventTypeFilter),
+		target.WithCertificateFingerprint(add.CertificateFingerprint),
+		target.WithResponseContentType(add.ResponseContentType),
+		target.WithPayloadFormat(add.PayloadFormat),
+		target.WithPhaseTimeouts(add.PhaseTimeouts),
+		target.WithOAuth2(oauth2),
+		target.WithBasicAuth(basicAuth),
+		target.WithMirrorEndpoints(add.MirrorEndpoints),
+		target.WithResponseSizeLimit(add.ResponseSizeLimit),
+		target.WithBatchDelivery(add.BatchDelivery),
+		target.WithOrderedDelivery(add.OrderedDelivery),
+		target.WithIncludePayload(add.IncludePayload),
+		target.WithCABundle(add.CABundle),
+		target.WithJWTDelivery(add.JWTDelivery),
+		target.WithFailoverEndpoints(add.FailoverEndpoints),
+		target.WithMaxRedirects(add.MaxRedirects),
+		target.WithRequestIDHeader(add.RequestIDHeader),
+		target.WithSigningKeys(signingKeys),
 	))
 	if err != nil {
 		return nil, err
@@ -77,6 +245,29 @@ func (c *Commands) AddTarget(ctx context.Context, add *AddTarget, resourceOwner
 	return writeModelToObjectDetails(&wm.WriteModel), nil
 }
 
+// AddTargetIfNotExists adds the target unless the resource owner already has a target with the same name,
+// which makes repeated provisioning idempotent.
+// An existing target is returned unchanged, even if its values differ from the passed ones, see [Commands.UpsertTargets] to update them.
+// The ID of the added or existing target is set as AggregateID of add and created reports if the target was added.
+func (c *Commands) AddTargetIfNotExists(ctx context.Context, add *AddTarget, resourceOwner string) (_ *domain.ObjectDetails, created bool, err error) {
+	if resourceOwner == "" {
+		return nil, false, zerrors.ThrowInvalidArgument(nil, "COMMAND-Ai4fNr", "Errors.IDMissing")
+	}
+	existing := NewTargetsOfResourceOwnerWriteModel(resourceOwner)
+	if err := c.eventstore.FilterToQueryReducer(ctx, existing); err != nil {
+		return nil, false, err
+	}
+	if wm, ok := existing.byName()[add.Name]; ok {
+		add.AggregateID = wm.AggregateID
+		return writeModelToObjectDetails(&wm.WriteModel), false, nil
+	}
+	details, err := c.AddTarget(ctx, add, resourceOwner)
+	if err != nil {
+		return nil, false, err
+	}
+	return details, true, nil
+}
+
 type ChangeTarget struct {
 	models.ObjectRoot
 
@@ -85,6 +276,42 @@ type ChangeTarget struct {
 	Endpoint         *string
 	Timeout          *time.Duration
 	InterruptOnError *bool
+	// SuccessCriteria replace the existing criteria, an empty struct removes them
+	SuccessCriteria *domain.TargetSuccessCriteria
+	// EventTypeFilter replaces the existing filter, an empty list removes it
+	EventTypeFilter *[]string
+	// CertificateFingerprint set to an empty string removes the pinning
+	CertificateFingerprint *string
+	// CABundle set to an empty string verifies against the certificate authorities of the system
+	CABundle *string
+	// ResponseContentType set to an empty string removes the expectation
+	ResponseContentType *string
+	// PayloadFormat replaces the format the payload is serialized in
+	PayloadFormat *domain.TargetPayloadFormat
+	// PhaseTimeouts replace the existing phase timeouts, an empty struct removes them
+	PhaseTimeouts *domain.TargetPhaseTimeouts
+	// OAuth2 replaces the existing client credentials, an empty struct removes them
+	OAuth2 *TargetOAuth2
+	// BasicAuth replaces the existing basic authentication, an empty struct removes it
+	BasicAuth *TargetBasicAuth
+	// MirrorEndpoints replace the existing mirrors, an empty list removes them
+	MirrorEndpoints *[]string
+	// ResponseSizeLimit replaces the existing limit, an empty struct removes it
+	ResponseSizeLimit *domain.TargetResponseSizeLimit
+	// BatchDelivery replaces the existing batch delivery, an empty struct delivers every event on its own
+	BatchDelivery *domain.TargetBatchDelivery
+	// OrderedDelivery enables or disables the ordered delivery of the payloads of an async target
+	OrderedDelivery *bool
+	// IncludePayload sends or omits the payload of the events
+	IncludePayload *bool
+	// JWTDelivery replaces the existing JWT delivery, an empty struct sends the payload in its payload format
+	JWTDelivery *domain.TargetJWTDelivery
+	// FailoverEndpoints replace the existing failover endpoints, an empty list removes them
+	FailoverEndpoints *[]string
+	// MaxRedirects replaces the amount of redirects followed, zero stops following redirects
+	MaxRedirects *int
+	// RequestIDHeader replaces the name of the header the request ID is sent in, empty stops sending it
+	RequestIDHeader *string
 }
 
 func (a *ChangeTarget) IsValid() error {
@@ -102,6 +329,81 @@ func (a *ChangeTarget) IsValid() error {
 		if err != nil || *a.Endpoint == "" {
 			return zerrors.ThrowInvalidArgument(err, "COMMAND-jsbaera7b6", "Errors.Target.InvalidURL")
 		}
+		if err := execution.CheckTargetHost(*a.Endpoint); err != nil {
+			return err
+		}
+		if err := execution.ValidateEndpointTemplate(*a.Endpoint); err != nil {
+			return err
+		}
+	}
+	if err := execution.ValidateSuccessCriteria(a.SuccessCriteria); err != nil {
+		return err
+	}
+	if a.EventTypeFilter != nil {
+		if err := execution.ValidateEventTypeFilter(*a.EventTypeFilter); err != nil {
+			return err
+		}
+	}
+	if a.CertificateFingerprint != nil {
+		if err := execution.ValidateCertificateFingerprint(*a.CertificateFingerprint); err != nil {
+			return err
+		}
+	}
+	if a.CABundle != nil {
+		if err := execution.ValidateCABundle(*a.CABundle); err != nil {
+			return err
+		}
+	}
+	if a.ResponseContentType != nil {
+		if err := execution.ValidateResponseContentType(*a.ResponseContentType); err != nil {
+			return err
+		}
+	}
+	if a.PayloadFormat != nil {
+		if err := execution.ValidatePayloadFormat(*a.PayloadFormat); err != nil {
+			return err
+		}
+	}
+	if err := execution.ValidatePhaseTimeouts(a.PhaseTimeouts); err != nil {
+		return err
+	}
+	if err := a.OAuth2.IsValid(); err != nil {
+		return err
+	}
+	if err := a.BasicAuth.IsValid(); err != nil {
+		return err
+	}
+	if !a.OAuth2.isZero() && !a.BasicAuth.isZero() {
+		return zerrors.ThrowInvalidArgument(nil, "COMMAND-Ba4Ex2", "Errors.Target.MultipleAuth")
+	}
+	if a.MirrorEndpoints != nil {
+		if err := execution.ValidateMirrorEndpoints(*a.MirrorEndpoints); err != nil {
+			return err
+		}
+	}
+	if err := execution.ValidateResponseSizeLimit(a.ResponseSizeLimit); err != nil {
+		return err
+	}
+	if err := execution.ValidateBatchDelivery(a.BatchDelivery); err != nil {
+		return err
+	}
+	if err := execution.ValidateJWTDelivery(a.JWTDelivery); err != nil {
+		return err
+	}
+	if a.FailoverEndpoints != nil {
+		if err := execution.ValidateFailoverEndpoints(*a.FailoverEndpoints); err != nil {
+			return err
+		}
+	}
+	if a.MaxRedirects != nil {
+		if err := execution.ValidateMaxRedirects(*a.MaxRedirects); err != nil {
+			return err
+		}
+	}
+	if a.RequestIDHeader != nil {
+		if err := execution.ValidateRequestIDHeader(*a.RequestIDHeader); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -122,14 +424,15 @@ func (c *Commands) ChangeTarget(ctx context.Context, change *ChangeTarget, resou
 		return nil, zerrors.ThrowNotFound(nil, "COMMAND-xj14f2cccn", "Errors.Target.NotFound")
 	}
 
-	changedEvent := existing.NewChangedEvent(
+	changedEvent, err := existing.NewChangedEvent(
 		ctx,
 		TargetAggregateFromWriteModel(&existing.WriteModel),
-		change.Name,
-		change.TargetType,
-		change.Endpoint,
-		change.Timeout,
-		change.InterruptOnError)
+		change,
+		c.idpConfigEncryption,
+	)
+	if err != nil {
+		return nil, err
+	}
 	if changedEvent == nil {
 		return writeModelToObjectDetails(&existing.WriteModel), nil
 	}
@@ -169,6 +472,372 @@ func (c *Commands) DeleteTarget(ctx context.Context, id, resourceOwner string) (
 	return writeModelToObjectDetails(&existing.WriteModel), nil
 }
 
+// PauseTarget pauses the calls of the target, the payloads of an async target are queued until it is resumed
+func (c *Commands) PauseTarget(ctx context.Context, id, resourceOwner string) (*domain.ObjectDetails, error) {
+	if id == "" || resourceOwner == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Pa5uTi", "Errors.IDMissing")
+	}
+
+	existing, err := c.getTargetWriteModelByID(ctx, id, resourceOwner)
+	if err != nil {
+		return nil, err
+	}
+	if !existing.State.Exists() {
+		return nil, zerrors.ThrowNotFound(nil, "COMMAND-Pa5uTn", "Errors.Target.NotFound")
+	}
+	if existing.State == domain.TargetInactive {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Pa5uTp", "Errors.Target.AlreadyPaused")
+	}
+
+	if err := c.pushAppendAndReduce(ctx,
+		existing,
+		target.NewPausedEvent(ctx, TargetAggregateFromWriteModel(&existing.WriteModel)),
+	); err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&existing.WriteModel), nil
+}
+
+// ResumeTarget resumes the calls of a paused target, the queued payloads are delivered in the background
+func (c *Commands) ResumeTarget(ctx context.Context, id, resourceOwner string) (*domain.ObjectDetails, error) {
+	if id == "" || resourceOwner == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Re5uMi", "Errors.IDMissing")
+	}
+
+	existing, err := c.getTargetWriteModelByID(ctx, id, resourceOwner)
+	if err != nil {
+		return nil, err
+	}
+	if !existing.State.Exists() {
+		return nil, zerrors.ThrowNotFound(nil, "COMMAND-Re5uMn", "Errors.Target.NotFound")
+	}
+	if existing.State != domain.TargetInactive {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Re5uMp", "Errors.Target.NotPaused")
+	}
+
+	if err := c.pushAppendAndReduce(ctx,
+		existing,
+		target.NewResumedEvent(ctx, TargetAggregateFromWriteModel(&existing.WriteModel)),
+	); err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&existing.WriteModel), nil
+}
+
+// UpsertTargets reconciles the targets of the resource owner with the desired targets, which are identified by their name:
+// missing targets are created, changed targets are updated and equal targets are left untouched.
+// Targets of the resource owner which are not desired are kept.
+// Omitted secrets of existing targets keep the stored ones, so they don't have to be declared again.
+// All changes are pushed at once, so either all or none of them are applied.
+func (c *Commands) UpsertTargets(ctx context.Context, resourceOwner string, desired []*AddTarget) (created, updated, unchanged int, err error) {
+	if resourceOwner == "" {
+		return 0, 0, 0, zerrors.ThrowInvalidArgument(nil, "COMMAND-Up5rTo", "Errors.IDMissing")
+	}
+	names := make(map[string]struct{}, len(desired))
+	for _, add := range desired {
+		if add == nil || add.Name == "" {
+			return 0, 0, 0, zerrors.ThrowInvalidArgument(nil, "COMMAND-Up5rTn", "Errors.Target.Invalid")
+		}
+		if _, ok := names[add.Name]; ok {
+			return 0, 0, 0, zerrors.ThrowInvalidArgument(nil, "COMMAND-Up5rTd", "Errors.Target.DuplicateName")
+		}
+		names[add.Name] = struct{}{}
+	}
+
+	existing := NewTargetsOfResourceOwnerWriteModel(resourceOwner)
+	if err := c.eventstore.FilterToQueryReducer(ctx, existing); err != nil {
+		return 0, 0, 0, err
+	}
+	byName := existing.byName()
+
+	cmds := make([]eventstore.Command, 0, len(desired))
+	for _, add := range desired {
+		if err := c.applyTargetDefaults(ctx, add); err != nil {
+			return 0, 0, 0, err
+		}
+		wm, ok := byName[add.Name]
+		if !ok {
+			cmd, err := c.newTargetAddedEvent(ctx, add, resourceOwner)
+			if err != nil {
+				return 0, 0, 0, err
+			}
+			cmds = append(cmds, cmd)
+			created++
+			continue
+		}
+		change := add.toChange(wm.AggregateID)
+		if err := change.IsValid(); err != nil {
+			return 0, 0, 0, err
+		}
+		wm.keepUnchangedSecrets(change, c.idpConfigEncryption)
+		changedEvent, err := wm.NewChangedEvent(ctx, TargetAggregateFromWriteModel(&wm.WriteModel), change, c.idpConfigEncryption)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		if changedEvent == nil {
+			unchanged++
+			continue
+		}
+		cmds = append(cmds, changedEvent)
+		updated++
+	}
+	if len(cmds) == 0 {
+		return created, updated, unchanged, nil
+	}
+	if _, err := c.eventstore.Push(ctx, cmds...); err != nil {
+		return 0, 0, 0, err
+	}
+	return created, updated, unchanged, nil
+}
+
+// newTargetAddedEvent validates the new target and returns the event adding it with a generated ID
+func (c *Commands) newTargetAddedEvent(ctx context.Context, add *AddTarget, resourceOwner string) (_ *target.AddedEvent, err error) {
+	if err := add.IsValid(); err != nil {
+		return nil, err
+	}
+	if add.AggregateID == "" {
+		add.AggregateID, err = c.idGenerator.Next()
+		if err != nil {
+			return nil, err
+		}
+	}
+	oauth2, err := encryptTargetOAuth2(add.OAuth2, c.idpConfigEncryption)
+	if err != nil {
+		return nil, err
+	}
+	basicAuth, err := encryptTargetBasicAuth(add.BasicAuth, c.idpConfigEncryption)
+	if err != nil {
+		return nil, err
+	}
+	signingKeys, err := encryptTargetSigningKey(add.SigningKey, c.idpConfigEncryption)
+	if err != nil {
+		return nil, err
+	}
+	return target.NewAddedEvent(
+		ctx,
+		TargetAggregateFromWriteModel(&NewTargetWriteModel(add.AggregateID, resourceOwner).WriteModel),
+		add.Name,
+		add.TargetType,
+		add.Endpoint,
+		add.Timeout,
+		add.InterruptOnError,
+		target.WithSuccessCriteria(add.SuccessCriteria),
+		target.WithEventTypeFilter(add.EventTypeFilter),
+		target.WithCertificateFingerprint(add.CertificateFingerprint),
+		target.WithResponseContentType(add.ResponseContentType),
+		target.WithPayloadFormat(add.PayloadFormat),
+		target.WithPhaseTimeouts(add.PhaseTimeouts),
+		target.WithOAuth2(oauth2),
+		target.WithBasicAuth(basicAuth),
+		target.WithMirrorEndpoints(add.MirrorEndpoints),
+		target.WithResponseSizeLimit(add.ResponseSizeLimit),
+		target.WithBatchDelivery(add.BatchDelivery),
+		target.WithOrderedDelivery(add.OrderedDelivery),
+		target.WithIncludePayload(add.IncludePayload),
+		target.WithCABundle(add.CABundle),
+		target.WithJWTDelivery(add.JWTDelivery),
+		target.WithFailoverEndpoints(add.FailoverEndpoints),
+		target.WithMaxRedirects(add.MaxRedirects),
+		target.WithRequestIDHeader(add.RequestIDHeader),
+		target.WithSigningKeys(signingKeys),
+	), nil
+}
+
+// toChange returns the change replacing all values of the target with id by the values of the declared target,
+// omitted values remove the existing ones
+func (a *AddTarget) toChange(id string) *ChangeTarget {
+	change := &ChangeTarget{
+		ObjectRoot:             models.ObjectRoot{AggregateID: id},
+		Name:                   &a.Name,
+		TargetType:             &a.TargetType,
+		Endpoint:               &a.Endpoint,
+		Timeout:                &a.Timeout,
+		InterruptOnError:       &a.InterruptOnError,
+		SuccessCriteria:        a.SuccessCriteria,
+		EventTypeFilter:        &a.EventTypeFilter,
+		CertificateFingerprint: &a.CertificateFingerprint,
+		CABundle:               &a.CABundle,
+		ResponseContentType:    &a.ResponseContentType,
+		PayloadFormat:          &a.PayloadFormat,
+		PhaseTimeouts:          a.PhaseTimeouts,
+		OAuth2:                 a.OAuth2,
+		BasicAuth:              a.BasicAuth,
+		MirrorEndpoints:        &a.MirrorEndpoints,
+		ResponseSizeLimit:      a.ResponseSizeLimit,
+		BatchDelivery:          a.BatchDelivery,
+		OrderedDelivery:        &a.OrderedDelivery,
+		IncludePayload:         a.IncludePayload,
+		JWTDelivery:            a.JWTDelivery,
+		FailoverEndpoints:      &a.FailoverEndpoints,
+		MaxRedirects:           &a.MaxRedirects,
+		RequestIDHeader:        &a.RequestIDHeader,
+	}
+	if change.SuccessCriteria == nil {
+		change.SuccessCriteria = new(domain.TargetSuccessCriteria)
+	}
+	if change.PhaseTimeouts == nil {
+		change.PhaseTimeouts = new(domain.TargetPhaseTimeouts)
+	}
+	if change.OAuth2 == nil {
+		change.OAuth2 = new(TargetOAuth2)
+	}
+	if change.BasicAuth == nil {
+		change.BasicAuth = new(TargetBasicAuth)
+	}
+	if change.ResponseSizeLimit == nil {
+		change.ResponseSizeLimit = new(domain.TargetResponseSizeLimit)
+	}
+	if change.BatchDelivery == nil {
+		change.BatchDelivery = new(domain.TargetBatchDelivery)
+	}
+	if change.IncludePayload == nil {
+		includePayload := true
+		change.IncludePayload = &includePayload
+	}
+	if change.JWTDelivery == nil {
+		change.JWTDelivery = new(domain.TargetJWTDelivery)
+	}
+	return change
+}
+
+// encryptTargetOAuth2 returns the client credentials with the encrypted client secret
+func encryptTargetOAuth2(oauth2 *TargetOAuth2, secretCrypto crypto.EncryptionAlgorithm) (*domain.TargetOAuth2, error) {
+	if oauth2.isZero() {
+		return nil, nil
+	}
+	secret, err := crypto.Encrypt([]byte(oauth2.ClientSecret), secretCrypto)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TargetOAuth2{
+		TokenEndpoint: oauth2.TokenEndpoint,
+		ClientID:      oauth2.ClientID,
+		ClientSecret:  secret,
+	}, nil
+}
+
+// encryptTargetBasicAuth returns the basic authentication with the encrypted password
+func encryptTargetBasicAuth(basicAuth *TargetBasicAuth, secretCrypto crypto.EncryptionAlgorithm) (*domain.TargetBasicAuth, error) {
+	if basicAuth.isZero() {
+		return nil, nil
+	}
+	password, err := crypto.Encrypt([]byte(basicAuth.Password), secretCrypto)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TargetBasicAuth{
+		Username: basicAuth.Username,
+		Password: password,
+	}, nil
+}
+
+// VerifyAndRecordTarget calls the target with an empty payload marked by the [execution.TestHeader]
+// and records the outcome as execution of the target, the latest one is the last verification of the target.
+// The verification fails if the target does not respond with a status between 200 and 299 or not according to its success criteria,
+// a failed verification is not returned as error, the error of the call is part of the recorded execution.
+// Credentials of the target are not sent, as only the reachability of the endpoint is verified.
+func (c *Commands) VerifyAndRecordTarget(ctx context.Context, id, resourceOwner string) (*execution.TargetExecution, error) {
+	if id == "" || resourceOwner == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Vr5fTi", "Errors.IDMissing")
+	}
+
+	existing, err := c.getTargetWriteModelByID(ctx, id, resourceOwner)
+	if err != nil {
+		return nil, err
+	}
+	if !existing.State.Exists() {
+		return nil, zerrors.ThrowNotFound(nil, "COMMAND-Vr5fTn", "Errors.Target.NotFound")
+	}
+	return c.verifyTarget(ctx, &verificationTarget{existing})
+}
+
+// SetTargetSigningKey sets the key the requests to the target are signed with.
+// A target without signing key signs with the key immediately.
+// Otherwise the key becomes the secondary key, which additionally signs the requests until validUntil,
+// so the target can accept it before it is promoted by [Commands.RotateTargetSigningKey].
+func (c *Commands) SetTargetSigningKey(ctx context.Context, id, resourceOwner, key string, validUntil time.Time) (*domain.ObjectDetails, error) {
+	if id == "" || resourceOwner == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Sk4Sti", "Errors.IDMissing")
+	}
+	if key == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Sk4Stk", "Errors.Target.InvalidSigningKey")
+	}
+	if err := execution.ValidateSigningKey(key); err != nil {
+		return nil, err
+	}
+
+	existing, err := c.getTargetWriteModelByID(ctx, id, resourceOwner)
+	if err != nil {
+		return nil, err
+	}
+	if !existing.State.Exists() {
+		return nil, zerrors.ThrowNotFound(nil, "COMMAND-Sk4Stn", "Errors.Target.NotFound")
+	}
+	encrypted, err := crypto.Encrypt([]byte(key), c.idpConfigEncryption)
+	if err != nil {
+		return nil, err
+	}
+	keys := &domain.TargetSigningKeys{Primary: encrypted}
+	if !existing.SigningKeys.IsZero() {
+		// the keys of the write model must only change by the pushed event
+		keys = &domain.TargetSigningKeys{Primary: existing.SigningKeys.Primary}
+		if err := keys.SetSecondary(encrypted, validUntil, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.pushAppendAndReduce(ctx,
+		existing,
+		target.NewChangedEvent(ctx, TargetAggregateFromWriteModel(&existing.WriteModel), []target.Changes{target.ChangeSigningKeys(keys)}),
+	); err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&existing.WriteModel), nil
+}
+
+// RotateTargetSigningKey promotes the secondary signing key of the target to its primary key and clears the secondary key,
+// the requests are signed with the promoted key afterwards
+func (c *Commands) RotateTargetSigningKey(ctx context.Context, id, resourceOwner string) (*domain.ObjectDetails, error) {
+	if id == "" || resourceOwner == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Sk5Roi", "Errors.IDMissing")
+	}
+
+	existing, err := c.getTargetWriteModelByID(ctx, id, resourceOwner)
+	if err != nil {
+		return nil, err
+	}
+	if !existing.State.Exists() {
+		return nil, zerrors.ThrowNotFound(nil, "COMMAND-Sk5Ron", "Errors.Target.NotFound")
+	}
+	keys := new(domain.TargetSigningKeys)
+	if existing.SigningKeys != nil {
+		*keys = *existing.SigningKeys
+	}
+	if err := keys.Rotate(); err != nil {
+		return nil, err
+	}
+
+	if err := c.pushAppendAndReduce(ctx,
+		existing,
+		target.NewChangedEvent(ctx, TargetAggregateFromWriteModel(&existing.WriteModel), []target.Changes{target.ChangeSigningKeys(keys)}),
+	); err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&existing.WriteModel), nil
+}
+
+// encryptTargetSigningKey returns the signing keys with the encrypted key as primary key
+func encryptTargetSigningKey(key string, secretCrypto crypto.EncryptionAlgorithm) (*domain.TargetSigningKeys, error) {
+	if key == "" {
+		return nil, nil
+	}
+	primary, err := crypto.Encrypt([]byte(key), secretCrypto)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TargetSigningKeys{Primary: primary}, nil
+}
+
 func (c *Commands) existsTargetsByIDs(ctx context.Context, ids []string, resourceOwner string) bool {
 	wm := NewTargetsExistsWriteModel(ids, resourceOwner)
 	err := c.eventstore.FilterToQueryReducer(ctx, wm)
@@ -186,3 +855,98 @@ func (c *Commands) getTargetWriteModelByID(ctx context.Context, id string, resou
 	}
 	return wm, nil
 }
+
+var _ execution.Target = (*verificationTarget)(nil)
+
+// verificationTarget is the target called by [Commands.VerifyAndRecordTarget], without credentials
+type verificationTarget struct {
+	*TargetWriteModel
+}
+
+func (t *verificationTarget) GetTargetID() string {
+	return t.AggregateID
+}
+func (t *verificationTarget) IsInterruptOnError() bool {
+	return t.InterruptOnError
+}
+func (t *verificationTarget) GetEndpoint() string {
+	return t.Endpoint
+}
+func (t *verificationTarget) GetTargetType() domain.TargetType {
+	return t.TargetType
+}
+func (t *verificationTarget) GetTimeout() time.Duration {
+	return t.Timeout
+}
+func (t *verificationTarget) GetSuccessCriteria() *domain.TargetSuccessCriteria {
+	return t.SuccessCriteria
+}
+func (t *verificationTarget) GetEventTypeFilter() []string {
+	return t.EventTypeFilter
+}
+func (t *verificationTarget) GetCertificateFingerprint() string {
+	return t.CertificateFingerprint
+}
+func (t *verificationTarget) GetCABundle() string {
+	return t.CABundle
+}
+func (t *verificationTarget) GetResponseContentType() string {
+	return t.ResponseContentType
+}
+func (t *verificationTarget) GetPhaseTimeouts() domain.TargetPhaseTimeouts {
+	if t.PhaseTimeouts == nil {
+		return domain.TargetPhaseTimeouts{}
+	}
+	return *t.PhaseTimeouts
+}
+func (t *verificationTarget) GetPayloadFormat() domain.TargetPayloadFormat {
+	return t.PayloadFormat
+}
+func (t *verificationTarget) GetOAuth2Credentials() *domain.TargetOAuth2Credentials {
+	return nil
+}
+func (t *verificationTarget) GetBasicAuthCredentials() *domain.TargetBasicAuthCredentials {
+	return nil
+}
+func (t *verificationTarget) GetMirrorEndpoints() []string {
+	return t.MirrorEndpoints
+}
+func (t *verificationTarget) GetResponseSizeLimit() domain.TargetResponseSizeLimit {
+	if t.ResponseSizeLimit == nil {
+		return domain.TargetResponseSizeLimit{}
+	}
+	return *t.ResponseSizeLimit
+}
+func (t *verificationTarget) GetBatchDelivery() domain.TargetBatchDelivery {
+	if t.BatchDelivery == nil {
+		return domain.TargetBatchDelivery{}
+	}
+	return *t.BatchDelivery
+}
+func (t *verificationTarget) IsOrderedDelivery() bool {
+	return t.OrderedDelivery
+}
+func (t *verificationTarget) IsIncludePayload() bool {
+	return t.IncludePayload
+}
+func (t *verificationTarget) GetJWTDelivery() *domain.TargetJWTDelivery {
+	return nil
+}
+func (t *verificationTarget) GetFailoverEndpoints() []string {
+	return t.FailoverEndpoints
+}
+func (t *verificationTarget) GetMaxRedirects() int {
+	return t.MaxRedirects
+}
+func (t *verificationTarget) GetRequestIDHeader() string {
+	return t.RequestIDHeader
+}
+func (t *verificationTarget) GetSigningKey() string {
+	return ""
+}
+func (t *verificationTarget) GetSecondarySigningKey() (string, time.Time) {
+	return "", time.Time{}
+}
+func (t *verificationTarget) IsPaused() bool {
+	return t.State == domain.TargetInactive
+}
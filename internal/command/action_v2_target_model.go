@@ -5,9 +5,11 @@ import (
 	"slices"
 	"time"
 
+	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/repository/target"
+	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 type TargetWriteModel struct {
@@ -18,6 +20,42 @@ type TargetWriteModel struct {
 	Endpoint         string
 	Timeout          time.Duration
 	InterruptOnError bool
+	SuccessCriteria  *domain.TargetSuccessCriteria
+	EventTypeFilter  []string
+	// CertificateFingerprint is the fingerprint of the pinned certificate
+	CertificateFingerprint string
+	// CABundle are the certificate authorities the endpoint is verified against
+	CABundle string
+	// ResponseContentType is the expected content type of the responses
+	ResponseContentType string
+	// PayloadFormat is the format the payload is serialized in
+	PayloadFormat domain.TargetPayloadFormat
+	// PhaseTimeouts limit the phases of the calls
+	PhaseTimeouts *domain.TargetPhaseTimeouts
+	// OAuth2 are the client credentials with the encrypted client secret
+	OAuth2 *domain.TargetOAuth2
+	// BasicAuth is the basic authentication with the encrypted password
+	BasicAuth *domain.TargetBasicAuth
+	// MirrorEndpoints additionally receive the payload
+	MirrorEndpoints []string
+	// ResponseSizeLimit limits the size of the response bodies
+	ResponseSizeLimit *domain.TargetResponseSizeLimit
+	// BatchDelivery coalesces the payloads delivered to an async target into batches
+	BatchDelivery *domain.TargetBatchDelivery
+	// OrderedDelivery delivers the payloads of an async target one after the other
+	OrderedDelivery bool
+	// IncludePayload sends the payload of the events to the target
+	IncludePayload bool
+	// JWTDelivery signs the payload as JWT with the signing keys
+	JWTDelivery *domain.TargetJWTDelivery
+	// FailoverEndpoints are called in order if the endpoint fails
+	FailoverEndpoints []string
+	// MaxRedirects is the amount of redirects followed
+	MaxRedirects int
+	// RequestIDHeader is the name of the header the request ID is sent in
+	RequestIDHeader string
+	// SigningKeys are the encrypted keys the requests are signed with
+	SigningKeys *domain.TargetSigningKeys
 
 	State domain.TargetState
 }
@@ -40,6 +78,25 @@ func (wm *TargetWriteModel) Reduce() error {
 			wm.TargetType = e.TargetType
 			wm.Endpoint = e.Endpoint
 			wm.Timeout = e.Timeout
+			wm.SuccessCriteria = e.SuccessCriteria
+			wm.EventTypeFilter = e.EventTypeFilter
+			wm.CertificateFingerprint = e.CertificateFingerprint
+			wm.CABundle = e.CABundle
+			wm.ResponseContentType = e.ResponseContentType
+			wm.PayloadFormat = e.PayloadFormat
+			wm.PhaseTimeouts = e.PhaseTimeouts
+			wm.OAuth2 = e.OAuth2
+			wm.BasicAuth = e.BasicAuth
+			wm.MirrorEndpoints = e.MirrorEndpoints
+			wm.ResponseSizeLimit = e.ResponseSizeLimit
+			wm.BatchDelivery = e.BatchDelivery
+			wm.OrderedDelivery = e.OrderedDelivery
+			wm.IncludePayload = e.PayloadIncluded()
+			wm.JWTDelivery = e.JWTDelivery
+			wm.FailoverEndpoints = e.FailoverEndpoints
+			wm.MaxRedirects = e.MaxRedirects
+			wm.RequestIDHeader = e.RequestIDHeader
+			wm.SigningKeys = e.SigningKeys
 			wm.State = domain.TargetActive
 		case *target.ChangedEvent:
 			if e.Name != nil {
@@ -57,8 +114,69 @@ func (wm *TargetWriteModel) Reduce() error {
 			if e.InterruptOnError != nil {
 				wm.InterruptOnError = *e.InterruptOnError
 			}
+			if e.SuccessCriteria != nil {
+				wm.SuccessCriteria = e.SuccessCriteria
+			}
+			if e.EventTypeFilter != nil {
+				wm.EventTypeFilter = *e.EventTypeFilter
+			}
+			if e.CertificateFingerprint != nil {
+				wm.CertificateFingerprint = *e.CertificateFingerprint
+			}
+			if e.CABundle != nil {
+				wm.CABundle = *e.CABundle
+			}
+			if e.ResponseContentType != nil {
+				wm.ResponseContentType = *e.ResponseContentType
+			}
+			if e.PayloadFormat != nil {
+				wm.PayloadFormat = *e.PayloadFormat
+			}
+			if e.PhaseTimeouts != nil {
+				wm.PhaseTimeouts = e.PhaseTimeouts
+			}
+			if e.OAuth2 != nil {
+				wm.OAuth2 = e.OAuth2
+			}
+			if e.BasicAuth != nil {
+				wm.BasicAuth = e.BasicAuth
+			}
+			if e.MirrorEndpoints != nil {
+				wm.MirrorEndpoints = *e.MirrorEndpoints
+			}
+			if e.ResponseSizeLimit != nil {
+				wm.ResponseSizeLimit = e.ResponseSizeLimit
+			}
+			if e.BatchDelivery != nil {
+				wm.BatchDelivery = e.BatchDelivery
+			}
+			if e.OrderedDelivery != nil {
+				wm.OrderedDelivery = *e.OrderedDelivery
+			}
+			if e.IncludePayload != nil {
+				wm.IncludePayload = *e.IncludePayload
+			}
+			if e.JWTDelivery != nil {
+				wm.JWTDelivery = e.JWTDelivery
+			}
+			if e.FailoverEndpoints != nil {
+				wm.FailoverEndpoints = *e.FailoverEndpoints
+			}
+			if e.MaxRedirects != nil {
+				wm.MaxRedirects = *e.MaxRedirects
+			}
+			if e.RequestIDHeader != nil {
+				wm.RequestIDHeader = *e.RequestIDHeader
+			}
+			if e.SigningKeys != nil {
+				wm.SigningKeys = e.SigningKeys
+			}
 		case *target.RemovedEvent:
 			wm.State = domain.TargetRemoved
+		case *target.PausedEvent:
+			wm.State = domain.TargetInactive
+		case *target.ResumedEvent:
+			wm.State = domain.TargetActive
 		}
 	}
 	return wm.WriteModel.Reduce()
@@ -72,39 +190,248 @@ func (wm *TargetWriteModel) Query() *eventstore.SearchQueryBuilder {
 		AggregateIDs(wm.AggregateID).
 		EventTypes(target.AddedEventType,
 			target.ChangedEventType,
-			target.RemovedEventType).
+			target.RemovedEventType,
+			target.PausedEventType,
+			target.ResumedEventType).
 		Builder()
 }
 
 func (wm *TargetWriteModel) NewChangedEvent(
 	ctx context.Context,
 	agg *eventstore.Aggregate,
-	name *string,
-	targetType *domain.TargetType,
-	endpoint *string,
-	timeout *time.Duration,
-	interruptOnError *bool,
-) *target.ChangedEvent {
+	change *ChangeTarget,
+	secretCrypto crypto.EncryptionAlgorithm,
+) (*target.ChangedEvent, error) {
 	changes := make([]target.Changes, 0)
-	if name != nil && wm.Name != *name {
-		changes = append(changes, target.ChangeName(wm.Name, *name))
+	if change.Name != nil && wm.Name != *change.Name {
+		changes = append(changes, target.ChangeName(wm.Name, *change.Name))
+	}
+	if change.TargetType != nil && wm.TargetType != *change.TargetType {
+		changes = append(changes, target.ChangeTargetType(*change.TargetType))
+	}
+	if change.Endpoint != nil && wm.Endpoint != *change.Endpoint {
+		changes = append(changes, target.ChangeEndpoint(*change.Endpoint))
+	}
+	if change.Timeout != nil && wm.Timeout != *change.Timeout {
+		changes = append(changes, target.ChangeTimeout(*change.Timeout))
+	}
+	if change.InterruptOnError != nil && wm.InterruptOnError != *change.InterruptOnError {
+		changes = append(changes, target.ChangeInterruptOnError(*change.InterruptOnError))
+	}
+	if change.SuccessCriteria != nil && !successCriteriaEqual(wm.SuccessCriteria, change.SuccessCriteria) {
+		changes = append(changes, target.ChangeSuccessCriteria(change.SuccessCriteria))
+	}
+	if change.EventTypeFilter != nil && !slices.Equal(wm.EventTypeFilter, *change.EventTypeFilter) {
+		changes = append(changes, target.ChangeEventTypeFilter(*change.EventTypeFilter))
+	}
+	if change.CertificateFingerprint != nil && wm.CertificateFingerprint != *change.CertificateFingerprint {
+		changes = append(changes, target.ChangeCertificateFingerprint(*change.CertificateFingerprint))
+	}
+	if change.CABundle != nil && wm.CABundle != *change.CABundle {
+		changes = append(changes, target.ChangeCABundle(*change.CABundle))
+	}
+	if change.ResponseContentType != nil && wm.ResponseContentType != *change.ResponseContentType {
+		changes = append(changes, target.ChangeResponseContentType(*change.ResponseContentType))
 	}
-	if targetType != nil && wm.TargetType != *targetType {
-		changes = append(changes, target.ChangeTargetType(*targetType))
+	if change.PayloadFormat != nil && wm.PayloadFormat != *change.PayloadFormat {
+		changes = append(changes, target.ChangePayloadFormat(*change.PayloadFormat))
 	}
-	if endpoint != nil && wm.Endpoint != *endpoint {
-		changes = append(changes, target.ChangeEndpoint(*endpoint))
+	if change.PhaseTimeouts != nil && !phaseTimeoutsEqual(wm.PhaseTimeouts, change.PhaseTimeouts) {
+		changes = append(changes, target.ChangePhaseTimeouts(change.PhaseTimeouts))
 	}
-	if timeout != nil && wm.Timeout != *timeout {
-		changes = append(changes, target.ChangeTimeout(*timeout))
+	if change.OAuth2 != nil {
+		oauth2, err := wm.changedOAuth2(change.OAuth2, secretCrypto)
+		if err != nil {
+			return nil, err
+		}
+		if oauth2 != nil {
+			changes = append(changes, target.ChangeOAuth2(oauth2))
+		}
+	}
+	if change.BasicAuth != nil {
+		basicAuth, err := wm.changedBasicAuth(change.BasicAuth, secretCrypto)
+		if err != nil {
+			return nil, err
+		}
+		if basicAuth != nil {
+			changes = append(changes, target.ChangeBasicAuth(basicAuth))
+		}
+	}
+	if change.MirrorEndpoints != nil && !slices.Equal(wm.MirrorEndpoints, *change.MirrorEndpoints) {
+		changes = append(changes, target.ChangeMirrorEndpoints(*change.MirrorEndpoints))
+	}
+	if change.ResponseSizeLimit != nil && !responseSizeLimitEqual(wm.ResponseSizeLimit, change.ResponseSizeLimit) {
+		changes = append(changes, target.ChangeResponseSizeLimit(change.ResponseSizeLimit))
+	}
+	if change.BatchDelivery != nil && !batchDeliveryEqual(wm.BatchDelivery, change.BatchDelivery) {
+		changes = append(changes, target.ChangeBatchDelivery(change.BatchDelivery))
+	}
+	if change.OrderedDelivery != nil && wm.OrderedDelivery != *change.OrderedDelivery {
+		changes = append(changes, target.ChangeOrderedDelivery(*change.OrderedDelivery))
+	}
+	if change.IncludePayload != nil && wm.IncludePayload != *change.IncludePayload {
+		changes = append(changes, target.ChangeIncludePayload(*change.IncludePayload))
+	}
+	if change.JWTDelivery != nil && !jwtDeliveryEqual(wm.JWTDelivery, change.JWTDelivery) {
+		if !change.JWTDelivery.IsZero() && wm.SigningKeys.IsZero() {
+			return nil, zerrors.ThrowPreconditionFailed(nil, "COMMAND-Jw1Ky2", "Errors.Target.NoSigningKey")
+		}
+		changes = append(changes, target.ChangeJWTDelivery(change.JWTDelivery))
+	}
+	if change.FailoverEndpoints != nil && !slices.Equal(wm.FailoverEndpoints, *change.FailoverEndpoints) {
+		changes = append(changes, target.ChangeFailoverEndpoints(*change.FailoverEndpoints))
+	}
+	if change.MaxRedirects != nil && wm.MaxRedirects != *change.MaxRedirects {
+		changes = append(changes, target.ChangeMaxRedirects(*change.MaxRedirects))
+	}
+	if change.RequestIDHeader != nil && wm.RequestIDHeader != *change.RequestIDHeader {
+		changes = append(changes, target.ChangeRequestIDHeader(*change.RequestIDHeader))
 	}
-	if interruptOnError != nil && wm.InterruptOnError != *interruptOnError {
-		changes = append(changes, target.ChangeInterruptOnError(*interruptOnError))
+	if wm.authorizedByOAuth2(change) && wm.authorizedByBasicAuth(change) {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Ba4Ex3", "Errors.Target.MultipleAuth")
 	}
 	if len(changes) == 0 {
-		return nil
+		return nil, nil
 	}
-	return target.NewChangedEvent(ctx, agg, changes)
+	return target.NewChangedEvent(ctx, agg, changes), nil
+}
+
+// changedOAuth2 returns the client credentials to store or nil if they did not change.
+// The stored client secret is kept if no secret is given,
+// but it is never sent to another token endpoint than the one it was stored for.
+func (wm *TargetWriteModel) changedOAuth2(change *TargetOAuth2, secretCrypto crypto.EncryptionAlgorithm) (*domain.TargetOAuth2, error) {
+	if change.isZero() {
+		if wm.OAuth2.IsZero() {
+			return nil, nil
+		}
+		return new(domain.TargetOAuth2), nil
+	}
+	if change.ClientSecret != "" {
+		return encryptTargetOAuth2(change, secretCrypto)
+	}
+	if wm.OAuth2.IsZero() || wm.OAuth2.TokenEndpoint != change.TokenEndpoint {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Oa2Sc2", "Errors.Target.InvalidOAuth2")
+	}
+	if wm.OAuth2.ClientID == change.ClientID {
+		return nil, nil
+	}
+	return &domain.TargetOAuth2{
+		TokenEndpoint: wm.OAuth2.TokenEndpoint,
+		ClientID:      change.ClientID,
+		ClientSecret:  wm.OAuth2.ClientSecret,
+	}, nil
+}
+
+// changedBasicAuth returns the basic authentication to store or nil if it did not change,
+// the stored password is kept if no password is given.
+func (wm *TargetWriteModel) changedBasicAuth(change *TargetBasicAuth, secretCrypto crypto.EncryptionAlgorithm) (*domain.TargetBasicAuth, error) {
+	if change.isZero() {
+		if wm.BasicAuth.IsZero() {
+			return nil, nil
+		}
+		return new(domain.TargetBasicAuth), nil
+	}
+	if change.Password != "" {
+		return encryptTargetBasicAuth(change, secretCrypto)
+	}
+	if wm.BasicAuth.IsZero() {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Ba3Pw2", "Errors.Target.InvalidBasicAuth")
+	}
+	if wm.BasicAuth.Username == change.Username {
+		return nil, nil
+	}
+	return &domain.TargetBasicAuth{
+		Username: change.Username,
+		Password: wm.BasicAuth.Password,
+	}, nil
+}
+
+// keepUnchangedSecrets omits the secrets of the change which equal the stored ones,
+// so that a declared but unchanged secret does not change the target.
+// Secrets which can't be decrypted are handled as changed.
+func (wm *TargetWriteModel) keepUnchangedSecrets(change *ChangeTarget, secretCrypto crypto.EncryptionAlgorithm) {
+	if !change.OAuth2.isZero() && change.OAuth2.ClientSecret != "" && !wm.OAuth2.IsZero() &&
+		wm.OAuth2.TokenEndpoint == change.OAuth2.TokenEndpoint &&
+		secretEquals(wm.OAuth2.ClientSecret, change.OAuth2.ClientSecret, secretCrypto) {
+		oauth2 := *change.OAuth2
+		oauth2.ClientSecret = ""
+		change.OAuth2 = &oauth2
+	}
+	if !change.BasicAuth.isZero() && change.BasicAuth.Password != "" && !wm.BasicAuth.IsZero() &&
+		secretEquals(wm.BasicAuth.Password, change.BasicAuth.Password, secretCrypto) {
+		basicAuth := *change.BasicAuth
+		basicAuth.Password = ""
+		change.BasicAuth = &basicAuth
+	}
+}
+
+func secretEquals(stored *crypto.CryptoValue, secret string, secretCrypto crypto.EncryptionAlgorithm) bool {
+	if stored == nil {
+		return false
+	}
+	decrypted, err := crypto.DecryptString(stored, secretCrypto)
+	return err == nil && decrypted == secret
+}
+
+// authorizedByOAuth2 reports if the calls are authorized with client credentials after the change
+func (wm *TargetWriteModel) authorizedByOAuth2(change *ChangeTarget) bool {
+	if change.OAuth2 != nil {
+		return !change.OAuth2.isZero()
+	}
+	return !wm.OAuth2.IsZero()
+}
+
+// authorizedByBasicAuth reports if the calls are authorized with basic authentication after the change
+func (wm *TargetWriteModel) authorizedByBasicAuth(change *ChangeTarget) bool {
+	if change.BasicAuth != nil {
+		return !change.BasicAuth.isZero()
+	}
+	return !wm.BasicAuth.IsZero()
+}
+
+// successCriteriaEqual handles nil and empty criteria as equal
+func successCriteriaEqual(a, b *domain.TargetSuccessCriteria) bool {
+	if a == nil {
+		a = new(domain.TargetSuccessCriteria)
+	}
+	if b == nil {
+		b = new(domain.TargetSuccessCriteria)
+	}
+	return slices.Equal(a.StatusCodes, b.StatusCodes) &&
+		a.BodyPath == b.BodyPath &&
+		a.BodyValue == b.BodyValue
+}
+
+// phaseTimeoutsEqual handles nil and empty phase timeouts as equal
+func phaseTimeoutsEqual(a, b *domain.TargetPhaseTimeouts) bool {
+	if a.IsZero() || b.IsZero() {
+		return a.IsZero() && b.IsZero()
+	}
+	return *a == *b
+}
+
+// responseSizeLimitEqual handles nil and empty limits as equal
+func responseSizeLimitEqual(a, b *domain.TargetResponseSizeLimit) bool {
+	if a.IsZero() || b.IsZero() {
+		return a.IsZero() && b.IsZero()
+	}
+	return *a == *b
+}
+
+// batchDeliveryEqual handles nil and empty batch deliveries as equal
+func batchDeliveryEqual(a, b *domain.TargetBatchDelivery) bool {
+	if a.IsZero() || b.IsZero() {
+		return a.IsZero() && b.IsZero()
+	}
+	return *a == *b
+}
+
+// jwtDeliveryEqual handles nil and empty JWT deliveries as equal
+func jwtDeliveryEqual(a, b *domain.TargetJWTDelivery) bool {
+	if a.IsZero() || b.IsZero() {
+		return a.IsZero() && b.IsZero()
+	}
+	return *a == *b
 }
 
 type TargetsExistsWriteModel struct {
@@ -155,6 +482,64 @@ func (wm *TargetsExistsWriteModel) Query() *eventstore.SearchQueryBuilder {
 		Builder()
 }
 
+// TargetsOfResourceOwnerWriteModel are all targets of a resource owner
+type TargetsOfResourceOwnerWriteModel struct {
+	eventstore.WriteModel
+
+	// targets are the targets by their ID, including the removed ones
+	targets map[string]*TargetWriteModel
+}
+
+func NewTargetsOfResourceOwnerWriteModel(resourceOwner string) *TargetsOfResourceOwnerWriteModel {
+	return &TargetsOfResourceOwnerWriteModel{
+		WriteModel: eventstore.WriteModel{
+			ResourceOwner: resourceOwner,
+			InstanceID:    resourceOwner,
+		},
+		targets: make(map[string]*TargetWriteModel),
+	}
+}
+
+func (wm *TargetsOfResourceOwnerWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		id := event.Aggregate().ID
+		existing, ok := wm.targets[id]
+		if !ok {
+			existing = NewTargetWriteModel(id, wm.ResourceOwner)
+			wm.targets[id] = existing
+		}
+		existing.AppendEvents(event)
+		if err := existing.Reduce(); err != nil {
+			return err
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *TargetsOfResourceOwnerWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(wm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(target.AggregateType).
+		EventTypes(target.AddedEventType,
+			target.ChangedEventType,
+			target.RemovedEventType,
+			target.PausedEventType,
+			target.ResumedEventType).
+		Builder()
+}
+
+// byName returns the existing targets by their name
+func (wm *TargetsOfResourceOwnerWriteModel) byName() map[string]*TargetWriteModel {
+	targets := make(map[string]*TargetWriteModel, len(wm.targets))
+	for _, existing := range wm.targets {
+		if existing.State.Exists() {
+			targets[existing.Name] = existing
+		}
+	}
+	return targets
+}
+
 func TargetAggregateFromWriteModel(wm *eventstore.WriteModel) *eventstore.Aggregate {
 	return &eventstore.Aggregate{
 		ID:            wm.AggregateID,
@@ -7,12 +7,18 @@ import (
 
 	"github.com/muhlemmer/gu"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/eventstore/v1/models"
+	"github.com/zitadel/zitadel/internal/execution"
 	"github.com/zitadel/zitadel/internal/id"
 	"github.com/zitadel/zitadel/internal/id/mock"
+	"github.com/zitadel/zitadel/internal/repository/instance"
 	"github.com/zitadel/zitadel/internal/repository/target"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
@@ -55,7 +61,9 @@ func TestCommands_AddTarget(t *testing.T) {
 		{
 			"no name, error",
 			fields{
-				eventstore: expectEventstore(),
+				eventstore: expectEventstore(
+					expectFilter(),
+				),
 			},
 			args{
 				ctx:           context.Background(),
@@ -67,9 +75,11 @@ func TestCommands_AddTarget(t *testing.T) {
 			},
 		},
 		{
-			"no timeout, error",
+			"no timeout and no default, error",
 			fields{
-				eventstore: expectEventstore(),
+				eventstore: expectEventstore(
+					expectFilter(),
+				),
 			},
 			args{
 				ctx: context.Background(),
@@ -119,155 +129,2282 @@ func TestCommands_AddTarget(t *testing.T) {
 			},
 		},
 		{
-			"unique constraint failed, error",
+			"invalid success criteria, error",
 			fields{
-				eventstore: expectEventstore(
-					expectFilter(),
-					expectPushFailed(
-						zerrors.ThrowPreconditionFailed(nil, "id", "name already exists"),
-						target.NewAddedEvent(context.Background(),
-							target.NewAggregate("id1", "instance"),
-							"name",
-							domain.TargetTypeWebhook,
-							"https://example.com",
-							time.Second,
-							false,
-						),
-					),
-				),
-				idGenerator: mock.ExpectID(t, "id1"),
+				eventstore: expectEventstore(),
 			},
 			args{
 				ctx: context.Background(),
 				add: &AddTarget{
-					Name:       "name",
-					Endpoint:   "https://example.com",
-					Timeout:    time.Second,
-					TargetType: domain.TargetTypeWebhook,
+					Name:     "name",
+					Timeout:  time.Second,
+					Endpoint: "https://example.com",
+					SuccessCriteria: &domain.TargetSuccessCriteria{
+						BodyPath: "status",
+					},
 				},
 				resourceOwner: "instance",
 			},
 			res{
-				err: zerrors.IsPreconditionFailed,
+				err: zerrors.IsErrorInvalidArgument,
 			},
 		},
 		{
-			"already existing",
+			"invalid event type filter, error",
 			fields{
-				eventstore: expectEventstore(
-					expectFilter(
-						eventFromEventPusher(
-							targetAddEvent("target", "instance"),
-						),
-					),
-				),
-				idGenerator: mock.ExpectID(t, "id1"),
+				eventstore: expectEventstore(),
 			},
 			args{
 				ctx: context.Background(),
 				add: &AddTarget{
-					Name:       "name",
-					TargetType: domain.TargetTypeWebhook,
-					Timeout:    time.Second,
-					Endpoint:   "https://example.com",
+					Name:            "name",
+					Timeout:         time.Second,
+					Endpoint:        "https://example.com",
+					EventTypeFilter: []string{"user.["},
 				},
 				resourceOwner: "instance",
 			},
 			res{
-				err: zerrors.IsErrorAlreadyExists,
+				err: zerrors.IsErrorInvalidArgument,
 			},
 		},
 		{
-			"push ok",
+			"invalid mirror endpoint, error",
 			fields{
-				eventstore: expectEventstore(
-					expectFilter(),
-					expectPush(
-						targetAddEvent("id1", "instance"),
-					),
-				),
-				idGenerator: mock.ExpectID(t, "id1"),
+				eventstore: expectEventstore(),
 			},
 			args{
 				ctx: context.Background(),
 				add: &AddTarget{
-					Name:       "name",
-					TargetType: domain.TargetTypeWebhook,
-					Timeout:    time.Second,
-					Endpoint:   "https://example.com",
+					Name:            "name",
+					Timeout:         time.Second,
+					Endpoint:        "https://example.com",
+					MirrorEndpoints: []string{"https://mirror.example.com", "mirror"},
 				},
 				resourceOwner: "instance",
 			},
 			res{
-				id: "id1",
-				details: &domain.ObjectDetails{
-					ResourceOwner: "instance",
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"invalid failover endpoint, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:              "name",
+					Timeout:           time.Second,
+					Endpoint:          "https://example.com",
+					FailoverEndpoints: []string{"ftp://failover.example.com"},
 				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
 			},
 		},
 		{
-			"push full ok",
+			"invalid max redirects, error",
 			fields{
-				eventstore: expectEventstore(
-					expectFilter(),
-					expectPush(
-						func() eventstore.Command {
-							event := targetAddEvent("id1", "instance")
-							event.InterruptOnError = true
-							return event
-						}(),
-					),
-				),
-				idGenerator: mock.ExpectID(t, "id1"),
+				eventstore: expectEventstore(),
 			},
 			args{
 				ctx: context.Background(),
 				add: &AddTarget{
-					Name:             "name",
-					TargetType:       domain.TargetTypeWebhook,
-					Endpoint:         "https://example.com",
-					Timeout:          time.Second,
-					InterruptOnError: true,
+					Name:         "name",
+					Timeout:      time.Second,
+					Endpoint:     "https://example.com",
+					MaxRedirects: 11,
 				},
 				resourceOwner: "instance",
 			},
 			res{
-				id: "id1",
-				details: &domain.ObjectDetails{
-					ResourceOwner: "instance",
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"invalid request id header, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:            "name",
+					Timeout:         time.Second,
+					Endpoint:        "https://example.com",
+					RequestIDHeader: "X Request-Id",
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"invalid response size limit, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:              "name",
+					Timeout:           time.Second,
+					Endpoint:          "https://example.com",
+					ResponseSizeLimit: &domain.TargetResponseSizeLimit{MaxBytes: -1},
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"invalid batch delivery, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:          "name",
+					Timeout:       time.Second,
+					Endpoint:      "https://example.com",
+					BatchDelivery: &domain.TargetBatchDelivery{MaxSize: 10},
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"invalid certificate fingerprint, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:                   "name",
+					Timeout:                time.Second,
+					Endpoint:               "https://example.com",
+					CertificateFingerprint: "d4:1d:8c",
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"malformed ca bundle, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:     "name",
+					Timeout:  time.Second,
+					Endpoint: "https://example.com",
+					CABundle: "-----BEGIN CERTIFICATE-----\nmalformed\n-----END CERTIFICATE-----\n",
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"invalid response content type, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:                "name",
+					Timeout:             time.Second,
+					Endpoint:            "https://example.com",
+					ResponseContentType: "application/json;;",
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"invalid payload format, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:          "name",
+					Timeout:       time.Second,
+					Endpoint:      "https://example.com",
+					PayloadFormat: domain.TargetPayloadFormat(99),
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"invalid phase timeouts, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:          "name",
+					Timeout:       time.Second,
+					Endpoint:      "https://example.com",
+					PhaseTimeouts: &domain.TargetPhaseTimeouts{Dial: -time.Second},
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"unknown url template variable, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:     "name",
+					Timeout:  time.Second,
+					Endpoint: "https://example.com/tenants/{tenantID}/hook",
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"oauth2 without client secret, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:     "name",
+					Timeout:  time.Second,
+					Endpoint: "https://example.com",
+					OAuth2: &TargetOAuth2{
+						TokenEndpoint: "https://auth.example.com/oauth/token",
+						ClientID:      "client",
+					},
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"basic auth without password, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:     "name",
+					Timeout:  time.Second,
+					Endpoint: "https://example.com",
+					BasicAuth: &TargetBasicAuth{
+						Username: "user",
+					},
 				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"oauth2 and basic auth, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:     "name",
+					Timeout:  time.Second,
+					Endpoint: "https://example.com",
+					OAuth2: &TargetOAuth2{
+						TokenEndpoint: "https://auth.example.com/oauth/token",
+						ClientID:      "client",
+						ClientSecret:  "secret",
+					},
+					BasicAuth: &TargetBasicAuth{
+						Username: "user",
+						Password: "password",
+					},
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"jwt delivery without signing key, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:     "name",
+					Timeout:  time.Second,
+					Endpoint: "https://example.com",
+					JWTDelivery: &domain.TargetJWTDelivery{
+						Algorithm: domain.TargetJWTAlgorithmHS256,
+						Issuer:    "zitadel",
+					},
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"unique constraint failed, error",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+					expectPushFailed(
+						zerrors.ThrowPreconditionFailed(nil, "id", "name already exists"),
+						target.NewAddedEvent(context.Background(),
+							target.NewAggregate("id1", "instance"),
+							"name",
+							domain.TargetTypeWebhook,
+							"https://example.com",
+							time.Second,
+							false,
+						),
+					),
+				),
+				idGenerator: mock.ExpectID(t, "id1"),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:       "name",
+					Endpoint:   "https://example.com",
+					Timeout:    time.Second,
+					TargetType: domain.TargetTypeWebhook,
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsPreconditionFailed,
+			},
+		},
+		{
+			"already existing",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("target", "instance"),
+						),
+					),
+				),
+				idGenerator: mock.ExpectID(t, "id1"),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:       "name",
+					TargetType: domain.TargetTypeWebhook,
+					Timeout:    time.Second,
+					Endpoint:   "https://example.com",
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorAlreadyExists,
+			},
+		},
+		{
+			"push ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+					expectPush(
+						targetAddEvent("id1", "instance"),
+					),
+				),
+				idGenerator: mock.ExpectID(t, "id1"),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:       "name",
+					TargetType: domain.TargetTypeWebhook,
+					Timeout:    time.Second,
+					Endpoint:   "https://example.com",
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				id: "id1",
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"push full ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+					expectPush(
+						func() eventstore.Command {
+							event := targetAddEvent("id1", "instance")
+							event.InterruptOnError = true
+							event.EventTypeFilter = []string{"user.*"}
+							event.PayloadFormat = domain.TargetPayloadFormatXML
+							event.MirrorEndpoints = []string{"https://mirror.example.com"}
+							event.FailoverEndpoints = []string{"https://failover.example.com"}
+							event.MaxRedirects = 3
+							event.RequestIDHeader = "X-Request-Id"
+							return event
+						}(),
+					),
+				),
+				idGenerator: mock.ExpectID(t, "id1"),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:              "name",
+					TargetType:        domain.TargetTypeWebhook,
+					Endpoint:          "https://example.com",
+					Timeout:           time.Second,
+					InterruptOnError:  true,
+					EventTypeFilter:   []string{"user.*"},
+					PayloadFormat:     domain.TargetPayloadFormatXML,
+					MirrorEndpoints:   []string{"https://mirror.example.com"},
+					FailoverEndpoints: []string{"https://failover.example.com"},
+					MaxRedirects:      3,
+					RequestIDHeader:   "X-Request-Id",
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				id: "id1",
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"push oauth2 ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+					expectPush(
+						func() eventstore.Command {
+							event := targetAddEvent("id1", "instance")
+							event.OAuth2 = &domain.TargetOAuth2{
+								TokenEndpoint: "https://auth.example.com/oauth/token",
+								ClientID:      "client",
+								ClientSecret: &crypto.CryptoValue{
+									CryptoType: crypto.TypeEncryption,
+									Algorithm:  "enc",
+									KeyID:      "id",
+									Crypted:    []byte("secret"),
+								},
+							}
+							return event
+						}(),
+					),
+				),
+				idGenerator: mock.ExpectID(t, "id1"),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:       "name",
+					TargetType: domain.TargetTypeWebhook,
+					Endpoint:   "https://example.com",
+					Timeout:    time.Second,
+					OAuth2: &TargetOAuth2{
+						TokenEndpoint: "https://auth.example.com/oauth/token",
+						ClientID:      "client",
+						ClientSecret:  "secret",
+					},
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				id: "id1",
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"push basic auth ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+					expectPush(
+						func() eventstore.Command {
+							event := targetAddEvent("id1", "instance")
+							event.BasicAuth = &domain.TargetBasicAuth{
+								Username: "user",
+								Password: &crypto.CryptoValue{
+									CryptoType: crypto.TypeEncryption,
+									Algorithm:  "enc",
+									KeyID:      "id",
+									Crypted:    []byte("password"),
+								},
+							}
+							return event
+						}(),
+					),
+				),
+				idGenerator: mock.ExpectID(t, "id1"),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:       "name",
+					TargetType: domain.TargetTypeWebhook,
+					Endpoint:   "https://example.com",
+					Timeout:    time.Second,
+					BasicAuth: &TargetBasicAuth{
+						Username: "user",
+						Password: "password",
+					},
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				id: "id1",
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"push without timeout, default of instance",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewTargetDefaultsSetEvent(context.Background(),
+								&instance.NewAggregate("instance").Aggregate,
+								5*time.Second,
+							),
+						),
+					),
+					expectFilter(),
+					expectPush(
+						func() eventstore.Command {
+							event := targetAddEvent("id1", "instance")
+							event.Timeout = 5 * time.Second
+							return event
+						}(),
+					),
+				),
+				idGenerator: mock.ExpectID(t, "id1"),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance"),
+				add: &AddTarget{
+					Name:       "name",
+					TargetType: domain.TargetTypeWebhook,
+					Endpoint:   "https://example.com",
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				id: "id1",
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"push with timeout, default of instance not applied",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+					expectPush(
+						targetAddEvent("id1", "instance"),
+					),
+				),
+				idGenerator: mock.ExpectID(t, "id1"),
+			},
+			args{
+				ctx: authz.WithInstanceID(context.Background(), "instance"),
+				add: &AddTarget{
+					Name:       "name",
+					TargetType: domain.TargetTypeWebhook,
+					Endpoint:   "https://example.com",
+					Timeout:    time.Second,
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				id: "id1",
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"push jwt delivery ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+					expectPush(
+						func() eventstore.Command {
+							event := targetAddEvent("id1", "instance")
+							event.JWTDelivery = &domain.TargetJWTDelivery{
+								Algorithm: domain.TargetJWTAlgorithmHS256,
+								Issuer:    "zitadel",
+							}
+							event.SigningKeys = &domain.TargetSigningKeys{
+								Primary: targetSigningKey("primary-signing-key-0123456789abcdef"),
+							}
+							return event
+						}(),
+					),
+				),
+				idGenerator: mock.ExpectID(t, "id1"),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:       "name",
+					TargetType: domain.TargetTypeWebhook,
+					Endpoint:   "https://example.com",
+					Timeout:    time.Second,
+					JWTDelivery: &domain.TargetJWTDelivery{
+						Algorithm: domain.TargetJWTAlgorithmHS256,
+						Issuer:    "zitadel",
+					},
+					SigningKey: "primary-signing-key-0123456789abcdef",
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				id: "id1",
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"push signing key ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+					expectPush(
+						func() eventstore.Command {
+							event := targetAddEvent("id1", "instance")
+							event.SigningKeys = &domain.TargetSigningKeys{
+								Primary: targetSigningKey("primary-signing-key-0123456789abcdef"),
+							}
+							return event
+						}(),
+					),
+				),
+				idGenerator: mock.ExpectID(t, "id1"),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:       "name",
+					TargetType: domain.TargetTypeWebhook,
+					Endpoint:   "https://example.com",
+					Timeout:    time.Second,
+					SigningKey: "primary-signing-key-0123456789abcdef",
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				id: "id1",
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"signing key too short, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				add: &AddTarget{
+					Name:       "name",
+					TargetType: domain.TargetTypeWebhook,
+					Endpoint:   "https://example.com",
+					Timeout:    time.Second,
+					SigningKey: "short",
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore:          tt.fields.eventstore(t),
+				idGenerator:         tt.fields.idGenerator,
+				idpConfigEncryption: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+			}
+			details, err := c.AddTarget(tt.args.ctx, tt.args.add, tt.args.resourceOwner)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.id, tt.args.add.AggregateID)
+				assert.Equal(t, tt.res.details, details)
+			}
+		})
+	}
+}
+
+func TestCommands_AddTargetIfNotExists(t *testing.T) {
+	namedTargetAddEvent := func(aggID, name string) *target.AddedEvent {
+		return target.NewAddedEvent(context.Background(),
+			target.NewAggregate(aggID, "instance"),
+			name,
+			domain.TargetTypeWebhook,
+			"https://example.com",
+			time.Second,
+			false,
+		)
+	}
+	type fields struct {
+		eventstore  func(t *testing.T) *eventstore.Eventstore
+		idGenerator id.Generator
+	}
+	type args struct {
+		add           *AddTarget
+		resourceOwner string
+	}
+	type res struct {
+		id      string
+		created bool
+		details *domain.ObjectDetails
+		err     func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "resourceowner missing, error",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			args: args{
+				add: &AddTarget{Name: "name"},
+			},
+			res: res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "first create, created",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(namedTargetAddEvent("id0", "other")),
+					),
+					expectFilter(),
+					expectPush(
+						namedTargetAddEvent("id1", "name"),
+					),
+				),
+				idGenerator: mock.ExpectID(t, "id1"),
+			},
+			args: args{
+				add: &AddTarget{
+					Name:       "name",
+					TargetType: domain.TargetTypeWebhook,
+					Endpoint:   "https://example.com",
+					Timeout:    time.Second,
+				},
+				resourceOwner: "instance",
+			},
+			res: res{
+				id:      "id1",
+				created: true,
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			name: "repeated create, existing returned unchanged",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(namedTargetAddEvent("id1", "name")),
+					),
+				),
+			},
+			args: args{
+				add: &AddTarget{
+					Name:       "name",
+					TargetType: domain.TargetTypeWebhook,
+					Endpoint:   "https://example2.com",
+					Timeout:    time.Second,
+				},
+				resourceOwner: "instance",
+			},
+			res: res{
+				id: "id1",
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			name: "removed target of same name, created",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(namedTargetAddEvent("id1", "name")),
+						eventFromEventPusher(targetRemoveEvent("id1", "instance")),
+					),
+					expectFilter(),
+					expectPush(
+						namedTargetAddEvent("id2", "name"),
+					),
+				),
+				idGenerator: mock.ExpectID(t, "id2"),
+			},
+			args: args{
+				add: &AddTarget{
+					Name:       "name",
+					TargetType: domain.TargetTypeWebhook,
+					Endpoint:   "https://example.com",
+					Timeout:    time.Second,
+				},
+				resourceOwner: "instance",
+			},
+			res: res{
+				id:      "id2",
+				created: true,
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore:          tt.fields.eventstore(t),
+				idGenerator:         tt.fields.idGenerator,
+				idpConfigEncryption: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+			}
+			details, created, err := c.AddTargetIfNotExists(context.Background(), tt.args.add, tt.args.resourceOwner)
+			if tt.res.err != nil {
+				assert.True(t, tt.res.err(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.res.id, tt.args.add.AggregateID)
+			assert.Equal(t, tt.res.created, created)
+			assert.Equal(t, tt.res.details, details)
+		})
+	}
+}
+
+func TestCommands_ValidateTarget(t *testing.T) {
+	execution.SetConfig(&execution.Config{HostPolicy: execution.HostPolicy{AllowList: []string{"example.com"}}})
+	t.Cleanup(func() { execution.SetConfig(nil) })
+
+	tests := []struct {
+		name    string
+		add     *AddTarget
+		wantIDs []string
+	}{
+		{
+			name: "valid",
+			add: &AddTarget{
+				Name:     "name",
+				Timeout:  time.Second,
+				Endpoint: "https://example.com",
+			},
+		},
+		{
+			name: "multiple violations",
+			add: &AddTarget{
+				Timeout:  0,
+				Endpoint: "https://denied.com",
+				OAuth2: &TargetOAuth2{
+					TokenEndpoint: "https://example.com/oauth/token",
+					ClientID:      "client",
+					ClientSecret:  "secret",
+				},
+				BasicAuth: &TargetBasicAuth{
+					Username: "user",
+					Password: "password",
+				},
+				BatchDelivery: &domain.TargetBatchDelivery{MaxSize: 10},
+			},
+			wantIDs: []string{
+				"COMMAND-ddqbm9us5p",
+				"COMMAND-39f35d8uri",
+				"EXEC-Ss9fQa",
+				"COMMAND-Ba4Ex1",
+				"EXEC-Bt4dVl",
+			},
+		},
+		{
+			name: "invalid url, endpoint checks skipped",
+			add: &AddTarget{
+				Name:              "name",
+				Timeout:           time.Second,
+				ResponseSizeLimit: &domain.TargetResponseSizeLimit{MaxBytes: -1},
+			},
+			wantIDs: []string{
+				"COMMAND-1r2k6qo6wg",
+				"EXEC-Rs5zLm",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{}
+			errs := c.ValidateTarget(context.Background(), tt.add)
+			ids := make([]string, len(errs))
+			for i, err := range errs {
+				zErr := new(zerrors.ZitadelError)
+				require.ErrorAs(t, err, &zErr)
+				ids[i] = zErr.GetID()
+			}
+			if len(tt.wantIDs) == 0 {
+				assert.Empty(t, ids)
+				return
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			// the first violation is the error of the validation on save
+			assert.Equal(t, errs[0], tt.add.IsValid())
+		})
+	}
+}
+
+func TestCommands_ChangeTarget(t *testing.T) {
+	type fields struct {
+		eventstore func(t *testing.T) *eventstore.Eventstore
+	}
+	type args struct {
+		ctx           context.Context
+		change        *ChangeTarget
+		resourceOwner string
+	}
+	type res struct {
+		details *domain.ObjectDetails
+		err     func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			"resourceowner missing, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx:           context.Background(),
+				change:        &ChangeTarget{},
+				resourceOwner: "",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"id missing, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx:           context.Background(),
+				change:        &ChangeTarget{},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"name empty, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				change: &ChangeTarget{
+					Name: gu.Ptr(""),
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"timeout empty, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				change: &ChangeTarget{
+					Timeout: gu.Ptr(time.Duration(0)),
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"Endpoint empty, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				change: &ChangeTarget{
+					Endpoint: gu.Ptr(""),
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"Endpoint not parsable, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				change: &ChangeTarget{
+					Endpoint: gu.Ptr("://"),
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"payload format not supported, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx: context.Background(),
+				change: &ChangeTarget{
+					ObjectRoot: models.ObjectRoot{
+						AggregateID: "id1",
+					},
+					PayloadFormat: gu.Ptr(domain.TargetPayloadFormat(99)),
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"not found, error",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+				),
+			},
+			args{
+				ctx: context.Background(),
+				change: &ChangeTarget{
+					ObjectRoot: models.ObjectRoot{
+						AggregateID: "id1",
+					},
+					Name: gu.Ptr("name"),
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsNotFound,
+			},
+		},
+		{
+			"no changes",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("target", "instance"),
+						),
+					),
+				),
+			},
+			args{
+				ctx: context.Background(),
+				change: &ChangeTarget{
+					ObjectRoot: models.ObjectRoot{
+						AggregateID: "id1",
+					},
+					TargetType: gu.Ptr(domain.TargetTypeWebhook),
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"oauth2 without client secret, error",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("target", "instance"),
+						),
+					),
+				),
+			},
+			args{
+				ctx: context.Background(),
+				change: &ChangeTarget{
+					ObjectRoot: models.ObjectRoot{
+						AggregateID: "id1",
+					},
+					OAuth2: &TargetOAuth2{
+						TokenEndpoint: "https://auth.example.com/oauth/token",
+						ClientID:      "client",
+					},
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"basic auth on target with oauth2, error",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							func() eventstore.Command {
+								event := targetAddEvent("id1", "instance")
+								event.OAuth2 = &domain.TargetOAuth2{
+									TokenEndpoint: "https://auth.example.com/oauth/token",
+									ClientID:      "client",
+									ClientSecret: &crypto.CryptoValue{
+										CryptoType: crypto.TypeEncryption,
+										Algorithm:  "enc",
+										KeyID:      "id",
+										Crypted:    []byte("secret"),
+									},
+								}
+								return event
+							}(),
+						),
+					),
+				),
+			},
+			args{
+				ctx: context.Background(),
+				change: &ChangeTarget{
+					ObjectRoot: models.ObjectRoot{
+						AggregateID: "id1",
+					},
+					BasicAuth: &TargetBasicAuth{
+						Username: "user",
+						Password: "password",
+					},
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"jwt delivery without signing key, error",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("id1", "instance"),
+						),
+					),
+				),
+			},
+			args{
+				ctx: context.Background(),
+				change: &ChangeTarget{
+					ObjectRoot: models.ObjectRoot{
+						AggregateID: "id1",
+					},
+					JWTDelivery: &domain.TargetJWTDelivery{
+						Algorithm: domain.TargetJWTAlgorithmHS256,
+						Issuer:    "zitadel",
+					},
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsPreconditionFailed,
+			},
+		},
+		{
+			"unique constraint failed, error",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("target", "instance"),
+						),
+					),
+					expectPushFailed(
+						zerrors.ThrowPreconditionFailed(nil, "id", "name already exists"),
+						target.NewChangedEvent(context.Background(),
+							target.NewAggregate("id1", "instance"),
+							[]target.Changes{
+								target.ChangeName("name", "name2"),
+							},
+						),
+					),
+				),
+			},
+			args{
+				ctx: context.Background(),
+				change: &ChangeTarget{
+					ObjectRoot: models.ObjectRoot{
+						AggregateID: "id1",
+					},
+					Name: gu.Ptr("name2"),
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsPreconditionFailed,
+			},
+		},
+		{
+			"push ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("id1", "instance"),
+						),
+					),
+					expectPush(
+						target.NewChangedEvent(context.Background(),
+							target.NewAggregate("id1", "instance"),
+							[]target.Changes{
+								target.ChangeName("name", "name2"),
+							},
+						),
+					),
+				),
+			},
+			args{
+				ctx: context.Background(),
+				change: &ChangeTarget{
+					ObjectRoot: models.ObjectRoot{
+						AggregateID: "id1",
+					},
+					Name: gu.Ptr("name2"),
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"push oauth2 to basic auth ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							func() eventstore.Command {
+								event := targetAddEvent("id1", "instance")
+								event.OAuth2 = &domain.TargetOAuth2{
+									TokenEndpoint: "https://auth.example.com/oauth/token",
+									ClientID:      "client",
+									ClientSecret: &crypto.CryptoValue{
+										CryptoType: crypto.TypeEncryption,
+										Algorithm:  "enc",
+										KeyID:      "id",
+										Crypted:    []byte("secret"),
+									},
+								}
+								return event
+							}(),
+						),
+					),
+					expectPush(
+						target.NewChangedEvent(context.Background(),
+							target.NewAggregate("id1", "instance"),
+							[]target.Changes{
+								target.ChangeOAuth2(new(domain.TargetOAuth2)),
+								target.ChangeBasicAuth(&domain.TargetBasicAuth{
+									Username: "user",
+									Password: &crypto.CryptoValue{
+										CryptoType: crypto.TypeEncryption,
+										Algorithm:  "enc",
+										KeyID:      "id",
+										Crypted:    []byte("password"),
+									},
+								}),
+							},
+						),
+					),
+				),
+			},
+			args{
+				ctx: context.Background(),
+				change: &ChangeTarget{
+					ObjectRoot: models.ObjectRoot{
+						AggregateID: "id1",
+					},
+					OAuth2: &TargetOAuth2{},
+					BasicAuth: &TargetBasicAuth{
+						Username: "user",
+						Password: "password",
+					},
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"push jwt delivery issuer ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							func() eventstore.Command {
+								event := targetAddEvent("id1", "instance")
+								event.JWTDelivery = &domain.TargetJWTDelivery{
+									Algorithm: domain.TargetJWTAlgorithmHS256,
+									Issuer:    "zitadel",
+								}
+								event.SigningKeys = &domain.TargetSigningKeys{
+									Primary: targetSigningKey("primary-signing-key-0123456789abcdef"),
+								}
+								return event
+							}(),
+						),
+					),
+					expectPush(
+						target.NewChangedEvent(context.Background(),
+							target.NewAggregate("id1", "instance"),
+							[]target.Changes{
+								target.ChangeJWTDelivery(&domain.TargetJWTDelivery{
+									Algorithm: domain.TargetJWTAlgorithmHS256,
+									Issuer:    "https://zitadel.example.com",
+								}),
+							},
+						),
+					),
+				),
+			},
+			args{
+				ctx: context.Background(),
+				change: &ChangeTarget{
+					ObjectRoot: models.ObjectRoot{
+						AggregateID: "id1",
+					},
+					JWTDelivery: &domain.TargetJWTDelivery{
+						Algorithm: domain.TargetJWTAlgorithmHS256,
+						Issuer:    "https://zitadel.example.com",
+					},
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"push full ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("id1", "instance"),
+						),
+					),
+					expectPush(
+						target.NewChangedEvent(context.Background(),
+							target.NewAggregate("id1", "instance"),
+							[]target.Changes{
+								target.ChangeName("name", "name2"),
+								target.ChangeEndpoint("https://example2.com"),
+								target.ChangeTargetType(domain.TargetTypeCall),
+								target.ChangeTimeout(10 * time.Second),
+								target.ChangeInterruptOnError(true),
+								target.ChangePayloadFormat(domain.TargetPayloadFormatForm),
+								target.ChangeFailoverEndpoints([]string{"https://failover.example.com"}),
+								target.ChangeMaxRedirects(3),
+								target.ChangeRequestIDHeader("X-Request-Id"),
+							},
+						),
+					),
+				),
+			},
+			args{
+				ctx: context.Background(),
+				change: &ChangeTarget{
+					ObjectRoot: models.ObjectRoot{
+						AggregateID: "id1",
+					},
+					Name:              gu.Ptr("name2"),
+					Endpoint:          gu.Ptr("https://example2.com"),
+					TargetType:        gu.Ptr(domain.TargetTypeCall),
+					Timeout:           gu.Ptr(10 * time.Second),
+					InterruptOnError:  gu.Ptr(true),
+					PayloadFormat:     gu.Ptr(domain.TargetPayloadFormatForm),
+					FailoverEndpoints: &[]string{"https://failover.example.com"},
+					MaxRedirects:      gu.Ptr(3),
+					RequestIDHeader:   gu.Ptr("X-Request-Id"),
+				},
+				resourceOwner: "instance",
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore:          tt.fields.eventstore(t),
+				idpConfigEncryption: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+			}
+			details, err := c.ChangeTarget(tt.args.ctx, tt.args.change, tt.args.resourceOwner)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.details, details)
+			}
+		})
+	}
+}
+
+func TestCommands_DeleteTarget(t *testing.T) {
+	type fields struct {
+		eventstore func(t *testing.T) *eventstore.Eventstore
+	}
+	type args struct {
+		ctx           context.Context
+		id            string
+		resourceOwner string
+	}
+	type res struct {
+		details *domain.ObjectDetails
+		err     func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			"id missing, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "",
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"not found, error",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "id1",
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsNotFound,
+			},
+		},
+		{
+			"remove ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("id1", "instance"),
+						),
+					),
+					expectPush(
+						target.NewRemovedEvent(context.Background(),
+							target.NewAggregate("id1", "instance"),
+							"name",
+						),
+					),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "id1",
+				resourceOwner: "instance",
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.fields.eventstore(t),
+			}
+			details, err := c.DeleteTarget(tt.args.ctx, tt.args.id, tt.args.resourceOwner)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.details, details)
+			}
+		})
+	}
+}
+
+func TestCommands_PauseTarget(t *testing.T) {
+	type fields struct {
+		eventstore func(t *testing.T) *eventstore.Eventstore
+	}
+	type args struct {
+		ctx           context.Context
+		id            string
+		resourceOwner string
+	}
+	type res struct {
+		details *domain.ObjectDetails
+		err     func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			"id missing, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "",
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"not found, error",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "id1",
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsNotFound,
+			},
+		},
+		{
+			"already paused, error",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("id1", "instance"),
+						),
+						eventFromEventPusher(
+							target.NewPausedEvent(context.Background(),
+								target.NewAggregate("id1", "instance"),
+							),
+						),
+					),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "id1",
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsPreconditionFailed,
+			},
+		},
+		{
+			"pause ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("id1", "instance"),
+						),
+					),
+					expectPush(
+						target.NewPausedEvent(context.Background(),
+							target.NewAggregate("id1", "instance"),
+						),
+					),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "id1",
+				resourceOwner: "instance",
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.fields.eventstore(t),
+			}
+			details, err := c.PauseTarget(tt.args.ctx, tt.args.id, tt.args.resourceOwner)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.details, details)
+			}
+		})
+	}
+}
+
+func TestCommands_ResumeTarget(t *testing.T) {
+	type fields struct {
+		eventstore func(t *testing.T) *eventstore.Eventstore
+	}
+	type args struct {
+		ctx           context.Context
+		id            string
+		resourceOwner string
+	}
+	type res struct {
+		details *domain.ObjectDetails
+		err     func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			"id missing, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "",
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"not found, error",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "id1",
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsNotFound,
+			},
+		},
+		{
+			"not paused, error",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("id1", "instance"),
+						),
+					),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "id1",
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsPreconditionFailed,
+			},
+		},
+		{
+			"resume ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("id1", "instance"),
+						),
+						eventFromEventPusher(
+							target.NewPausedEvent(context.Background(),
+								target.NewAggregate("id1", "instance"),
+							),
+						),
+					),
+					expectPush(
+						target.NewResumedEvent(context.Background(),
+							target.NewAggregate("id1", "instance"),
+						),
+					),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "id1",
+				resourceOwner: "instance",
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.fields.eventstore(t),
+			}
+			details, err := c.ResumeTarget(tt.args.ctx, tt.args.id, tt.args.resourceOwner)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.details, details)
+			}
+		})
+	}
+}
+
+func TestCommands_VerifyAndRecordTarget(t *testing.T) {
+	type fields struct {
+		eventstore func(t *testing.T) *eventstore.Eventstore
+	}
+	type args struct {
+		ctx           context.Context
+		id            string
+		resourceOwner string
+	}
+	type res struct {
+		execution *execution.TargetExecution
+		err       func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			"id missing, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "",
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"not found, error",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "id1",
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsNotFound,
+			},
+		},
+		{
+			"removed, error",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("id1", "instance"),
+						),
+						eventFromEventPusher(
+							targetRemoveEvent("id1", "instance"),
+						),
+					),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "id1",
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsNotFound,
+			},
+		},
+		{
+			"verify ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("id1", "instance"),
+						),
+					),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "id1",
+				resourceOwner: "instance",
+			},
+			res{
+				execution: &execution.TargetExecution{
+					TargetID:   "id1",
+					StatusCode: 200,
+					Succeeded:  true,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.fields.eventstore(t),
+				verifyTarget: func(_ context.Context, target execution.Target) (*execution.TargetExecution, error) {
+					assert.Equal(t, "id1", target.GetTargetID())
+					assert.Equal(t, "https://example.com", target.GetEndpoint())
+					assert.Equal(t, time.Second, target.GetTimeout())
+					// only the reachability is verified, credentials are not sent
+					assert.Nil(t, target.GetBasicAuthCredentials())
+					assert.Nil(t, target.GetOAuth2Credentials())
+					assert.Nil(t, target.GetJWTDelivery())
+					assert.Empty(t, target.GetSigningKey())
+					return &execution.TargetExecution{TargetID: target.GetTargetID(), StatusCode: 200, Succeeded: true}, nil
+				},
+			}
+			got, err := c.VerifyAndRecordTarget(tt.args.ctx, tt.args.id, tt.args.resourceOwner)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.execution, got)
+			}
+		})
+	}
+}
+
+func TestCommands_UpsertTargets(t *testing.T) {
+	namedTargetAddEvent := func(aggID, name string) *target.AddedEvent {
+		return target.NewAddedEvent(context.Background(),
+			target.NewAggregate(aggID, "instance"),
+			name,
+			domain.TargetTypeWebhook,
+			"https://example.com",
+			time.Second,
+			false,
+		)
+	}
+	basicAuthTargetAddEvent := func(aggID, name string) *target.AddedEvent {
+		return target.NewAddedEvent(context.Background(),
+			target.NewAggregate(aggID, "instance"),
+			name,
+			domain.TargetTypeWebhook,
+			"https://example.com",
+			time.Second,
+			false,
+			target.WithBasicAuth(&domain.TargetBasicAuth{
+				Username: "user",
+				Password: &crypto.CryptoValue{
+					CryptoType: crypto.TypeEncryption,
+					Algorithm:  "enc",
+					KeyID:      "id",
+					Crypted:    []byte("password"),
+				},
+			}),
+		)
+	}
+	desiredTarget := func(name, endpoint string) *AddTarget {
+		return &AddTarget{
+			Name:       name,
+			TargetType: domain.TargetTypeWebhook,
+			Endpoint:   endpoint,
+			Timeout:    time.Second,
+		}
+	}
+	type fields struct {
+		eventstore  func(t *testing.T) *eventstore.Eventstore
+		idGenerator id.Generator
+	}
+	type args struct {
+		resourceOwner string
+		desired       []*AddTarget
+	}
+	type res struct {
+		created, updated, unchanged int
+		err                         func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			name: "resourceowner missing, error",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			args: args{
+				desired: []*AddTarget{desiredTarget("name", "https://example.com")},
+			},
+			res: res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "duplicate name, error",
+			fields: fields{
+				eventstore: expectEventstore(),
+			},
+			args: args{
+				resourceOwner: "instance",
+				desired: []*AddTarget{
+					desiredTarget("name", "https://example.com"),
+					desiredTarget("name", "https://example2.com"),
+				},
+			},
+			res: res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "invalid new target, nothing pushed",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(namedTargetAddEvent("id1", "name")),
+					),
+				),
+			},
+			args: args{
+				resourceOwner: "instance",
+				desired: []*AddTarget{
+					desiredTarget("name", "https://example2.com"),
+					desiredTarget("new", ""),
+				},
+			},
+			res: res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			name: "all equal, nothing pushed",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(namedTargetAddEvent("id1", "name")),
+						eventFromEventPusher(namedTargetAddEvent("id2", "other")),
+					),
+				),
+			},
+			args: args{
+				resourceOwner: "instance",
+				desired: []*AddTarget{
+					desiredTarget("name", "https://example.com"),
+					desiredTarget("other", "https://example.com"),
+				},
+			},
+			res: res{
+				unchanged: 2,
+			},
+		},
+		{
+			name: "created, updated and unchanged, pushed at once",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(namedTargetAddEvent("id1", "name")),
+						eventFromEventPusher(namedTargetAddEvent("id2", "other")),
+						// removed targets are created again
+						eventFromEventPusher(namedTargetAddEvent("id3", "removed")),
+						eventFromEventPusher(targetRemoveEvent("id3", "instance")),
+						// not desired targets are kept
+						eventFromEventPusher(namedTargetAddEvent("id4", "undesired")),
+					),
+					expectPush(
+						target.NewChangedEvent(context.Background(),
+							target.NewAggregate("id2", "instance"),
+							[]target.Changes{
+								target.ChangeEndpoint("https://example2.com"),
+							},
+						),
+						namedTargetAddEvent("id5", "new"),
+						namedTargetAddEvent("id6", "removed"),
+					),
+				),
+				idGenerator: mock.NewIDGeneratorExpectIDs(t, "id5", "id6"),
+			},
+			args: args{
+				resourceOwner: "instance",
+				desired: []*AddTarget{
+					desiredTarget("name", "https://example.com"),
+					desiredTarget("other", "https://example2.com"),
+					desiredTarget("new", "https://example.com"),
+					desiredTarget("removed", "https://example.com"),
+				},
+			},
+			res: res{
+				created:   2,
+				updated:   1,
+				unchanged: 1,
+			},
+		},
+		{
+			name: "secrets omitted or equal, preserved",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(basicAuthTargetAddEvent("id1", "omitted")),
+						eventFromEventPusher(basicAuthTargetAddEvent("id2", "equal")),
+						eventFromEventPusher(basicAuthTargetAddEvent("id3", "username")),
+					),
+					expectPush(
+						target.NewChangedEvent(context.Background(),
+							target.NewAggregate("id3", "instance"),
+							[]target.Changes{
+								target.ChangeBasicAuth(&domain.TargetBasicAuth{
+									Username: "user2",
+									Password: &crypto.CryptoValue{
+										CryptoType: crypto.TypeEncryption,
+										Algorithm:  "enc",
+										KeyID:      "id",
+										Crypted:    []byte("password"),
+									},
+								}),
+							},
+						),
+					),
+				),
+			},
+			args: args{
+				resourceOwner: "instance",
+				desired: []*AddTarget{
+					func() *AddTarget {
+						add := desiredTarget("omitted", "https://example.com")
+						add.BasicAuth = &TargetBasicAuth{Username: "user"}
+						return add
+					}(),
+					func() *AddTarget {
+						add := desiredTarget("equal", "https://example.com")
+						add.BasicAuth = &TargetBasicAuth{Username: "user", Password: "password"}
+						return add
+					}(),
+					func() *AddTarget {
+						add := desiredTarget("username", "https://example.com")
+						add.BasicAuth = &TargetBasicAuth{Username: "user2"}
+						return add
+					}(),
+				},
+			},
+			res: res{
+				updated:   1,
+				unchanged: 2,
+			},
+		},
+		{
+			name: "push failed, error",
+			fields: fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+					expectPushFailed(
+						zerrors.ThrowPreconditionFailed(nil, "id", "name already exists"),
+						namedTargetAddEvent("id1", "new"),
+					),
+				),
+				idGenerator: mock.ExpectID(t, "id1"),
+			},
+			args: args{
+				resourceOwner: "instance",
+				desired:       []*AddTarget{desiredTarget("new", "https://example.com")},
+			},
+			res: res{
+				err: zerrors.IsPreconditionFailed,
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &Commands{
-				eventstore:  tt.fields.eventstore(t),
-				idGenerator: tt.fields.idGenerator,
-			}
-			details, err := c.AddTarget(tt.args.ctx, tt.args.add, tt.args.resourceOwner)
-			if tt.res.err == nil {
-				assert.NoError(t, err)
-			}
-			if tt.res.err != nil && !tt.res.err(err) {
-				t.Errorf("got wrong err: %v ", err)
+				eventstore:          tt.fields.eventstore(t),
+				idGenerator:         tt.fields.idGenerator,
+				idpConfigEncryption: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
 			}
-			if tt.res.err == nil {
-				assert.Equal(t, tt.res.id, tt.args.add.AggregateID)
-				assert.Equal(t, tt.res.details, details)
+			created, updated, unchanged, err := c.UpsertTargets(context.Background(), tt.args.resourceOwner, tt.args.desired)
+			if tt.res.err != nil {
+				assert.True(t, tt.res.err(err), "unexpected error: %v", err)
+				return
 			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.res.created, created)
+			assert.Equal(t, tt.res.updated, updated)
+			assert.Equal(t, tt.res.unchanged, unchanged)
 		})
 	}
 }
 
-func TestCommands_ChangeTarget(t *testing.T) {
+// targetSigningKey returns the key as encrypted by [crypto.CreateMockEncryptionAlg]
+func targetSigningKey(key string) *crypto.CryptoValue {
+	return &crypto.CryptoValue{
+		CryptoType: crypto.TypeEncryption,
+		Algorithm:  "enc",
+		KeyID:      "id",
+		Crypted:    []byte(key),
+	}
+}
+
+func targetSigningKeysChangedEvent(aggID, resourceOwner string, keys *domain.TargetSigningKeys) *target.ChangedEvent {
+	return target.NewChangedEvent(context.Background(),
+		target.NewAggregate(aggID, resourceOwner),
+		[]target.Changes{target.ChangeSigningKeys(keys)},
+	)
+}
+
+func TestCommands_SetTargetSigningKey(t *testing.T) {
+	validUntil := time.Now().Add(time.Hour)
 	type fields struct {
 		eventstore func(t *testing.T) *eventstore.Eventstore
 	}
 	type args struct {
 		ctx           context.Context
-		change        *ChangeTarget
+		id            string
 		resourceOwner string
+		key           string
+		validUntil    time.Time
 	}
 	type res struct {
 		details *domain.ObjectDetails
@@ -279,20 +2416,6 @@ func TestCommands_ChangeTarget(t *testing.T) {
 		args   args
 		res    res
 	}{
-		{
-			"resourceowner missing, error",
-			fields{
-				eventstore: expectEventstore(),
-			},
-			args{
-				ctx:           context.Background(),
-				change:        &ChangeTarget{},
-				resourceOwner: "",
-			},
-			res{
-				err: zerrors.IsErrorInvalidArgument,
-			},
-		},
 		{
 			"id missing, error",
 			fields{
@@ -300,72 +2423,25 @@ func TestCommands_ChangeTarget(t *testing.T) {
 			},
 			args{
 				ctx:           context.Background(),
-				change:        &ChangeTarget{},
-				resourceOwner: "instance",
-			},
-			res{
-				err: zerrors.IsErrorInvalidArgument,
-			},
-		},
-		{
-			"name empty, error",
-			fields{
-				eventstore: expectEventstore(),
-			},
-			args{
-				ctx: context.Background(),
-				change: &ChangeTarget{
-					Name: gu.Ptr(""),
-				},
-				resourceOwner: "instance",
-			},
-			res{
-				err: zerrors.IsErrorInvalidArgument,
-			},
-		},
-		{
-			"timeout empty, error",
-			fields{
-				eventstore: expectEventstore(),
-			},
-			args{
-				ctx: context.Background(),
-				change: &ChangeTarget{
-					Timeout: gu.Ptr(time.Duration(0)),
-				},
-				resourceOwner: "instance",
-			},
-			res{
-				err: zerrors.IsErrorInvalidArgument,
-			},
-		},
-		{
-			"Endpoint empty, error",
-			fields{
-				eventstore: expectEventstore(),
-			},
-			args{
-				ctx: context.Background(),
-				change: &ChangeTarget{
-					Endpoint: gu.Ptr(""),
-				},
 				resourceOwner: "instance",
+				key:           "secondary-signing-key-0123456789abcdef",
+				validUntil:    validUntil,
 			},
 			res{
 				err: zerrors.IsErrorInvalidArgument,
 			},
 		},
 		{
-			"Endpoint not parsable, error",
+			"key too short, error",
 			fields{
 				eventstore: expectEventstore(),
 			},
 			args{
-				ctx: context.Background(),
-				change: &ChangeTarget{
-					Endpoint: gu.Ptr("://"),
-				},
+				ctx:           context.Background(),
+				id:            "id1",
 				resourceOwner: "instance",
+				key:           "short",
+				validUntil:    validUntil,
 			},
 			res{
 				err: zerrors.IsErrorInvalidArgument,
@@ -379,108 +2455,101 @@ func TestCommands_ChangeTarget(t *testing.T) {
 				),
 			},
 			args{
-				ctx: context.Background(),
-				change: &ChangeTarget{
-					ObjectRoot: models.ObjectRoot{
-						AggregateID: "id1",
-					},
-					Name: gu.Ptr("name"),
-				},
+				ctx:           context.Background(),
+				id:            "id1",
 				resourceOwner: "instance",
+				key:           "secondary-signing-key-0123456789abcdef",
+				validUntil:    validUntil,
 			},
 			res{
 				err: zerrors.IsNotFound,
 			},
 		},
 		{
-			"no changes",
+			"grace window ended, error",
 			fields{
 				eventstore: expectEventstore(
 					expectFilter(
 						eventFromEventPusher(
-							targetAddEvent("target", "instance"),
+							targetAddEvent("id1", "instance"),
+						),
+						eventFromEventPusher(
+							targetSigningKeysChangedEvent("id1", "instance", &domain.TargetSigningKeys{
+								Primary: targetSigningKey("primary-signing-key-0123456789abcdef"),
+							}),
 						),
 					),
 				),
 			},
 			args{
-				ctx: context.Background(),
-				change: &ChangeTarget{
-					ObjectRoot: models.ObjectRoot{
-						AggregateID: "id1",
-					},
-					TargetType: gu.Ptr(domain.TargetTypeWebhook),
-				},
+				ctx:           context.Background(),
+				id:            "id1",
 				resourceOwner: "instance",
+				key:           "secondary-signing-key-0123456789abcdef",
+				validUntil:    time.Now().Add(-time.Hour),
 			},
 			res{
-				details: &domain.ObjectDetails{
-					ResourceOwner: "instance",
-				},
+				err: zerrors.IsErrorInvalidArgument,
 			},
 		},
 		{
-			"unique constraint failed, error",
+			"without signing key, primary ok",
 			fields{
 				eventstore: expectEventstore(
 					expectFilter(
 						eventFromEventPusher(
-							targetAddEvent("target", "instance"),
+							targetAddEvent("id1", "instance"),
 						),
 					),
-					expectPushFailed(
-						zerrors.ThrowPreconditionFailed(nil, "id", "name already exists"),
-						target.NewChangedEvent(context.Background(),
-							target.NewAggregate("id1", "instance"),
-							[]target.Changes{
-								target.ChangeName("name", "name2"),
-							},
-						),
+					expectPush(
+						targetSigningKeysChangedEvent("id1", "instance", &domain.TargetSigningKeys{
+							Primary: targetSigningKey("primary-signing-key-0123456789abcdef"),
+						}),
 					),
 				),
 			},
 			args{
-				ctx: context.Background(),
-				change: &ChangeTarget{
-					ObjectRoot: models.ObjectRoot{
-						AggregateID: "id1",
-					},
-					Name: gu.Ptr("name2"),
-				},
+				ctx:           context.Background(),
+				id:            "id1",
 				resourceOwner: "instance",
+				key:           "primary-signing-key-0123456789abcdef",
+				validUntil:    validUntil,
 			},
 			res{
-				err: zerrors.IsPreconditionFailed,
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
 			},
 		},
 		{
-			"push ok",
+			"with signing key, secondary ok",
 			fields{
 				eventstore: expectEventstore(
 					expectFilter(
 						eventFromEventPusher(
 							targetAddEvent("id1", "instance"),
 						),
+						eventFromEventPusher(
+							targetSigningKeysChangedEvent("id1", "instance", &domain.TargetSigningKeys{
+								Primary: targetSigningKey("primary-signing-key-0123456789abcdef"),
+							}),
+						),
 					),
 					expectPush(
-						target.NewChangedEvent(context.Background(),
-							target.NewAggregate("id1", "instance"),
-							[]target.Changes{
-								target.ChangeName("name", "name2"),
-							},
-						),
+						targetSigningKeysChangedEvent("id1", "instance", &domain.TargetSigningKeys{
+							Primary:             targetSigningKey("primary-signing-key-0123456789abcdef"),
+							Secondary:           targetSigningKey("secondary-signing-key-0123456789abcdef"),
+							SecondaryValidUntil: validUntil,
+						}),
 					),
 				),
 			},
 			args{
-				ctx: context.Background(),
-				change: &ChangeTarget{
-					ObjectRoot: models.ObjectRoot{
-						AggregateID: "id1",
-					},
-					Name: gu.Ptr("name2"),
-				},
+				ctx:           context.Background(),
+				id:            "id1",
 				resourceOwner: "instance",
+				key:           "secondary-signing-key-0123456789abcdef",
+				validUntil:    validUntil,
 			},
 			res{
 				details: &domain.ObjectDetails{
@@ -489,41 +2558,36 @@ func TestCommands_ChangeTarget(t *testing.T) {
 			},
 		},
 		{
-			"push full ok",
+			"rotating, secondary replaced ok",
 			fields{
 				eventstore: expectEventstore(
 					expectFilter(
 						eventFromEventPusher(
 							targetAddEvent("id1", "instance"),
 						),
+						eventFromEventPusher(
+							targetSigningKeysChangedEvent("id1", "instance", &domain.TargetSigningKeys{
+								Primary:             targetSigningKey("primary-signing-key-0123456789abcdef"),
+								Secondary:           targetSigningKey("secondary-signing-key-0123456789abcdef"),
+								SecondaryValidUntil: validUntil,
+							}),
+						),
 					),
 					expectPush(
-						target.NewChangedEvent(context.Background(),
-							target.NewAggregate("id1", "instance"),
-							[]target.Changes{
-								target.ChangeName("name", "name2"),
-								target.ChangeEndpoint("https://example2.com"),
-								target.ChangeTargetType(domain.TargetTypeCall),
-								target.ChangeTimeout(10 * time.Second),
-								target.ChangeInterruptOnError(true),
-							},
-						),
+						targetSigningKeysChangedEvent("id1", "instance", &domain.TargetSigningKeys{
+							Primary:             targetSigningKey("primary-signing-key-0123456789abcdef"),
+							Secondary:           targetSigningKey("replaced-signing-key-0123456789abcdef"),
+							SecondaryValidUntil: validUntil,
+						}),
 					),
 				),
 			},
 			args{
-				ctx: context.Background(),
-				change: &ChangeTarget{
-					ObjectRoot: models.ObjectRoot{
-						AggregateID: "id1",
-					},
-					Name:             gu.Ptr("name2"),
-					Endpoint:         gu.Ptr("https://example2.com"),
-					TargetType:       gu.Ptr(domain.TargetTypeCall),
-					Timeout:          gu.Ptr(10 * time.Second),
-					InterruptOnError: gu.Ptr(true),
-				},
+				ctx:           context.Background(),
+				id:            "id1",
 				resourceOwner: "instance",
+				key:           "replaced-signing-key-0123456789abcdef",
+				validUntil:    validUntil,
 			},
 			res{
 				details: &domain.ObjectDetails{
@@ -535,9 +2599,10 @@ func TestCommands_ChangeTarget(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &Commands{
-				eventstore: tt.fields.eventstore(t),
+				eventstore:          tt.fields.eventstore(t),
+				idpConfigEncryption: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
 			}
-			details, err := c.ChangeTarget(tt.args.ctx, tt.args.change, tt.args.resourceOwner)
+			details, err := c.SetTargetSigningKey(tt.args.ctx, tt.args.id, tt.args.resourceOwner, tt.args.key, tt.args.validUntil)
 			if tt.res.err == nil {
 				assert.NoError(t, err)
 			}
@@ -551,7 +2616,8 @@ func TestCommands_ChangeTarget(t *testing.T) {
 	}
 }
 
-func TestCommands_DeleteTarget(t *testing.T) {
+func TestCommands_RotateTargetSigningKey(t *testing.T) {
+	validUntil := time.Now().Add(time.Hour)
 	type fields struct {
 		eventstore func(t *testing.T) *eventstore.Eventstore
 	}
@@ -577,7 +2643,6 @@ func TestCommands_DeleteTarget(t *testing.T) {
 			},
 			args{
 				ctx:           context.Background(),
-				id:            "",
 				resourceOwner: "instance",
 			},
 			res{
@@ -601,20 +2666,105 @@ func TestCommands_DeleteTarget(t *testing.T) {
 			},
 		},
 		{
-			"remove ok",
+			"without signing key, error",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("id1", "instance"),
+						),
+					),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "id1",
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsPreconditionFailed,
+			},
+		},
+		{
+			"without secondary key, error",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("id1", "instance"),
+						),
+						eventFromEventPusher(
+							targetSigningKeysChangedEvent("id1", "instance", &domain.TargetSigningKeys{
+								Primary: targetSigningKey("primary-signing-key-0123456789abcdef"),
+							}),
+						),
+					),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "id1",
+				resourceOwner: "instance",
+			},
+			res{
+				err: zerrors.IsPreconditionFailed,
+			},
+		},
+		{
+			"rotating, secondary promoted ok",
 			fields{
 				eventstore: expectEventstore(
 					expectFilter(
 						eventFromEventPusher(
 							targetAddEvent("id1", "instance"),
 						),
+						eventFromEventPusher(
+							targetSigningKeysChangedEvent("id1", "instance", &domain.TargetSigningKeys{
+								Primary:             targetSigningKey("primary-signing-key-0123456789abcdef"),
+								Secondary:           targetSigningKey("secondary-signing-key-0123456789abcdef"),
+								SecondaryValidUntil: validUntil,
+							}),
+						),
 					),
 					expectPush(
-						target.NewRemovedEvent(context.Background(),
-							target.NewAggregate("id1", "instance"),
-							"name",
+						targetSigningKeysChangedEvent("id1", "instance", &domain.TargetSigningKeys{
+							Primary: targetSigningKey("secondary-signing-key-0123456789abcdef"),
+						}),
+					),
+				),
+			},
+			args{
+				ctx:           context.Background(),
+				id:            "id1",
+				resourceOwner: "instance",
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"grace window ended, secondary promoted ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							targetAddEvent("id1", "instance"),
+						),
+						eventFromEventPusher(
+							targetSigningKeysChangedEvent("id1", "instance", &domain.TargetSigningKeys{
+								Primary:             targetSigningKey("primary-signing-key-0123456789abcdef"),
+								Secondary:           targetSigningKey("secondary-signing-key-0123456789abcdef"),
+								SecondaryValidUntil: time.Now().Add(-time.Hour),
+							}),
 						),
 					),
+					expectPush(
+						targetSigningKeysChangedEvent("id1", "instance", &domain.TargetSigningKeys{
+							Primary: targetSigningKey("secondary-signing-key-0123456789abcdef"),
+						}),
+					),
 				),
 			},
 			args{
@@ -634,7 +2784,7 @@ func TestCommands_DeleteTarget(t *testing.T) {
 			c := &Commands{
 				eventstore: tt.fields.eventstore(t),
 			}
-			details, err := c.DeleteTarget(tt.args.ctx, tt.args.id, tt.args.resourceOwner)
+			details, err := c.RotateTargetSigningKey(tt.args.ctx, tt.args.id, tt.args.resourceOwner)
 			if tt.res.err == nil {
 				assert.NoError(t, err)
 			}
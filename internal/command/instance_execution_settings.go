@@ -0,0 +1,38 @@
+package command
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/execution"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// SetExecutionSettings sets the settings of the instance for the calls of its targets,
+// a zero value resets the setting to its default.
+func (c *Commands) SetExecutionSettings(ctx context.Context, settings *domain.ExecutionSettings) (*domain.ObjectDetails, error) {
+	if settings.AsyncPoolSize < 0 || settings.AsyncPoolSize > execution.MaxAsyncPoolSize {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Ex6Ps", "Errors.Execution.InvalidSettings")
+	}
+	if settings.TargetQueueDepth < 0 || settings.TargetQueueDepth > execution.MaxTargetQueueDepth || !settings.TargetQueueOverflow.Valid() {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Ex6Qd", "Errors.Execution.InvalidSettings")
+	}
+	writeModel := NewInstanceExecutionSettingsWriteModel(ctx)
+	if err := c.eventstore.FilterToQueryReducer(ctx, writeModel); err != nil {
+		return nil, err
+	}
+	if writeModel.ExecutionSettings == *settings {
+		return writeModelToObjectDetails(&writeModel.WriteModel), nil
+	}
+	instanceAgg := instance.NewAggregate(authz.GetInstance(ctx).InstanceID())
+	if err := c.pushAppendAndReduce(ctx, writeModel, instance.NewExecutionSettingsSetEvent(
+		ctx,
+		&instanceAgg.Aggregate,
+		settings,
+	)); err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&writeModel.WriteModel), nil
+}
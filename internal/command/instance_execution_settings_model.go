@@ -0,0 +1,46 @@
+package command
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+type InstanceExecutionSettingsWriteModel struct {
+	eventstore.WriteModel
+	domain.ExecutionSettings
+}
+
+func NewInstanceExecutionSettingsWriteModel(ctx context.Context) *InstanceExecutionSettingsWriteModel {
+	return &InstanceExecutionSettingsWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   authz.GetInstance(ctx).InstanceID(),
+			ResourceOwner: authz.GetInstance(ctx).InstanceID(),
+		},
+	}
+}
+
+func (wm *InstanceExecutionSettingsWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		if e, ok := event.(*instance.ExecutionSettingsSetEvent); ok {
+			wm.AsyncPoolSize = e.AsyncPoolSize
+			wm.TargetQueueDepth = e.TargetQueueDepth
+			wm.TargetQueueOverflow = e.TargetQueueOverflow
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *InstanceExecutionSettingsWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(wm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(instance.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			instance.ExecutionSettingsSetEventType).
+		Builder()
+}
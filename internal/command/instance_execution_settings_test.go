@@ -0,0 +1,204 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/execution"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestCommands_SetExecutionSettings(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	type fields struct {
+		eventstore func(t *testing.T) *eventstore.Eventstore
+	}
+	type args struct {
+		settings *domain.ExecutionSettings
+	}
+	type res struct {
+		details *domain.ObjectDetails
+		err     func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			"negative pool size, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				settings: &domain.ExecutionSettings{AsyncPoolSize: -1},
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"pool size too large, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				settings: &domain.ExecutionSettings{AsyncPoolSize: execution.MaxAsyncPoolSize + 1},
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"queue depth too large, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				settings: &domain.ExecutionSettings{TargetQueueDepth: execution.MaxTargetQueueDepth + 1},
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"unknown queue overflow, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				settings: &domain.ExecutionSettings{TargetQueueOverflow: domain.TargetQueueOverflowFail + 1},
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"unchanged, ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewExecutionSettingsSetEvent(ctx,
+								&instance.NewAggregate("instance").Aggregate,
+								&domain.ExecutionSettings{AsyncPoolSize: 5},
+							),
+						),
+					),
+				),
+			},
+			args{
+				settings: &domain.ExecutionSettings{AsyncPoolSize: 5},
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"set, ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+					expectPush(
+						instance.NewExecutionSettingsSetEvent(ctx,
+							&instance.NewAggregate("instance").Aggregate,
+							&domain.ExecutionSettings{AsyncPoolSize: 5},
+						),
+					),
+				),
+			},
+			args{
+				settings: &domain.ExecutionSettings{AsyncPoolSize: 5},
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"set queue, ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewExecutionSettingsSetEvent(ctx,
+								&instance.NewAggregate("instance").Aggregate,
+								&domain.ExecutionSettings{AsyncPoolSize: 5},
+							),
+						),
+					),
+					expectPush(
+						instance.NewExecutionSettingsSetEvent(ctx,
+							&instance.NewAggregate("instance").Aggregate,
+							&domain.ExecutionSettings{AsyncPoolSize: 5, TargetQueueDepth: 20, TargetQueueOverflow: domain.TargetQueueOverflowFail},
+						),
+					),
+				),
+			},
+			args{
+				settings: &domain.ExecutionSettings{AsyncPoolSize: 5, TargetQueueDepth: 20, TargetQueueOverflow: domain.TargetQueueOverflowFail},
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"reset, ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewExecutionSettingsSetEvent(ctx,
+								&instance.NewAggregate("instance").Aggregate,
+								&domain.ExecutionSettings{AsyncPoolSize: 5},
+							),
+						),
+					),
+					expectPush(
+						instance.NewExecutionSettingsSetEvent(ctx,
+							&instance.NewAggregate("instance").Aggregate,
+							&domain.ExecutionSettings{},
+						),
+					),
+				),
+			},
+			args{
+				settings: &domain.ExecutionSettings{},
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.fields.eventstore(t),
+			}
+			details, err := c.SetExecutionSettings(ctx, tt.args.settings)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.details, details)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package command
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// SetTargetDefaults sets the defaults applied to targets of the instance created without the respective values,
+// a zero value removes the default.
+// Existing targets are not changed.
+func (c *Commands) SetTargetDefaults(ctx context.Context, defaults *domain.TargetDefaults) (*domain.ObjectDetails, error) {
+	if defaults.Timeout < 0 {
+		return nil, zerrors.ThrowInvalidArgument(nil, "COMMAND-Td5To", "Errors.Target.InvalidDefaults")
+	}
+	writeModel, err := c.getTargetDefaultsWriteModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if writeModel.TargetDefaults == *defaults {
+		return writeModelToObjectDetails(&writeModel.WriteModel), nil
+	}
+	instanceAgg := instance.NewAggregate(authz.GetInstance(ctx).InstanceID())
+	if err = c.pushAppendAndReduce(ctx, writeModel, instance.NewTargetDefaultsSetEvent(
+		ctx,
+		&instanceAgg.Aggregate,
+		defaults.Timeout,
+	)); err != nil {
+		return nil, err
+	}
+	return writeModelToObjectDetails(&writeModel.WriteModel), nil
+}
+
+func (c *Commands) getTargetDefaultsWriteModel(ctx context.Context) (*InstanceTargetDefaultsWriteModel, error) {
+	writeModel := NewInstanceTargetDefaultsWriteModel(ctx)
+	if err := c.eventstore.FilterToQueryReducer(ctx, writeModel); err != nil {
+		return nil, err
+	}
+	return writeModel, nil
+}
+
+// applyTargetDefaults sets the defaults of the instance on the values omitted by the target
+func (c *Commands) applyTargetDefaults(ctx context.Context, add *AddTarget) error {
+	if add.Timeout != 0 {
+		return nil
+	}
+	defaults, err := c.getTargetDefaultsWriteModel(ctx)
+	if err != nil {
+		return err
+	}
+	add.Timeout = defaults.Timeout
+	return nil
+}
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+type InstanceTargetDefaultsWriteModel struct {
+	eventstore.WriteModel
+	domain.TargetDefaults
+}
+
+func NewInstanceTargetDefaultsWriteModel(ctx context.Context) *InstanceTargetDefaultsWriteModel {
+	return &InstanceTargetDefaultsWriteModel{
+		WriteModel: eventstore.WriteModel{
+			AggregateID:   authz.GetInstance(ctx).InstanceID(),
+			ResourceOwner: authz.GetInstance(ctx).InstanceID(),
+		},
+	}
+}
+
+func (wm *InstanceTargetDefaultsWriteModel) Reduce() error {
+	for _, event := range wm.Events {
+		if e, ok := event.(*instance.TargetDefaultsSetEvent); ok {
+			wm.Timeout = e.Timeout
+		}
+	}
+	return wm.WriteModel.Reduce()
+}
+
+func (wm *InstanceTargetDefaultsWriteModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		ResourceOwner(wm.ResourceOwner).
+		AddQuery().
+		AggregateTypes(instance.AggregateType).
+		AggregateIDs(wm.AggregateID).
+		EventTypes(
+			instance.TargetDefaultsSetEventType).
+		Builder()
+}
@@ -0,0 +1,139 @@
+package command
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestCommands_SetTargetDefaults(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	type fields struct {
+		eventstore func(t *testing.T) *eventstore.Eventstore
+	}
+	type args struct {
+		defaults *domain.TargetDefaults
+	}
+	type res struct {
+		details *domain.ObjectDetails
+		err     func(error) bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		res    res
+	}{
+		{
+			"negative timeout, error",
+			fields{
+				eventstore: expectEventstore(),
+			},
+			args{
+				defaults: &domain.TargetDefaults{Timeout: -time.Second},
+			},
+			res{
+				err: zerrors.IsErrorInvalidArgument,
+			},
+		},
+		{
+			"unchanged, ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewTargetDefaultsSetEvent(ctx,
+								&instance.NewAggregate("instance").Aggregate,
+								5*time.Second,
+							),
+						),
+					),
+				),
+			},
+			args{
+				defaults: &domain.TargetDefaults{Timeout: 5 * time.Second},
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"set, ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(),
+					expectPush(
+						instance.NewTargetDefaultsSetEvent(ctx,
+							&instance.NewAggregate("instance").Aggregate,
+							5*time.Second,
+						),
+					),
+				),
+			},
+			args{
+				defaults: &domain.TargetDefaults{Timeout: 5 * time.Second},
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+		{
+			"removed, ok",
+			fields{
+				eventstore: expectEventstore(
+					expectFilter(
+						eventFromEventPusher(
+							instance.NewTargetDefaultsSetEvent(ctx,
+								&instance.NewAggregate("instance").Aggregate,
+								5*time.Second,
+							),
+						),
+					),
+					expectPush(
+						instance.NewTargetDefaultsSetEvent(ctx,
+							&instance.NewAggregate("instance").Aggregate,
+							0,
+						),
+					),
+				),
+			},
+			args{
+				defaults: &domain.TargetDefaults{},
+			},
+			res{
+				details: &domain.ObjectDetails{
+					ResourceOwner: "instance",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Commands{
+				eventstore: tt.fields.eventstore(t),
+			}
+			details, err := c.SetTargetDefaults(ctx, tt.args.defaults)
+			if tt.res.err == nil {
+				assert.NoError(t, err)
+			}
+			if tt.res.err != nil && !tt.res.err(err) {
+				t.Errorf("got wrong err: %v ", err)
+			}
+			if tt.res.err == nil {
+				assert.Equal(t, tt.res.details, details)
+			}
+		})
+	}
+}
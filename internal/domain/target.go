@@ -1,19 +1,76 @@
 package domain
 
+import (
+	"time"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+)
+
 type TargetType uint
 
 const (
 	TargetTypeWebhook TargetType = iota
 	TargetTypeCall
 	TargetTypeAsync
+	targetTypeCount
+)
+
+// Valid reports if the target type is known
+func (t TargetType) Valid() bool {
+	return t < targetTypeCount
+}
+
+// TargetPayloadFormat is the format the payload is serialized in before it is sent to a target
+type TargetPayloadFormat uint
+
+const (
+	TargetPayloadFormatJSON TargetPayloadFormat = iota
+	TargetPayloadFormatForm
+	TargetPayloadFormatXML
+	targetPayloadFormatCount
+)
+
+// Valid reports if the payload format is known
+func (f TargetPayloadFormat) Valid() bool {
+	return f < targetPayloadFormatCount
+}
+
+// ContentType returns the content type of payloads in the format
+func (f TargetPayloadFormat) ContentType() string {
+	switch f {
+	case TargetPayloadFormatForm:
+		return "application/x-www-form-urlencoded"
+	case TargetPayloadFormatXML:
+		return "application/xml"
+	default:
+		return "application/json"
+	}
+}
+
+// TargetResponseSizePolicy defines how a response body exceeding the response size limit of a target is handled
+type TargetResponseSizePolicy uint
+
+const (
+	// TargetResponseSizePolicyError fails the call
+	TargetResponseSizePolicyError TargetResponseSizePolicy = iota
+	// TargetResponseSizePolicyTruncate uses the response body up to the limit
+	TargetResponseSizePolicyTruncate
+	targetResponseSizePolicyCount
 )
 
+// Valid reports if the response size policy is known
+func (p TargetResponseSizePolicy) Valid() bool {
+	return p < targetResponseSizePolicyCount
+}
+
 type TargetState int32
 
 const (
 	TargetUnspecified TargetState = iota
 	TargetActive
 	TargetRemoved
+	// TargetInactive targets are paused, they are not called until they are resumed and the payloads of async targets are queued meanwhile
+	TargetInactive
 	targetStateCount
 )
 
@@ -24,3 +81,155 @@ func (s TargetState) Valid() bool {
 func (s TargetState) Exists() bool {
 	return s != TargetUnspecified && s != TargetRemoved
 }
+
+// TargetSuccessCriteria defines when the response of a target is handled as success.
+// Without criteria every 2xx status code is a success.
+type TargetSuccessCriteria struct {
+	// StatusCodes replace the default 2xx range if set
+	StatusCodes []int `json:"statusCodes,omitempty"`
+	// BodyPath is a JSONPath (e.g. $.result.status) into the response body,
+	// the value found must equal BodyValue
+	BodyPath  string `json:"bodyPath,omitempty"`
+	BodyValue string `json:"bodyValue,omitempty"`
+}
+
+// TargetPhaseTimeouts limit the phases of a call to a target.
+// Unset phases are limited by the overall timeout of the target.
+type TargetPhaseTimeouts struct {
+	// Dial limits establishing the connection
+	Dial time.Duration `json:"dial,omitempty"`
+	// TLSHandshake limits the TLS handshake after the connection is established
+	TLSHandshake time.Duration `json:"tlsHandshake,omitempty"`
+	// ResponseHeader limits waiting for the response headers after the request was written
+	ResponseHeader time.Duration `json:"responseHeader,omitempty"`
+}
+
+// IsZero reports if no phase timeout is set
+func (t *TargetPhaseTimeouts) IsZero() bool {
+	return t == nil || *t == TargetPhaseTimeouts{}
+}
+
+// TargetResponseSizeLimit limits the size of the response bodies read from a target.
+// Without limit the response bodies are read completely.
+type TargetResponseSizeLimit struct {
+	// MaxBytes is the maximum size of a response body, 0 if the size is not limited
+	MaxBytes int64 `json:"maxBytes,omitempty"`
+	// Policy defines how larger response bodies are handled
+	Policy TargetResponseSizePolicy `json:"policy,omitempty"`
+}
+
+// IsZero reports if the response size is not limited
+func (l *TargetResponseSizeLimit) IsZero() bool {
+	return l == nil || l.MaxBytes == 0
+}
+
+// TargetBatchDelivery coalesces the payloads delivered to an async target into a single call with a JSON array of the payloads.
+// Without batch delivery every payload is delivered in its own call.
+type TargetBatchDelivery struct {
+	// MaxSize is the maximum count of payloads delivered in a single call, 0 if the payloads are not batched
+	MaxSize int `json:"maxSize,omitempty"`
+	// MaxWait is the maximum time the first payload of a batch waits for further payloads
+	MaxWait time.Duration `json:"maxWait,omitempty"`
+}
+
+// IsZero reports if the payloads are delivered one by one
+func (b *TargetBatchDelivery) IsZero() bool {
+	return b == nil || b.MaxSize == 0
+}
+
+// TargetOAuth2 configures the OAuth2 client credentials grant used to authorize the calls to a target.
+type TargetOAuth2 struct {
+	TokenEndpoint string              `json:"tokenEndpoint,omitempty"`
+	ClientID      string              `json:"clientID,omitempty"`
+	ClientSecret  *crypto.CryptoValue `json:"clientSecret,omitempty"`
+}
+
+// IsZero reports if no client credentials are configured
+func (o *TargetOAuth2) IsZero() bool {
+	return o == nil || o.TokenEndpoint == ""
+}
+
+// TargetBasicAuth configures HTTP basic authentication (RFC 7617) of the calls to a target.
+type TargetBasicAuth struct {
+	Username string              `json:"username,omitempty"`
+	Password *crypto.CryptoValue `json:"password,omitempty"`
+}
+
+// IsZero reports if no basic authentication is configured
+func (b *TargetBasicAuth) IsZero() bool {
+	return b == nil || b.Username == ""
+}
+
+// TargetBasicAuthCredentials are the decrypted basic authentication credentials of a target,
+// they must only be used to call the target and never be returned to clients.
+type TargetBasicAuthCredentials struct {
+	Username string
+	Password string
+}
+
+// TargetJWTAlgorithm is the algorithm the JWTs delivered to a target are signed with
+type TargetJWTAlgorithm string
+
+const (
+	// TargetJWTAlgorithmHS256 signs with HMAC SHA-256 and the primary signing key of the target
+	TargetJWTAlgorithmHS256 TargetJWTAlgorithm = "HS256"
+)
+
+func (a TargetJWTAlgorithm) Valid() bool {
+	return a == TargetJWTAlgorithmHS256
+}
+
+// TargetJWTDelivery delivers the payload to a target as JWT signed with the signing key of the target (see [TargetSigningKeys]),
+// the payload is sent as claims instead of in the payload format of the target.
+// The target can verify the JWTs with the same key it verifies the signature header with.
+type TargetJWTDelivery struct {
+	Algorithm TargetJWTAlgorithm `json:"algorithm,omitempty"`
+	// Issuer is set as iss claim of the JWTs
+	Issuer string `json:"issuer,omitempty"`
+}
+
+// IsZero reports if the payload is not delivered as JWT
+func (j *TargetJWTDelivery) IsZero() bool {
+	return j == nil || j.Algorithm == ""
+}
+
+// TargetOAuth2Credentials are the decrypted client credentials of a target,
+// they must only be used to request tokens and never be returned to clients.
+type TargetOAuth2Credentials struct {
+	TokenEndpoint string
+	ClientID      string
+	ClientSecret  string
+}
+
+// TargetDefaults are the defaults of an instance applied to targets created without the respective values.
+// Explicit values of a target always override the defaults.
+type TargetDefaults struct {
+	// Timeout is applied to targets created without timeout, 0 if there is no default
+	Timeout time.Duration
+}
+
+// ExecutionSettings are the settings of an instance for the calls of its targets
+type ExecutionSettings struct {
+	// AsyncPoolSize is the amount of async targets called concurrently, 0 if the default pool size is used
+	AsyncPoolSize int
+	// TargetQueueDepth is the amount of payloads queued per paused async target, 0 if the default depth is used
+	TargetQueueDepth int
+	// TargetQueueOverflow defines what happens to the payloads exceeding the queue of a paused target
+	TargetQueueOverflow TargetQueueOverflow
+}
+
+// TargetQueueOverflow defines what happens to a payload delivered to a paused target with a full queue
+type TargetQueueOverflow int32
+
+const (
+	// TargetQueueOverflowDrop drops the payload and counts it as dropped
+	TargetQueueOverflowDrop TargetQueueOverflow = iota
+	// TargetQueueOverflowFail reports the payload as failed delivery
+	TargetQueueOverflowFail
+	targetQueueOverflowCount
+)
+
+// Valid reports if the queue overflow is known
+func (o TargetQueueOverflow) Valid() bool {
+	return o >= 0 && o < targetQueueOverflowCount
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// TargetSigningKeys are the encrypted keys used to sign the requests sent to a target.
+// Requests are always signed with the primary key,
+// the secondary key is the successor and additionally signs the requests until its grace window ends,
+// which allows the target to roll over to the new key before it is promoted.
+type TargetSigningKeys struct {
+	Primary   *crypto.CryptoValue `json:"primary,omitempty"`
+	Secondary *crypto.CryptoValue `json:"secondary,omitempty"`
+	// SecondaryValidUntil ends the grace window of the secondary key
+	SecondaryValidUntil time.Time `json:"secondaryValidUntil,omitempty"`
+}
+
+// TargetSigningKeyState describes in which state of the rotation the keys are.
+type TargetSigningKeyState int32
+
+const (
+	TargetSigningKeyStateUnspecified TargetSigningKeyState = iota
+	// TargetSigningKeyStatePrimary only has a primary key
+	TargetSigningKeyStatePrimary
+	// TargetSigningKeyStateRotating has a secondary key within its grace window
+	TargetSigningKeyStateRotating
+	// TargetSigningKeyStateExpired has a secondary key which is no longer valid
+	TargetSigningKeyStateExpired
+)
+
+// IsZero reports if the requests are not signed
+func (k *TargetSigningKeys) IsZero() bool {
+	return k == nil || k.Primary == nil
+}
+
+// State returns the rotation state of the keys at the given time.
+func (k *TargetSigningKeys) State(now time.Time) TargetSigningKeyState {
+	switch {
+	case k.IsZero():
+		return TargetSigningKeyStateUnspecified
+	case k.Secondary == nil:
+		return TargetSigningKeyStatePrimary
+	case now.Before(k.SecondaryValidUntil):
+		return TargetSigningKeyStateRotating
+	default:
+		return TargetSigningKeyStateExpired
+	}
+}
+
+// SetSecondary starts a rotation with the key as successor of the primary key,
+// the key is valid for verification until the grace window ends at validUntil.
+func (k *TargetSigningKeys) SetSecondary(key *crypto.CryptoValue, validUntil, now time.Time) error {
+	if key == nil || !validUntil.After(now) {
+		return zerrors.ThrowInvalidArgument(nil, "DOMAIN-Vb4mQ", "Errors.Target.InvalidSigningKey")
+	}
+	if k.IsZero() {
+		return zerrors.ThrowPreconditionFailed(nil, "DOMAIN-s1Ngk", "Errors.Target.NoSigningKey")
+	}
+	k.Secondary = key
+	k.SecondaryValidUntil = validUntil
+	return nil
+}
+
+// Rotate promotes the secondary key to primary and clears the secondary key afterwards.
+func (k *TargetSigningKeys) Rotate() error {
+	if k.IsZero() || k.Secondary == nil {
+		return zerrors.ThrowPreconditionFailed(nil, "DOMAIN-R0t8e", "Errors.Target.NoSecondarySigningKey")
+	}
+	k.Primary = k.Secondary
+	k.Secondary = nil
+	k.SecondaryValidUntil = time.Time{}
+	return nil
+}
+
+// ActiveSigningKey is a masked key which is currently valid.
+type ActiveSigningKey struct {
+	MaskedKey  string
+	Primary    bool
+	ValidUntil time.Time
+}
+
+// ActiveKeys returns the decrypted and masked keys which are valid at the given time, the primary key first.
+func (k *TargetSigningKeys) ActiveKeys(now time.Time, secretCrypto crypto.EncryptionAlgorithm) ([]*ActiveSigningKey, error) {
+	state := k.State(now)
+	if state == TargetSigningKeyStateUnspecified {
+		return nil, nil
+	}
+	primary, err := crypto.DecryptString(k.Primary, secretCrypto)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "DOMAIN-Sk3Dc1", "Errors.Internal")
+	}
+	keys := []*ActiveSigningKey{{MaskedKey: MaskSigningKey(primary), Primary: true}}
+	if state != TargetSigningKeyStateRotating {
+		return keys, nil
+	}
+	secondary, err := crypto.DecryptString(k.Secondary, secretCrypto)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "DOMAIN-Sk3Dc2", "Errors.Internal")
+	}
+	return append(keys, &ActiveSigningKey{MaskedKey: MaskSigningKey(secondary), ValidUntil: k.SecondaryValidUntil}), nil
+}
+
+const signingKeyVisibleSuffix = 4
+
+// MaskSigningKey hides all but the last characters of the key,
+// short keys are masked completely.
+func MaskSigningKey(key string) string {
+	if len(key) <= 2*signingKeyVisibleSuffix {
+		return strings.Repeat("*", len(key))
+	}
+	return strings.Repeat("*", len(key)-signingKeyVisibleSuffix) + key[len(key)-signingKeyVisibleSuffix:]
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// encryptedSigningKey returns the key as encrypted by [crypto.CreateMockEncryptionAlg]
+func encryptedSigningKey(key string) *crypto.CryptoValue {
+	return &crypto.CryptoValue{
+		CryptoType: crypto.TypeEncryption,
+		Algorithm:  "enc",
+		KeyID:      "id",
+		Crypted:    []byte(key),
+	}
+}
+
+func TestTargetSigningKeys_rotation(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	secretCrypto := crypto.CreateMockEncryptionAlg(gomock.NewController(t))
+	keys := &TargetSigningKeys{Primary: encryptedSigningKey("primary-key-0001")}
+
+	// only primary
+	assert.Equal(t, TargetSigningKeyStatePrimary, keys.State(now))
+	active, err := keys.ActiveKeys(now, secretCrypto)
+	require.NoError(t, err)
+	assert.Equal(t, []*ActiveSigningKey{{MaskedKey: "************0001", Primary: true}}, active)
+
+	// rotating, requests are still signed with the primary key
+	require.NoError(t, keys.SetSecondary(encryptedSigningKey("secondary-key-0002"), now.Add(time.Hour), now))
+	assert.Equal(t, TargetSigningKeyStateRotating, keys.State(now))
+	assert.Equal(t, encryptedSigningKey("primary-key-0001"), keys.Primary)
+	active, err = keys.ActiveKeys(now, secretCrypto)
+	require.NoError(t, err)
+	assert.Equal(t, []*ActiveSigningKey{
+		{MaskedKey: "************0001", Primary: true},
+		{MaskedKey: "**************0002", ValidUntil: now.Add(time.Hour)},
+	}, active)
+
+	// grace window ended
+	assert.Equal(t, TargetSigningKeyStateExpired, keys.State(now.Add(time.Hour)))
+	active, err = keys.ActiveKeys(now.Add(time.Hour), secretCrypto)
+	require.NoError(t, err)
+	assert.Equal(t, []*ActiveSigningKey{{MaskedKey: "************0001", Primary: true}}, active)
+
+	// rotated
+	require.NoError(t, keys.Rotate())
+	assert.Equal(t, TargetSigningKeyStatePrimary, keys.State(now))
+	assert.Equal(t, &TargetSigningKeys{Primary: encryptedSigningKey("secondary-key-0002")}, keys)
+}
+
+func TestTargetSigningKeys_errors(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		keys    *TargetSigningKeys
+		do      func(*TargetSigningKeys) error
+		wantErr error
+	}{
+		{
+			name: "secondary empty",
+			keys: &TargetSigningKeys{Primary: encryptedSigningKey("key")},
+			do: func(k *TargetSigningKeys) error {
+				return k.SetSecondary(nil, now.Add(time.Hour), now)
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "DOMAIN-Vb4mQ", "Errors.Target.InvalidSigningKey"),
+		},
+		{
+			name: "secondary without grace window",
+			keys: &TargetSigningKeys{Primary: encryptedSigningKey("key")},
+			do: func(k *TargetSigningKeys) error {
+				return k.SetSecondary(encryptedSigningKey("next"), now, now)
+			},
+			wantErr: zerrors.ThrowInvalidArgument(nil, "DOMAIN-Vb4mQ", "Errors.Target.InvalidSigningKey"),
+		},
+		{
+			name: "secondary without primary",
+			keys: &TargetSigningKeys{},
+			do: func(k *TargetSigningKeys) error {
+				return k.SetSecondary(encryptedSigningKey("next"), now.Add(time.Hour), now)
+			},
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "DOMAIN-s1Ngk", "Errors.Target.NoSigningKey"),
+		},
+		{
+			name:    "rotate without secondary",
+			keys:    &TargetSigningKeys{Primary: encryptedSigningKey("key")},
+			do:      (*TargetSigningKeys).Rotate,
+			wantErr: zerrors.ThrowPreconditionFailed(nil, "DOMAIN-R0t8e", "Errors.Target.NoSecondarySigningKey"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.do(tt.keys), tt.wantErr)
+		})
+	}
+}
+
+func TestTargetSigningKeys_unspecified(t *testing.T) {
+	var keys *TargetSigningKeys
+	assert.True(t, keys.IsZero())
+	assert.Equal(t, TargetSigningKeyStateUnspecified, keys.State(time.Now()))
+	active, err := keys.ActiveKeys(time.Now(), nil)
+	require.NoError(t, err)
+	assert.Nil(t, active)
+}
+
+func TestMaskSigningKey(t *testing.T) {
+	assert.Equal(t, "", MaskSigningKey(""))
+	assert.Equal(t, "********", MaskSigningKey("shortkey"))
+	assert.Equal(t, "*****1234", MaskSigningKey("abcde1234"))
+}
@@ -0,0 +1,61 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+)
+
+type causationKey struct{}
+
+type correlationKey struct{}
+
+// WithCausationID returns a context in which the pushed events are caused by the event with the ID,
+// see [EventID] for the ID of an event.
+func WithCausationID(ctx context.Context, causationID string) context.Context {
+	return context.WithValue(ctx, causationKey{}, causationID)
+}
+
+// CausationIDFromContext returns the causation ID set by [WithCausationID], empty if none is set
+func CausationIDFromContext(ctx context.Context) string {
+	causationID, _ := ctx.Value(causationKey{}).(string)
+	return causationID
+}
+
+// WithCorrelationID returns a context in which the pushed events originate from the request with the ID
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, correlationID)
+}
+
+// CorrelationIDFromContext returns the correlation ID set by [WithCorrelationID], empty if none is set
+func CorrelationIDFromContext(ctx context.Context) string {
+	correlationID, _ := ctx.Value(correlationKey{}).(string)
+	return correlationID
+}
+
+// WithCausingEvent returns a context in which the pushed events are caused by the event.
+// If the context has no correlation ID, the events continue the correlation of the causing event,
+// so all events of a chain share the correlation ID of the originating request.
+func WithCausingEvent(ctx context.Context, event Event) context.Context {
+	ctx = WithCausationID(ctx, EventID(event))
+	if CorrelationIDFromContext(ctx) != "" {
+		return ctx
+	}
+	if provider, ok := event.(CausationProvider); ok && provider.CorrelationID() != "" {
+		ctx = WithCorrelationID(ctx, provider.CorrelationID())
+	}
+	return ctx
+}
+
+// EventID identifies an event by its instance, aggregate and sequence, which are unique for every event
+func EventID(event Event) string {
+	aggregate := event.Aggregate()
+	return fmt.Sprintf("%s:%s:%s:%d", aggregate.InstanceID, aggregate.Type, aggregate.ID, event.Sequence())
+}
+
+// CausationProvider is implemented by the events which carry their causation and correlation
+type CausationProvider interface {
+	// CausationID returns the ID of the event which caused the event, empty if unknown
+	CausationID() string
+	// CorrelationID returns the ID of the request the event originates from, empty if unknown
+	CorrelationID() string
+}
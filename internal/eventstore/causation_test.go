@@ -0,0 +1,51 @@
+package eventstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type causationEvent struct {
+	*BaseEvent
+	causationID   string
+	correlationID string
+}
+
+func (e *causationEvent) CausationID() string {
+	return e.causationID
+}
+
+func (e *causationEvent) CorrelationID() string {
+	return e.correlationID
+}
+
+func TestWithCausingEvent(t *testing.T) {
+	cause := &causationEvent{
+		BaseEvent: &BaseEvent{
+			Agg: &Aggregate{
+				ID:         "user-1",
+				Type:       "user",
+				InstanceID: "instance",
+			},
+			Seq: 3,
+		},
+		correlationID: "request",
+	}
+	assert.Equal(t, "instance:user:user-1:3", EventID(cause))
+
+	ctx := context.Background()
+	assert.Empty(t, CausationIDFromContext(ctx))
+	assert.Empty(t, CorrelationIDFromContext(ctx))
+
+	// the correlation of the causing event is continued
+	caused := WithCausingEvent(ctx, cause)
+	assert.Equal(t, "instance:user:user-1:3", CausationIDFromContext(caused))
+	assert.Equal(t, "request", CorrelationIDFromContext(caused))
+
+	// the correlation of the context is kept
+	caused = WithCausingEvent(WithCorrelationID(ctx, "other"), cause)
+	assert.Equal(t, "instance:user:user-1:3", CausationIDFromContext(caused))
+	assert.Equal(t, "other", CorrelationIDFromContext(caused))
+}
@@ -7,7 +7,38 @@ import (
 type Config struct {
 	PushTimeout time.Duration
 	MaxRetries  uint32
+	// RetryBudget limits the retries shared by all pushes
+	RetryBudget RetryBudgetConfig
+	// Push configures how the pusher writes the events
+	Push PushConfig
 
 	Pusher  Pusher
 	Querier Querier
 }
+
+// PushConfig configures how the pusher writes the events to the database
+type PushConfig struct {
+	// AcquireTimeout limits the time a push waits for a connection of the pool, 0 waits until the push timed out
+	AcquireTimeout time.Duration
+	// ValidatePayloads checks that the payload of each event is serialized to a JSON object before it is written
+	ValidatePayloads bool
+	// CompressionThreshold is the size in bytes above which payloads are written compressed, 0 disables the compression
+	CompressionThreshold int
+	// PayloadCodec serializes the payloads, "json" (default) or "protobuf"
+	PayloadCodec string
+	// DeduplicationWindow is the time in which pushes identical to the latest events of their aggregates are not written again, 0 disables the deduplication
+	DeduplicationWindow time.Duration
+	// SkipSingleCommandSavepoint pushes single commands without the savepoint used to retry within the transaction
+	SkipSingleCommandSavepoint bool
+	// MaxPayloadSize limits the size in bytes of the payload of each event, 0 does not limit the size
+	MaxPayloadSize int
+	// Redactions are the fields removed from the payloads of event types before they are written
+	Redactions []RedactionConfig
+}
+
+// RedactionConfig are the fields removed from the payloads of the event type,
+// a field is a dot separated path of keys of nested objects, e.g. "profile.email"
+type RedactionConfig struct {
+	EventType string
+	Fields    []string
+}
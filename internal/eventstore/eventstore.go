@@ -18,6 +18,7 @@ import (
 type Eventstore struct {
 	PushTimeout time.Duration
 	maxRetries  int
+	retryBudget *retryBudget
 
 	pusher  Pusher
 	querier Querier
@@ -61,6 +62,7 @@ func NewEventstore(config *Config) *Eventstore {
 	return &Eventstore{
 		PushTimeout: config.PushTimeout,
 		maxRetries:  int(config.MaxRetries),
+		retryBudget: newRetryBudget(config.RetryBudget),
 
 		pusher:  config.Pusher,
 		querier: config.Querier,
@@ -101,6 +103,16 @@ retry:
 		if !errors.As(err, &pgErr) || pgErr.ConstraintName != "events2_pkey" || pgErr.SQLState() != "23505" {
 			break retry
 		}
+		if i == es.maxRetries {
+			err = throwRetriesExhausted(i+1, pgErr.SQLState(), err)
+			break retry
+		}
+		// under sustained contention the shared budget stops retries early to relieve the database
+		if !es.retryBudget.take() {
+			logging.WithError(err).Info("eventstore push retry budget exhausted")
+			err = throwRetriesExhausted(i+1, pgErr.SQLState(), err)
+			break retry
+		}
 		logging.WithError(err).Info("eventstore push retry")
 	}
 	if err != nil {
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// ReprojectAggregate rebuilds the rows of a single aggregate of the instance in the context from its events,
+// e.g. if the row of the aggregate is corrupt and reprojecting the whole projection is not required.
+// The rows matching the conditions are deleted and the events of the aggregate are reduced again
+// using the reducers of the projection.
+// Only events up to the current position of the projection are reduced, later events are reduced by the handler as usual.
+// The state of the projection is locked for the instance during the rebuild.
+func (h *Handler) ReprojectAggregate(ctx context.Context, aggregateType eventstore.AggregateType, aggregateID string, conditions []Condition) (err error) {
+	eventTypes, ok := h.eventTypes[aggregateType]
+	if !ok {
+		return zerrors.ThrowInvalidArgumentf(nil, "V2-Rp4Ty", "aggregate type %s is not reduced by projection %s", aggregateType, h.projection.Name())
+	}
+	if len(conditions) == 0 {
+		return ErrNoCondition
+	}
+
+	unlock := h.lockInstance(ctx, &triggerConfig{awaitRunning: true})
+	if unlock == nil {
+		return ctx.Err()
+	}
+	defer unlock()
+
+	ctx, spanBeginTx := tracing.NewNamedSpan(ctx, "db.BeginTx")
+	tx, err := h.client.BeginTx(ctx, nil)
+	spanBeginTx.EndWithError(err)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			h.log().OnError(rollbackErr).Debug("unable to rollback tx")
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	currentState, err := h.currentState(ctx, tx, &triggerConfig{awaitRunning: true})
+	if err != nil {
+		return err
+	}
+
+	events, err := h.es.Filter(ctx, eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		InstanceID(currentState.instanceID).
+		OrderAsc().
+		AddQuery().
+		AggregateTypes(aggregateType).
+		AggregateIDs(aggregateID).
+		EventTypes(eventTypes...).
+		Builder(),
+	)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return zerrors.ThrowNotFoundf(nil, "V2-Rp5Nf", "no events of aggregate %s %s found", aggregateType, aggregateID)
+	}
+
+	statements := make([]*Statement, 0, len(events)+1)
+	statements = append(statements, NewDeleteStatement(events[0], conditions))
+	for _, event := range events {
+		if event.Position() > currentState.position {
+			break
+		}
+		statement, err := h.reduce(event)
+		if err != nil {
+			return err
+		}
+		statements = append(statements, statement)
+	}
+
+	for _, statement := range statements {
+		if statement.Execute == nil {
+			continue
+		}
+		if err = statement.Execute(tx, h.projection.Name()); err != nil {
+			h.logEvent(events[0]).WithError(err).Warn("reprojection of aggregate failed")
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// filterEventStore returns the same events for each filter
+type filterEventStore struct {
+	EventStore
+	events []eventstore.Event
+}
+
+func (es *filterEventStore) Filter(context.Context, *eventstore.SearchQueryBuilder) ([]eventstore.Event, error) {
+	return es.events, nil
+}
+
+func TestHandler_ReprojectAggregate(t *testing.T) {
+	reprojected := &projection{
+		name: "projection",
+		reducers: []AggregateReducer{
+			{
+				Aggregate: "target",
+				EventReducers: []EventReducer{
+					{
+						Event: "added",
+						Reduce: func(event eventstore.Event) (*Statement, error) {
+							return NewCreateStatement(event, []Column{
+								NewCol("instance_id", event.Aggregate().InstanceID),
+								NewCol("id", event.Aggregate().ID),
+								NewCol("sequence", event.Sequence()),
+							}), nil
+						},
+					},
+					{
+						Event: "changed",
+						Reduce: func(event eventstore.Event) (*Statement, error) {
+							return NewUpdateStatement(event,
+								[]Column{NewCol("sequence", event.Sequence())},
+								[]Condition{
+									NewCond("instance_id", event.Aggregate().InstanceID),
+									NewCond("id", event.Aggregate().ID),
+								},
+							), nil
+						},
+					},
+				},
+			},
+		},
+	}
+	newEvent := func(typ eventstore.EventType, sequence uint64, position float64) eventstore.Event {
+		return &eventstore.BaseEvent{
+			EventType: typ,
+			Agg:       &eventstore.Aggregate{Type: "target", ID: "1", InstanceID: "instance"},
+			Seq:       sequence,
+			Pos:       position,
+		}
+	}
+	expectState := mock.ExpectQuery(currentStateAwaitStmt,
+		mock.WithQueryArgs("instance", "projection"),
+		mock.WithQueryResult(
+			[]string{"aggregate_id", "aggregate_type", "event_sequence", "event_date", "position", "offset"},
+			[][]driver.Value{{"1", "target", int64(2), nil, float64(2), uint16(1)}},
+		),
+	)
+	expectExec := func(stmt string, args ...driver.Value) []mock.Expectation {
+		return []mock.Expectation{
+			mock.ExcpectExec("SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+			mock.ExcpectExec(stmt, mock.WithExecArgs(args...), mock.WithExecRowsAffected(1)),
+			mock.ExcpectExec("RELEASE SAVEPOINT stmt_exec", mock.WithExecNoRowsAffected()),
+		}
+	}
+	conditions := []Condition{
+		NewCond("instance_id", "instance"),
+		NewCond("id", "1"),
+	}
+
+	tests := []struct {
+		name          string
+		aggregateType eventstore.AggregateType
+		events        []eventstore.Event
+		expectations  []mock.Expectation
+		wantErr       error
+	}{
+		{
+			name:          "aggregate type not reduced",
+			aggregateType: "user",
+			wantErr:       zerrors.ThrowInvalidArgument(nil, "V2-Rp4Ty", ""),
+		},
+		{
+			name:          "no events",
+			aggregateType: "target",
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				expectState,
+				mock.ExpectRollback(nil),
+			},
+			wantErr: zerrors.ThrowNotFound(nil, "V2-Rp5Nf", ""),
+		},
+		{
+			name:          "only the row of the aggregate is rebuilt up to the current position",
+			aggregateType: "target",
+			events: []eventstore.Event{
+				newEvent("added", 1, 1),
+				newEvent("changed", 2, 2),
+				// reduced by the handler later on
+				newEvent("changed", 3, 3),
+			},
+			expectations: append(append(append(append([]mock.Expectation{
+				mock.ExpectBegin(nil),
+				expectState,
+			},
+				expectExec("DELETE FROM projection WHERE (instance_id = $1) AND (id = $2)", "instance", "1")...),
+				expectExec("INSERT INTO projection (instance_id, id, sequence) VALUES ($1, $2, $3)", "instance", "1", uint64(1))...),
+				expectExec("UPDATE projection SET sequence = $1 WHERE (instance_id = $2) AND (id = $3)", uint64(2), "instance", "1")...),
+				mock.ExpectCommit(nil),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlMock := mock.NewSQLMock(t, tt.expectations...)
+			defer sqlMock.Assert(t)
+
+			h := NewHandler(context.Background(), &Config{
+				Client:     &database.DB{DB: sqlMock.DB},
+				Eventstore: &filterEventStore{events: tt.events},
+			}, reprojected)
+
+			err := h.ReprojectAggregate(authz.WithInstanceID(context.Background(), "instance"), tt.aggregateType, "1", conditions)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
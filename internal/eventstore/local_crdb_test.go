@@ -53,6 +53,8 @@ func TestMain(m *testing.M) {
 
 	pushers["v3(inmemory)"] = new_es.NewEventstore(testCRDBClient)
 	clients["v3(inmemory)"] = testCRDBClient
+	pushers["v3(inmemory,without single command savepoint)"] = new_es.NewEventstore(testCRDBClient, new_es.WithoutSingleCommandSavepoint())
+	clients["v3(inmemory,without single command savepoint)"] = testCRDBClient
 
 	if localDB, err := connectLocalhost(); err == nil {
 		if err = initDB(localDB); err != nil {
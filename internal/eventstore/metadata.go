@@ -0,0 +1,35 @@
+package eventstore
+
+import (
+	"context"
+	"maps"
+)
+
+// Metadata are operational annotations of an event like the trace ID, the request ID or the source IP.
+// They are stored separately from the payload, are not part of the domain and must not be used to reduce events.
+type Metadata map[string]string
+
+type metadataKey struct{}
+
+// WithMetadata returns a context in which the pushed events are annotated with the metadata.
+// Metadata of the parent context is kept, values with the same key are replaced.
+func WithMetadata(ctx context.Context, metadata Metadata) context.Context {
+	merged := maps.Clone(MetadataFromContext(ctx))
+	if merged == nil {
+		merged = make(Metadata, len(metadata))
+	}
+	maps.Copy(merged, metadata)
+	return context.WithValue(ctx, metadataKey{}, merged)
+}
+
+// MetadataFromContext returns the metadata set by [WithMetadata], nil if none is set
+func MetadataFromContext(ctx context.Context) Metadata {
+	metadata, _ := ctx.Value(metadataKey{}).(Metadata)
+	return metadata
+}
+
+// MetadataProvider is implemented by the events which carry metadata
+type MetadataProvider interface {
+	// EventMetadata returns the metadata of the event, nil if the event has none
+	EventMetadata() Metadata
+}
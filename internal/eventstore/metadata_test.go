@@ -0,0 +1,20 @@
+package eventstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMetadata(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, MetadataFromContext(ctx))
+
+	parent := WithMetadata(ctx, Metadata{"traceID": "trace", "requestID": "request"})
+	child := WithMetadata(parent, Metadata{"requestID": "other", "sourceIP": "127.0.0.1"})
+
+	assert.Equal(t, Metadata{"traceID": "trace", "requestID": "other", "sourceIP": "127.0.0.1"}, MetadataFromContext(child))
+	// the metadata of the parent is not changed
+	assert.Equal(t, Metadata{"traceID": "trace", "requestID": "request"}, MetadataFromContext(parent))
+}
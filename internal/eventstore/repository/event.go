@@ -2,13 +2,16 @@ package repository
 
 import (
 	"database/sql"
-	"encoding/json"
 	"time"
 
 	"github.com/zitadel/zitadel/internal/eventstore"
 )
 
-var _ eventstore.Event = (*Event)(nil)
+var (
+	_ eventstore.Event             = (*Event)(nil)
+	_ eventstore.MetadataProvider  = (*Event)(nil)
+	_ eventstore.CausationProvider = (*Event)(nil)
+)
 
 // Event represents all information about a manipulation of an aggregate
 type Event struct {
@@ -33,6 +36,8 @@ type Event struct {
 	//Data describe the changed fields (e.g. userName = "hodor")
 	// data must always a pointer to a struct, a struct or a byte array containing json bytes
 	Data []byte
+	// Encoding is the encoding the payload is stored with, Data is JSON unless it is [PayloadEncodingProtobuf]
+	Encoding PayloadEncoding
 
 	//EditorUser should be a unique identifier for the user which created the event
 	// it's meant for maintainability.
@@ -56,6 +61,13 @@ type Event struct {
 	// use the ID of the instance
 	InstanceID string
 
+	// Metadata are the operational annotations of the event, they are not part of Data
+	Metadata eventstore.Metadata
+	// Causation is the ID of the event which caused the event, empty if unknown
+	Causation string
+	// Correlation is the ID of the request the event originates from, empty if unknown
+	Correlation string
+
 	Constraints []*eventstore.UniqueConstraint
 }
 
@@ -105,7 +117,7 @@ func (e *Event) Unmarshal(ptr any) error {
 	if len(e.Data) == 0 {
 		return nil
 	}
-	return json.Unmarshal(e.Data, ptr)
+	return UnmarshalPayload(e.Encoding, e.Data, ptr)
 }
 
 // DataAsBytes implements [eventstore.Event]
@@ -120,3 +132,18 @@ func (e *Event) Payload() any {
 func (e *Event) UniqueConstraints() []*eventstore.UniqueConstraint {
 	return e.Constraints
 }
+
+// EventMetadata implements [eventstore.MetadataProvider]
+func (e *Event) EventMetadata() eventstore.Metadata {
+	return e.Metadata
+}
+
+// CausationID implements [eventstore.CausationProvider]
+func (e *Event) CausationID() string {
+	return e.Causation
+}
+
+// CorrelationID implements [eventstore.CausationProvider]
+func (e *Event) CorrelationID() string {
+	return e.Correlation
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"encoding/json"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// UnmarshalMetadata returns the metadata stored in the metadata column of an event, nil if the event has none
+func UnmarshalMetadata(data []byte) (eventstore.Metadata, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var metadata eventstore.Metadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, zerrors.ThrowInternal(err, "REPO-Md4Um", "Errors.Internal")
+	}
+	return metadata, nil
+}
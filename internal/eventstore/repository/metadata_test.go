@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestUnmarshalMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    eventstore.Metadata
+		wantErr error
+	}{
+		{
+			name: "no metadata",
+		},
+		{
+			name: "empty metadata",
+			data: []byte(`{}`),
+			want: eventstore.Metadata{},
+		},
+		{
+			name: "metadata",
+			data: []byte(`{"traceID":"trace","sourceIP":"127.0.0.1"}`),
+			want: eventstore.Metadata{"traceID": "trace", "sourceIP": "127.0.0.1"},
+		},
+		{
+			name:    "invalid metadata",
+			data:    []byte(`["trace"]`),
+			wantErr: zerrors.ThrowInternal(nil, "REPO-Md4Um", "Errors.Internal"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalMetadata(tt.data)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
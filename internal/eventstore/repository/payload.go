@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// PayloadEncoding describes how the payload of an event is stored
+type PayloadEncoding int16
+
+const (
+	// PayloadEncodingJSON payloads are stored as JSONB in the payload column.
+	// Events written before the encoding was introduced have no encoding and are stored the same way.
+	PayloadEncodingJSON PayloadEncoding = iota
+	// PayloadEncodingGzip payloads are stored gzip compressed in the compressed_payload column.
+	// Filters on the payload do not match these events.
+	PayloadEncodingGzip
+	// PayloadEncodingProtobuf payloads are stored protobuf serialized in the compressed_payload column.
+	// Filters on the payload do not match these events.
+	PayloadEncodingProtobuf
+)
+
+// CompressPayload compresses the serialized payload for [PayloadEncodingGzip]
+func CompressPayload(payload []byte) ([]byte, error) {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(payload); err != nil {
+		return nil, zerrors.ThrowInternal(err, "REPO-Kd9wQ", "Errors.Internal")
+	}
+	if err := writer.Close(); err != nil {
+		return nil, zerrors.ThrowInternal(err, "REPO-p2Xn4", "Errors.Internal")
+	}
+	return compressed.Bytes(), nil
+}
+
+// DecodePayload returns the serialized payload of an event stored with the given encoding.
+// payload is the value of the payload column and compressed the value of the compressed_payload column.
+func DecodePayload(encoding PayloadEncoding, payload, compressed []byte) ([]byte, error) {
+	switch encoding {
+	case PayloadEncodingJSON:
+		return payload, nil
+	case PayloadEncodingGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, zerrors.ThrowInternal(err, "REPO-Vb4sT", "Errors.Internal")
+		}
+		defer reader.Close()
+		decoded, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, zerrors.ThrowInternal(err, "REPO-R7mLc", "Errors.Internal")
+		}
+		return decoded, nil
+	case PayloadEncodingProtobuf:
+		return compressed, nil
+	default:
+		return nil, zerrors.ThrowInternalf(nil, "REPO-Zq3hE", "unknown payload encoding %d", encoding)
+	}
+}
+
+// UnmarshalPayload unmarshals the payload returned by [DecodePayload] into ptr.
+// Protobuf payloads require ptr to be a [proto.Message], all other payloads are JSON.
+func UnmarshalPayload(encoding PayloadEncoding, payload []byte, ptr any) error {
+	if encoding != PayloadEncodingProtobuf {
+		return json.Unmarshal(payload, ptr)
+	}
+	message, ok := ptr.(proto.Message)
+	if !ok {
+		return zerrors.ThrowInternalf(nil, "REPO-Pb7uM", "protobuf payload can not be unmarshaled into %T", ptr)
+	}
+	return proto.Unmarshal(payload, message)
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/apipb"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestDecodePayload(t *testing.T) {
+	payload := []byte(`{"key":"value"}`)
+	compressed, err := CompressPayload(payload)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		encoding   PayloadEncoding
+		payload    []byte
+		compressed []byte
+		want       []byte
+		wantErr    bool
+	}{
+		{
+			name:     "json",
+			encoding: PayloadEncodingJSON,
+			payload:  payload,
+			want:     payload,
+		},
+		{
+			name:     "json without payload",
+			encoding: PayloadEncodingJSON,
+		},
+		{
+			name:       "gzip",
+			encoding:   PayloadEncodingGzip,
+			compressed: compressed,
+			want:       payload,
+		},
+		{
+			name:       "gzip invalid",
+			encoding:   PayloadEncodingGzip,
+			compressed: payload,
+			wantErr:    true,
+		},
+		{
+			name:       "protobuf",
+			encoding:   PayloadEncodingProtobuf,
+			compressed: payload,
+			want:       payload,
+		},
+		{
+			name:     "unknown encoding",
+			encoding: 42,
+			payload:  payload,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodePayload(tt.encoding, tt.payload, tt.compressed)
+			if tt.wantErr {
+				assert.True(t, zerrors.IsInternal(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnmarshalPayload(t *testing.T) {
+	message := &apipb.Method{Name: "AddTarget"}
+	protoPayload, err := proto.Marshal(message)
+	require.NoError(t, err)
+
+	t.Run("json", func(t *testing.T) {
+		var got map[string]string
+		require.NoError(t, UnmarshalPayload(PayloadEncodingJSON, []byte(`{"key":"value"}`), &got))
+		assert.Equal(t, map[string]string{"key": "value"}, got)
+	})
+	t.Run("decompressed json", func(t *testing.T) {
+		var got map[string]string
+		require.NoError(t, UnmarshalPayload(PayloadEncodingGzip, []byte(`{"key":"value"}`), &got))
+		assert.Equal(t, map[string]string{"key": "value"}, got)
+	})
+	t.Run("protobuf", func(t *testing.T) {
+		got := new(apipb.Method)
+		require.NoError(t, UnmarshalPayload(PayloadEncodingProtobuf, protoPayload, got))
+		assert.True(t, proto.Equal(message, got))
+	})
+	t.Run("protobuf, no message", func(t *testing.T) {
+		var got map[string]string
+		err := UnmarshalPayload(PayloadEncodingProtobuf, protoPayload, &got)
+		assert.True(t, zerrors.IsInternal(err), "unexpected error: %v", err)
+	})
+}
@@ -254,13 +254,46 @@ func (crdb *CRDB) FilterToReducer(ctx context.Context, searchQuery *eventstore.S
 		return nil
 	}
 	pgErr := new(pgconn.PgError)
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
 	// check events2 not exists
-	if errors.As(err, &pgErr) && pgErr.Code == "42P01" {
+	case "42P01":
 		return query(ctx, crdb, searchQuery, reduce, true)
+	// check the columns added to events2 by setup steps not exist
+	case "42703":
+		return query(ctx, &withoutAddedEventColumns{crdb}, searchQuery, reduce, false)
 	}
 	return err
 }
 
+// addedEventColumns are the columns added to events2 by setup steps, in the order they are scanned
+var addedEventColumns = []string{
+	"payload_encoding",
+	"compressed_payload",
+	"metadata",
+	"causation_id",
+	"correlation_id",
+}
+
+// withoutAddedEventColumns reads events2 before the setup steps added the [addedEventColumns],
+// the missing columns are read as NULL
+type withoutAddedEventColumns struct {
+	*CRDB
+}
+
+func (db *withoutAddedEventColumns) eventQuery(useV1 bool) string {
+	if useV1 {
+		return db.CRDB.eventQuery(useV1)
+	}
+	columns := make([]string, len(addedEventColumns))
+	for i, column := range addedEventColumns {
+		columns[i] = "NULL AS " + column
+	}
+	return events2Query(columns)
+}
+
 // LatestSequence returns the latest sequence found by the search query
 func (db *CRDB) LatestSequence(ctx context.Context, searchQuery *eventstore.SearchQueryBuilder) (float64, error) {
 	var position sql.NullFloat64
@@ -311,6 +344,10 @@ func (db *CRDB) eventQuery(useV1 bool) string {
 			", aggregate_version" +
 			" FROM eventstore.events"
 	}
+	return events2Query(addedEventColumns)
+}
+
+func events2Query(addedColumns []string) string {
 	return "SELECT" +
 		" created_at" +
 		", event_type" +
@@ -323,6 +360,7 @@ func (db *CRDB) eventQuery(useV1 bool) string {
 		", aggregate_type" +
 		", aggregate_id" +
 		", revision" +
+		", " + strings.Join(addedColumns, ", ") +
 		" FROM eventstore.events2"
 }
 
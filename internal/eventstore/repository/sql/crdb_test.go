@@ -1,9 +1,15 @@
 package sql
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"testing"
 
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/eventstore/repository"
 )
@@ -303,6 +309,29 @@ func TestCRDB_columnName(t *testing.T) {
 	}
 }
 
+func TestCRDB_FilterToReducer_withoutAddedEventColumns(t *testing.T) {
+	// the setup steps adding the columns did not run yet
+	client := newMockClient(t).expectQueryErr(t,
+		`SELECT created_at, .*, revision, payload_encoding, compressed_payload, metadata, causation_id, correlation_id FROM eventstore\.events2 WHERE aggregate_type = \$1`,
+		[]driver.Value{eventstore.AggregateType("user")},
+		&pgconn.PgError{Code: "42703"},
+	)
+	client.mock.ExpectRollback()
+	client.expectQuery(t,
+		`SELECT created_at, .*, revision, NULL AS payload_encoding, NULL AS compressed_payload, NULL AS metadata, NULL AS causation_id, NULL AS correlation_id FROM eventstore\.events2 WHERE aggregate_type = \$1`,
+		[]driver.Value{eventstore.AggregateType("user")},
+	)
+	crdb := NewCRDB(&database.DB{Database: new(testDB)})
+	crdb.DB.DB = client.client
+
+	err := crdb.FilterToReducer(context.Background(),
+		eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).AddQuery().AggregateTypes("user").Builder(),
+		func(eventstore.Event) error { return nil },
+	)
+	require.NoError(t, err)
+	require.NoError(t, client.mock.ExpectationsWereMet())
+}
+
 func generateEvent(t *testing.T, aggregateID string, opts ...func(*repository.Event)) *repository.Event {
 	t.Helper()
 	e := &repository.Event{
@@ -179,6 +179,14 @@ func eventsScanner(useV1 bool) func(scanner scan, dest interface{}) (err error)
 		}
 		event := new(repository.Event)
 		position := new(sql.NullFloat64)
+		// events of the v1 table are always stored as JSON
+		var (
+			encoding    sql.NullInt16
+			compressed  []byte
+			metadata    []byte
+			causation   sql.NullString
+			correlation sql.NullString
+		)
 
 		if useV1 {
 			err = scanner(
@@ -207,6 +215,11 @@ func eventsScanner(useV1 bool) func(scanner scan, dest interface{}) (err error)
 				&event.AggregateType,
 				&event.AggregateID,
 				&revision,
+				&encoding,
+				&compressed,
+				&metadata,
+				&causation,
+				&correlation,
 			)
 			event.Version = eventstore.Version("v" + strconv.Itoa(int(revision)))
 		}
@@ -216,6 +229,17 @@ func eventsScanner(useV1 bool) func(scanner scan, dest interface{}) (err error)
 			return zerrors.ThrowInternal(err, "SQL-M0dsf", "unable to scan row")
 		}
 		event.Pos = position.Float64
+		event.Encoding = repository.PayloadEncoding(encoding.Int16)
+		event.Data, err = repository.DecodePayload(event.Encoding, event.Data, compressed)
+		if err != nil {
+			return err
+		}
+		event.Metadata, err = repository.UnmarshalMetadata(metadata)
+		if err != nil {
+			return err
+		}
+		event.Causation = causation.String
+		event.Correlation = correlation.String
 		return reduce(event)
 	}
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/database/cockroach"
@@ -77,6 +78,8 @@ func Test_getCondition(t *testing.T) {
 
 func Test_prepareColumns(t *testing.T) {
 	var reducedEvents []eventstore.Event
+	compressedPayload, err := repository.CompressPayload([]byte(`{"key":"value"}`))
+	require.NoError(t, err)
 
 	type fields struct {
 		dbRow []interface{}
@@ -176,13 +179,70 @@ func Test_prepareColumns(t *testing.T) {
 				}),
 			},
 			res: res{
-				query: `SELECT created_at, event_type, "sequence", "position", payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision FROM eventstore.events2`,
+				query: `SELECT created_at, event_type, "sequence", "position", payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision, payload_encoding, compressed_payload, metadata, causation_id, correlation_id FROM eventstore.events2`,
 				expected: []eventstore.Event{
 					&repository.Event{AggregateID: "hodor", AggregateType: "user", Seq: 5, Pos: 42, Data: nil, Version: "v1"},
 				},
 			},
 			fields: fields{
-				dbRow: []interface{}{time.Time{}, eventstore.EventType(""), uint64(5), sql.NullFloat64{Float64: 42, Valid: true}, sql.RawBytes(nil), "", sql.NullString{}, "", eventstore.AggregateType("user"), "hodor", uint8(1)},
+				dbRow: []interface{}{time.Time{}, eventstore.EventType(""), uint64(5), sql.NullFloat64{Float64: 42, Valid: true}, sql.RawBytes(nil), "", sql.NullString{}, "", eventstore.AggregateType("user"), "hodor", uint8(1), sql.NullInt16{}, []byte(nil), []byte(nil), sql.NullString{}, sql.NullString{}},
+			},
+		},
+		{
+			name: "events v2 compressed payload",
+			args: args{
+				columns: eventstore.ColumnsEvent,
+				dest: eventstore.Reducer(func(event eventstore.Event) error {
+					reducedEvents = append(reducedEvents, event)
+					return nil
+				}),
+			},
+			res: res{
+				query: `SELECT created_at, event_type, "sequence", "position", payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision, payload_encoding, compressed_payload, metadata, causation_id, correlation_id FROM eventstore.events2`,
+				expected: []eventstore.Event{
+					&repository.Event{AggregateID: "hodor", AggregateType: "user", Seq: 5, Pos: 42, Data: []byte(`{"key":"value"}`), Version: "v1"},
+				},
+			},
+			fields: fields{
+				dbRow: []interface{}{time.Time{}, eventstore.EventType(""), uint64(5), sql.NullFloat64{Float64: 42, Valid: true}, sql.RawBytes(nil), "", sql.NullString{}, "", eventstore.AggregateType("user"), "hodor", uint8(1), sql.NullInt16{Int16: int16(repository.PayloadEncodingGzip), Valid: true}, compressedPayload, []byte(nil), sql.NullString{}, sql.NullString{}},
+			},
+		},
+		{
+			name: "events v2 metadata",
+			args: args{
+				columns: eventstore.ColumnsEvent,
+				dest: eventstore.Reducer(func(event eventstore.Event) error {
+					reducedEvents = append(reducedEvents, event)
+					return nil
+				}),
+			},
+			res: res{
+				query: `SELECT created_at, event_type, "sequence", "position", payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision, payload_encoding, compressed_payload, metadata, causation_id, correlation_id FROM eventstore.events2`,
+				expected: []eventstore.Event{
+					&repository.Event{AggregateID: "hodor", AggregateType: "user", Seq: 5, Pos: 42, Data: nil, Version: "v1", Metadata: eventstore.Metadata{"requestID": "request", "traceID": "trace"}},
+				},
+			},
+			fields: fields{
+				dbRow: []interface{}{time.Time{}, eventstore.EventType(""), uint64(5), sql.NullFloat64{Float64: 42, Valid: true}, sql.RawBytes(nil), "", sql.NullString{}, "", eventstore.AggregateType("user"), "hodor", uint8(1), sql.NullInt16{}, []byte(nil), []byte(`{"requestID":"request","traceID":"trace"}`), sql.NullString{}, sql.NullString{}},
+			},
+		},
+		{
+			name: "events v2 causation",
+			args: args{
+				columns: eventstore.ColumnsEvent,
+				dest: eventstore.Reducer(func(event eventstore.Event) error {
+					reducedEvents = append(reducedEvents, event)
+					return nil
+				}),
+			},
+			res: res{
+				query: `SELECT created_at, event_type, "sequence", "position", payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision, payload_encoding, compressed_payload, metadata, causation_id, correlation_id FROM eventstore.events2`,
+				expected: []eventstore.Event{
+					&repository.Event{AggregateID: "hodor", AggregateType: "user", Seq: 5, Pos: 42, Data: nil, Version: "v1", Causation: "instance:user:hodor:4", Correlation: "request"},
+				},
+			},
+			fields: fields{
+				dbRow: []interface{}{time.Time{}, eventstore.EventType(""), uint64(5), sql.NullFloat64{Float64: 42, Valid: true}, sql.RawBytes(nil), "", sql.NullString{}, "", eventstore.AggregateType("user"), "hodor", uint8(1), sql.NullInt16{}, []byte(nil), []byte(nil), sql.NullString{String: "instance:user:hodor:4", Valid: true}, sql.NullString{String: "request", Valid: true}},
 			},
 		},
 		{
@@ -195,13 +255,13 @@ func Test_prepareColumns(t *testing.T) {
 				}),
 			},
 			res: res{
-				query: `SELECT created_at, event_type, "sequence", "position", payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision FROM eventstore.events2`,
+				query: `SELECT created_at, event_type, "sequence", "position", payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision, payload_encoding, compressed_payload, metadata, causation_id, correlation_id FROM eventstore.events2`,
 				expected: []eventstore.Event{
 					&repository.Event{AggregateID: "hodor", AggregateType: "user", Seq: 5, Pos: 0, Data: nil, Version: "v1"},
 				},
 			},
 			fields: fields{
-				dbRow: []interface{}{time.Time{}, eventstore.EventType(""), uint64(5), sql.NullFloat64{Float64: 0, Valid: false}, sql.RawBytes(nil), "", sql.NullString{}, "", eventstore.AggregateType("user"), "hodor", uint8(1)},
+				dbRow: []interface{}{time.Time{}, eventstore.EventType(""), uint64(5), sql.NullFloat64{Float64: 0, Valid: false}, sql.RawBytes(nil), "", sql.NullString{}, "", eventstore.AggregateType("user"), "hodor", uint8(1), sql.NullInt16{}, []byte(nil), []byte(nil), sql.NullString{}, sql.NullString{}},
 			},
 		},
 		{
@@ -0,0 +1,35 @@
+package eventstore
+
+import (
+	"fmt"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// ErrRetriesExhausted is the cause of the errors returned if a push failed with retryable errors
+// until no retry was left, either because the max retries or the retry budget were exhausted.
+// It distinguishes a transient overload of the database from errors which fail on every attempt.
+type ErrRetriesExhausted struct {
+	// Attempts is the count of pushes including the first one
+	Attempts int
+	// SQLState is the state of the error of the last attempt
+	SQLState string
+	// Err is the error of the last attempt
+	Err error
+}
+
+func (e *ErrRetriesExhausted) Error() string {
+	return fmt.Sprintf("push failed after %d attempts (SQLSTATE %s): %v", e.Attempts, e.SQLState, e.Err)
+}
+
+func (e *ErrRetriesExhausted) Unwrap() error {
+	return e.Err
+}
+
+// throwRetriesExhausted returns the error for a push which failed on all its attempts
+func throwRetriesExhausted(attempts int, sqlState string, err error) error {
+	return zerrors.ThrowUnavailable(
+		&ErrRetriesExhausted{Attempts: attempts, SQLState: sqlState, Err: err},
+		"V2-Rt5Exh", "Errors.Eventstore.RetriesExhausted",
+	)
+}
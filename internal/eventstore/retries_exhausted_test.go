@@ -0,0 +1,79 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestEventstore_Push_retriesExhausted(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxRetries   int
+		budget       RetryBudgetConfig
+		wantAttempts int
+	}{
+		{
+			name:         "max retries",
+			maxRetries:   3,
+			wantAttempts: 4,
+		},
+		{
+			name:         "retry disabled",
+			maxRetries:   0,
+			wantAttempts: 1,
+		},
+		{
+			name:       "retry budget",
+			maxRetries: 3,
+			budget: RetryBudgetConfig{
+				Retries: 1,
+				Window:  time.Hour,
+			},
+			wantAttempts: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pusher := new(contendedPusher)
+			es := NewEventstore(&Config{
+				MaxRetries:  uint32(tt.maxRetries),
+				RetryBudget: tt.budget,
+				Pusher:      pusher,
+			})
+
+			_, err := es.Push(context.Background(), newTestEvent("1", "", func() interface{} { return []byte(nil) }, false))
+			assert.True(t, zerrors.IsUnavailable(err), "unexpected error: %v", err)
+			var exhausted *ErrRetriesExhausted
+			require.ErrorAs(t, err, &exhausted)
+			assert.Equal(t, tt.wantAttempts, exhausted.Attempts)
+			assert.Equal(t, "23505", exhausted.SQLState)
+			assert.Equal(t, int32(tt.wantAttempts), pusher.calls.Load())
+
+			// the error of the last attempt is kept
+			var pgErr *pgconn.PgError
+			assert.True(t, errors.As(err, &pgErr))
+		})
+	}
+}
+
+func TestEventstore_Push_notRetryable(t *testing.T) {
+	es := NewEventstore(&Config{
+		MaxRetries: 3,
+		Pusher: &testPusher{
+			t:    t,
+			errs: []error{zerrors.ThrowInternal(nil, "TEST-Nr7tRy", "Errors.Internal")},
+		},
+	})
+	_, err := es.Push(context.Background(), newTestEvent("1", "", func() interface{} { return []byte(nil) }, false))
+	assert.True(t, zerrors.IsInternal(err), "unexpected error: %v", err)
+	var exhausted *ErrRetriesExhausted
+	assert.False(t, errors.As(err, &exhausted))
+}
@@ -0,0 +1,59 @@
+package eventstore
+
+import (
+	"sync"
+	"time"
+)
+
+// RetryBudgetConfig limits the push retries of all callers of the eventstore within a time window.
+// If Retries is zero the budget is unlimited and each push retries up to MaxRetries times.
+type RetryBudgetConfig struct {
+	// Retries is the amount of retries allowed within the window
+	Retries uint32
+	// Window is the duration after which the budget is refilled
+	Window time.Duration
+}
+
+// retryBudget is a fixed window counter shared by all pushes of an eventstore.
+// A nil budget allows every retry.
+type retryBudget struct {
+	mu          sync.Mutex
+	retries     int
+	window      time.Duration
+	windowStart time.Time
+	used        int
+
+	now func() time.Time
+}
+
+func newRetryBudget(config RetryBudgetConfig) *retryBudget {
+	if config.Retries == 0 || config.Window <= 0 {
+		return nil
+	}
+	return &retryBudget{
+		retries: int(config.Retries),
+		window:  config.Window,
+		now:     time.Now,
+	}
+}
+
+// take consumes one retry of the budget
+// and returns false if the budget of the current window is exhausted
+func (b *retryBudget) take() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if now.Sub(b.windowStart) >= b.window {
+		b.windowStart = now
+		b.used = 0
+	}
+	if b.used >= b.retries {
+		return false
+	}
+	b.used++
+	return true
+}
@@ -0,0 +1,99 @@
+package eventstore
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// contendedPusher always fails with a collision of the sequence
+type contendedPusher struct {
+	calls atomic.Int32
+}
+
+func (p *contendedPusher) Health(ctx context.Context) error {
+	return nil
+}
+
+func (p *contendedPusher) Push(ctx context.Context, commands ...Command) ([]Event, error) {
+	p.calls.Add(1)
+	return nil, zerrors.ThrowInternal(&pgconn.PgError{
+		ConstraintName: "events2_pkey",
+		Code:           "23505",
+	}, "TEST-Ew2cK", "Errors.Internal")
+}
+
+func TestEventstore_Push_retryBudget(t *testing.T) {
+	const (
+		pushes     = 50
+		maxRetries = 5
+	)
+	tests := []struct {
+		name      string
+		budget    RetryBudgetConfig
+		wantCalls int32
+	}{
+		{
+			name:      "unlimited",
+			budget:    RetryBudgetConfig{},
+			wantCalls: pushes * (maxRetries + 1),
+		},
+		{
+			name: "limited",
+			budget: RetryBudgetConfig{
+				Retries: 10,
+				Window:  time.Hour,
+			},
+			wantCalls: pushes + 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pusher := new(contendedPusher)
+			es := NewEventstore(&Config{
+				MaxRetries:  maxRetries,
+				RetryBudget: tt.budget,
+				Pusher:      pusher,
+			})
+
+			var wg sync.WaitGroup
+			for i := 0; i < pushes; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := es.Push(context.Background(), newTestEvent("1", "", func() interface{} { return []byte(nil) }, false))
+					assert.Error(t, err)
+				}()
+			}
+			wg.Wait()
+
+			assert.Equal(t, tt.wantCalls, pusher.calls.Load())
+		})
+	}
+}
+
+func Test_retryBudget_take(t *testing.T) {
+	now := time.Now()
+	budget := newRetryBudget(RetryBudgetConfig{Retries: 2, Window: time.Second})
+	budget.now = func() time.Time { return now }
+
+	assert.True(t, budget.take())
+	assert.True(t, budget.take())
+	assert.False(t, budget.take(), "budget of the window exhausted")
+
+	now = now.Add(time.Second)
+	assert.True(t, budget.take(), "budget refilled in new window")
+}
+
+func Test_retryBudget_nil(t *testing.T) {
+	assert.Nil(t, newRetryBudget(RetryBudgetConfig{}))
+	var budget *retryBudget
+	assert.True(t, budget.take())
+}
@@ -0,0 +1,59 @@
+package eventstore
+
+import (
+	"context"
+)
+
+var _ SerializedPayloadCommand = (*SerializedCommand)(nil)
+
+// PayloadContentType is the serialization of the payload of a [SerializedPayloadCommand]
+type PayloadContentType int
+
+const (
+	// PayloadContentTypeJSON payloads are JSON
+	PayloadContentTypeJSON PayloadContentType = iota
+	// PayloadContentTypeProtobuf payloads are serialized protobuf messages
+	PayloadContentTypeProtobuf
+)
+
+// SerializedPayloadCommand is implemented by commands whose payload is already serialized,
+// the eventstore stores the payload as it is instead of marshaling [Command.Payload].
+type SerializedPayloadCommand interface {
+	Command
+	// SerializedPayload returns the serialized payload and its content type
+	SerializedPayload() (payload []byte, contentType PayloadContentType)
+}
+
+// SerializedCommand is a command of producers which already have the serialized payload of the event,
+// it saves marshaling the payload again on push
+type SerializedCommand struct {
+	*BaseEvent
+
+	Data        []byte
+	ContentType PayloadContentType
+	Constraints []*UniqueConstraint
+}
+
+// NewSerializedCommand returns the command of the event with the serialized payload
+func NewSerializedCommand(ctx context.Context, aggregate *Aggregate, typ EventType, payload []byte, contentType PayloadContentType) *SerializedCommand {
+	return &SerializedCommand{
+		BaseEvent:   NewBaseEventForPush(ctx, aggregate, typ),
+		Data:        payload,
+		ContentType: contentType,
+	}
+}
+
+// Payload implements [Command], it returns the serialized payload
+func (c *SerializedCommand) Payload() any {
+	return c.Data
+}
+
+// UniqueConstraints implements [Command]
+func (c *SerializedCommand) UniqueConstraints() []*UniqueConstraint {
+	return c.Constraints
+}
+
+// SerializedPayload implements [SerializedPayloadCommand]
+func (c *SerializedCommand) SerializedPayload() ([]byte, PayloadContentType) {
+	return c.Data, c.ContentType
+}
@@ -11,6 +11,11 @@ type UniqueConstraint struct {
 	ErrorMessage string
 	// IsGlobal defines if the unique constraint is globally unique or just within a single instance
 	IsGlobal bool
+	// ConditionWasMet is the state of the condition of a conditional unique constraint before the command
+	ConditionWasMet bool
+	// ConditionMet is the state of the condition of a conditional unique constraint after the command,
+	// the unique field is reserved while the condition is met and released otherwise
+	ConditionMet bool
 }
 
 type UniqueConstraintAction int8
@@ -19,6 +24,8 @@ const (
 	UniqueConstraintAdd UniqueConstraintAction = iota
 	UniqueConstraintRemove
 	UniqueConstraintInstanceRemove
+	// UniqueConstraintConditional reserves or releases the unique field depending on [UniqueConstraint.ConditionMet]
+	UniqueConstraintConditional
 
 	uniqueConstraintActionCount
 )
@@ -78,3 +85,25 @@ func NewRemoveGlobalUniqueConstraint(
 		Action:      UniqueConstraintRemove,
 	}
 }
+
+// NewConditionalUniqueConstraint returns a unique constraint which only applies while a condition is met,
+// like a partial unique index, e.g. user names which are only unique among the active users.
+// The command passes the state of the condition before and after the command:
+// if the condition becomes met the unique field is reserved, if it is no longer met the field is released and can be reused.
+// If the state does not change, the field is neither reserved nor released.
+// If the condition of the same field changes multiple times within a push, only the net change applies.
+func NewConditionalUniqueConstraint(
+	uniqueType,
+	uniqueField,
+	errMessage string,
+	conditionWasMet,
+	conditionMet bool) *UniqueConstraint {
+	return &UniqueConstraint{
+		UniqueType:      uniqueType,
+		UniqueField:     uniqueField,
+		ErrorMessage:    errMessage,
+		ConditionWasMet: conditionWasMet,
+		ConditionMet:    conditionMet,
+		Action:          UniqueConstraintConditional,
+	}
+}
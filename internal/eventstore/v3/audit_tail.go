@@ -0,0 +1,93 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+//go:embed audit_tail.sql
+var auditTailStmt string
+
+// AuditTailQuery describes a page of [Eventstore.AuditTail].
+type AuditTailQuery struct {
+	InstanceID    string
+	AggregateType eventstore.AggregateType
+	// From is the inclusive lower bound of the creation date of the events
+	From time.Time
+	// To is the exclusive upper bound of the creation date of the events
+	To time.Time
+	// Before is the position of the last event of the previous page, nil for the first page
+	Before *AuditTailCursor
+	Limit  uint32
+}
+
+// AuditTailCursor is the position of an event in the result of [Eventstore.AuditTail].
+// The creation date is not unique, events of the same transaction share it,
+// so the aggregate id and the sequence break the tie.
+type AuditTailCursor struct {
+	CreatedAt   time.Time
+	AggregateID string
+	Sequence    uint64
+}
+
+// AuditTailCursorOf returns the cursor of the event to request the next page
+func AuditTailCursorOf(event eventstore.Event) *AuditTailCursor {
+	return &AuditTailCursor{
+		CreatedAt:   event.CreatedAt(),
+		AggregateID: event.Aggregate().ID,
+		Sequence:    event.Sequence(),
+	}
+}
+
+// AuditTail returns the events of all aggregates of the type created within the time range, newest first.
+// It is intended for activity feeds of auditors.
+// The next page is requested with the cursor of the last returned event as [AuditTailQuery.Before],
+// less events than the limit mean there are no more pages.
+func (es *Eventstore) AuditTail(ctx context.Context, query *AuditTailQuery) (events []eventstore.Event, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if query.AggregateType == "" || query.Limit == 0 || query.To.IsZero() || !query.From.Before(query.To) {
+		return nil, zerrors.ThrowInvalidArgument(nil, "V3-Ta1lQ", "Errors.Eventstore.InvalidQuery")
+	}
+	// the upper bound of the time range is the cursor of the first page,
+	// events created at the upper bound are excluded as every aggregate id is greater than the empty one
+	before := &AuditTailCursor{CreatedAt: query.To}
+	if query.Before != nil && query.Before.CreatedAt.Before(query.To) {
+		before = query.Before
+	}
+
+	events = make([]eventstore.Event, 0, query.Limit)
+	err = es.client.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			for rows.Next() {
+				var aggregateID string
+				e, err := scanEvent(rows, string(query.AggregateType), "", query.InstanceID, &aggregateID)
+				if err != nil {
+					return err
+				}
+				e.aggregate.ID = aggregateID
+				events = append(events, e)
+			}
+			return nil
+		},
+		auditTailStmt,
+		query.InstanceID,
+		string(query.AggregateType),
+		query.From,
+		before.CreatedAt,
+		before.AggregateID,
+		before.Sequence,
+		query.Limit,
+	)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "V3-Ta1lS", "Errors.Internal")
+	}
+	return events, nil
+}
@@ -0,0 +1,27 @@
+SELECT
+    created_at
+    , event_type
+    , "sequence"
+    , "position"
+    , payload
+    , creator
+    , "owner"
+    , revision
+    , payload_encoding
+    , compressed_payload
+    , metadata
+    , causation_id
+    , correlation_id
+    , aggregate_id
+FROM
+    eventstore.events2
+WHERE
+    instance_id = $1
+    AND aggregate_type = $2
+    AND created_at >= $3
+    AND (created_at, aggregate_id, "sequence") < ($4, $5, $6)
+ORDER BY
+    created_at DESC
+    , aggregate_id DESC
+    , "sequence" DESC
+LIMIT $7;
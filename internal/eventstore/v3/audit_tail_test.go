@@ -0,0 +1,161 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+var auditTailColumns = append(append([]string{}, filterColumns...), "aggregate_id")
+
+func auditTailRow(createdAt time.Time, aggregateID string, sequence uint64) []driver.Value {
+	return []driver.Value{createdAt, "user.added", sequence, float64(sequence), []byte(`{}`), "creator", "ro", int64(1), nil, nil, nil, nil, nil, aggregateID}
+}
+
+func TestEventstore_AuditTail(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	newest := from.Add(3 * time.Hour)
+	oldest := from.Add(time.Hour)
+
+	tests := []struct {
+		name         string
+		query        *AuditTailQuery
+		expectations []mock.Expectation
+		want         []*AuditTailCursor
+		wantErr      func(error) bool
+	}{
+		{
+			name:    "no limit",
+			query:   &AuditTailQuery{InstanceID: "instance", AggregateType: "user", From: from, To: to},
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+		{
+			name:    "no aggregate type",
+			query:   &AuditTailQuery{InstanceID: "instance", From: from, To: to, Limit: 10},
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+		{
+			name:    "empty time range",
+			query:   &AuditTailQuery{InstanceID: "instance", AggregateType: "user", From: to, To: to, Limit: 10},
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+		{
+			name:    "no upper bound",
+			query:   &AuditTailQuery{InstanceID: "instance", AggregateType: "user", From: from, Limit: 10},
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+		{
+			name:  "first page, bounded by the time range",
+			query: &AuditTailQuery{InstanceID: "instance", AggregateType: "user", From: from, To: to, Limit: 3},
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery(auditTailStmt,
+					mock.WithQueryArgs("instance", "user", from, to, "", uint64(0), uint32(3)),
+					mock.WithQueryResult(auditTailColumns, [][]driver.Value{
+						auditTailRow(newest, "user-2", 1),
+						auditTailRow(oldest, "user-1", 2),
+						auditTailRow(oldest, "user-1", 1),
+					}),
+				),
+				mock.ExpectCommit(nil),
+			},
+			want: []*AuditTailCursor{
+				{CreatedAt: newest, AggregateID: "user-2", Sequence: 1},
+				{CreatedAt: oldest, AggregateID: "user-1", Sequence: 2},
+				{CreatedAt: oldest, AggregateID: "user-1", Sequence: 1},
+			},
+		},
+		{
+			name: "next page, continues before the cursor",
+			query: &AuditTailQuery{
+				InstanceID:    "instance",
+				AggregateType: "user",
+				From:          from,
+				To:            to,
+				Before:        &AuditTailCursor{CreatedAt: oldest, AggregateID: "user-1", Sequence: 2},
+				Limit:         3,
+			},
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery(auditTailStmt,
+					mock.WithQueryArgs("instance", "user", from, oldest, "user-1", uint64(2), uint32(3)),
+					mock.WithQueryResult(auditTailColumns, [][]driver.Value{
+						auditTailRow(oldest, "user-1", 1),
+					}),
+				),
+				mock.ExpectCommit(nil),
+			},
+			want: []*AuditTailCursor{
+				{CreatedAt: oldest, AggregateID: "user-1", Sequence: 1},
+			},
+		},
+		{
+			name: "cursor after the time range, bounded by the time range",
+			query: &AuditTailQuery{
+				InstanceID:    "instance",
+				AggregateType: "user",
+				From:          from,
+				To:            oldest,
+				Before:        &AuditTailCursor{CreatedAt: newest, AggregateID: "user-2", Sequence: 1},
+				Limit:         3,
+			},
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery(auditTailStmt,
+					mock.WithQueryArgs("instance", "user", from, oldest, "", uint64(0), uint32(3)),
+					mock.WithQueryResult(auditTailColumns, nil),
+				),
+				mock.ExpectCommit(nil),
+			},
+			want: []*AuditTailCursor{},
+		},
+		{
+			name:  "query fails",
+			query: &AuditTailQuery{InstanceID: "instance", AggregateType: "user", From: from, To: to, Limit: 3},
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery(auditTailStmt,
+					mock.WithQueryErr(errors.New("connection lost")),
+				),
+				mock.ExpectRollback(nil),
+			},
+			wantErr: zerrors.IsInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlMock := mock.NewSQLMock(t, tt.expectations...)
+			defer sqlMock.Assert(t)
+			es := NewEventstore(
+				&database.DB{DB: sqlMock.DB, Database: new(cockroach.Config)},
+				WithMetrics(new(testMetrics)),
+			)
+
+			got, err := es.AuditTail(context.Background(), tt.query)
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			cursors := make([]*AuditTailCursor, len(got))
+			for i, e := range got {
+				assert.Equal(t, eventstore.AggregateType("user"), e.Aggregate().Type)
+				assert.Equal(t, "instance", e.Aggregate().InstanceID)
+				cursors[i] = AuditTailCursorOf(e)
+			}
+			assert.Equal(t, tt.want, cursors)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package eventstore
+
+import (
+	"encoding/json"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+var (
+	_ PayloadCodec = JSONCodec{}
+	_ PayloadCodec = ProtobufCodec{}
+)
+
+// PayloadCodec serializes the payloads of the commands in [commandToEvent].
+// The encoding is stored per event, so events of different codecs can be read from the same aggregate.
+type PayloadCodec interface {
+	// Marshal serializes the payload and returns the encoding it is stored with
+	Marshal(payload any) (Payload, repository.PayloadEncoding, error)
+}
+
+// JSONCodec serializes all payloads as JSON, it is the default codec
+type JSONCodec struct{}
+
+// Marshal implements [PayloadCodec]
+func (JSONCodec) Marshal(payload any) (Payload, repository.PayloadEncoding, error) {
+	data, err := json.Marshal(payload)
+	return data, repository.PayloadEncodingJSON, err
+}
+
+// ProtobufCodec serializes payloads which are a [proto.Message] as protobuf.
+// All other payloads are serialized as JSON, so commands can be migrated one by one.
+type ProtobufCodec struct{}
+
+// Marshal implements [PayloadCodec]
+func (ProtobufCodec) Marshal(payload any) (Payload, repository.PayloadEncoding, error) {
+	message, ok := payload.(proto.Message)
+	if !ok {
+		return JSONCodec{}.Marshal(payload)
+	}
+	data, err := proto.Marshal(message)
+	return data, repository.PayloadEncodingProtobuf, err
+}
@@ -0,0 +1,227 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/apipb"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+func testProtoPayload() *apipb.Method {
+	return &apipb.Method{
+		Name:              "AddTarget",
+		RequestTypeUrl:    "type.googleapis.com/zitadel.action.v3alpha.AddTargetRequest",
+		ResponseTypeUrl:   "type.googleapis.com/zitadel.action.v3alpha.AddTargetResponse",
+		ResponseStreaming: true,
+	}
+}
+
+func TestPayloadCodec_Marshal(t *testing.T) {
+	protoPayload := testProtoPayload()
+	protoMarshalled, err := proto.Marshal(protoPayload)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		codec        PayloadCodec
+		payload      any
+		wantPayload  Payload
+		wantEncoding repository.PayloadEncoding
+	}{
+		{
+			name:         "json",
+			codec:        JSONCodec{},
+			payload:      map[string]string{"key": "value"},
+			wantPayload:  Payload(`{"key":"value"}`),
+			wantEncoding: repository.PayloadEncodingJSON,
+		},
+		{
+			name:         "protobuf, message",
+			codec:        ProtobufCodec{},
+			payload:      protoPayload,
+			wantPayload:  protoMarshalled,
+			wantEncoding: repository.PayloadEncodingProtobuf,
+		},
+		{
+			name:         "protobuf, no message",
+			codec:        ProtobufCodec{},
+			payload:      map[string]string{"key": "value"},
+			wantPayload:  Payload(`{"key":"value"}`),
+			wantEncoding: repository.PayloadEncodingJSON,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, encoding, err := tt.codec.Marshal(tt.payload)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPayload, payload)
+			assert.Equal(t, tt.wantEncoding, encoding)
+		})
+	}
+}
+
+func Test_mapCommands_protobufCodec(t *testing.T) {
+	es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithPayloadCodec(ProtobufCodec{}))
+
+	commands := []eventstore.Command{
+		&mockCommand{aggregate: mockAggregate("V3-Pb4cD"), payload: testProtoPayload()},
+		&mockCommand{aggregate: mockAggregate("V3-Pb4cD"), payload: map[string]string{"key": "value"}},
+	}
+	events, placeholders, args, err := es.mapCommands(context.Background(), commands, []*latestSequence{
+		{aggregate: mockAggregate("V3-Pb4cD")},
+	})
+	require.NoError(t, err)
+
+	// the payload encoding is written for all events
+	assert.Equal(t, []string{
+		"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10, $11, $12, $13, $14, $15)",
+		"($16, $17, $18, $19, $20, $21, $22, $23, $24, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $25, $26, $27, $28, $29, $30)",
+	}, placeholders)
+	require.Len(t, args, 2*argsPerCompressedCommand)
+
+	// protobuf payloads are moved to the compressed column
+	protoArgs := args[:argsPerCompressedCommand]
+	assert.Nil(t, protoArgs[7])
+	assert.Equal(t, int16(repository.PayloadEncodingProtobuf), protoArgs[13])
+	assert.Equal(t, []byte(events[0].DataAsBytes()), protoArgs[14])
+
+	// other payloads stay JSON
+	jsonArgs := args[argsPerCompressedCommand:]
+	assert.Equal(t, Payload(`{"key":"value"}`), jsonArgs[7])
+	assert.Equal(t, int16(repository.PayloadEncodingJSON), jsonArgs[13])
+	assert.Nil(t, jsonArgs[14])
+}
+
+func TestEventstore_Filter_mixedCodecs(t *testing.T) {
+	// sets the [pushCompressedPlaceholderFmt] used by mapCommands
+	es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithPayloadCodec(ProtobufCodec{}), WithPayloadCompression(32))
+
+	commands := []eventstore.Command{
+		&mockCommand{aggregate: mockAggregate("V3-Mx7cD"), payload: testProtoPayload()},
+		&mockCommand{aggregate: mockAggregate("V3-Mx7cD"), payload: map[string]string{"key": "value"}},
+		&mockCommand{aggregate: mockAggregate("V3-Mx7cD"), payload: map[string]string{"key": "a value above the compression threshold"}},
+		&mockCommand{aggregate: mockAggregate("V3-Mx7cD")},
+	}
+	_, _, args, err := es.mapCommands(context.Background(), commands, []*latestSequence{
+		{aggregate: mockAggregate("V3-Mx7cD")},
+	})
+	require.NoError(t, err)
+
+	// the rows are read back as they were written
+	rows := make([][]driver.Value, len(commands))
+	for i := range commands {
+		row := args[i*argsPerCompressedCommand : (i+1)*argsPerCompressedCommand]
+		rows[i] = []driver.Value{
+			time.Now(),
+			string(commands[i].Type()),
+			row[8],
+			float64(1),
+			payloadValue(row[7].(Payload)),
+			row[5],
+			row[1],
+			row[4],
+			row[13],
+			row[14],
+			nil,
+			nil,
+			nil,
+		}
+	}
+	sqlMock := mock.NewSQLMock(t,
+		mock.ExpectBegin(nil),
+		mock.ExpectQuery(filterStmt,
+			mock.WithQueryArgs("instance", "type", "V3-Mx7cD"),
+			mock.WithQueryResult(filterColumns, rows),
+		),
+		mock.ExpectCommit(nil),
+	)
+	defer sqlMock.Assert(t)
+	es.client = &database.DB{DB: sqlMock.DB, Database: new(cockroach.Config)}
+
+	events, err := es.Filter(context.Background(), "type", "V3-Mx7cD", "instance")
+	require.NoError(t, err)
+	require.Len(t, events, len(commands))
+
+	protoPayload := new(apipb.Method)
+	require.NoError(t, events[0].Unmarshal(protoPayload))
+	assert.True(t, proto.Equal(testProtoPayload(), protoPayload))
+
+	for i, want := range []map[string]string{{"key": "value"}, {"key": "a value above the compression threshold"}} {
+		var payload map[string]string
+		require.NoError(t, events[i+1].Unmarshal(&payload))
+		assert.Equal(t, want, payload)
+	}
+
+	var payload map[string]string
+	require.NoError(t, events[3].Unmarshal(&payload))
+	assert.Nil(t, payload)
+
+	// protobuf payloads require a message
+	assert.Error(t, events[0].Unmarshal(&payload))
+}
+
+// payloadValue returns the value of the payload as it is read from the database
+func payloadValue(payload Payload) driver.Value {
+	if payload == nil {
+		return nil
+	}
+	return []byte(payload)
+}
+
+func BenchmarkPayloadCodec_Marshal(b *testing.B) {
+	payload := testProtoPayload()
+	codecs := []struct {
+		name  string
+		codec PayloadCodec
+	}{
+		{name: "json", codec: JSONCodec{}},
+		{name: "protobuf", codec: ProtobufCodec{}},
+	}
+	for _, c := range codecs {
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := c.codec.Marshal(payload); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkPayloadCodec_Unmarshal(b *testing.B) {
+	codecs := []struct {
+		name  string
+		codec PayloadCodec
+	}{
+		{name: "json", codec: JSONCodec{}},
+		{name: "protobuf", codec: ProtobufCodec{}},
+	}
+	for _, c := range codecs {
+		b.Run(c.name, func(b *testing.B) {
+			payload, encoding, err := c.codec.Marshal(testProtoPayload())
+			if err != nil {
+				b.Fatal(err)
+			}
+			e := &event{payload: payload, encoding: encoding}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := e.Unmarshal(new(apipb.Method)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package eventstore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+// ConfigOptions returns the options of the push config of the eventstore
+func ConfigOptions(config eventstore.PushConfig) ([]Option, error) {
+	codec, err := payloadCodec(config.PayloadCodec)
+	if err != nil {
+		return nil, err
+	}
+	opts := []Option{
+		WithAcquireTimeout(config.AcquireTimeout),
+		WithPayloadCompression(config.CompressionThreshold),
+		WithPayloadCodec(codec),
+		WithMaxPayloadSize(config.MaxPayloadSize),
+	}
+	if config.ValidatePayloads {
+		opts = append(opts, WithPayloadValidation())
+	}
+	if config.SkipSingleCommandSavepoint {
+		opts = append(opts, WithoutSingleCommandSavepoint())
+	}
+	if config.DeduplicationWindow > 0 {
+		opts = append(opts, WithDeduplication(config.DeduplicationWindow))
+	}
+	if len(config.Redactions) > 0 {
+		opts = append(opts, WithRedactionPolicy(redactionPolicy(config.Redactions)))
+	}
+	return opts, nil
+}
+
+// redactionPolicy merges the configured redactions per event type
+func redactionPolicy(redactions []eventstore.RedactionConfig) RedactionPolicy {
+	policy := make(RedactionPolicy, len(redactions))
+	for _, redaction := range redactions {
+		eventType := eventstore.EventType(redaction.EventType)
+		policy[eventType] = append(policy[eventType], redaction.Fields...)
+	}
+	return policy
+}
+
+// payloadCodec returns the codec of the configured name, [JSONCodec] if none is configured
+func payloadCodec(name string) (PayloadCodec, error) {
+	switch strings.ToLower(name) {
+	case "", "json":
+		return JSONCodec{}, nil
+	case "protobuf":
+		return ProtobufCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unknown payload codec %q", name)
+	}
+}
@@ -0,0 +1,111 @@
+package eventstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+func TestConfigOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  eventstore.PushConfig
+		want    *Eventstore
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			want: &Eventstore{codec: JSONCodec{}},
+		},
+		{
+			name: "acquire timeout",
+			config: eventstore.PushConfig{
+				AcquireTimeout: time.Second,
+			},
+			want: &Eventstore{acquireTimeout: time.Second, codec: JSONCodec{}},
+		},
+		{
+			name: "validate payloads",
+			config: eventstore.PushConfig{
+				ValidatePayloads: true,
+			},
+			want: &Eventstore{validatePayloads: true, codec: JSONCodec{}},
+		},
+		{
+			name: "compression threshold",
+			config: eventstore.PushConfig{
+				CompressionThreshold: 1024,
+			},
+			want: &Eventstore{compressionThreshold: 1024, codec: JSONCodec{}},
+		},
+		{
+			name: "protobuf codec",
+			config: eventstore.PushConfig{
+				PayloadCodec: "protobuf",
+			},
+			want: &Eventstore{codec: ProtobufCodec{}},
+		},
+		{
+			name: "deduplication window",
+			config: eventstore.PushConfig{
+				DeduplicationWindow: time.Second,
+			},
+			want: &Eventstore{codec: JSONCodec{}, dedup: newPushDeduplicator(time.Second, clock.New())},
+		},
+		{
+			name: "skip single command savepoint",
+			config: eventstore.PushConfig{
+				SkipSingleCommandSavepoint: true,
+			},
+			want: &Eventstore{codec: JSONCodec{}, skipSingleCommandSavepoint: true},
+		},
+		{
+			name: "max payload size",
+			config: eventstore.PushConfig{
+				MaxPayloadSize: 1 << 20,
+			},
+			want: &Eventstore{codec: JSONCodec{}, maxPayloadSize: 1 << 20},
+		},
+		{
+			name: "redactions",
+			config: eventstore.PushConfig{
+				Redactions: []eventstore.RedactionConfig{
+					{EventType: "user.added", Fields: []string{"email"}},
+					{EventType: "user.changed", Fields: []string{"profile.nickName"}},
+					{EventType: "user.added", Fields: []string{"phone"}},
+				},
+			},
+			want: &Eventstore{codec: JSONCodec{}, redaction: RedactionPolicy{
+				"user.added":   {"email", "phone"},
+				"user.changed": {"profile.nickName"},
+			}},
+		},
+		{
+			name: "unknown codec",
+			config: eventstore.PushConfig{
+				PayloadCodec: "xml",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := ConfigOptions(tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			es := new(Eventstore)
+			for _, opt := range opts {
+				opt(es)
+			}
+			assert.Equal(t, tt.want, es)
+		})
+	}
+}
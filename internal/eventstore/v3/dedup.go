@@ -0,0 +1,159 @@
+package eventstore
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// WithDeduplication suppresses commands of [Eventstore.Push] which are identical to an event written within the window,
+// e.g. the events of a double click. Commands are identical if their aggregate, event type and serialized payload are equal.
+// Instead of writing a suppressed command again, Push returns the event written before.
+//
+// A push is either suppressed as a whole or written as a whole, so the commands of a push stay atomic.
+// It is only suppressed if each of its commands is identical to the latest event written to its aggregate,
+// so a command is written again as soon as another event was written to the aggregate in between,
+// e.g. a lock after a lock and an unlock.
+//
+// The events are remembered in memory of the eventstore only after the push committed,
+// so identical pushes on other instances of the eventstore or running concurrently are still written.
+// Events written by other instances of the eventstore are not known, so deduplication must only be enabled
+// if a single instance writes to the aggregates or if suppressing such a push is acceptable.
+// In contrast to idempotency keys, the caller does not have to provide a key.
+func WithDeduplication(window time.Duration) Option {
+	return func(es *Eventstore) {
+		es.dedup = newPushDeduplicator(window, clock.New())
+	}
+}
+
+// pushDeduplicator remembers the events written by [Eventstore.Push] within the window by the hash of their content
+// and the latest sequence written to each aggregate
+type pushDeduplicator struct {
+	window time.Duration
+	clock  clock.Clock
+
+	mu     sync.Mutex
+	recent map[string]*recentEvent
+	latest map[aggregateKey]*recentSequence
+}
+
+type recentEvent struct {
+	event     eventstore.Event
+	writtenAt time.Time
+}
+
+type aggregateKey struct {
+	instanceID    string
+	aggregateType eventstore.AggregateType
+	aggregateID   string
+}
+
+func newAggregateKey(aggregate *eventstore.Aggregate) aggregateKey {
+	return aggregateKey{
+		instanceID:    aggregate.InstanceID,
+		aggregateType: aggregate.Type,
+		aggregateID:   aggregate.ID,
+	}
+}
+
+type recentSequence struct {
+	sequence  uint64
+	writtenAt time.Time
+}
+
+func newPushDeduplicator(window time.Duration, clock clock.Clock) *pushDeduplicator {
+	return &pushDeduplicator{
+		window: window,
+		clock:  clock,
+		recent: make(map[string]*recentEvent),
+		latest: make(map[aggregateKey]*recentSequence),
+	}
+}
+
+// contentKeys returns the hashes of the content of the commands
+func (d *pushDeduplicator) contentKeys(codec PayloadCodec, commands []eventstore.Command) ([]string, error) {
+	keys := make([]string, len(commands))
+	for i, command := range commands {
+		hash := sha256.New()
+		for _, part := range []string{
+			command.Aggregate().InstanceID,
+			string(command.Aggregate().Type),
+			command.Aggregate().ID,
+			string(command.Type()),
+		} {
+			hash.Write([]byte(part))
+			// separates the parts, so that different splits of the same string do not collide
+			hash.Write([]byte{0})
+		}
+		if command.Payload() != nil {
+			payload, _, err := codec.Marshal(command.Payload())
+			if err != nil {
+				return nil, zerrors.ThrowInternal(err, "V3-Dd3pH", "Errors.Internal")
+			}
+			hash.Write(payload)
+		}
+		keys[i] = string(hash.Sum(nil))
+	}
+	return keys, nil
+}
+
+// lookup returns the events written before if each command is identical to the latest event written to its aggregate within the window.
+// Otherwise it returns nil and all commands must be written.
+func (d *pushDeduplicator) lookup(commands []eventstore.Command, keys []string) []eventstore.Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.clock.Now()
+	events := make([]eventstore.Event, len(keys))
+	aggregates := make(map[aggregateKey]struct{}, len(commands))
+	for i, key := range keys {
+		aggregate := newAggregateKey(commands[i].Aggregate())
+		// only the latest event of an aggregate can match, so a push writing multiple events to it is never identical
+		if _, ok := aggregates[aggregate]; ok {
+			return nil
+		}
+		aggregates[aggregate] = struct{}{}
+
+		recent, ok := d.recent[key]
+		if !ok || now.Sub(recent.writtenAt) >= d.window {
+			return nil
+		}
+		latest, ok := d.latest[aggregate]
+		if !ok || latest.sequence != recent.event.Sequence() {
+			return nil
+		}
+		events[i] = recent.event
+	}
+	return events
+}
+
+// record remembers the written events and the latest sequence of their aggregates
+// and forgets the events written before the window
+func (d *pushDeduplicator) record(keys []string, events []eventstore.Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.clock.Now()
+	for key, recent := range d.recent {
+		if now.Sub(recent.writtenAt) >= d.window {
+			delete(d.recent, key)
+		}
+	}
+	for aggregate, latest := range d.latest {
+		if now.Sub(latest.writtenAt) >= d.window {
+			delete(d.latest, aggregate)
+		}
+	}
+	for i, key := range keys {
+		d.recent[key] = &recentEvent{event: events[i], writtenAt: now}
+		aggregate := newAggregateKey(events[i].Aggregate())
+		if latest, ok := d.latest[aggregate]; !ok || latest.sequence < events[i].Sequence() {
+			d.latest[aggregate] = &recentSequence{sequence: events[i].Sequence(), writtenAt: now}
+		}
+	}
+}
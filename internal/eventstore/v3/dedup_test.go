@@ -0,0 +1,195 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+const dedupWindow = time.Second
+
+// expectPush expects the push of the amount of commands on the aggregate with the latest sequence
+func expectPush(aggregate *eventstore.Aggregate, latest uint64, commands int, insertOpts ...mock.QueryOpt) []mock.Expectation {
+	sequenceConditions, _ := sequencesToSql([]*latestSequence{{aggregate: aggregate}})
+	placeholders := make([]string, commands)
+	rows := make([][]driver.Value, commands)
+	for i := range placeholders {
+		placeholders[i] = fmt.Sprintf(pushPlaceholderFmt, placeholderIndexes(i*argsPerCommand, argsPerCommand)...)
+		rows[i] = []driver.Value{time.Now(), float64(1)}
+	}
+	if len(insertOpts) == 0 {
+		insertOpts = []mock.QueryOpt{mock.WithQueryResult([]string{"created_at", "position"}, rows)}
+	}
+	return []mock.Expectation{
+		mock.ExpectQuery(
+			fmt.Sprintf(latestSequencesStmt, strings.Join(sequenceConditions, " UNION ALL ")),
+			mock.WithQueryResult(
+				[]string{"instance_id", "owner", "aggregate_type", "aggregate_id", "sequence"},
+				[][]driver.Value{{aggregate.InstanceID, aggregate.ResourceOwner, string(aggregate.Type), aggregate.ID, latest}},
+			),
+		),
+		mock.ExpectQuery(fmt.Sprintf(pushStmt, strings.Join(placeholders, ", ")), insertOpts...),
+	}
+}
+
+// newDedupEventstore returns an eventstore with deduplication pushing to the expectations
+func newDedupEventstore(t *testing.T, expectations ...[]mock.Expectation) (*Eventstore, *clock.Mock) {
+	// sets the [pushPlaceholderFmt] used in the expectations
+	NewEventstore(&database.DB{Database: new(cockroach.Config)})
+
+	var all []mock.Expectation
+	for _, e := range expectations {
+		all = append(all, e...)
+	}
+	sqlMock := mock.NewSQLMock(t, all...)
+	t.Cleanup(func() { sqlMock.Assert(t) })
+
+	es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithMetrics(new(testMetrics)), WithDeduplication(dedupWindow))
+	es.txBeginner = &fakeTxBeginner{tx: &fakeTx{queries: sqlMock.DB}}
+	mockClock := clock.NewMock()
+	es.dedup.clock = mockClock
+	return es, mockClock
+}
+
+func TestEventstore_Push_deduplication(t *testing.T) {
+	aggregate := mockAggregate("V3-Dd3pH")
+	command := &mockCommand{aggregate: aggregate, payload: map[string]string{"name": "click"}}
+
+	es, clock := newDedupEventstore(t,
+		expectPush(aggregate, 1, 1),
+		// written again after the window passed
+		expectPush(aggregate, 2, 1),
+	)
+
+	first, err := es.Push(context.Background(), command)
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+	assert.Equal(t, uint64(2), first[0].Sequence())
+
+	// rapid duplicates return the event written before
+	for _, elapsed := range []time.Duration{0, 100 * time.Millisecond, 500 * time.Millisecond} {
+		clock.Add(elapsed)
+		duplicate, err := es.Push(context.Background(), &mockCommand{aggregate: aggregate, payload: map[string]string{"name": "click"}})
+		require.NoError(t, err)
+		assert.Equal(t, first, duplicate)
+	}
+
+	clock.Add(dedupWindow)
+	later, err := es.Push(context.Background(), command)
+	require.NoError(t, err)
+	require.Len(t, later, 1)
+	assert.Equal(t, uint64(3), later[0].Sequence())
+}
+
+func TestEventstore_Push_deduplicationDistinct(t *testing.T) {
+	aggregate := mockAggregate("V3-Dd3pH")
+	other := mockAggregate("V3-Dd4pH")
+
+	es, clock := newDedupEventstore(t,
+		expectPush(aggregate, 1, 1),
+		// different payload
+		expectPush(aggregate, 2, 1),
+		// different aggregate
+		expectPush(other, 1, 1),
+		// the mixed push is written as a whole
+		expectPush(aggregate, 3, 2),
+	)
+
+	first, err := es.Push(context.Background(), &mockCommand{aggregate: aggregate, payload: map[string]string{"name": "first"}})
+	require.NoError(t, err)
+
+	clock.Add(100 * time.Millisecond)
+	second, err := es.Push(context.Background(), &mockCommand{aggregate: aggregate, payload: map[string]string{"name": "second"}})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), second[0].Sequence())
+
+	otherAggregate, err := es.Push(context.Background(), &mockCommand{aggregate: other, payload: map[string]string{"name": "first"}})
+	require.NoError(t, err)
+	assert.Equal(t, "V3-Dd4pH", otherAggregate[0].Aggregate().ID)
+
+	mixed, err := es.Push(context.Background(),
+		&mockCommand{aggregate: aggregate, payload: map[string]string{"name": "first"}},
+		&mockCommand{aggregate: aggregate, payload: map[string]string{"name": "third"}},
+	)
+	require.NoError(t, err)
+	require.Len(t, mixed, 2)
+	assert.NotEqual(t, first[0], mixed[0])
+	assert.Equal(t, uint64(4), mixed[0].Sequence())
+	assert.Equal(t, uint64(5), mixed[1].Sequence())
+}
+
+func TestEventstore_Push_deduplicationInterleaved(t *testing.T) {
+	aggregate := mockAggregate("V3-Dd3pH")
+	lock := func() eventstore.Command {
+		return &mockCommand{aggregate: aggregate, payload: map[string]string{"state": "locked"}}
+	}
+	unlock := func() eventstore.Command {
+		return &mockCommand{aggregate: aggregate, payload: map[string]string{"state": "unlocked"}}
+	}
+
+	es, clock := newDedupEventstore(t,
+		expectPush(aggregate, 1, 1),
+		expectPush(aggregate, 2, 1),
+		// the second lock is written, because the aggregate was unlocked in between
+		expectPush(aggregate, 3, 1),
+		// the unlock is written, because its event is not the latest of the aggregate anymore
+		expectPush(aggregate, 4, 1),
+	)
+
+	_, err := es.Push(context.Background(), lock())
+	require.NoError(t, err)
+	clock.Add(100 * time.Millisecond)
+	_, err = es.Push(context.Background(), unlock())
+	require.NoError(t, err)
+	clock.Add(100 * time.Millisecond)
+	locked, err := es.Push(context.Background(), lock())
+	require.NoError(t, err)
+	require.Len(t, locked, 1)
+	assert.Equal(t, uint64(4), locked[0].Sequence())
+
+	// the rapid duplicate of the latest event is suppressed
+	clock.Add(100 * time.Millisecond)
+	duplicate, err := es.Push(context.Background(), lock())
+	require.NoError(t, err)
+	assert.Equal(t, locked, duplicate)
+
+	clock.Add(100 * time.Millisecond)
+	unlocked, err := es.Push(context.Background(), unlock())
+	require.NoError(t, err)
+	require.Len(t, unlocked, 1)
+	assert.Equal(t, uint64(5), unlocked[0].Sequence())
+}
+
+func TestEventstore_Push_deduplicationRetry(t *testing.T) {
+	aggregate := mockAggregate("V3-Dd3pH")
+
+	es, clock := newDedupEventstore(t,
+		// the first attempt fails with a retryable error
+		expectPush(aggregate, 1, 1, mock.WithQueryErr(&pgconn.PgError{Code: "40001"})),
+		expectPush(aggregate, 1, 1),
+	)
+
+	pushed, err := es.Push(context.Background(), &mockCommand{aggregate: aggregate, payload: map[string]string{"name": "click"}})
+	require.NoError(t, err)
+	require.Len(t, pushed, 1)
+	assert.Equal(t, uint64(2), pushed[0].Sequence())
+
+	// the retried push is remembered once it committed
+	clock.Add(100 * time.Millisecond)
+	duplicate, err := es.Push(context.Background(), &mockCommand{aggregate: aggregate, payload: map[string]string{"name": "click"}})
+	require.NoError(t, err)
+	assert.Equal(t, pushed, duplicate)
+}
@@ -0,0 +1,44 @@
+package eventstore
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+type durableCommitKey struct{}
+
+// WithDurableCommit returns a context in which [Eventstore.Push] returns only after the commit of the events is durable.
+//
+// On PostgreSQL the push transaction sets synchronous_commit to remote_apply,
+// the commit returns after it is flushed to the WAL of the primary
+// and applied by the synchronous standbys configured in synchronous_standby_names,
+// independent of the synchronous_commit setting of the database or the session.
+// Reads on these standbys see the events as soon as Push returned.
+// Without synchronous standbys the commit waits for the local flush only.
+//
+// CockroachDB acknowledges every commit only after it is persisted by a quorum of the replicas of the range,
+// so a committed push is already durable and the option does not change the push.
+//
+// The latency of the push increases by the time the standbys need to apply the commit.
+func WithDurableCommit(ctx context.Context) context.Context {
+	return context.WithValue(ctx, durableCommitKey{}, true)
+}
+
+func durableCommitRequested(ctx context.Context) bool {
+	requested, _ := ctx.Value(durableCommitKey{}).(bool)
+	return requested
+}
+
+const durableCommitStmt = "SET LOCAL synchronous_commit TO remote_apply"
+
+// requireDurableCommit sets the durability of the commit of the transaction requested by [WithDurableCommit]
+func (es *Eventstore) requireDurableCommit(ctx context.Context, tx pushTx) error {
+	if !durableCommitRequested(ctx) || es.client.Type() != "postgres" {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, durableCommitStmt); err != nil {
+		return zerrors.ThrowInternal(err, "V3-Dc7mQe", "Errors.Internal")
+	}
+	return nil
+}
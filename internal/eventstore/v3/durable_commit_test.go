@@ -0,0 +1,125 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/database/dialect"
+	"github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/database/postgres"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestEventstore_Push_durableCommit(t *testing.T) {
+	aggregate := mockAggregate("V3-Dc4pLk")
+	sequenceConditions, _ := sequencesToSql([]*latestSequence{{aggregate: aggregate}})
+	expectPush := func(db dialect.Database, durable ...mock.Expectation) []mock.Expectation {
+		// sets the [pushPlaceholderFmt] of the database used in the expectations
+		NewEventstore(&database.DB{Database: db})
+		expectations := []mock.Expectation{
+			mock.ExpectBegin(nil),
+			mock.ExcpectExec("SAVEPOINT cockroach_restart", mock.WithExecNoRowsAffected()),
+		}
+		expectations = append(expectations, durable...)
+		return append(expectations,
+			mock.ExpectQuery(
+				fmt.Sprintf(latestSequencesStmt, strings.Join(sequenceConditions, " UNION ALL ")),
+				mock.WithQueryResult(
+					[]string{"instance_id", "owner", "aggregate_type", "aggregate_id", "sequence"},
+					[][]driver.Value{{"instance", "ro", "type", "V3-Dc4pLk", uint64(5)}},
+				),
+			),
+			mock.ExpectQuery(
+				fmt.Sprintf(pushStmt, fmt.Sprintf(pushPlaceholderFmt, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13)),
+				mock.WithQueryResult(
+					[]string{"created_at", "position"},
+					[][]driver.Value{{time.Now(), float64(1)}},
+				),
+			),
+			mock.ExcpectExec("RELEASE SAVEPOINT cockroach_restart", mock.WithExecNoRowsAffected()),
+			mock.ExpectCommit(nil),
+		)
+	}
+
+	tests := []struct {
+		name         string
+		database     dialect.Database
+		durable      bool
+		expectations func() []mock.Expectation
+		wantErr      error
+	}{
+		{
+			name:     "postgres, default",
+			database: new(postgres.Config),
+			expectations: func() []mock.Expectation {
+				return expectPush(new(postgres.Config))
+			},
+		},
+		{
+			name:     "postgres, durable",
+			database: new(postgres.Config),
+			durable:  true,
+			expectations: func() []mock.Expectation {
+				return expectPush(new(postgres.Config), mock.ExcpectExec(durableCommitStmt, mock.WithExecNoRowsAffected()))
+			},
+		},
+		{
+			name:     "postgres, durable failed",
+			database: new(postgres.Config),
+			durable:  true,
+			expectations: func() []mock.Expectation {
+				NewEventstore(&database.DB{Database: new(postgres.Config)})
+				return []mock.Expectation{
+					mock.ExpectBegin(nil),
+					mock.ExcpectExec("SAVEPOINT cockroach_restart", mock.WithExecNoRowsAffected()),
+					mock.ExcpectExec(durableCommitStmt, mock.WithExecErr(errors.New("unsupported"))),
+					mock.ExpectRollback(nil),
+				}
+			},
+			wantErr: zerrors.ThrowInternal(nil, "V3-Dc7mQe", "Errors.Internal"),
+		},
+		{
+			name:     "cockroach, durable by default",
+			database: new(cockroach.Config),
+			durable:  true,
+			expectations: func() []mock.Expectation {
+				return expectPush(new(cockroach.Config))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlMock := mock.NewSQLMock(t, tt.expectations()...)
+			defer sqlMock.Assert(t)
+
+			es := NewEventstore(
+				&database.DB{DB: sqlMock.DB, Database: tt.database},
+				WithMetrics(new(testMetrics)),
+			)
+
+			ctx := context.Background()
+			if tt.durable {
+				ctx = WithDurableCommit(ctx)
+			}
+			events, err := es.Push(ctx, &mockCommand{aggregate: aggregate})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, events)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			assert.Equal(t, uint64(6), events[0].Sequence())
+		})
+	}
+}
@@ -1,19 +1,31 @@
 package eventstore
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/zitadel/logging"
 
 	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 var (
-	_ eventstore.Event = (*event)(nil)
+	_ eventstore.Event             = (*event)(nil)
+	_ eventstore.MetadataProvider  = (*event)(nil)
+	_ eventstore.CausationProvider = (*event)(nil)
+	_ RawPayloader                 = (*event)(nil)
 )
 
+// RawPayloader is implemented by the events returned by [Eventstore.Push]
+type RawPayloader interface {
+	// RawPayload returns the serialized payload exactly as it was marshaled for the database
+	RawPayload() []byte
+}
+
 type event struct {
 	aggregate *eventstore.Aggregate
 	creator   string
@@ -23,16 +35,43 @@ type event struct {
 	sequence  uint64
 	position  float64
 	payload   Payload
+	// encoding is the encoding of the payload, it is JSON unless the payload was serialized as protobuf
+	encoding repository.PayloadEncoding
+	metadata eventstore.Metadata
+	// causationID is the ID of the event which caused the event, empty if unknown
+	causationID string
+	// correlationID is the ID of the request the event originates from, empty if unknown
+	correlationID string
 }
 
-func commandToEvent(sequence *latestSequence, command eventstore.Command) (_ *event, err error) {
-	var payload Payload
-	if command.Payload() != nil {
-		payload, err = json.Marshal(command.Payload())
+// commandToEvent maps the command to the event, the payload is serialized by the codec.
+// The payload of a [eventstore.SerializedPayloadCommand] is taken as it is.
+// The fields of the redaction policy are removed from the payload, such payloads are always serialized as JSON.
+func commandToEvent(codec PayloadCodec, redaction RedactionPolicy, sequence *latestSequence, command eventstore.Command) (_ *event, err error) {
+	var (
+		payload  Payload
+		encoding repository.PayloadEncoding
+	)
+	if serialized, ok := command.(eventstore.SerializedPayloadCommand); ok {
+		payload, encoding, err = serializedPayload(serialized, redaction.fields(command.Type()))
+		if err != nil {
+			return nil, err
+		}
+	} else if command.Payload() != nil {
+		redacted := redaction.fields(command.Type())
+		if len(redacted) > 0 {
+			codec = JSONCodec{}
+		}
+		payload, encoding, err = codec.Marshal(command.Payload())
 		if err != nil {
 			logging.WithError(err).Warn("marshal payload failed")
 			return nil, zerrors.ThrowInternal(err, "V3-MInPK", "Errors.Internal")
 		}
+		payload, err = redactPayload(payload, redacted)
+		if err != nil {
+			logging.WithError(err).Warn("redact payload failed")
+			return nil, zerrors.ThrowInternal(err, "V3-Rd4cTp", "Errors.Internal")
+		}
 	}
 	return &event{
 		aggregate: sequence.aggregate,
@@ -40,10 +79,73 @@ func commandToEvent(sequence *latestSequence, command eventstore.Command) (_ *ev
 		revision:  command.Revision(),
 		typ:       command.Type(),
 		payload:   payload,
+		encoding:  encoding,
 		sequence:  sequence.sequence,
 	}, nil
 }
 
+// serializedPayload returns the payload of the command as it is, only redacted fields are removed.
+// Protobuf payloads can not be redacted, their fields are unknown to the eventstore.
+func serializedPayload(command eventstore.SerializedPayloadCommand, redacted []string) (Payload, repository.PayloadEncoding, error) {
+	data, contentType := command.SerializedPayload()
+	var encoding repository.PayloadEncoding
+	switch contentType {
+	case eventstore.PayloadContentTypeJSON:
+		encoding = repository.PayloadEncodingJSON
+	case eventstore.PayloadContentTypeProtobuf:
+		encoding = repository.PayloadEncodingProtobuf
+		if len(redacted) > 0 {
+			return nil, 0, zerrors.ThrowInvalidArgument(nil, "V3-Sr3dPb", "Errors.Eventstore.InvalidPayload")
+		}
+	default:
+		return nil, 0, zerrors.ThrowInvalidArgument(nil, "V3-Sr3dCt", "Errors.Eventstore.InvalidPayload")
+	}
+	if len(data) == 0 {
+		return nil, encoding, nil
+	}
+	payload, err := redactPayload(data, redacted)
+	if err != nil {
+		return nil, 0, zerrors.ThrowInvalidArgument(err, "V3-Sr3dRd", "Errors.Eventstore.InvalidPayload")
+	}
+	return payload, encoding, nil
+}
+
+// marshalMetadata returns the value of the metadata column, empty metadata is stored as NULL
+func marshalMetadata(metadata eventstore.Metadata) (Payload, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	value, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "V3-Md3Mq", "Errors.Internal")
+	}
+	return value, nil
+}
+
+// nullableID returns the value of an optional ID column, empty IDs are stored as NULL
+func nullableID(id string) any {
+	if id == "" {
+		return nil
+	}
+	return id
+}
+
+// validatePayload checks that the serialized payload is either empty, null or a JSON object,
+// which is required by the reducers of the read side
+func validatePayload(payload Payload) error {
+	if len(payload) == 0 {
+		return nil
+	}
+	if !json.Valid(payload) {
+		return errors.New("payload is not valid JSON")
+	}
+	trimmed := bytes.TrimSpace(payload)
+	if trimmed[0] != '{' && !bytes.Equal(trimmed, []byte("null")) {
+		return errors.New("payload is not a JSON object")
+	}
+	return nil
+}
+
 // CreationDate implements [eventstore.Event]
 func (e *event) CreationDate() time.Time {
 	return e.CreatedAt()
@@ -94,7 +196,7 @@ func (e *event) Unmarshal(ptr any) error {
 	if len(e.payload) == 0 {
 		return nil
 	}
-	if err := json.Unmarshal(e.payload, ptr); err != nil {
+	if err := repository.UnmarshalPayload(e.encoding, e.payload, ptr); err != nil {
 		return zerrors.ThrowInternal(err, "V3-u8qVo", "Errors.Internal")
 	}
 
@@ -105,3 +207,31 @@ func (e *event) Unmarshal(ptr any) error {
 func (e *event) DataAsBytes() []byte {
 	return e.payload
 }
+
+// RawPayload implements [RawPayloader].
+// Compressed payloads are returned uncompressed, decompressing the stored payload results in exactly these bytes.
+// Uncompressed payloads are stored as JSONB, so the payload read from the database is normalized
+// and might differ in whitespace and the order of the keys.
+// Protobuf payloads are returned as they are serialized.
+// The returned bytes are a copy, nil if the event has no payload.
+func (e *event) RawPayload() []byte {
+	if e.payload == nil {
+		return nil
+	}
+	return bytes.Clone(e.payload)
+}
+
+// EventMetadata implements [eventstore.MetadataProvider]
+func (e *event) EventMetadata() eventstore.Metadata {
+	return e.metadata
+}
+
+// CausationID implements [eventstore.CausationProvider]
+func (e *event) CausationID() string {
+	return e.causationID
+}
+
+// CorrelationID implements [eventstore.CausationProvider]
+func (e *event) CorrelationID() string {
+	return e.correlationID
+}
@@ -0,0 +1,50 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+//go:embed event_counts.sql
+var eventCountsStmt string
+
+// EventCountsByAggregateType returns the amount of events of the instance per aggregate type.
+// It is a read-only diagnostic for operators to identify the aggregates dominating the storage,
+// the events are counted by the database and not read.
+// Aggregate types without events of the instance are not part of the result.
+func (es *Eventstore) EventCountsByAggregateType(ctx context.Context, instanceID string) (counts map[string]uint64, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	// the counts are never aggregated over all instances
+	if instanceID == "" {
+		return nil, zerrors.ThrowInvalidArgument(nil, "V3-Cnt5Ag", "Errors.Eventstore.InvalidQuery")
+	}
+
+	counts = make(map[string]uint64)
+	err = es.client.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			for rows.Next() {
+				var (
+					aggregateType string
+					count         uint64
+				)
+				if err := rows.Scan(&aggregateType, &count); err != nil {
+					return err
+				}
+				counts[aggregateType] = count
+			}
+			return nil
+		},
+		eventCountsStmt,
+		instanceID,
+	)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "V3-Cnt6Qr", "Errors.Internal")
+	}
+	return counts, nil
+}
@@ -0,0 +1,9 @@
+SELECT
+    aggregate_type
+    , COUNT(*)
+FROM
+    eventstore.events2
+WHERE
+    instance_id = $1
+GROUP BY
+    aggregate_type;
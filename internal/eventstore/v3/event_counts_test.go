@@ -0,0 +1,95 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestEventstore_EventCountsByAggregateType(t *testing.T) {
+	tests := []struct {
+		name         string
+		instanceID   string
+		expectations []mock.Expectation
+		want         map[string]uint64
+		wantErr      func(error) bool
+	}{
+		{
+			name:    "no instance",
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+		{
+			name:       "no events",
+			instanceID: "instance",
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery(eventCountsStmt,
+					mock.WithQueryArgs("instance"),
+					mock.WithQueryResult([]string{"aggregate_type", "count"}, nil),
+				),
+				mock.ExpectCommit(nil),
+			},
+			want: map[string]uint64{},
+		},
+		{
+			name:       "several aggregate types",
+			instanceID: "instance",
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery(eventCountsStmt,
+					mock.WithQueryArgs("instance"),
+					mock.WithQueryResult([]string{"aggregate_type", "count"}, [][]driver.Value{
+						{"user", uint64(1200)},
+						{"org", uint64(3)},
+						{"target", uint64(42)},
+					}),
+				),
+				mock.ExpectCommit(nil),
+			},
+			want: map[string]uint64{
+				"user":   1200,
+				"org":    3,
+				"target": 42,
+			},
+		},
+		{
+			name:       "query fails",
+			instanceID: "instance",
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery(eventCountsStmt,
+					mock.WithQueryErr(errors.New("connection lost")),
+				),
+				mock.ExpectRollback(nil),
+			},
+			wantErr: zerrors.IsInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlMock := mock.NewSQLMock(t, tt.expectations...)
+			defer sqlMock.Assert(t)
+			es := NewEventstore(
+				&database.DB{DB: sqlMock.DB, Database: new(cockroach.Config)},
+				WithMetrics(new(testMetrics)),
+			)
+
+			got, err := es.EventCountsByAggregateType(context.Background(), tt.instanceID)
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package eventstore
 
 import (
 	"context"
+	"time"
 
 	"github.com/zitadel/zitadel/internal/database"
 )
@@ -9,25 +10,151 @@ import (
 var (
 	// pushPlaceholderFmt defines how data are inserted into the events table
 	pushPlaceholderFmt string
+	// pushCompressedPlaceholderFmt extends [pushPlaceholderFmt] by the payload encoding and the compressed payload
+	pushCompressedPlaceholderFmt string
+	// pushWithoutAddedColumnsPlaceholderFmt is [pushPlaceholderFmt] without the metadata, the causation and the correlation
+	pushWithoutAddedColumnsPlaceholderFmt string
 	// uniqueConstraintPlaceholderFmt defines the format of the unique constraint error returned from the database
 	uniqueConstraintPlaceholderFmt string
 )
 
 type Eventstore struct {
-	client *database.DB
+	client  *database.DB
+	metrics Metrics
+	// txBeginner starts the transactions of pushes on the client, tests replace it to push without a database
+	txBeginner txBeginner
+	// acquireTimeout limits the time [Eventstore.Push] waits for a connection of the pool, zero waits until ctx is done
+	acquireTimeout time.Duration
+	// validatePayloads enables the validation of the serialized payloads before they are written
+	validatePayloads bool
+	// compressionThreshold is the size in bytes above which payloads are written compressed, zero disables compression
+	compressionThreshold int
+	// faultPolicy injects faults into pushes for tests, nil injects no faults
+	faultPolicy FaultPolicy
+	// codec serializes the payloads of the commands, [JSONCodec] by default
+	codec PayloadCodec
+	// redaction removes fields from the payloads before they are written, nil if nothing is redacted
+	redaction RedactionPolicy
+	// dedup suppresses commands identical to recently written events, nil if disabled
+	dedup *pushDeduplicator
+	// skipSingleCommandSavepoint pushes single commands without the savepoint used to retry within the transaction
+	skipSingleCommandSavepoint bool
+	// transforms enrich the commands before they are inserted, in the order they were registered
+	transforms []CommandTransform
+	// placeholderFormat formats the placeholders of the insert of the events, [DollarPlaceholders] by default
+	placeholderFormat PlaceholderFormat
+	// maxPayloadSize limits the size in bytes of the serialized payload of each command, zero if unlimited
+	maxPayloadSize int
+	// withoutAddedColumns pushes the events only to the columns events2 was created with
+	withoutAddedColumns bool
 }
 
-func NewEventstore(client *database.DB) *Eventstore {
+// Option configures optional behaviour of the [Eventstore]
+type Option func(es *Eventstore)
+
+// WithMetrics replaces the default metrics which report to the global telemetry provider
+func WithMetrics(metrics Metrics) Option {
+	return func(es *Eventstore) {
+		es.metrics = metrics
+	}
+}
+
+// WithAcquireTimeout limits the time [Eventstore.Push] waits for a free connection of the pool.
+// If no connection is available in time, Push fails with [ErrPoolExhausted].
+// Zero waits until the context is done.
+func WithAcquireTimeout(timeout time.Duration) Option {
+	return func(es *Eventstore) {
+		es.acquireTimeout = timeout
+	}
+}
+
+// WithPayloadValidation checks that the payload of each command is serialized to a JSON object before it is written.
+// It is intended for untrusted callers, the serialization of trusted commands is not verified to avoid the overhead.
+func WithPayloadValidation() Option {
+	return func(es *Eventstore) {
+		es.validatePayloads = true
+	}
+}
+
+// WithPayloadCompression writes payloads larger than threshold bytes gzip compressed to the compressed_payload column
+// instead of the JSONB payload column.
+// Smaller payloads are still written as JSONB. Payload filters of queries do not match compressed events.
+func WithPayloadCompression(threshold int) Option {
+	return func(es *Eventstore) {
+		es.compressionThreshold = threshold
+	}
+}
+
+// WithPayloadCodec serializes the payloads of the commands with the codec instead of JSON.
+// Payloads which are not serialized as JSON are written to the compressed_payload column
+// and are not matched by payload filters of queries.
+func WithPayloadCodec(codec PayloadCodec) Option {
+	return func(es *Eventstore) {
+		es.codec = codec
+	}
+}
+
+// WithoutSingleCommandSavepoint pushes single commands in a transaction without the savepoint,
+// which is otherwise used to retry the push within the transaction.
+// If such a push fails with a retryable error, the transaction is rolled back
+// and the push is retried in a new transaction with the savepoint.
+// Pushes of multiple commands always use the savepoint.
+func WithoutSingleCommandSavepoint() Option {
+	return func(es *Eventstore) {
+		es.skipSingleCommandSavepoint = true
+	}
+}
+
+// WithMaxPayloadSize limits the size of the serialized payload of each command to maxBytes.
+// The size is checked before the payload is compressed, a push with a larger payload fails with [ErrPayloadTooLarge]
+// before the events are inserted.
+// Zero does not limit the size.
+func WithMaxPayloadSize(maxBytes int) Option {
+	return func(es *Eventstore) {
+		es.maxPayloadSize = maxBytes
+	}
+}
+
+// WithoutAddedColumns pushes the events only to the columns events2 was created with.
+// It is meant for the setup, which pushes events before the steps adding the columns ran.
+// The payloads are written as JSON and never compressed,
+// the metadata, the causation and the correlation of the events are not stored.
+func WithoutAddedColumns() Option {
+	return func(es *Eventstore) {
+		es.withoutAddedColumns = true
+	}
+}
+
+func NewEventstore(client *database.DB, opts ...Option) *Eventstore {
 	switch client.Type() {
 	case "cockroach":
-		pushPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $%d)"
+		pushPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $%d, $%d, $%d, $%d)"
+		pushCompressedPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $%d, $%d, $%d, $%d, $%d, $%d)"
+		pushWithoutAddedColumnsPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $%d)"
 		uniqueConstraintPlaceholderFmt = "('%s', '%s', '%s')"
 	case "postgres":
-		pushPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, statement_timestamp(), EXTRACT(EPOCH FROM clock_timestamp()), $%d)"
+		pushPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, statement_timestamp(), EXTRACT(EPOCH FROM clock_timestamp()), $%d, $%d, $%d, $%d)"
+		pushCompressedPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, statement_timestamp(), EXTRACT(EPOCH FROM clock_timestamp()), $%d, $%d, $%d, $%d, $%d, $%d)"
+		pushWithoutAddedColumnsPlaceholderFmt = "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, statement_timestamp(), EXTRACT(EPOCH FROM clock_timestamp()), $%d)"
 		uniqueConstraintPlaceholderFmt = "(%s, %s, %s)"
 	}
 
-	return &Eventstore{client: client}
+	es := &Eventstore{client: client}
+	for _, opt := range opts {
+		opt(es)
+	}
+	if es.metrics == nil {
+		es.metrics = newTelemetryMetrics()
+	}
+	// without the payload encoding, payloads can only be stored as JSON
+	if es.codec == nil || es.withoutAddedColumns {
+		es.codec = JSONCodec{}
+	}
+	if es.placeholderFormat == nil {
+		es.placeholderFormat = DollarPlaceholders
+	}
+	es.txBeginner = &clientTxBeginner{client: client, acquireTimeout: es.acquireTimeout}
+	return es
 }
 
 func (es *Eventstore) Health(ctx context.Context) error {
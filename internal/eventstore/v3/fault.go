@@ -0,0 +1,35 @@
+package eventstore
+
+import "context"
+
+// FaultPoint identifies a step of [Eventstore.Push] at which a fault can be injected
+type FaultPoint string
+
+const (
+	// FaultPointInsertEvents is checked before the events are inserted
+	FaultPointInsertEvents FaultPoint = "insert_events"
+	// FaultPointUniqueConstraints is checked before the unique constraints are handled,
+	// it is not reached if the push skips them (see [WithoutUniqueConstraints])
+	FaultPointUniqueConstraints FaultPoint = "unique_constraints"
+)
+
+// FaultPolicy is called at each [FaultPoint] of a push.
+// If it returns an error the step fails with it, so retryable errors (SQLSTATE 40001) restart the transaction
+// and all other errors roll it back.
+// It is intended for tests which exercise the retry and rollback paths without a contended database.
+type FaultPolicy func(ctx context.Context, point FaultPoint) error
+
+// WithFaultPolicy injects the faults of the policy into [Eventstore.Push] and [Eventstore.PushTx]
+func WithFaultPolicy(policy FaultPolicy) Option {
+	return func(es *Eventstore) {
+		es.faultPolicy = policy
+	}
+}
+
+// injectFault returns the fault of the policy at the point, without a policy no fault is injected
+func (es *Eventstore) injectFault(ctx context.Context, point FaultPoint) error {
+	if es.faultPolicy == nil {
+		return nil
+	}
+	return es.faultPolicy(ctx, point)
+}
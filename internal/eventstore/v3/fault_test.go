@@ -0,0 +1,161 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/database/mock"
+)
+
+func TestEventstore_Push_faultPolicy(t *testing.T) {
+	// sets the [pushPlaceholderFmt] used in the expectations
+	NewEventstore(&database.DB{Database: new(cockroach.Config)})
+
+	aggregate := mockAggregate("V3-Fq7vR")
+	sequenceConditions, _ := sequencesToSql([]*latestSequence{{aggregate: aggregate}})
+	expectSequence := mock.ExpectQuery(
+		fmt.Sprintf(latestSequencesStmt, strings.Join(sequenceConditions, " UNION ALL ")),
+		mock.WithQueryResult(
+			[]string{"instance_id", "owner", "aggregate_type", "aggregate_id", "sequence"},
+			[][]driver.Value{{"instance", "ro", "type", "V3-Fq7vR", uint64(5)}},
+		),
+	)
+	expectInsert := mock.ExpectQuery(
+		fmt.Sprintf(pushStmt, fmt.Sprintf(pushPlaceholderFmt, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13)),
+		mock.WithQueryResult(
+			[]string{"created_at", "position"},
+			[][]driver.Value{{time.Now(), float64(1)}},
+		),
+	)
+	errFatal := errors.New("fatal")
+
+	tests := []struct {
+		name         string
+		faults       map[FaultPoint][]error
+		expectations []mock.Expectation
+		wantErr      error
+		wantRetries  int
+		wantOutcome  PushOutcome
+	}{
+		{
+			name: "no faults",
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExcpectExec("SAVEPOINT cockroach_restart", mock.WithExecNoRowsAffected()),
+				expectSequence,
+				expectInsert,
+				mock.ExcpectExec("RELEASE SAVEPOINT cockroach_restart", mock.WithExecNoRowsAffected()),
+				mock.ExpectCommit(nil),
+			},
+			wantOutcome: PushOutcomeSuccess,
+		},
+		{
+			name: "retry then success",
+			faults: map[FaultPoint][]error{
+				FaultPointInsertEvents: {&pgconn.PgError{Code: "40001"}},
+			},
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExcpectExec("SAVEPOINT cockroach_restart", mock.WithExecNoRowsAffected()),
+				// first attempt fails before the events are inserted
+				expectSequence,
+				mock.ExcpectExec("ROLLBACK TO SAVEPOINT cockroach_restart", mock.WithExecNoRowsAffected()),
+				// second attempt succeeds
+				expectSequence,
+				expectInsert,
+				mock.ExcpectExec("RELEASE SAVEPOINT cockroach_restart", mock.WithExecNoRowsAffected()),
+				mock.ExpectCommit(nil),
+			},
+			wantRetries: 1,
+			wantOutcome: PushOutcomeRetried,
+		},
+		{
+			name: "retry twice then success",
+			faults: map[FaultPoint][]error{
+				FaultPointInsertEvents:      {&pgconn.PgError{Code: "40001"}},
+				FaultPointUniqueConstraints: {&pgconn.PgError{Code: "40001"}},
+			},
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExcpectExec("SAVEPOINT cockroach_restart", mock.WithExecNoRowsAffected()),
+				// first attempt fails before the events are inserted
+				expectSequence,
+				mock.ExcpectExec("ROLLBACK TO SAVEPOINT cockroach_restart", mock.WithExecNoRowsAffected()),
+				// second attempt fails before the unique constraints are handled
+				expectSequence,
+				expectInsert,
+				mock.ExcpectExec("ROLLBACK TO SAVEPOINT cockroach_restart", mock.WithExecNoRowsAffected()),
+				// third attempt succeeds
+				expectSequence,
+				expectInsert,
+				mock.ExcpectExec("RELEASE SAVEPOINT cockroach_restart", mock.WithExecNoRowsAffected()),
+				mock.ExpectCommit(nil),
+			},
+			wantRetries: 2,
+			wantOutcome: PushOutcomeRetried,
+		},
+		{
+			name: "fatal then rollback",
+			faults: map[FaultPoint][]error{
+				FaultPointUniqueConstraints: {errFatal},
+			},
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExcpectExec("SAVEPOINT cockroach_restart", mock.WithExecNoRowsAffected()),
+				expectSequence,
+				expectInsert,
+				mock.ExpectRollback(nil),
+			},
+			wantErr:     errFatal,
+			wantOutcome: PushOutcomeFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlMock := mock.NewSQLMock(t, tt.expectations...)
+			defer sqlMock.Assert(t)
+
+			// each fault is injected once, in the given order
+			policy := func(_ context.Context, point FaultPoint) error {
+				faults := tt.faults[point]
+				if len(faults) == 0 {
+					return nil
+				}
+				tt.faults[point] = faults[1:]
+				return faults[0]
+			}
+			recorder := new(testMetrics)
+			es := NewEventstore(
+				&database.DB{DB: sqlMock.DB, Database: new(cockroach.Config)},
+				WithMetrics(recorder),
+				WithFaultPolicy(policy),
+			)
+
+			ctx, result := WithPushResult(context.Background())
+			events, err := es.Push(ctx, &mockCommand{aggregate: aggregate})
+			assert.Equal(t, tt.wantRetries, result.Retries)
+			// the latency is observed once per push, including the rollback path
+			require.Len(t, recorder.latencies, 1)
+			assert.Equal(t, tt.wantOutcome, recorder.latencies[0].outcome)
+			assert.Positive(t, recorder.latencies[0].latency)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			assert.Equal(t, uint64(6), events[0].Sequence())
+		})
+	}
+}
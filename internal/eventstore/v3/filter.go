@@ -0,0 +1,101 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"strconv"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+//go:embed filter.sql
+var filterStmt string
+
+// Filter returns all events of the aggregate ordered by their sequence,
+// the events are the same as returned by [Eventstore.Push].
+// It is intended for building read models in tests and tooling, an aggregate without events results in an empty slice.
+func (es *Eventstore) Filter(ctx context.Context, aggregateType, aggregateID, instanceID string) (events []eventstore.Event, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	events = make([]eventstore.Event, 0)
+	err = es.client.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			for rows.Next() {
+				e, err := scanEvent(rows, aggregateType, aggregateID, instanceID)
+				if err != nil {
+					return err
+				}
+				events = append(events, e)
+			}
+			return nil
+		},
+		filterStmt,
+		instanceID,
+		aggregateType,
+		aggregateID,
+	)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "V3-Fi1tR", "Errors.Internal")
+	}
+	return events, nil
+}
+
+// scanEvent decodes a row of the [filterStmt] into the event of the aggregate,
+// columns selected after the columns of the event are scanned into extra
+func scanEvent(rows *sql.Rows, aggregateType, aggregateID, instanceID string, extra ...any) (*event, error) {
+	e := &event{
+		aggregate: &eventstore.Aggregate{
+			ID:         aggregateID,
+			Type:       eventstore.AggregateType(aggregateType),
+			InstanceID: instanceID,
+		},
+	}
+	var (
+		encoding    sql.NullInt16
+		compressed  []byte
+		metadata    []byte
+		causation   sql.NullString
+		correlation sql.NullString
+	)
+	err := rows.Scan(append([]any{
+		&e.createdAt,
+		&e.typ,
+		&e.sequence,
+		&e.position,
+		&e.payload,
+		&e.creator,
+		&e.aggregate.ResourceOwner,
+		&e.revision,
+		&encoding,
+		&compressed,
+		&metadata,
+		&causation,
+		&correlation,
+	}, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	// the revision column stores the version of the aggregate, see [aggregateRevision]
+	e.aggregate.Version = eventstore.Version("v" + strconv.Itoa(int(e.revision)))
+	stored := repository.PayloadEncoding(encoding.Int16)
+	e.payload, err = repository.DecodePayload(stored, e.payload, compressed)
+	if err != nil {
+		return nil, err
+	}
+	// decompressed payloads are JSON like the payloads of the JSONB column
+	if stored == repository.PayloadEncodingProtobuf {
+		e.encoding = stored
+	}
+	e.metadata, err = repository.UnmarshalMetadata(metadata)
+	if err != nil {
+		return nil, err
+	}
+	e.causationID = causation.String
+	e.correlationID = correlation.String
+	return e, nil
+}
@@ -0,0 +1,22 @@
+SELECT
+    created_at
+    , event_type
+    , "sequence"
+    , "position"
+    , payload
+    , creator
+    , "owner"
+    , revision
+    , payload_encoding
+    , compressed_payload
+    , metadata
+    , causation_id
+    , correlation_id
+FROM
+    eventstore.events2
+WHERE
+    instance_id = $1
+    AND aggregate_type = $2
+    AND aggregate_id = $3
+ORDER BY
+    "sequence";
@@ -0,0 +1,227 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+var filterColumns = []string{
+	"created_at",
+	"event_type",
+	"sequence",
+	"position",
+	"payload",
+	"creator",
+	"owner",
+	"revision",
+	"payload_encoding",
+	"compressed_payload",
+	"metadata",
+	"causation_id",
+	"correlation_id",
+}
+
+func TestEventstore_Filter(t *testing.T) {
+	tests := []struct {
+		name         string
+		expectations []mock.Expectation
+		want         []eventstore.Event
+		wantErr      func(error) bool
+	}{
+		{
+			name: "no events",
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery(filterStmt,
+					mock.WithQueryArgs("instance", "type", "V3-Fi1tR"),
+					mock.WithQueryResult(filterColumns, nil),
+				),
+				mock.ExpectCommit(nil),
+			},
+			want: []eventstore.Event{},
+		},
+		{
+			name: "query fails",
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery(filterStmt,
+					mock.WithQueryErr(errors.New("connection lost")),
+				),
+				mock.ExpectRollback(nil),
+			},
+			wantErr: zerrors.IsInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlMock := mock.NewSQLMock(t, tt.expectations...)
+			defer sqlMock.Assert(t)
+			es := NewEventstore(
+				&database.DB{DB: sqlMock.DB, Database: new(cockroach.Config)},
+				WithMetrics(new(testMetrics)),
+			)
+
+			got, err := es.Filter(context.Background(), "type", "V3-Fi1tR", "instance")
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEventstore_Filter_pushed(t *testing.T) {
+	// sets the [pushPlaceholderFmt] used in the expectations
+	NewEventstore(&database.DB{Database: new(cockroach.Config)})
+
+	aggregate := mockAggregate("V3-Fi1tR")
+	createdAt := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	sequenceConditions, _ := sequencesToSql([]*latestSequence{{aggregate: aggregate}})
+	insertStmt := fmt.Sprintf(pushStmt, strings.Join([]string{
+		fmt.Sprintf(pushPlaceholderFmt, placeholderIndexes(0, argsPerCommand)...),
+		fmt.Sprintf(pushPlaceholderFmt, placeholderIndexes(argsPerCommand, argsPerCommand)...),
+	}, ", "))
+
+	pushMock := mock.NewSQLMock(t,
+		mock.ExpectQuery(
+			fmt.Sprintf(latestSequencesStmt, strings.Join(sequenceConditions, " UNION ALL ")),
+			mock.WithQueryResult(
+				[]string{"instance_id", "owner", "aggregate_type", "aggregate_id", "sequence"},
+				[][]driver.Value{{"instance", "ro", "type", "V3-Fi1tR", uint64(5)}},
+			),
+		),
+		mock.ExpectQuery(insertStmt, mock.WithQueryResult(
+			[]string{"created_at", "position"},
+			[][]driver.Value{{createdAt, float64(1)}, {createdAt, float64(1)}},
+		)),
+	)
+	defer pushMock.Assert(t)
+	es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithMetrics(new(testMetrics)))
+	es.txBeginner = &fakeTxBeginner{tx: &fakeTx{queries: pushMock.DB}}
+
+	pushed, err := es.Push(context.Background(),
+		&mockCommand{aggregate: aggregate, payload: map[string]string{"name": "first"}},
+		&mockCommand{aggregate: aggregate},
+	)
+	require.NoError(t, err)
+	require.Len(t, pushed, 2)
+
+	rows := make([][]driver.Value, len(pushed))
+	for i, e := range pushed {
+		rows[i] = []driver.Value{
+			e.CreatedAt(),
+			string(e.Type()),
+			e.Sequence(),
+			e.Position(),
+			[]byte(e.DataAsBytes()),
+			e.Creator(),
+			e.Aggregate().ResourceOwner,
+			int64(1),
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+		}
+	}
+	filterMock := mock.NewSQLMock(t,
+		mock.ExpectBegin(nil),
+		mock.ExpectQuery(filterStmt,
+			mock.WithQueryArgs("instance", "type", "V3-Fi1tR"),
+			mock.WithQueryResult(filterColumns, rows),
+		),
+		mock.ExpectCommit(nil),
+	)
+	defer filterMock.Assert(t)
+	es.client = &database.DB{DB: filterMock.DB, Database: new(cockroach.Config)}
+
+	filtered, err := es.Filter(context.Background(), "type", "V3-Fi1tR", "instance")
+	require.NoError(t, err)
+	assert.Equal(t, pushed, filtered)
+}
+
+func TestEventstore_Filter_causationChain(t *testing.T) {
+	es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithMetrics(new(testMetrics)))
+	cause := mockAggregate("V3-Cs5Ch")
+	effect := mockAggregate("V3-Cs6Ch")
+
+	// the first event originates from the request, the second one is caused by the first one
+	first, _, firstArgs, err := es.mapCommands(
+		eventstore.WithCorrelationID(context.Background(), "request"),
+		[]eventstore.Command{&mockCommand{aggregate: cause}},
+		[]*latestSequence{{aggregate: cause}},
+	)
+	require.NoError(t, err)
+	second, _, secondArgs, err := es.mapCommands(
+		eventstore.WithCausingEvent(context.Background(), first[0]),
+		[]eventstore.Command{&mockCommand{aggregate: effect}},
+		[]*latestSequence{{aggregate: effect}},
+	)
+	require.NoError(t, err)
+
+	// the events are read back as they were written
+	row := func(args []any) []driver.Value {
+		return []driver.Value{
+			time.Now(),
+			"event.type",
+			args[8],
+			float64(1),
+			payloadValue(args[7].(Payload)),
+			args[5],
+			args[1],
+			int64(1),
+			nil,
+			nil,
+			nil,
+			args[11],
+			args[12],
+		}
+	}
+	sqlMock := mock.NewSQLMock(t,
+		mock.ExpectBegin(nil),
+		mock.ExpectQuery(filterStmt,
+			mock.WithQueryArgs("instance", "type", "V3-Cs5Ch"),
+			mock.WithQueryResult(filterColumns, [][]driver.Value{row(firstArgs)}),
+		),
+		mock.ExpectCommit(nil),
+		mock.ExpectBegin(nil),
+		mock.ExpectQuery(filterStmt,
+			mock.WithQueryArgs("instance", "type", "V3-Cs6Ch"),
+			mock.WithQueryResult(filterColumns, [][]driver.Value{row(secondArgs)}),
+		),
+		mock.ExpectCommit(nil),
+	)
+	defer sqlMock.Assert(t)
+	es.client = &database.DB{DB: sqlMock.DB, Database: new(cockroach.Config)}
+
+	causes, err := es.Filter(context.Background(), "type", "V3-Cs5Ch", "instance")
+	require.NoError(t, err)
+	require.Len(t, causes, 1)
+	assert.Empty(t, causes[0].(eventstore.CausationProvider).CausationID())
+	assert.Equal(t, "request", causes[0].(eventstore.CausationProvider).CorrelationID())
+
+	effects, err := es.Filter(context.Background(), "type", "V3-Cs6Ch", "instance")
+	require.NoError(t, err)
+	require.Len(t, effects, 1)
+	assert.Equal(t, "instance:type:V3-Cs5Ch:1", effects[0].(eventstore.CausationProvider).CausationID())
+	assert.Equal(t, eventstore.EventID(causes[0]), effects[0].(eventstore.CausationProvider).CausationID())
+	assert.Equal(t, "request", effects[0].(eventstore.CausationProvider).CorrelationID())
+	// the pushed events carry the same causation as the events read back
+	assert.Equal(t, second[0].(eventstore.CausationProvider).CausationID(), effects[0].(eventstore.CausationProvider).CausationID())
+}
@@ -0,0 +1,177 @@
+package eventstore
+
+import (
+	"context"
+	"maps"
+	"strings"
+	"sync"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+var _ eventstore.Pusher = (*InmemEventstore)(nil)
+
+// InmemEventstore is an in-memory implementation of [eventstore.Pusher] for tests of command handlers.
+// Sequences are assigned deterministically per aggregate, all events of a push share the same position
+// and unique constraints are enforced like in the database.
+// A push either stores all events or none.
+type InmemEventstore struct {
+	mux      sync.Mutex
+	clock    clock.Clock
+	position float64
+
+	sequences         map[inmemAggregate]inmemSequence
+	uniqueConstraints map[inmemUniqueConstraint]struct{}
+	commands          []eventstore.Command
+	events            []eventstore.Event
+}
+
+type inmemAggregate struct {
+	instanceID    string
+	aggregateType eventstore.AggregateType
+	aggregateID   string
+}
+
+// inmemSequence is the latest sequence and the owner of an aggregate
+type inmemSequence struct {
+	sequence      uint64
+	resourceOwner string
+}
+
+type inmemUniqueConstraint struct {
+	instanceID  string
+	uniqueType  string
+	uniqueField string
+}
+
+// NewInmemEventstore creates an empty eventstore, the creation date of the events is taken from the clock
+func NewInmemEventstore(clock clock.Clock) *InmemEventstore {
+	return &InmemEventstore{
+		clock:             clock,
+		sequences:         make(map[inmemAggregate]inmemSequence),
+		uniqueConstraints: make(map[inmemUniqueConstraint]struct{}),
+	}
+}
+
+// Health implements [eventstore.Pusher]
+func (es *InmemEventstore) Health(context.Context) error {
+	return nil
+}
+
+// Push implements [eventstore.Pusher]
+func (es *InmemEventstore) Push(ctx context.Context, commands ...eventstore.Command) ([]eventstore.Event, error) {
+	es.mux.Lock()
+	defer es.mux.Unlock()
+
+	sequences := commandsToSequences(ctx, commands)
+	for _, sequence := range sequences {
+		latest, ok := es.sequences[inmemAggregateOf(sequence.aggregate)]
+		if !ok {
+			continue
+		}
+		sequence.sequence = latest.sequence
+		if sequence.aggregate.ResourceOwner == "" {
+			sequence.aggregate.ResourceOwner = latest.resourceOwner
+		}
+	}
+
+	createdAt := es.clock.Now()
+	position := es.position + 1
+	events := make([]eventstore.Event, len(commands))
+	for i, command := range commands {
+		sequence := searchSequenceByCommand(sequences, command)
+		sequence.sequence++
+		e, err := commandToEvent(JSONCodec{}, nil, sequence, command)
+		if err != nil {
+			return nil, err
+		}
+		e.createdAt = createdAt
+		e.position = position
+		e.metadata = eventstore.MetadataFromContext(ctx)
+		e.causationID = eventstore.CausationIDFromContext(ctx)
+		e.correlationID = eventstore.CorrelationIDFromContext(ctx)
+		events[i] = e
+	}
+
+	uniqueConstraints := es.uniqueConstraints
+	if !uniqueConstraintsSkipped(ctx) {
+		var err error
+		uniqueConstraints, err = es.handleUniqueConstraints(commands)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	es.uniqueConstraints = uniqueConstraints
+	for _, sequence := range sequences {
+		es.sequences[inmemAggregateOf(sequence.aggregate)] = inmemSequence{
+			sequence:      sequence.sequence,
+			resourceOwner: sequence.aggregate.ResourceOwner,
+		}
+	}
+	es.position = position
+	es.commands = append(es.commands, commands...)
+	es.events = append(es.events, events...)
+	return events, nil
+}
+
+// handleUniqueConstraints returns the unique constraints after the commands,
+// the constraints of the eventstore are not changed
+func (es *InmemEventstore) handleUniqueConstraints(commands []eventstore.Command) (map[inmemUniqueConstraint]struct{}, error) {
+	uniqueConstraints := maps.Clone(es.uniqueConstraints)
+	adds := make([]*eventstore.UniqueConstraint, 0)
+	addKeys := make([]inmemUniqueConstraint, 0)
+
+	// like in the database all constraints are removed before they are added
+	for _, constraint := range instanceUniqueConstraints(commands) {
+		instanceID := constraint.instanceID
+		key := inmemUniqueConstraint{
+			instanceID:  instanceID,
+			uniqueType:  constraint.UniqueType,
+			uniqueField: strings.ToLower(constraint.UniqueField),
+		}
+		switch constraint.Action {
+		case eventstore.UniqueConstraintAdd:
+			adds = append(adds, constraint.UniqueConstraint)
+			addKeys = append(addKeys, key)
+		case eventstore.UniqueConstraintRemove:
+			delete(uniqueConstraints, key)
+		case eventstore.UniqueConstraintInstanceRemove:
+			maps.DeleteFunc(uniqueConstraints, func(existing inmemUniqueConstraint, _ struct{}) bool {
+				return existing.instanceID == instanceID
+			})
+		}
+	}
+	for i, key := range addKeys {
+		if _, ok := uniqueConstraints[key]; ok {
+			return nil, zerrors.ThrowAlreadyExists(nil, "V3-Im3Uc", adds[i].ErrorMessage)
+		}
+		uniqueConstraints[key] = struct{}{}
+	}
+	return uniqueConstraints, nil
+}
+
+// Commands returns all pushed commands in the order they were pushed
+func (es *InmemEventstore) Commands() []eventstore.Command {
+	es.mux.Lock()
+	defer es.mux.Unlock()
+	return append([]eventstore.Command(nil), es.commands...)
+}
+
+// Events returns all stored events in the order they were pushed
+func (es *InmemEventstore) Events() []eventstore.Event {
+	es.mux.Lock()
+	defer es.mux.Unlock()
+	return append([]eventstore.Event(nil), es.events...)
+}
+
+func inmemAggregateOf(aggregate *eventstore.Aggregate) inmemAggregate {
+	return inmemAggregate{
+		instanceID:    aggregate.InstanceID,
+		aggregateType: aggregate.Type,
+		aggregateID:   aggregate.ID,
+	}
+}
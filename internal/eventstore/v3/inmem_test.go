@@ -0,0 +1,194 @@
+package eventstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestInmemEventstore_Push_sequences(t *testing.T) {
+	clock := clock.NewMock()
+	clock.Set(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	es := NewInmemEventstore(clock)
+	ctx := context.Background()
+
+	first, err := es.Push(ctx,
+		&mockCommand{aggregate: mockAggregate("1")},
+		&mockCommand{aggregate: mockAggregate("2")},
+		&mockCommand{aggregate: mockAggregate("1"), payload: map[string]string{"key": "value"}},
+	)
+	require.NoError(t, err)
+	require.Len(t, first, 3)
+	assert.Equal(t, uint64(1), first[0].Sequence())
+	assert.Equal(t, uint64(1), first[1].Sequence())
+	assert.Equal(t, uint64(2), first[2].Sequence())
+	for _, e := range first {
+		assert.Equal(t, float64(1), e.Position())
+		assert.Equal(t, clock.Now(), e.CreatedAt())
+	}
+	var payload map[string]string
+	require.NoError(t, first[2].Unmarshal(&payload))
+	assert.Equal(t, map[string]string{"key": "value"}, payload)
+
+	// the owner of an existing aggregate is kept
+	clock.Add(time.Second)
+	withoutOwner := mockAggregate("1")
+	withoutOwner.ResourceOwner = ""
+	second, err := es.Push(ctx, &mockCommand{aggregate: withoutOwner})
+	require.NoError(t, err)
+	require.Len(t, second, 1)
+	assert.Equal(t, uint64(3), second[0].Sequence())
+	assert.Equal(t, float64(2), second[0].Position())
+	assert.Equal(t, clock.Now(), second[0].CreatedAt())
+	assert.Equal(t, "ro", second[0].Aggregate().ResourceOwner)
+
+	// aggregates of other instances have their own sequence
+	otherInstance := mockAggregate("1")
+	otherInstance.InstanceID = "other"
+	third, err := es.Push(ctx, &mockCommand{aggregate: otherInstance})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), third[0].Sequence())
+
+	assert.Len(t, es.Commands(), 5)
+	assert.Equal(t, append(append(first, second...), third...), es.Events())
+}
+
+func TestInmemEventstore_Push_uniqueConstraints(t *testing.T) {
+	es := NewInmemEventstore(clock.NewMock())
+	ctx := context.Background()
+
+	add := func(field string) *eventstore.UniqueConstraint {
+		return eventstore.NewAddEventUniqueConstraint("name", field, "Errors.Name.AlreadyExists")
+	}
+
+	_, err := es.Push(ctx, &mockCommand{
+		aggregate:   mockAggregate("1"),
+		constraints: []*eventstore.UniqueConstraint{add("Name")},
+	})
+	require.NoError(t, err)
+
+	// constraints are case insensitive
+	_, err = es.Push(ctx,
+		&mockCommand{aggregate: mockAggregate("2")},
+		&mockCommand{
+			aggregate:   mockAggregate("2"),
+			constraints: []*eventstore.UniqueConstraint{add("name")},
+		},
+	)
+	assert.ErrorIs(t, err, zerrors.ThrowAlreadyExists(nil, "V3-Im3Uc", "Errors.Name.AlreadyExists"))
+	// nothing of the failed push is stored
+	assert.Len(t, es.Events(), 1)
+	assert.Len(t, es.Commands(), 1)
+	events, err := es.Push(ctx, &mockCommand{aggregate: mockAggregate("2")})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), events[0].Sequence())
+
+	// constraints of other instances do not conflict
+	otherInstance := mockAggregate("1")
+	otherInstance.InstanceID = "other"
+	_, err = es.Push(ctx, &mockCommand{
+		aggregate:   otherInstance,
+		constraints: []*eventstore.UniqueConstraint{add("name")},
+	})
+	require.NoError(t, err)
+
+	// removed constraints can be added again within the same push
+	_, err = es.Push(ctx, &mockCommand{
+		aggregate: mockAggregate("2"),
+		constraints: []*eventstore.UniqueConstraint{
+			add("name"),
+			eventstore.NewRemoveUniqueConstraint("name", "name"),
+		},
+	})
+	require.NoError(t, err)
+
+	// removing the instance removes all its constraints
+	_, err = es.Push(ctx, &mockCommand{
+		aggregate:   mockAggregate("instance"),
+		constraints: []*eventstore.UniqueConstraint{eventstore.NewRemoveInstanceUniqueConstraints()},
+	})
+	require.NoError(t, err)
+	_, err = es.Push(ctx, &mockCommand{
+		aggregate:   mockAggregate("3"),
+		constraints: []*eventstore.UniqueConstraint{add("name")},
+	})
+	require.NoError(t, err)
+	_, err = es.Push(ctx, &mockCommand{
+		aggregate:   otherInstance,
+		constraints: []*eventstore.UniqueConstraint{add("name")},
+	})
+	assert.True(t, zerrors.IsErrorAlreadyExists(err), "unexpected error: %v", err)
+}
+
+func TestInmemEventstore_Push_withoutUniqueConstraints(t *testing.T) {
+	es := NewInmemEventstore(clock.NewMock())
+	ctx := context.Background()
+	add := &mockCommand{
+		aggregate:   mockAggregate("1"),
+		constraints: []*eventstore.UniqueConstraint{eventstore.NewAddEventUniqueConstraint("name", "name", "Errors.Name.AlreadyExists")},
+	}
+
+	_, err := es.Push(ctx, add)
+	require.NoError(t, err)
+	// the duplicate is not detected if the constraints are skipped
+	_, err = es.Push(WithoutUniqueConstraints(ctx), add)
+	require.NoError(t, err)
+	assert.Len(t, es.Events(), 2)
+
+	// the default push still checks the constraints
+	_, err = es.Push(ctx, add)
+	assert.True(t, zerrors.IsErrorAlreadyExists(err), "unexpected error: %v", err)
+}
+
+func TestInmemEventstore_Push_conditionalUniqueConstraints(t *testing.T) {
+	es := NewInmemEventstore(clock.NewMock())
+	ctx := context.Background()
+
+	// user names are only unique among the active users
+	username := func(id string, wasActive, active bool) *mockCommand {
+		return &mockCommand{
+			aggregate: mockAggregate(id),
+			constraints: []*eventstore.UniqueConstraint{
+				eventstore.NewConditionalUniqueConstraint("username", "gigi", "Errors.User.AlreadyExists", wasActive, active),
+			},
+		}
+	}
+
+	_, err := es.Push(ctx, username("1", false, true))
+	require.NoError(t, err)
+	_, err = es.Push(ctx, username("2", false, true))
+	assert.ErrorIs(t, err, zerrors.ThrowAlreadyExists(nil, "V3-Im3Uc", "Errors.User.AlreadyExists"))
+
+	// a change of the active user keeps its name without a conflict
+	_, err = es.Push(ctx, username("1", true, true))
+	require.NoError(t, err)
+
+	// the name of the deleted user can be reused
+	_, err = es.Push(ctx, username("1", true, false))
+	require.NoError(t, err)
+	_, err = es.Push(ctx, username("2", false, true))
+	require.NoError(t, err)
+
+	// the deleted user cannot be reactivated while the name is used
+	_, err = es.Push(ctx, username("1", false, true))
+	assert.ErrorIs(t, err, zerrors.ThrowAlreadyExists(nil, "V3-Im3Uc", "Errors.User.AlreadyExists"))
+
+	// a change of the deleted user does not release the name of the active user
+	_, err = es.Push(ctx, username("1", false, false))
+	require.NoError(t, err)
+	_, err = es.Push(ctx, username("3", false, true))
+	assert.ErrorIs(t, err, zerrors.ThrowAlreadyExists(nil, "V3-Im3Uc", "Errors.User.AlreadyExists"))
+
+	// the name can be released and reused within the same push
+	_, err = es.Push(ctx, username("2", true, false), username("1", false, true))
+	require.NoError(t, err)
+	_, err = es.Push(ctx, username("3", false, true))
+	assert.ErrorIs(t, err, zerrors.ThrowAlreadyExists(nil, "V3-Im3Uc", "Errors.User.AlreadyExists"))
+}
@@ -0,0 +1,105 @@
+package eventstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/logging"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/telemetry/metrics"
+)
+
+const (
+	PushedCommandsCounter            = "zitadel.eventstore.pushed_commands"
+	PushedCommandsCounterDescription = "Count of commands written to the eventstore"
+	PushedPayloadBytesCounter        = "zitadel.eventstore.pushed_payload_bytes"
+	PushedPayloadBytesDescription    = "Sum of the serialized payload sizes written to the eventstore in bytes"
+	PushLatencyHistogram             = "zitadel.eventstore.push_latency"
+	PushLatencyDescription           = "Duration of the pushes to the eventstore including the begin and commit of the transaction"
+)
+
+// pushLatencyBuckets are the upper bounds of the buckets of the [PushLatencyHistogram] in seconds
+var pushLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PushOutcome describes how a push to the database ended
+type PushOutcome string
+
+const (
+	// PushOutcomeSuccess is a push which committed in the first attempt
+	PushOutcomeSuccess PushOutcome = "success"
+	// PushOutcomeRetried is a push which committed after the transaction was retried
+	PushOutcomeRetried PushOutcome = "retried"
+	// PushOutcomeFailed is a push which did not commit
+	PushOutcomeFailed PushOutcome = "failed"
+)
+
+// pushOutcome returns the outcome of a push after the given attempts
+func pushOutcome(attempts int, err error) PushOutcome {
+	switch {
+	case err != nil:
+		return PushOutcomeFailed
+	case attempts > 1:
+		return PushOutcomeRetried
+	default:
+		return PushOutcomeSuccess
+	}
+}
+
+// Metrics observes the write volume and latency of [Eventstore.Push]
+type Metrics interface {
+	// ObservePushedCommands records the amount of commands written by a single push
+	ObservePushedCommands(ctx context.Context, count int)
+	// ObservePushedPayloadBytes records the sum of the serialized payloads written by a single push
+	ObservePushedPayloadBytes(ctx context.Context, bytes int)
+	// ObservePushLatency records the wall-clock duration of a single push to the database,
+	// including the begin, the retries and the commit or rollback of the transaction
+	ObservePushLatency(ctx context.Context, outcome PushOutcome, latency time.Duration)
+}
+
+var _ Metrics = (*telemetryMetrics)(nil)
+
+// telemetryMetrics reports to the globally configured [metrics.M]
+type telemetryMetrics struct{}
+
+func newTelemetryMetrics() *telemetryMetrics {
+	registerCounter(PushedCommandsCounter, PushedCommandsCounterDescription)
+	registerCounter(PushedPayloadBytesCounter, PushedPayloadBytesDescription)
+	err := metrics.RegisterHistogram(PushLatencyHistogram, PushLatencyDescription, "s", pushLatencyBuckets)
+	logging.WithFields("metric", PushLatencyHistogram).OnError(err).Panic("unable to register histogram")
+	return new(telemetryMetrics)
+}
+
+func registerCounter(counter, desc string) {
+	err := metrics.RegisterCounter(counter, desc)
+	logging.WithFields("metric", counter).OnError(err).Panic("unable to register counter")
+}
+
+// ObservePushedCommands implements [Metrics]
+func (*telemetryMetrics) ObservePushedCommands(ctx context.Context, count int) {
+	addCount(ctx, PushedCommandsCounter, int64(count))
+}
+
+// ObservePushedPayloadBytes implements [Metrics]
+func (*telemetryMetrics) ObservePushedPayloadBytes(ctx context.Context, bytes int) {
+	addCount(ctx, PushedPayloadBytesCounter, int64(bytes))
+}
+
+// ObservePushLatency implements [Metrics]
+func (*telemetryMetrics) ObservePushLatency(ctx context.Context, outcome PushOutcome, latency time.Duration) {
+	labels := map[string]attribute.Value{
+		"instance": attribute.StringValue(authz.GetInstance(ctx).InstanceID()),
+		"outcome":  attribute.StringValue(string(outcome)),
+	}
+	err := metrics.RecordHistogram(ctx, PushLatencyHistogram, latency.Seconds(), labels)
+	logging.WithFields("name", PushLatencyHistogram).OnError(err).Warn("recording histogram metric failed")
+}
+
+func addCount(ctx context.Context, name string, value int64) {
+	labels := map[string]attribute.Value{
+		"instance": attribute.StringValue(authz.GetInstance(ctx).InstanceID()),
+	}
+	err := metrics.AddCount(ctx, name, value, labels)
+	logging.WithFields("name", name).OnError(err).Warn("incrementing counter metric failed")
+}
@@ -62,3 +62,22 @@ func mockAggregate(id string) *eventstore.Aggregate {
 		Version:       "v1",
 	}
 }
+
+var _ eventstore.SerializedPayloadCommand = (*mockSerializedCommand)(nil)
+
+// mockSerializedCommand is a [mockCommand] with an already serialized payload
+type mockSerializedCommand struct {
+	mockCommand
+	data        []byte
+	contentType eventstore.PayloadContentType
+}
+
+// Payload implements [eventstore.Command]
+func (m *mockSerializedCommand) Payload() any {
+	return m.data
+}
+
+// SerializedPayload implements [eventstore.SerializedPayloadCommand]
+func (m *mockSerializedCommand) SerializedPayload() ([]byte, eventstore.PayloadContentType) {
+	return m.data, m.contentType
+}
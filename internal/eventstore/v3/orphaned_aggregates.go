@@ -0,0 +1,70 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"regexp"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+var (
+	//go:embed orphaned_aggregates.sql
+	orphanedAggregatesStmt string
+
+	// projectionTableName matches a schema qualified table name like projections.targets2
+	projectionTableName = regexp.MustCompile(`^[a-z_][a-z0-9_]*\.[a-z_][a-z0-9_]*$`)
+)
+
+// OrphanedAggregatesQuery describes a page of [Eventstore.OrphanedAggregates].
+type OrphanedAggregatesQuery struct {
+	InstanceID    string
+	AggregateType eventstore.AggregateType
+	// ProjectionTable is the schema qualified table of the projection,
+	// it must contain the columns instance_id and id
+	ProjectionTable string
+	// After is the last aggregate id of the previous page, empty for the first page
+	After string
+	Limit uint32
+}
+
+// OrphanedAggregates returns the ids of the aggregates which have events
+// but are not present in the projection table, ordered by the aggregate id.
+// It is a read-only diagnostic for operators, e.g. during projection rebuilds.
+// The next page is requested with the last returned id as [OrphanedAggregatesQuery.After],
+// less ids than the limit mean there are no more pages.
+func (es *Eventstore) OrphanedAggregates(ctx context.Context, query *OrphanedAggregatesQuery) (ids []string, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if !projectionTableName.MatchString(query.ProjectionTable) || query.AggregateType == "" || query.Limit == 0 {
+		return nil, zerrors.ThrowInvalidArgument(nil, "V3-0rphA", "Errors.Eventstore.InvalidQuery")
+	}
+
+	ids = make([]string, 0, query.Limit)
+	err = es.client.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			for rows.Next() {
+				var id string
+				if err := rows.Scan(&id); err != nil {
+					return err
+				}
+				ids = append(ids, id)
+			}
+			return nil
+		},
+		fmt.Sprintf(orphanedAggregatesStmt, query.ProjectionTable),
+		query.InstanceID,
+		string(query.AggregateType),
+		query.After,
+		query.Limit,
+	)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "V3-b6Wkq", "Errors.Internal")
+	}
+	return ids, nil
+}
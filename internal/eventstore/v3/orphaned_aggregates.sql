@@ -0,0 +1,14 @@
+SELECT DISTINCT
+    e.aggregate_id
+FROM
+    eventstore.events2 e
+WHERE
+    e.instance_id = $1
+    AND e.aggregate_type = $2
+    AND e.aggregate_id > $3
+    AND NOT EXISTS (
+        SELECT 1 FROM %s p WHERE p.instance_id = e.instance_id AND p.id = e.aggregate_id
+    )
+ORDER BY
+    e.aggregate_id
+LIMIT $4;
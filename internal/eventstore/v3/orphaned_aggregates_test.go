@@ -0,0 +1,138 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestEventstore_OrphanedAggregates(t *testing.T) {
+	stmt := fmt.Sprintf(orphanedAggregatesStmt, "projections.targets2")
+	tests := []struct {
+		name         string
+		query        *OrphanedAggregatesQuery
+		expectations []mock.Expectation
+		want         []string
+		wantErr      func(error) bool
+	}{
+		{
+			name: "invalid projection table",
+			query: &OrphanedAggregatesQuery{
+				InstanceID:      "instance",
+				AggregateType:   "target",
+				ProjectionTable: "projections.targets2; DROP TABLE eventstore.events2",
+				Limit:           10,
+			},
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+		{
+			name: "no limit",
+			query: &OrphanedAggregatesQuery{
+				InstanceID:      "instance",
+				AggregateType:   "target",
+				ProjectionTable: "projections.targets2",
+			},
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+		{
+			name: "no orphans",
+			query: &OrphanedAggregatesQuery{
+				InstanceID:      "instance",
+				AggregateType:   "target",
+				ProjectionTable: "projections.targets2",
+				Limit:           10,
+			},
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery(stmt,
+					mock.WithQueryArgs("instance", "target", "", uint32(10)),
+					mock.WithQueryResult([]string{"aggregate_id"}, nil),
+				),
+				mock.ExpectCommit(nil),
+			},
+			want: []string{},
+		},
+		{
+			name: "deleted from projection, events present",
+			query: &OrphanedAggregatesQuery{
+				InstanceID:      "instance",
+				AggregateType:   "target",
+				ProjectionTable: "projections.targets2",
+				Limit:           2,
+			},
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery(stmt,
+					mock.WithQueryArgs("instance", "target", "", uint32(2)),
+					mock.WithQueryResult([]string{"aggregate_id"}, [][]driver.Value{{"target1"}, {"target2"}}),
+				),
+				mock.ExpectCommit(nil),
+			},
+			want: []string{"target1", "target2"},
+		},
+		{
+			name: "next page",
+			query: &OrphanedAggregatesQuery{
+				InstanceID:      "instance",
+				AggregateType:   "target",
+				ProjectionTable: "projections.targets2",
+				After:           "target2",
+				Limit:           2,
+			},
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery(stmt,
+					mock.WithQueryArgs("instance", "target", "target2", uint32(2)),
+					mock.WithQueryResult([]string{"aggregate_id"}, [][]driver.Value{{"target3"}}),
+				),
+				mock.ExpectCommit(nil),
+			},
+			want: []string{"target3"},
+		},
+		{
+			name: "query fails",
+			query: &OrphanedAggregatesQuery{
+				InstanceID:      "instance",
+				AggregateType:   "target",
+				ProjectionTable: "projections.targets2",
+				Limit:           2,
+			},
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery(stmt,
+					mock.WithQueryErr(errors.New("connection lost")),
+				),
+				mock.ExpectRollback(nil),
+			},
+			wantErr: zerrors.IsInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlMock := mock.NewSQLMock(t, tt.expectations...)
+			defer sqlMock.Assert(t)
+			es := NewEventstore(
+				&database.DB{DB: sqlMock.DB, Database: new(cockroach.Config)},
+				WithMetrics(new(testMetrics)),
+			)
+
+			got, err := es.OrphanedAggregates(context.Background(), tt.query)
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
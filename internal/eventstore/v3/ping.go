@@ -0,0 +1,51 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// Ping checks if the eventstore is able to accept writes without writing any data.
+// It starts a transaction, executes a trivial statement and rolls the transaction back.
+//
+// The returned error is a [zerrors.PermissionDeniedError] if the database user lacks the required privileges,
+// a [zerrors.DeadlineExceededError] if the deadline of ctx exceeded
+// and a [zerrors.UnavailableError] for all other failures like connection issues.
+func (es *Eventstore) Ping(ctx context.Context) (err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	tx, err := es.client.BeginTx(ctx, nil)
+	if err != nil {
+		return pingError(ctx, err, "V3-Rn2aU")
+	}
+	defer func() {
+		rollbackErr := tx.Rollback()
+		logging.OnError(rollbackErr).Debug("rollback of ping failed")
+	}()
+
+	var one int
+	if err = tx.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+		return pingError(ctx, err, "V3-c0Lfe")
+	}
+	return nil
+}
+
+func pingError(ctx context.Context, err error, id string) error {
+	if ctx.Err() != nil || errors.Is(err, context.DeadlineExceeded) {
+		return zerrors.ThrowDeadlineExceeded(err, id, "Errors.Eventstore.Unavailable")
+	}
+	var pgErr *pgconn.PgError
+	// class 28: invalid authorization specification, 42501: insufficient privilege
+	if errors.As(err, &pgErr) && (strings.HasPrefix(pgErr.Code, "28") || pgErr.Code == "42501") {
+		return zerrors.ThrowPermissionDenied(err, id, "Errors.Eventstore.PermissionDenied")
+	}
+	return zerrors.ThrowUnavailable(err, id, "Errors.Eventstore.Unavailable")
+}
@@ -0,0 +1,91 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestEventstore_Ping(t *testing.T) {
+	tests := []struct {
+		name         string
+		expectations []mock.Expectation
+		timeout      time.Duration
+		wantErr      func(error) bool
+	}{
+		{
+			name: "ok",
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery("SELECT 1",
+					mock.WithQueryResult([]string{"?column?"}, [][]driver.Value{{1}}),
+				),
+				mock.ExpectRollback(nil),
+			},
+		},
+		{
+			name: "begin fails, unavailable",
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(errors.New("connection refused")),
+			},
+			wantErr: zerrors.IsUnavailable,
+		},
+		{
+			name: "begin fails, permission denied",
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(&pgconn.PgError{Code: "28P01"}),
+			},
+			wantErr: zerrors.IsPermissionDenied,
+		},
+		{
+			name: "query fails, permission denied",
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExpectQuery("SELECT 1",
+					mock.WithQueryErr(&pgconn.PgError{Code: "42501"}),
+				),
+				mock.ExpectRollback(nil),
+			},
+			wantErr: zerrors.IsPermissionDenied,
+		},
+		{
+			name:    "deadline exceeded",
+			timeout: -time.Second,
+			wantErr: zerrors.IsDeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlMock := mock.NewSQLMock(t, tt.expectations...)
+			defer sqlMock.Assert(t)
+			es := NewEventstore(
+				&database.DB{DB: sqlMock.DB, Database: new(cockroach.Config)},
+				WithMetrics(new(testMetrics)),
+			)
+
+			ctx := context.Background()
+			if tt.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			err := es.Ping(ctx)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package eventstore
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PlaceholderFormat returns the placeholder of the argument at position of the insert of the events, starting at 1
+type PlaceholderFormat func(position int) string
+
+var (
+	// DollarPlaceholders formats the placeholders as $1, $2, ..., as expected by Postgres and CockroachDB
+	DollarPlaceholders PlaceholderFormat = func(position int) string {
+		return "$" + strconv.Itoa(position)
+	}
+	// QuestionPlaceholders formats all placeholders as ?
+	QuestionPlaceholders PlaceholderFormat = func(int) string {
+		return "?"
+	}
+)
+
+// WithPlaceholderFormat formats the placeholders of the insert of the events with format instead of [DollarPlaceholders].
+// The placeholders of the unique constraints are not changed, as their statements refer to arguments multiple times.
+func WithPlaceholderFormat(format PlaceholderFormat) Option {
+	return func(es *Eventstore) {
+		es.placeholderFormat = format
+	}
+}
+
+// rowPlaceholders formats the placeholders of a row of the insert starting after offset.
+// rowFmt is one of [pushPlaceholderFmt], [pushCompressedPlaceholderFmt] and [pushWithoutAddedColumnsPlaceholderFmt].
+func (es *Eventstore) rowPlaceholders(rowFmt string, offset int) string {
+	var b strings.Builder
+	position := offset
+	for {
+		i := strings.Index(rowFmt, "$%d")
+		if i < 0 {
+			break
+		}
+		position++
+		b.WriteString(rowFmt[:i])
+		b.WriteString(es.placeholderFormat(position))
+		rowFmt = rowFmt[i+len("$%d"):]
+	}
+	b.WriteString(rowFmt)
+	return b.String()
+}
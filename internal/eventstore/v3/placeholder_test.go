@@ -0,0 +1,92 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/database/dialect"
+	"github.com/zitadel/zitadel/internal/database/postgres"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+func Test_mapCommands_placeholderFormat(t *testing.T) {
+	questionRow := "(?, ?, ?, ?, ?, ?, ?, ?, ?, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), ?, ?, ?, ?)"
+	tests := []struct {
+		name             string
+		database         dialect.Database
+		opts             []Option
+		wantPlaceholders []string
+	}{
+		{
+			name:     "default cockroach",
+			database: new(cockroach.Config),
+			wantPlaceholders: []string{
+				"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10, $11, $12, $13)",
+				"($14, $15, $16, $17, $18, $19, $20, $21, $22, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $23, $24, $25, $26)",
+			},
+		},
+		{
+			name:     "dollar postgres",
+			database: new(postgres.Config),
+			opts:     []Option{WithPlaceholderFormat(DollarPlaceholders)},
+			wantPlaceholders: []string{
+				"($1, $2, $3, $4, $5, $6, $7, $8, $9, statement_timestamp(), EXTRACT(EPOCH FROM clock_timestamp()), $10, $11, $12, $13)",
+				"($14, $15, $16, $17, $18, $19, $20, $21, $22, statement_timestamp(), EXTRACT(EPOCH FROM clock_timestamp()), $23, $24, $25, $26)",
+			},
+		},
+		{
+			name:             "question cockroach",
+			database:         new(cockroach.Config),
+			opts:             []Option{WithPlaceholderFormat(QuestionPlaceholders)},
+			wantPlaceholders: []string{questionRow, questionRow},
+		},
+		{
+			name:     "question compressed",
+			database: new(cockroach.Config),
+			opts:     []Option{WithPlaceholderFormat(QuestionPlaceholders), WithPayloadCompression(16)},
+			wantPlaceholders: []string{
+				"(?, ?, ?, ?, ?, ?, ?, ?, ?, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), ?, ?, ?, ?, ?, ?)",
+				"(?, ?, ?, ?, ?, ?, ?, ?, ?, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), ?, ?, ?, ?, ?, ?)",
+			},
+		},
+		{
+			name:     "custom",
+			database: new(cockroach.Config),
+			opts: []Option{WithPlaceholderFormat(func(position int) string {
+				return fmt.Sprintf(":arg%d", position)
+			})},
+			wantPlaceholders: []string{
+				"(:arg1, :arg2, :arg3, :arg4, :arg5, :arg6, :arg7, :arg8, :arg9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), :arg10, :arg11, :arg12, :arg13)",
+				"(:arg14, :arg15, :arg16, :arg17, :arg18, :arg19, :arg20, :arg21, :arg22, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), :arg23, :arg24, :arg25, :arg26)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := NewEventstore(&database.DB{Database: tt.database}, tt.opts...)
+			commands := []eventstore.Command{
+				&mockCommand{aggregate: mockAggregate("V3-Ph1Fm")},
+				&mockCommand{aggregate: mockAggregate("V3-Ph1Fm")},
+			}
+			_, placeholders, args, err := es.mapCommands(context.Background(), commands, []*latestSequence{{aggregate: mockAggregate("V3-Ph1Fm")}})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPlaceholders, placeholders)
+
+			// each argument has exactly one placeholder
+			row := strings.Join(placeholders, ", ")
+			assert.Equal(t, len(args), countPlaceholders(row))
+		})
+	}
+}
+
+// countPlaceholders counts the $n, ? and :argn placeholders of the statement
+func countPlaceholders(stmt string) int {
+	return strings.Count(stmt, "$") + strings.Count(stmt, "?") + strings.Count(stmt, ":arg")
+}
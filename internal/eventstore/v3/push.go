@@ -3,65 +3,244 @@ package eventstore
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	_ "embed"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/cockroachdb/cockroach-go/v2/crdb"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/zitadel/logging"
 
 	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
+// ErrPoolExhausted is returned by [Eventstore.Push] if no connection was available within the acquire timeout
+var ErrPoolExhausted = errors.New("no database connection available")
+
+// ErrConnectionLost is returned by [Eventstore.Push] if the connection to the database was lost while the events were inserted.
+// The transaction did not commit, so the caller can safely retry the whole push on a new connection.
+var ErrConnectionLost = errors.New("database connection lost")
+
+// ErrPayloadTooLarge is returned by [Eventstore.Push] if the payload of a command exceeds the limit set by [WithMaxPayloadSize],
+// the error identifies the offending command
+var ErrPayloadTooLarge = errors.New("payload too large")
+
+// Push appends the events of the commands in a single transaction.
+// Push returns only after the commit is durable if it is requested using [WithDurableCommit].
+// If [WithDeduplication] is enabled, a push identical to the latest events of its aggregates is not written again.
+// If the connection is lost while the events are inserted, Push fails with [ErrConnectionLost].
+// The commands are enriched by the transforms registered with [WithCommandTransform] before they are inserted.
 func (es *Eventstore) Push(ctx context.Context, commands ...eventstore.Command) (events []eventstore.Event, err error) {
+	commands, err = es.transformCommands(ctx, commands)
+	if err != nil {
+		return nil, err
+	}
+	if es.dedup == nil {
+		return es.pushInTx(ctx, commands)
+	}
+	return es.pushDeduplicated(ctx, commands)
+}
+
+// pushDeduplicated pushes the commands unless each of them is identical to the latest event written to its aggregate
+// within the deduplication window, in which case the events written before are returned in the order of the commands.
+// The events are remembered after the transaction committed, so retries of the transaction are never suppressed.
+func (es *Eventstore) pushDeduplicated(ctx context.Context, commands []eventstore.Command) ([]eventstore.Event, error) {
+	keys, err := es.dedup.contentKeys(es.codec, commands)
+	if err != nil {
+		return nil, err
+	}
+	if events := es.dedup.lookup(commands, keys); events != nil {
+		return events, nil
+	}
+
+	events, err := es.pushInTx(ctx, commands)
+	if err != nil {
+		return nil, err
+	}
+	es.dedup.record(keys, events)
+	return events, nil
+}
+
+// pushInTx appends the events of the commands in a new transaction, which is retried on retryable errors.
+// The latency of the push is observed regardless of its outcome.
+func (es *Eventstore) pushInTx(ctx context.Context, commands []eventstore.Command) (events []eventstore.Event, err error) {
+	start := time.Now()
+	var attempts int
+	defer func() {
+		es.metrics.ObservePushLatency(ctx, pushOutcome(attempts, err), time.Since(start))
+	}()
+	if es.skipSingleCommandSavepoint && len(commands) == 1 {
+		attempts++
+		events, err = es.pushWithoutSavepoint(ctx, commands)
+		if isRetryableErr(err) {
+			// the push is contended, the retries use the savepoint
+			events, err = es.pushWithSavepoint(ctx, commands, &attempts)
+		}
+	} else {
+		events, err = es.pushWithSavepoint(ctx, commands, &attempts)
+	}
+	reportPushResult(ctx, attempts)
+
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// pushWithSavepoint appends the events of the commands in a new transaction,
+// the push is retried within the transaction on retryable errors by rolling back to a savepoint
+func (es *Eventstore) pushWithSavepoint(ctx context.Context, commands []eventstore.Command, attempts *int) (events []eventstore.Event, err error) {
 	ctx, spanBeginTx := tracing.NewNamedSpan(ctx, "db.BeginTx")
-	tx, err := es.client.BeginTx(ctx, nil)
+	tx, release, err := es.txBeginner.beginTx(ctx)
 	spanBeginTx.EndWithError(err)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	// tx is not closed because [crdb.ExecuteInTx] takes care of that
-	var (
-		sequences []*latestSequence
-	)
-
 	err = crdb.ExecuteInTx(ctx, &transaction{tx}, func() error {
-		sequences, err = latestSequences(ctx, tx, commands)
-		if err != nil {
-			return err
-		}
+		*attempts++
+		events, err = es.push(ctx, tx, commands)
+		return err
+	})
+	return events, err
+}
 
-		events, err = insertEvents(ctx, tx, sequences, commands)
-		if err != nil {
-			return err
-		}
+// pushWithoutSavepoint appends the events of the commands in a new transaction without a savepoint,
+// the transaction is rolled back if the push fails and is not retried
+func (es *Eventstore) pushWithoutSavepoint(ctx context.Context, commands []eventstore.Command) (events []eventstore.Event, err error) {
+	ctx, spanBeginTx := tracing.NewNamedSpan(ctx, "db.BeginTx")
+	tx, release, err := es.txBeginner.beginTx(ctx)
+	spanBeginTx.EndWithError(err)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	events, err = es.push(ctx, tx, commands)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		logging.OnError(rollbackErr).Debug("rollback of push failed")
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
 
-		return handleUniqueConstraints(ctx, tx, commands)
-	})
+// isRetryableErr returns true if the transaction failed because of a conflict with a concurrent transaction
+func isRetryableErr(err error) bool {
+	pgErr := new(pgconn.PgError)
+	return errors.As(err, &pgErr) && pgErr.Code == "40001"
+}
+
+// isConnectionLostErr returns true if the error was caused by a closed or reset connection.
+// Such errors are not retried within the transaction, because the connection it runs on is gone.
+func isConnectionLostErr(err error) bool {
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, driver.ErrBadConn)
+}
+
+// throwConnectionLost classifies the error as [ErrConnectionLost] and keeps the cause
+func throwConnectionLost(err error) error {
+	return zerrors.ThrowUnavailable(fmt.Errorf("%w: %w", ErrConnectionLost, err), "V3-Cn7lSt", "Errors.Eventstore.ConnectionLost")
+}
 
+// PushTx appends the events of the commands within a transaction owned by the caller.
+// The transaction is neither committed nor rolled back, the caller is responsible for its lifecycle.
+// The push is wrapped in a savepoint so that a failed push can be rolled back without aborting the caller's transaction.
+func (es *Eventstore) PushTx(ctx context.Context, tx *sql.Tx, commands ...eventstore.Command) (events []eventstore.Event, err error) {
+	commands, err = es.transformCommands(ctx, commands)
 	if err != nil {
 		return nil, err
 	}
+	if _, err = tx.ExecContext(ctx, "SAVEPOINT push"); err != nil {
+		return nil, zerrors.ThrowInternal(err, "V3-Gm2kD", "Errors.Internal")
+	}
+	events, err = es.push(ctx, tx, commands)
+	if err != nil {
+		_, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT push")
+		logging.OnError(rollbackErr).Debug("rollback to savepoint failed")
+		return nil, err
+	}
+	if _, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT push"); err != nil {
+		return nil, zerrors.ThrowInternal(err, "V3-qB1zW", "Errors.Internal")
+	}
+	return events, nil
+}
 
+func (es *Eventstore) push(ctx context.Context, tx pushTx, commands []eventstore.Command) ([]eventstore.Event, error) {
+	if err := es.requireDurableCommit(ctx, tx); err != nil {
+		return nil, err
+	}
+	sequences, err := latestSequences(ctx, tx, commands)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = es.injectFault(ctx, FaultPointInsertEvents); err != nil {
+		return nil, err
+	}
+	events, err := es.insertEvents(ctx, tx, sequences, commands)
+	if err != nil {
+		return nil, err
+	}
+
+	if uniqueConstraintsSkipped(ctx) {
+		return events, nil
+	}
+	if err = es.injectFault(ctx, FaultPointUniqueConstraints); err != nil {
+		return nil, err
+	}
+	if err = handleUniqueConstraints(ctx, tx, commands); err != nil {
+		return nil, err
+	}
 	return events, nil
 }
 
-//go:embed push.sql
-var pushStmt string
+var (
+	//go:embed push.sql
+	pushStmt string
+	//go:embed push_compressed.sql
+	pushCompressedStmt string
+	//go:embed push_without_added_columns.sql
+	pushWithoutAddedColumnsStmt string
+)
 
-func insertEvents(ctx context.Context, tx *sql.Tx, sequences []*latestSequence, commands []eventstore.Command) ([]eventstore.Event, error) {
-	events, placeholders, args, err := mapCommands(commands, sequences)
+func (es *Eventstore) insertEvents(ctx context.Context, tx pushTx, sequences []*latestSequence, commands []eventstore.Command) ([]eventstore.Event, error) {
+	events, placeholders, args, err := es.mapCommands(ctx, commands, sequences)
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := tx.QueryContext(ctx, fmt.Sprintf(pushStmt, strings.Join(placeholders, ", ")), args...)
+	stmt := pushStmt
+	if es.withoutAddedColumns {
+		stmt = pushWithoutAddedColumnsStmt
+	} else if es.writesPayloadEncoding() {
+		stmt = pushCompressedStmt
+	}
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(stmt, strings.Join(placeholders, ", ")), args...)
 	if err != nil {
+		pushFailureLogger(err, commands).Debug("insert events failed")
+		if isConnectionLostErr(err) {
+			return nil, throwConnectionLost(err)
+		}
 		return nil, err
 	}
 	defer rows.Close()
@@ -69,12 +248,17 @@ func insertEvents(ctx context.Context, tx *sql.Tx, sequences []*latestSequence,
 	for i := 0; rows.Next(); i++ {
 		err = rows.Scan(&events[i].(*event).createdAt, &events[i].(*event).position)
 		if err != nil {
+			pushFailureLogger(err, commands).Debug("scan of inserted events failed")
 			logging.WithError(err).Warn("failed to scan events")
 			return nil, err
 		}
 	}
 
 	if err := rows.Err(); err != nil {
+		pushFailureLogger(err, commands).Debug("insert events failed")
+		if isConnectionLostErr(err) {
+			return nil, throwConnectionLost(err)
+		}
 		pgErr := new(pgconn.PgError)
 		if errors.As(err, &pgErr) {
 			// Check if push tries to write an event just written
@@ -87,18 +271,94 @@ func insertEvents(ctx context.Context, tx *sql.Tx, sequences []*latestSequence,
 		logging.WithError(rows.Err()).Warn("failed to push events")
 		return nil, zerrors.ThrowInternal(err, "V3-VGnZY", "Errors.Internal")
 	}
+	es.observePush(ctx, events)
 
 	return events, nil
 }
 
-const argsPerCommand = 10
+// pushFailureLogger returns a logger describing the commands of a failed push.
+// Payloads are not logged as they might contain sensitive data.
+func pushFailureLogger(err error, commands []eventstore.Command) *logging.Entry {
+	aggregateTypes := make([]string, 0, len(commands))
+	instances := make([]string, 0, 1)
+	for _, command := range commands {
+		if aggregateType := string(command.Aggregate().Type); !slices.Contains(aggregateTypes, aggregateType) {
+			aggregateTypes = append(aggregateTypes, aggregateType)
+		}
+		if instance := command.Aggregate().InstanceID; !slices.Contains(instances, instance) {
+			instances = append(instances, instance)
+		}
+	}
+	var sqlState string
+	pgErr := new(pgconn.PgError)
+	if errors.As(err, &pgErr) {
+		sqlState = pgErr.Code
+	}
+	return logging.WithFields(
+		"aggregateTypes", aggregateTypes,
+		"commandCount", len(commands),
+		"instances", instances,
+		"sqlState", sqlState,
+	).WithError(err)
+}
+
+// observePush records the write volume of the inserted events,
+// the payload size is measured on the serialized payload as it is passed to the database
+func (es *Eventstore) observePush(ctx context.Context, events []eventstore.Event) {
+	var payloadBytes int
+	for _, e := range events {
+		payloadBytes += len(e.(*event).payload)
+	}
+	es.metrics.ObservePushedCommands(ctx, len(events))
+	es.metrics.ObservePushedPayloadBytes(ctx, payloadBytes)
+}
+
+const (
+	argsPerCommand = 13
+	// argsPerCompressedCommand adds the payload encoding and the compressed payload
+	argsPerCompressedCommand = argsPerCommand + 2
+	// argsWithoutAddedColumns omits the metadata, the causation and the correlation
+	argsWithoutAddedColumns = argsPerCommand - 3
+)
 
-func mapCommands(commands []eventstore.Command, sequences []*latestSequence) (events []eventstore.Event, placeholders []string, args []any, err error) {
+// mapCommands maps the commands to the events and the arguments of the insert,
+// all events are annotated with the metadata, the causation and the correlation of the context.
+// The metadata of commands enriched by transforms replace the metadata of the context.
+func (es *Eventstore) mapCommands(ctx context.Context, commands []eventstore.Command, sequences []*latestSequence) (events []eventstore.Event, placeholders []string, args []any, err error) {
+	argsPerRow, placeholderFmt := argsPerCommand, pushPlaceholderFmt
+	if es.withoutAddedColumns {
+		argsPerRow, placeholderFmt = argsWithoutAddedColumns, pushWithoutAddedColumnsPlaceholderFmt
+	} else if es.writesPayloadEncoding() {
+		argsPerRow, placeholderFmt = argsPerCompressedCommand, pushCompressedPlaceholderFmt
+	}
+	metadata := eventstore.MetadataFromContext(ctx)
+	metadataValue, err := marshalMetadata(metadata)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	causationID := eventstore.CausationIDFromContext(ctx)
+	correlationID := eventstore.CorrelationIDFromContext(ctx)
 	events = make([]eventstore.Event, len(commands))
-	args = make([]any, 0, len(commands)*argsPerCommand)
+	args = make([]any, 0, len(commands)*argsPerRow)
 	placeholders = make([]string, len(commands))
 
 	for i, command := range commands {
+		commandMetadata, commandMetadataValue := metadata, metadataValue
+		if transformed, ok := command.(*transformedCommand); ok {
+			// the command is unwrapped, so that the interfaces it implements are detected
+			command, commandMetadata = transformed.Command, transformed.metadata
+			if commandMetadataValue, err = marshalMetadata(commandMetadata); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		revision, err := aggregateRevision(command.Aggregate().Version)
+		if err != nil {
+			return nil, nil, nil, zerrors.ThrowInvalidArgument(
+				fmt.Errorf("command %d (type %s of aggregate %s %s): %w", i, command.Type(), command.Aggregate().Type, command.Aggregate().ID, err),
+				"V3-Vr5nQa",
+				"Errors.Eventstore.InvalidAggregateVersion",
+			)
+		}
 		sequence := searchSequenceByCommand(sequences, command)
 		if sequence == nil {
 			logging.WithFields(
@@ -111,27 +371,38 @@ func mapCommands(commands []eventstore.Command, sequences []*latestSequence) (ev
 		}
 		sequence.sequence++
 
-		events[i], err = commandToEvent(sequence, command)
+		events[i], err = commandToEvent(es.codec, es.redaction, sequence, command)
 		if err != nil {
 			return nil, nil, nil, err
 		}
+		if !es.withoutAddedColumns {
+			events[i].(*event).metadata = commandMetadata
+			events[i].(*event).causationID = causationID
+			events[i].(*event).correlationID = correlationID
+		}
+		if es.maxPayloadSize > 0 && len(events[i].(*event).payload) > es.maxPayloadSize {
+			return nil, nil, nil, zerrors.ThrowInvalidArgument(
+				fmt.Errorf("command %d (type %s of aggregate %s %s): %w: %d bytes exceed the limit of %d bytes", i, command.Type(), command.Aggregate().Type, command.Aggregate().ID, ErrPayloadTooLarge, len(events[i].(*event).payload), es.maxPayloadSize),
+				"V3-Pl3sZe",
+				"Errors.Eventstore.PayloadTooLarge",
+			)
+		}
+		// only JSON payloads are reduced as objects by the read side
+		if es.validatePayloads && events[i].(*event).encoding == repository.PayloadEncodingJSON {
+			if err = validatePayload(events[i].(*event).payload); err != nil {
+				return nil, nil, nil, zerrors.ThrowInvalidArgument(
+					fmt.Errorf("command %d (type %s of aggregate %s %s): %w", i, command.Type(), command.Aggregate().Type, command.Aggregate().ID, err),
+					"V3-Uf3sM",
+					"Errors.Eventstore.InvalidPayload",
+				)
+			}
+		}
 
-		placeholders[i] = fmt.Sprintf(pushPlaceholderFmt,
-			i*argsPerCommand+1,
-			i*argsPerCommand+2,
-			i*argsPerCommand+3,
-			i*argsPerCommand+4,
-			i*argsPerCommand+5,
-			i*argsPerCommand+6,
-			i*argsPerCommand+7,
-			i*argsPerCommand+8,
-			i*argsPerCommand+9,
-			i*argsPerCommand+10,
-		)
+		placeholders[i] = es.rowPlaceholders(placeholderFmt, i*argsPerRow)
 
-		revision, err := strconv.Atoi(strings.TrimPrefix(string(events[i].(*event).aggregate.Version), "v"))
+		payload, encoding, err := es.encodePayload(events[i].(*event).payload, events[i].(*event).encoding)
 		if err != nil {
-			return nil, nil, nil, zerrors.ThrowInternal(err, "V3-JoZEp", "Errors.Internal")
+			return nil, nil, nil, err
 		}
 		args = append(args,
 			events[i].(*event).aggregate.InstanceID,
@@ -141,30 +412,78 @@ func mapCommands(commands []eventstore.Command, sequences []*latestSequence) (ev
 			revision,
 			events[i].(*event).creator,
 			events[i].(*event).typ,
-			events[i].(*event).payload,
+			payload,
 			events[i].(*event).sequence,
 			i,
 		)
+		if es.withoutAddedColumns {
+			continue
+		}
+		args = append(args,
+			commandMetadataValue,
+			nullableID(causationID),
+			nullableID(correlationID),
+		)
+		args = append(args, encoding...)
 	}
 
 	return events, placeholders, args, nil
 }
 
-type transaction struct {
-	*sql.Tx
-}
+// aggregateRevisionRegexp matches the versions stored as revision of the events, e.g. v1
+var aggregateRevisionRegexp = regexp.MustCompile(`^v[1-9][0-9]*$`)
 
-var _ crdb.Tx = (*transaction)(nil)
+// aggregateRevision returns the revision column of the version of an aggregate.
+// Only versions of the form v<revision> are known, as the revision column can not store anything else.
+func aggregateRevision(version eventstore.Version) (int, error) {
+	if version == "" {
+		return 0, errors.New("aggregate version is empty")
+	}
+	if !aggregateRevisionRegexp.MatchString(string(version)) {
+		return 0, fmt.Errorf("aggregate version %q is not of the form v<revision>", version)
+	}
+	revision, err := strconv.Atoi(strings.TrimPrefix(string(version), "v"))
+	if err != nil {
+		return 0, fmt.Errorf("aggregate version %q: %w", version, err)
+	}
+	return revision, nil
+}
 
-func (t *transaction) Exec(ctx context.Context, query string, args ...interface{}) error {
-	_, err := t.Tx.ExecContext(ctx, query, args...)
-	return err
+// placeholderIndexes returns the indexes of the placeholders of a row starting after offset
+func placeholderIndexes(offset, count int) []any {
+	indexes := make([]any, count)
+	for i := range indexes {
+		indexes[i] = offset + i + 1
+	}
+	return indexes
 }
 
-func (t *transaction) Commit(ctx context.Context) error {
-	return t.Tx.Commit()
+// writesPayloadEncoding reports if the payload encoding is written, which is required for compressed payloads
+// and payloads of codecs other than JSON
+func (es *Eventstore) writesPayloadEncoding() bool {
+	if es.withoutAddedColumns {
+		return false
+	}
+	_, isJSON := es.codec.(JSONCodec)
+	return es.compressionThreshold > 0 || !isJSON
 }
 
-func (t *transaction) Rollback(ctx context.Context) error {
-	return t.Tx.Rollback()
+// encodePayload returns the value of the payload column and the additional args for the payload encoding.
+// Protobuf payloads and JSON payloads above the compression threshold are moved to the compressed payload column.
+// No additional args are returned if the payload encoding is not written.
+func (es *Eventstore) encodePayload(payload Payload, payloadEncoding repository.PayloadEncoding) (_ Payload, encoding []any, err error) {
+	if !es.writesPayloadEncoding() {
+		return payload, nil, nil
+	}
+	if payloadEncoding == repository.PayloadEncodingProtobuf {
+		return nil, []any{int16(repository.PayloadEncodingProtobuf), []byte(payload)}, nil
+	}
+	if es.compressionThreshold <= 0 || len(payload) <= es.compressionThreshold {
+		return payload, []any{int16(repository.PayloadEncodingJSON), nil}, nil
+	}
+	compressed, err := repository.CompressPayload(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, []any{int16(repository.PayloadEncodingGzip), compressed}, nil
 }
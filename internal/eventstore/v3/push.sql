@@ -13,6 +13,9 @@ INSERT INTO eventstore.events2 (
 
     , "position"
     , in_tx_order
+    , metadata
+    , causation_id
+    , correlation_id
 ) VALUES
     %s
 RETURNING created_at, "position";
\ No newline at end of file
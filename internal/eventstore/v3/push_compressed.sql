@@ -0,0 +1,23 @@
+INSERT INTO eventstore.events2 (
+    instance_id
+    , "owner"
+    , aggregate_type
+    , aggregate_id
+    , revision
+
+    , creator
+    , event_type
+    , payload
+    , "sequence"
+    , created_at
+
+    , "position"
+    , in_tx_order
+    , metadata
+    , causation_id
+    , correlation_id
+    , payload_encoding
+    , compressed_payload
+) VALUES
+    %s
+RETURNING created_at, "position";
\ No newline at end of file
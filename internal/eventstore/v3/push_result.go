@@ -0,0 +1,28 @@
+package eventstore
+
+import "context"
+
+type pushResultKey struct{}
+
+// PushResult describes how a push was executed.
+// It is purely informational and does not change the outcome of the push.
+type PushResult struct {
+	// Retries is the count of restarted transactions, e.g. because of contention
+	Retries int
+}
+
+// WithPushResult returns a context in which [Eventstore.Push] reports its result.
+// The result is filled after the push returned, regardless of its success.
+func WithPushResult(ctx context.Context) (context.Context, *PushResult) {
+	result := new(PushResult)
+	return context.WithValue(ctx, pushResultKey{}, result), result
+}
+
+// reportPushResult sets the result of the push if requested by the caller
+func reportPushResult(ctx context.Context, attempts int) {
+	result, ok := ctx.Value(pushResultKey{}).(*PushResult)
+	if !ok || attempts == 0 {
+		return
+	}
+	result.Retries = attempts - 1
+}
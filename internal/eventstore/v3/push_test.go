@@ -1,21 +1,37 @@
 package eventstore
 
 import (
+	"context"
+	"database/sql/driver"
 	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/database/mock"
 	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 func Test_mapCommands(t *testing.T) {
 	type args struct {
-		commands  []eventstore.Command
-		sequences []*latestSequence
+		commands         []eventstore.Command
+		sequences        []*latestSequence
+		validatePayloads bool
 	}
 	type want struct {
 		events       []eventstore.Event
@@ -65,7 +81,7 @@ func Test_mapCommands(t *testing.T) {
 					),
 				},
 				placeHolders: []string{
-					"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10)",
+					"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10, $11, $12, $13)",
 				},
 				args: []any{
 					"instance",
@@ -78,6 +94,9 @@ func Test_mapCommands(t *testing.T) {
 					Payload(nil),
 					uint64(1),
 					0,
+					Payload(nil),
+					nil,
+					nil,
 				},
 				err: func(t *testing.T, err error) {},
 			},
@@ -114,8 +133,8 @@ func Test_mapCommands(t *testing.T) {
 					),
 				},
 				placeHolders: []string{
-					"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10)",
-					"($11, $12, $13, $14, $15, $16, $17, $18, $19, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $20)",
+					"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10, $11, $12, $13)",
+					"($14, $15, $16, $17, $18, $19, $20, $21, $22, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $23, $24, $25, $26)",
 				},
 				args: []any{
 					// first event
@@ -129,6 +148,9 @@ func Test_mapCommands(t *testing.T) {
 					Payload(nil),
 					uint64(6),
 					0,
+					Payload(nil),
+					nil,
+					nil,
 					// second event
 					"instance",
 					"ro",
@@ -140,6 +162,9 @@ func Test_mapCommands(t *testing.T) {
 					Payload(nil),
 					uint64(7),
 					1,
+					Payload(nil),
+					nil,
+					nil,
 				},
 				err: func(t *testing.T, err error) {},
 			},
@@ -180,8 +205,8 @@ func Test_mapCommands(t *testing.T) {
 					),
 				},
 				placeHolders: []string{
-					"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10)",
-					"($11, $12, $13, $14, $15, $16, $17, $18, $19, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $20)",
+					"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10, $11, $12, $13)",
+					"($14, $15, $16, $17, $18, $19, $20, $21, $22, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $23, $24, $25, $26)",
 				},
 				args: []any{
 					// first event
@@ -195,6 +220,9 @@ func Test_mapCommands(t *testing.T) {
 					Payload(nil),
 					uint64(6),
 					0,
+					Payload(nil),
+					nil,
+					nil,
 					// second event
 					"instance",
 					"ro",
@@ -206,10 +234,141 @@ func Test_mapCommands(t *testing.T) {
 					Payload(nil),
 					uint64(1),
 					1,
+					Payload(nil),
+					nil,
+					nil,
 				},
 				err: func(t *testing.T, err error) {},
 			},
 		},
+		{
+			name: "valid payload, validated",
+			args: args{
+				commands: []eventstore.Command{
+					&mockCommand{
+						aggregate: mockAggregate("V3-Rz8cK"),
+						payload:   struct{ Name string }{Name: "name"},
+					},
+				},
+				sequences: []*latestSequence{
+					{
+						aggregate: mockAggregate("V3-Rz8cK"),
+						sequence:  0,
+					},
+				},
+				validatePayloads: true,
+			},
+			want: want{
+				events: []eventstore.Event{
+					mockEvent(
+						mockAggregate("V3-Rz8cK"),
+						1,
+						Payload(`{"Name":"name"}`),
+					),
+				},
+				placeHolders: []string{
+					"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10, $11, $12, $13)",
+				},
+				args: []any{
+					"instance",
+					"ro",
+					eventstore.AggregateType("type"),
+					"V3-Rz8cK",
+					1,
+					"creator",
+					eventstore.EventType("event.type"),
+					Payload(`{"Name":"name"}`),
+					uint64(1),
+					0,
+					Payload(nil),
+					nil,
+					nil,
+				},
+			},
+		},
+		{
+			name: "malformed payload, validated",
+			args: args{
+				commands: []eventstore.Command{
+					&mockCommand{
+						aggregate: mockAggregate("V3-Rz8cK"),
+					},
+					&mockCommand{
+						aggregate: mockAggregate("V3-Rz8cK"),
+						// already serialized payloads are encoded as JSON string
+						payload: []byte(`{"Name":"name"}`),
+					},
+				},
+				sequences: []*latestSequence{
+					{
+						aggregate: mockAggregate("V3-Rz8cK"),
+						sequence:  0,
+					},
+				},
+				validatePayloads: true,
+			},
+			want: want{
+				err: func(t *testing.T, err error) {
+					assert.True(t, zerrors.IsErrorInvalidArgument(err))
+					assert.ErrorContains(t, err, "command 1 (type event.type of aggregate type V3-Rz8cK)")
+				},
+			},
+		},
+		{
+			name: "empty aggregate version",
+			args: args{
+				commands: []eventstore.Command{
+					&mockCommand{
+						aggregate: mockAggregate("V3-VEIvq"),
+					},
+					&mockCommand{
+						aggregate: func() *eventstore.Aggregate {
+							aggregate := mockAggregate("V3-VEIvq")
+							aggregate.Version = ""
+							return aggregate
+						}(),
+					},
+				},
+				sequences: []*latestSequence{
+					{
+						aggregate: mockAggregate("V3-VEIvq"),
+						sequence:  0,
+					},
+				},
+			},
+			want: want{
+				err: func(t *testing.T, err error) {
+					assert.ErrorIs(t, err, zerrors.ThrowInvalidArgument(nil, "V3-Vr5nQa", "Errors.Eventstore.InvalidAggregateVersion"))
+					assert.ErrorContains(t, err, "command 1 (type event.type of aggregate type V3-VEIvq): aggregate version is empty")
+				},
+			},
+		},
+		{
+			name: "unknown aggregate version",
+			args: args{
+				commands: []eventstore.Command{
+					&mockCommand{
+						aggregate: func() *eventstore.Aggregate {
+							aggregate := mockAggregate("V3-VEIvq")
+							aggregate.Version = "v1.2"
+							return aggregate
+						}(),
+					},
+				},
+				sequences: []*latestSequence{
+					{
+						aggregate: mockAggregate("V3-VEIvq"),
+						sequence:  0,
+					},
+				},
+			},
+			want: want{
+				err: func(t *testing.T, err error) {
+					assert.ErrorIs(t, err, zerrors.ThrowInvalidArgument(nil, "V3-Vr5nQa", "Errors.Eventstore.InvalidAggregateVersion"))
+					assert.ErrorContains(t, err, `aggregate version "v1.2" is not of the form v<revision>`)
+				},
+			},
+		},
 		{
 			name: "missing sequence",
 			args: args{
@@ -236,13 +395,14 @@ func Test_mapCommands(t *testing.T) {
 			}
 		}
 		// is used to set the the [pushPlaceholderFmt]
-		NewEventstore(&database.DB{Database: new(cockroach.Config)})
+		es := NewEventstore(&database.DB{Database: new(cockroach.Config)})
+		es.validatePayloads = tt.args.validatePayloads
 		t.Run(tt.name, func(t *testing.T) {
 			defer func() {
 				cause := recover()
 				assert.Equal(t, tt.want.shouldPanic, cause != nil)
 			}()
-			gotEvents, gotPlaceHolders, gotArgs, err := mapCommands(tt.args.commands, tt.args.sequences)
+			gotEvents, gotPlaceHolders, gotArgs, err := es.mapCommands(context.Background(), tt.args.commands, tt.args.sequences)
 			tt.want.err(t, err)
 
 			assert.ElementsMatch(t, tt.want.events, gotEvents)
@@ -251,3 +411,713 @@ func Test_mapCommands(t *testing.T) {
 		})
 	}
 }
+
+func Test_mapCommands_payloadCompression(t *testing.T) {
+	es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithPayloadCompression(16))
+
+	commands := []eventstore.Command{
+		&mockCommand{
+			aggregate: mockAggregate("V3-Hx2tN"),
+			payload:   map[string]string{"k": "v"},
+		},
+		&mockCommand{
+			aggregate: mockAggregate("V3-Hx2tN"),
+			payload:   map[string]string{"key": "a value above the threshold"},
+		},
+	}
+	events, placeholders, args, err := es.mapCommands(context.Background(), commands, []*latestSequence{
+		{
+			aggregate: mockAggregate("V3-Hx2tN"),
+			sequence:  0,
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10, $11, $12, $13, $14, $15)",
+		"($16, $17, $18, $19, $20, $21, $22, $23, $24, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $25, $26, $27, $28, $29, $30)",
+	}, placeholders)
+	require.Len(t, args, 2*argsPerCompressedCommand)
+
+	// small payloads stay JSON
+	small := args[:argsPerCompressedCommand]
+	assert.Equal(t, Payload(`{"k":"v"}`), small[7])
+	assert.Equal(t, int16(repository.PayloadEncodingJSON), small[13])
+	assert.Nil(t, small[14])
+
+	// large payloads are moved to the compressed column
+	large := args[argsPerCompressedCommand:]
+	assert.Nil(t, large[7])
+	assert.Equal(t, int16(repository.PayloadEncodingGzip), large[13])
+	decoded, err := repository.DecodePayload(repository.PayloadEncodingGzip, nil, large[14].([]byte))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"key":"a value above the threshold"}`, string(decoded))
+
+	// the returned events contain the uncompressed payload
+	assert.Equal(t, decoded, events[1].DataAsBytes())
+}
+
+func Test_mapCommands_metadata(t *testing.T) {
+	es := NewEventstore(&database.DB{Database: new(cockroach.Config)})
+	commands := []eventstore.Command{
+		&mockCommand{aggregate: mockAggregate("V3-Md5Tq")},
+		&mockCommand{aggregate: mockAggregate("V3-Md5Tq")},
+	}
+	sequences := func() []*latestSequence {
+		return []*latestSequence{{aggregate: mockAggregate("V3-Md5Tq")}}
+	}
+
+	// empty metadata is stored as NULL
+	events, _, args, err := es.mapCommands(context.Background(), commands, sequences())
+	require.NoError(t, err)
+	assert.Nil(t, args[10])
+	assert.Nil(t, events[0].(eventstore.MetadataProvider).EventMetadata())
+
+	// all events of the push are annotated and read back from the column
+	metadata := eventstore.Metadata{"traceID": "trace", "requestID": "request", "sourceIP": "127.0.0.1"}
+	ctx := eventstore.WithMetadata(context.Background(), metadata)
+	events, _, args, err = es.mapCommands(ctx, commands, sequences())
+	require.NoError(t, err)
+	for i, e := range events {
+		assert.Equal(t, metadata, e.(eventstore.MetadataProvider).EventMetadata())
+
+		column := args[i*argsPerCommand+10].(Payload)
+		read, err := repository.UnmarshalMetadata(column)
+		require.NoError(t, err)
+		assert.Equal(t, metadata, read)
+	}
+	// the metadata is not part of the payload
+	assert.Nil(t, args[7])
+}
+
+func Test_mapCommands_causation(t *testing.T) {
+	es := NewEventstore(&database.DB{Database: new(cockroach.Config)})
+	commands := []eventstore.Command{
+		&mockCommand{aggregate: mockAggregate("V3-Cs4Rl")},
+		&mockCommand{aggregate: mockAggregate("V3-Cs4Rl")},
+	}
+	sequences := func() []*latestSequence {
+		return []*latestSequence{{aggregate: mockAggregate("V3-Cs4Rl")}}
+	}
+
+	// unknown causation and correlation are stored as NULL
+	events, _, args, err := es.mapCommands(context.Background(), commands, sequences())
+	require.NoError(t, err)
+	assert.Nil(t, args[11])
+	assert.Nil(t, args[12])
+	assert.Empty(t, events[0].(eventstore.CausationProvider).CausationID())
+	assert.Empty(t, events[0].(eventstore.CausationProvider).CorrelationID())
+
+	// all events of the push are annotated
+	ctx := eventstore.WithCorrelationID(eventstore.WithCausationID(context.Background(), "cause"), "request")
+	events, _, args, err = es.mapCommands(ctx, commands, sequences())
+	require.NoError(t, err)
+	for i, e := range events {
+		assert.Equal(t, "cause", e.(eventstore.CausationProvider).CausationID())
+		assert.Equal(t, "request", e.(eventstore.CausationProvider).CorrelationID())
+		assert.Equal(t, "cause", args[i*argsPerCommand+11])
+		assert.Equal(t, "request", args[i*argsPerCommand+12])
+	}
+}
+
+func Test_mapCommands_withoutAddedColumns(t *testing.T) {
+	es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithoutAddedColumns(), WithPayloadCompression(1))
+	commands := []eventstore.Command{
+		&mockCommand{aggregate: mockAggregate("V3-Ac3Wq"), payload: map[string]string{"key": "value"}},
+		&mockCommand{aggregate: mockAggregate("V3-Ac3Wq")},
+	}
+	ctx := eventstore.WithCorrelationID(eventstore.WithMetadata(context.Background(), eventstore.Metadata{"traceID": "trace"}), "request")
+
+	events, placeholders, args, err := es.mapCommands(ctx, commands, []*latestSequence{{aggregate: mockAggregate("V3-Ac3Wq")}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"($1, $2, $3, $4, $5, $6, $7, $8, $9, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $10)",
+		"($11, $12, $13, $14, $15, $16, $17, $18, $19, hlc_to_timestamp(cluster_logical_timestamp()), cluster_logical_timestamp(), $20)",
+	}, placeholders)
+	require.Len(t, args, 2*argsWithoutAddedColumns)
+	// the payload is neither compressed nor encoded
+	assert.Equal(t, Payload(`{"key":"value"}`), args[7])
+	// the annotations are not stored, so the events do not claim them
+	assert.Nil(t, events[0].(eventstore.MetadataProvider).EventMetadata())
+	assert.Empty(t, events[0].(eventstore.CausationProvider).CorrelationID())
+}
+
+func Test_event_RawPayload(t *testing.T) {
+	es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithPayloadCompression(16))
+
+	commands := []eventstore.Command{
+		&mockCommand{
+			aggregate: mockAggregate("V3-Rw7pL"),
+			payload:   map[string]string{"k": "v"},
+		},
+		&mockCommand{
+			aggregate: mockAggregate("V3-Rw7pL"),
+			payload:   map[string]string{"key": "a value above the threshold"},
+		},
+		&mockCommand{
+			aggregate: mockAggregate("V3-Rw7pL"),
+		},
+	}
+	events, _, args, err := es.mapCommands(context.Background(), commands, []*latestSequence{
+		{
+			aggregate: mockAggregate("V3-Rw7pL"),
+			sequence:  0,
+		},
+	})
+	require.NoError(t, err)
+
+	// uncompressed payloads are the bytes passed to the payload column
+	small := events[0].(RawPayloader).RawPayload()
+	assert.Equal(t, []byte(args[7].(Payload)), small)
+
+	// compressed payloads round trip to the same bytes
+	compressed := args[argsPerCompressedCommand+14].([]byte)
+	decoded, err := repository.DecodePayload(repository.PayloadEncodingGzip, nil, compressed)
+	require.NoError(t, err)
+	large := events[1].(RawPayloader).RawPayload()
+	assert.Equal(t, decoded, large)
+	recompressed, err := repository.CompressPayload(large)
+	require.NoError(t, err)
+	assert.Equal(t, compressed, recompressed)
+
+	// events without payload have no raw payload
+	assert.Nil(t, events[2].(RawPayloader).RawPayload())
+
+	// the raw payload is a copy
+	small[0] = 'x'
+	assert.Equal(t, []byte(`{"k":"v"}`), events[0].(RawPayloader).RawPayload())
+}
+
+var _ Metrics = (*testMetrics)(nil)
+
+type testMetrics struct {
+	commands     []int
+	payloadBytes []int
+	latencies    []pushLatency
+}
+
+type pushLatency struct {
+	outcome PushOutcome
+	latency time.Duration
+}
+
+func (m *testMetrics) ObservePushedCommands(_ context.Context, count int) {
+	m.commands = append(m.commands, count)
+}
+
+func (m *testMetrics) ObservePushedPayloadBytes(_ context.Context, bytes int) {
+	m.payloadBytes = append(m.payloadBytes, bytes)
+}
+
+func (m *testMetrics) ObservePushLatency(_ context.Context, outcome PushOutcome, latency time.Duration) {
+	m.latencies = append(m.latencies, pushLatency{outcome: outcome, latency: latency})
+}
+
+func Test_insertEvents_metrics(t *testing.T) {
+	recorder := new(testMetrics)
+	es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithMetrics(recorder))
+
+	commands := []eventstore.Command{
+		&mockCommand{
+			aggregate: mockAggregate("V3-mZ8yL"),
+			payload:   map[string]string{"key": "value"},
+		},
+		&mockCommand{
+			aggregate: mockAggregate("V3-mZ8yL"),
+		},
+		&mockCommand{
+			aggregate: mockAggregate("V3-mZ8yL"),
+			payload:   []string{"a", "b"},
+		},
+	}
+	// {"key":"value"} and ["a","b"]
+	wantPayloadBytes := 15 + 9
+
+	sqlMock := mock.NewSQLMock(t,
+		mock.ExpectBegin(nil),
+		mock.ExpectQuery(
+			fmt.Sprintf(pushStmt, strings.Join([]string{
+				fmt.Sprintf(pushPlaceholderFmt, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13),
+				fmt.Sprintf(pushPlaceholderFmt, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22),
+				fmt.Sprintf(pushPlaceholderFmt, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33),
+			}, ", ")),
+			mock.WithQueryResult(
+				[]string{"created_at", "position"},
+				[][]driver.Value{
+					{time.Now(), float64(1)},
+					{time.Now(), float64(1)},
+					{time.Now(), float64(1)},
+				},
+			),
+		),
+	)
+	defer sqlMock.Assert(t)
+
+	tx, err := sqlMock.DB.Begin()
+	require.NoError(t, err)
+
+	_, err = es.insertEvents(context.Background(), tx,
+		[]*latestSequence{
+			{
+				aggregate: mockAggregate("V3-mZ8yL"),
+				sequence:  0,
+			},
+		},
+		commands,
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{len(commands)}, recorder.commands)
+	assert.Equal(t, []int{wantPayloadBytes}, recorder.payloadBytes)
+}
+
+func TestEventstore_PushTx(t *testing.T) {
+	aggregate := mockAggregate("V3-Xk2fP")
+	sequenceConditions, _ := sequencesToSql([]*latestSequence{{aggregate: aggregate}})
+	sequenceStmt := fmt.Sprintf(latestSequencesStmt, strings.Join(sequenceConditions, " UNION ALL "))
+	sequenceResult := mock.WithQueryResult(
+		[]string{"instance_id", "owner", "aggregate_type", "aggregate_id", "sequence"},
+		[][]driver.Value{{"instance", "ro", "type", "V3-Xk2fP", uint64(5)}},
+	)
+
+	tests := []struct {
+		name         string
+		expectations []mock.Expectation
+		wantSequence uint64
+		wantErr      bool
+	}{
+		{
+			name: "push within outer transaction",
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExcpectExec("SAVEPOINT push", mock.WithExecNoRowsAffected()),
+				mock.ExpectQuery(sequenceStmt, sequenceResult),
+				mock.ExpectQuery(
+					fmt.Sprintf(pushStmt, fmt.Sprintf(pushPlaceholderFmt, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13)),
+					mock.WithQueryResult(
+						[]string{"created_at", "position"},
+						[][]driver.Value{{time.Now(), float64(1)}},
+					),
+				),
+				mock.ExcpectExec("RELEASE SAVEPOINT push", mock.WithExecNoRowsAffected()),
+				mock.ExpectRollback(nil),
+			},
+			wantSequence: 6,
+		},
+		{
+			name: "failed push is rolled back to savepoint",
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(nil),
+				mock.ExcpectExec("SAVEPOINT push", mock.WithExecNoRowsAffected()),
+				mock.ExpectQuery(sequenceStmt, sequenceResult),
+				mock.ExpectQuery(
+					fmt.Sprintf(pushStmt, fmt.Sprintf(pushPlaceholderFmt, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13)),
+					mock.WithQueryErr(errors.New("insert failed")),
+				),
+				mock.ExcpectExec("ROLLBACK TO SAVEPOINT push", mock.WithExecNoRowsAffected()),
+				mock.ExpectRollback(nil),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithMetrics(new(testMetrics)))
+			sqlMock := mock.NewSQLMock(t, tt.expectations...)
+			defer sqlMock.Assert(t)
+
+			tx, err := sqlMock.DB.Begin()
+			require.NoError(t, err)
+
+			events, err := es.PushTx(context.Background(), tx, &mockCommand{aggregate: mockAggregate("V3-Xk2fP")})
+			// the caller decides about the outcome of the transaction
+			require.NoError(t, tx.Rollback())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			assert.Equal(t, tt.wantSequence, events[0].Sequence())
+		})
+	}
+}
+
+func TestEventstore_Push_acquireTimeout(t *testing.T) {
+	errBegin := errors.New("begin failed")
+	tests := []struct {
+		name           string
+		acquireTimeout time.Duration
+		ctxTimeout     time.Duration
+		poolExhausted  bool
+		expectations   []mock.Expectation
+		wantErr        func(t *testing.T, err error)
+	}{
+		{
+			name:           "pool exhausted",
+			acquireTimeout: 20 * time.Millisecond,
+			poolExhausted:  true,
+			wantErr: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrPoolExhausted)
+				assert.True(t, zerrors.IsUnavailable(err))
+			},
+		},
+		{
+			name:           "connection available",
+			acquireTimeout: 20 * time.Millisecond,
+			expectations: []mock.Expectation{
+				mock.ExpectBegin(errBegin),
+			},
+			wantErr: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, errBegin)
+			},
+		},
+		{
+			name:          "no acquire timeout, blocks until context is done",
+			ctxTimeout:    50 * time.Millisecond,
+			poolExhausted: true,
+			wantErr: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				assert.NotErrorIs(t, err, ErrPoolExhausted)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlMock := mock.NewSQLMock(t, tt.expectations...)
+			defer sqlMock.Assert(t)
+			sqlMock.DB.SetMaxOpenConns(1)
+
+			ctx := context.Background()
+			if tt.ctxTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.ctxTimeout)
+				defer cancel()
+			}
+			if tt.poolExhausted {
+				held, err := sqlMock.DB.Conn(context.Background())
+				require.NoError(t, err)
+				defer held.Close()
+			}
+
+			es := NewEventstore(
+				&database.DB{DB: sqlMock.DB, Database: new(cockroach.Config)},
+				WithMetrics(new(testMetrics)),
+				WithAcquireTimeout(tt.acquireTimeout),
+			)
+			start := time.Now()
+			_, err := es.Push(ctx, &mockCommand{aggregate: mockAggregate("V3-a8Qm2")})
+			tt.wantErr(t, err)
+			assert.Less(t, time.Since(start), time.Second)
+		})
+	}
+}
+
+func Test_handleUniqueConstraints_failureLog(t *testing.T) {
+	hook := logtest.NewGlobal()
+	level := logrus.GetLevel()
+	logrus.SetLevel(logrus.DebugLevel)
+	t.Cleanup(func() {
+		logrus.SetLevel(level)
+		hook.Reset()
+	})
+
+	commands := []eventstore.Command{
+		&mockCommand{
+			aggregate: mockAggregate("V3-Rt8pK"),
+			payload:   map[string]string{"secret": "value"},
+			constraints: []*eventstore.UniqueConstraint{
+				eventstore.NewAddEventUniqueConstraint("type", "field", "Errors.AlreadyExists"),
+			},
+		},
+		&mockCommand{
+			aggregate: mockAggregate("V3-Rt8pK"),
+		},
+	}
+
+	sqlMock := mock.NewSQLMock(t,
+		mock.ExpectBegin(nil),
+		mock.ExcpectExec(
+			fmt.Sprintf(addConstraintStmt, "($1, $2, $3)"),
+			mock.WithExecErr(&pgconn.PgError{Code: "23505"}),
+		),
+	)
+	defer sqlMock.Assert(t)
+
+	tx, err := sqlMock.DB.Begin()
+	require.NoError(t, err)
+
+	err = handleUniqueConstraints(context.Background(), tx, commands)
+	require.Error(t, err)
+
+	var entry *logrus.Entry
+	for _, e := range hook.AllEntries() {
+		if e.Level == logrus.DebugLevel && e.Message == "add unique constraint failed" {
+			entry = e
+		}
+	}
+	require.NotNil(t, entry, "debug log missing")
+	assert.Equal(t, []string{"type"}, entry.Data["aggregateTypes"])
+	assert.Equal(t, 2, entry.Data["commandCount"])
+	assert.Equal(t, []string{"instance"}, entry.Data["instances"])
+	assert.Equal(t, "23505", entry.Data["sqlState"])
+	for _, value := range entry.Data {
+		assert.NotContains(t, fmt.Sprint(value), "secret", "payload must not be logged")
+	}
+}
+
+func Test_handleUniqueConstraints_conditional(t *testing.T) {
+	conditional := func(id, field string, conditionWasMet, conditionMet bool) *mockCommand {
+		return &mockCommand{
+			aggregate: mockAggregate(id),
+			constraints: []*eventstore.UniqueConstraint{
+				eventstore.NewConditionalUniqueConstraint("username", field, "Errors.User.AlreadyExists", conditionWasMet, conditionMet),
+			},
+		}
+	}
+	commands := []eventstore.Command{
+		// the deleted user releases its name
+		conditional("1", "old", true, false),
+		// the new user reserves its name
+		conditional("2", "New", false, true),
+		// the name is transferred to another user, it stays reserved
+		conditional("3", "name", true, false),
+		conditional("4", "Name", false, true),
+		// the name is reserved and released again, it is never reserved
+		conditional("5", "tmp", false, true),
+		conditional("5", "tmp", true, false),
+		// the state of the active user does not change, its name is not reserved again
+		conditional("6", "kept", true, true),
+		// the state of the inactive user does not change, its name is not released
+		conditional("7", "inactive", false, false),
+	}
+
+	sqlMock := mock.NewSQLMock(t,
+		mock.ExpectBegin(nil),
+		mock.ExcpectExec(
+			fmt.Sprintf(deleteConstraintStmt, fmt.Sprintf(deleteConstraintPlaceholdersStmt, 1, 2, 3)),
+			mock.WithExecArgs("instance", "username", "old"),
+			mock.WithExecRowsAffected(1),
+		),
+		mock.ExcpectExec(
+			fmt.Sprintf(addConstraintStmt, "($1, $2, $3)"),
+			mock.WithExecArgs("instance", "username", "new"),
+			mock.WithExecRowsAffected(1),
+		),
+	)
+	defer sqlMock.Assert(t)
+
+	tx, err := sqlMock.DB.Begin()
+	require.NoError(t, err)
+
+	err = handleUniqueConstraints(context.Background(), tx, commands)
+	require.NoError(t, err)
+}
+
+func Test_aggregateRevision(t *testing.T) {
+	tests := []struct {
+		version eventstore.Version
+		want    int
+		wantErr bool
+	}{
+		{version: "v1", want: 1},
+		{version: "v2", want: 2},
+		{version: "v10", want: 10},
+		{version: "", wantErr: true},
+		{version: "1", wantErr: true},
+		{version: "v", wantErr: true},
+		{version: "v0", wantErr: true},
+		{version: "v01", wantErr: true},
+		{version: "v1.0.0", wantErr: true},
+		{version: "v99999999999999999999", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.version), func(t *testing.T) {
+			got, err := aggregateRevision(tt.version)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEventstore_Push_connectionLost(t *testing.T) {
+	aggregate := mockAggregate("V3-Cn7lSt")
+	command := &mockCommand{aggregate: aggregate, payload: map[string]string{"name": "name"}}
+	errFatal := errors.New("fatal")
+
+	tests := []struct {
+		name               string
+		insertErr          error
+		withoutSavepoint   bool
+		wantConnectionLost bool
+	}{
+		{
+			name:               "unexpected EOF",
+			insertErr:          io.ErrUnexpectedEOF,
+			wantConnectionLost: true,
+		},
+		{
+			name:               "connection reset",
+			insertErr:          &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET},
+			wantConnectionLost: true,
+		},
+		{
+			name:               "connection closed, without savepoint",
+			insertErr:          net.ErrClosed,
+			withoutSavepoint:   true,
+			wantConnectionLost: true,
+		},
+		{
+			name:      "other error",
+			insertErr: errFatal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// sets the [pushPlaceholderFmt] used in the expectations
+			NewEventstore(&database.DB{Database: new(cockroach.Config)})
+
+			// the insert is expected exactly once, so it must not be retried on the lost connection
+			sqlMock := mock.NewSQLMock(t, expectPush(aggregate, 1, 1, mock.WithQueryErr(tt.insertErr))...)
+			defer sqlMock.Assert(t)
+
+			opts := []Option{WithMetrics(new(testMetrics))}
+			if tt.withoutSavepoint {
+				opts = append(opts, WithoutSingleCommandSavepoint())
+			}
+			es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, opts...)
+			tx := &fakeTx{queries: sqlMock.DB}
+			es.txBeginner = &fakeTxBeginner{tx: tx}
+
+			_, err := es.Push(context.Background(), command)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tt.insertErr)
+			assert.False(t, tx.committed)
+			assert.True(t, tx.rolledBack)
+			if !tt.wantConnectionLost {
+				assert.NotErrorIs(t, err, ErrConnectionLost)
+				return
+			}
+			assert.ErrorIs(t, err, ErrConnectionLost)
+			assert.True(t, zerrors.IsUnavailable(err))
+		})
+	}
+}
+
+func TestEventstore_Push_withoutUniqueConstraints(t *testing.T) {
+	aggregate := mockAggregate("V3-Sk1pUc")
+	command := &mockCommand{
+		aggregate: aggregate,
+		constraints: []*eventstore.UniqueConstraint{
+			eventstore.NewAddEventUniqueConstraint("username", "name", "Errors.User.AlreadyExists"),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		skip      bool
+		wantExecs []string
+	}{
+		{
+			name: "default, constraints handled",
+			wantExecs: []string{
+				"SAVEPOINT cockroach_restart",
+				fmt.Sprintf(addConstraintStmt, "($1, $2, $3)"),
+				"RELEASE SAVEPOINT cockroach_restart",
+			},
+		},
+		{
+			name: "skipped",
+			skip: true,
+			wantExecs: []string{
+				"SAVEPOINT cockroach_restart",
+				"RELEASE SAVEPOINT cockroach_restart",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// sets the [pushPlaceholderFmt] used in the expectations
+			NewEventstore(&database.DB{Database: new(cockroach.Config)})
+
+			sqlMock := mock.NewSQLMock(t, expectPush(aggregate, 1, 1)...)
+			defer sqlMock.Assert(t)
+
+			es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithMetrics(new(testMetrics)))
+			tx := &fakeTx{queries: sqlMock.DB}
+			es.txBeginner = &fakeTxBeginner{tx: tx}
+
+			ctx := context.Background()
+			if tt.skip {
+				ctx = WithoutUniqueConstraints(ctx)
+			}
+			events, err := es.Push(ctx, command)
+			require.NoError(t, err)
+			assert.Len(t, events, 1)
+			assert.Equal(t, tt.wantExecs, tx.execs)
+			assert.True(t, tx.committed)
+		})
+	}
+}
+
+func Test_mapCommands_maxPayloadSize(t *testing.T) {
+	// payload returns a JSON object of exactly size bytes
+	payload := func(size int) []byte {
+		return []byte(`{"name":"` + strings.Repeat("a", size-len(`{"name":""}`)) + `"}`)
+	}
+	tests := []struct {
+		name           string
+		maxPayloadSize int
+		size           int
+		wantErr        bool
+	}{
+		{
+			name: "unlimited",
+			size: 4096,
+		},
+		{
+			name:           "just under the limit",
+			maxPayloadSize: 100,
+			size:           99,
+		},
+		{
+			name:           "at the limit",
+			maxPayloadSize: 100,
+			size:           100,
+		},
+		{
+			name:           "just over the limit",
+			maxPayloadSize: 100,
+			size:           101,
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithMaxPayloadSize(tt.maxPayloadSize))
+			commands := []eventstore.Command{
+				&mockCommand{aggregate: mockAggregate("V3-Pl4sZe")},
+				&mockSerializedCommand{
+					mockCommand: mockCommand{aggregate: mockAggregate("V3-Pl4sZe")},
+					data:        payload(tt.size),
+					contentType: eventstore.PayloadContentTypeJSON,
+				},
+			}
+			events, _, _, err := es.mapCommands(context.Background(), commands, []*latestSequence{{aggregate: mockAggregate("V3-Pl4sZe")}})
+			if !tt.wantErr {
+				require.NoError(t, err)
+				assert.Len(t, events[1].DataAsBytes(), tt.size)
+				return
+			}
+			assert.ErrorIs(t, err, ErrPayloadTooLarge)
+			assert.True(t, zerrors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+			// the offending command is identified
+			assert.ErrorContains(t, err, "command 1")
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package eventstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+// RedactionPolicy are the fields removed from the payloads of the events per event type before they are written,
+// e.g. personal data which must never be persisted to the event stream.
+// A field is a dot separated path of keys of nested objects, e.g. "profile.email".
+// If a path passes an array, the field is removed from every object of the array.
+// Fields which are not part of a payload are ignored.
+type RedactionPolicy map[eventstore.EventType][]string
+
+// WithRedactionPolicy removes the fields of the policy from the payloads before they are written.
+// The payloads of event types with redacted fields are always serialized as JSON, independent of the codec.
+// The events returned by [Eventstore.Push] contain the redacted payloads, like the events read later on.
+func WithRedactionPolicy(policy RedactionPolicy) Option {
+	return func(es *Eventstore) {
+		es.redaction = policy
+	}
+}
+
+// fields returns the fields redacted from the payloads of the event type, nil if nothing is redacted
+func (p RedactionPolicy) fields(typ eventstore.EventType) []string {
+	if p == nil {
+		return nil
+	}
+	return p[typ]
+}
+
+// redactPayload removes the fields from the JSON payload, the result is still valid JSON
+func redactPayload(payload Payload, fields []string) (Payload, error) {
+	if len(fields) == 0 || len(payload) == 0 {
+		return payload, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	// numbers are kept as they are, e.g. large integers are not rounded to floats
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		removeField(value, strings.Split(field, "."))
+	}
+	return json.Marshal(value)
+}
+
+// removeField removes the field at the path of keys from the decoded JSON value
+func removeField(value any, keys []string) {
+	switch v := value.(type) {
+	case map[string]any:
+		if len(keys) == 1 {
+			delete(v, keys[0])
+			return
+		}
+		removeField(v[keys[0]], keys[1:])
+	case []any:
+		for _, element := range v {
+			removeField(element, keys)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package eventstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+)
+
+func Test_redactPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		fields  []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "no fields",
+			payload: `{"email":"alice@example.com"}`,
+			want:    `{"email":"alice@example.com"}`,
+		},
+		{
+			name:    "top level field",
+			payload: `{"userName":"alice","email":"alice@example.com"}`,
+			fields:  []string{"email"},
+			want:    `{"userName":"alice"}`,
+		},
+		{
+			name:    "nested field",
+			payload: `{"profile":{"firstName":"Alice","birthDate":"1990-01-01"},"userName":"alice"}`,
+			fields:  []string{"profile.birthDate"},
+			want:    `{"profile":{"firstName":"Alice"},"userName":"alice"}`,
+		},
+		{
+			name:    "field of objects in array",
+			payload: `{"addresses":[{"street":"Main St","country":"CH"},{"street":"Side St","country":"DE"}]}`,
+			fields:  []string{"addresses.street"},
+			want:    `{"addresses":[{"country":"CH"},{"country":"DE"}]}`,
+		},
+		{
+			name:    "missing fields ignored",
+			payload: `{"userName":"alice","profile":"none"}`,
+			fields:  []string{"email", "profile.birthDate", "phone.number"},
+			want:    `{"userName":"alice","profile":"none"}`,
+		},
+		{
+			name:    "numbers preserved",
+			payload: `{"sequence":18446744073709551615,"amount":1.5,"secret":"s"}`,
+			fields:  []string{"secret"},
+			want:    `{"sequence":18446744073709551615,"amount":1.5}`,
+		},
+		{
+			name:    "null payload",
+			payload: `null`,
+			fields:  []string{"email"},
+			want:    `null`,
+		},
+		{
+			name:    "invalid JSON",
+			payload: `{"email":`,
+			fields:  []string{"email"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := redactPayload(Payload(tt.payload), tt.fields)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_commandToEvent_redaction(t *testing.T) {
+	type profile struct {
+		FirstName string `json:"firstName"`
+		BirthDate string `json:"birthDate"`
+	}
+	payload := struct {
+		UserName string  `json:"userName"`
+		Email    string  `json:"email"`
+		Profile  profile `json:"profile"`
+	}{
+		UserName: "alice",
+		Email:    "alice@example.com",
+		Profile:  profile{FirstName: "Alice", BirthDate: "1990-01-01"},
+	}
+
+	tests := []struct {
+		name   string
+		policy RedactionPolicy
+		want   string
+	}{
+		{
+			name: "no policy",
+			want: `{"userName":"alice","email":"alice@example.com","profile":{"firstName":"Alice","birthDate":"1990-01-01"}}`,
+		},
+		{
+			name:   "other event type",
+			policy: RedactionPolicy{"user.added": {"email"}},
+			want:   `{"userName":"alice","email":"alice@example.com","profile":{"firstName":"Alice","birthDate":"1990-01-01"}}`,
+		},
+		{
+			name:   "redacted",
+			policy: RedactionPolicy{"event.type": {"email", "profile.birthDate"}},
+			want:   `{"userName":"alice","profile":{"firstName":"Alice"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := commandToEvent(JSONCodec{}, tt.policy, &latestSequence{aggregate: mockAggregate("V3-Rd4cTp")}, &mockCommand{
+				aggregate: mockAggregate("V3-Rd4cTp"),
+				payload:   payload,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, repository.PayloadEncodingJSON, got.encoding)
+			assert.JSONEq(t, tt.want, string(got.payload))
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package eventstore
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+// Reducer folds a single event and its unmarshalled payload into the state of the caller
+type Reducer[P any] func(event eventstore.Event, payload *P) error
+
+// ReduceEvents walks the events returned by [Eventstore.Push] in sequence order
+// and calls reduce with the payload of each event unmarshalled into P.
+// The iteration stops at the first error.
+func ReduceEvents[P any](events []eventstore.Event, reduce Reducer[P]) error {
+	sorted := slices.Clone(events)
+	slices.SortStableFunc(sorted, func(a, b eventstore.Event) int {
+		return cmp.Compare(a.Sequence(), b.Sequence())
+	})
+	for _, event := range sorted {
+		payload := new(P)
+		if err := event.Unmarshal(payload); err != nil {
+			return err
+		}
+		if err := reduce(event, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package eventstore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+type reducePayload struct {
+	Amount int `json:"amount"`
+}
+
+func TestReduceEvents(t *testing.T) {
+	errReduce := errors.New("reduce failed")
+	type want struct {
+		sum       int
+		sequences []uint64
+		err       func(t *testing.T, err error)
+	}
+	tests := []struct {
+		name   string
+		events []eventstore.Event
+		failAt uint64
+		want   want
+	}{
+		{
+			name: "no events",
+			want: want{
+				sequences: []uint64{},
+			},
+		},
+		{
+			name: "in sequence order",
+			events: []eventstore.Event{
+				&event{sequence: 3, payload: Payload(`{"amount":3}`)},
+				&event{sequence: 1, payload: Payload(`{"amount":1}`)},
+				&event{sequence: 2, payload: Payload(`{"amount":2}`)},
+			},
+			want: want{
+				sum:       6,
+				sequences: []uint64{1, 2, 3},
+			},
+		},
+		{
+			name: "without payload",
+			events: []eventstore.Event{
+				&event{sequence: 1, payload: Payload(`{"amount":5}`)},
+				&event{sequence: 2},
+			},
+			want: want{
+				sum:       5,
+				sequences: []uint64{1, 2},
+			},
+		},
+		{
+			name: "stops on reduce error",
+			events: []eventstore.Event{
+				&event{sequence: 1, payload: Payload(`{"amount":1}`)},
+				&event{sequence: 2, payload: Payload(`{"amount":2}`)},
+				&event{sequence: 3, payload: Payload(`{"amount":3}`)},
+			},
+			failAt: 2,
+			want: want{
+				sum:       1,
+				sequences: []uint64{1, 2},
+				err: func(t *testing.T, err error) {
+					assert.ErrorIs(t, err, errReduce)
+				},
+			},
+		},
+		{
+			name: "stops on unmarshal error",
+			events: []eventstore.Event{
+				&event{sequence: 1, payload: Payload(`{"amount":1}`)},
+				&event{sequence: 2, payload: Payload(`{"amount":"two"}`)},
+				&event{sequence: 3, payload: Payload(`{"amount":3}`)},
+			},
+			want: want{
+				sum:       1,
+				sequences: []uint64{1},
+				err: func(t *testing.T, err error) {
+					assert.Error(t, err)
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sum int
+			sequences := make([]uint64, 0, len(tt.events))
+			err := ReduceEvents(tt.events, func(event eventstore.Event, payload *reducePayload) error {
+				sequences = append(sequences, event.Sequence())
+				if event.Sequence() == tt.failAt {
+					return errReduce
+				}
+				sum += payload.Amount
+				return nil
+			})
+			if tt.want.err == nil {
+				assert.NoError(t, err)
+			} else {
+				tt.want.err(t, err)
+			}
+			assert.Equal(t, tt.want.sum, sum)
+			assert.Equal(t, tt.want.sequences, sequences)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/eventstore"
+)
+
+func TestEventstore_Push_withoutSingleCommandSavepoint(t *testing.T) {
+	aggregate := mockAggregate("V3-Sp7nQ")
+	command := &mockCommand{aggregate: aggregate, payload: map[string]string{"name": "name"}}
+	errFatal := errors.New("fatal")
+
+	tests := []struct {
+		name         string
+		commands     []eventstore.Command
+		expectations [][]mock.Expectation
+		wantErr      error
+		wantExecs    []string
+		wantRetries  int
+		wantRollback bool
+	}{
+		{
+			name:         "single command, no savepoint",
+			commands:     []eventstore.Command{command},
+			expectations: [][]mock.Expectation{expectPush(aggregate, 1, 1)},
+		},
+		{
+			name:         "multiple commands, savepoint",
+			commands:     []eventstore.Command{command, command},
+			expectations: [][]mock.Expectation{expectPush(aggregate, 1, 2)},
+			wantExecs:    []string{"SAVEPOINT cockroach_restart", "RELEASE SAVEPOINT cockroach_restart"},
+		},
+		{
+			name:     "single command contended, retried with savepoint",
+			commands: []eventstore.Command{command},
+			expectations: [][]mock.Expectation{
+				expectPush(aggregate, 1, 1, mock.WithQueryErr(&pgconn.PgError{Code: "40001"})),
+				expectPush(aggregate, 2, 1),
+			},
+			wantExecs:    []string{"SAVEPOINT cockroach_restart", "RELEASE SAVEPOINT cockroach_restart"},
+			wantRetries:  1,
+			wantRollback: true,
+		},
+		{
+			name:     "single command failed, not retried",
+			commands: []eventstore.Command{command},
+			expectations: [][]mock.Expectation{
+				expectPush(aggregate, 1, 1, mock.WithQueryErr(errFatal)),
+			},
+			wantErr:      errFatal,
+			wantRollback: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// sets the [pushPlaceholderFmt] used in the expectations
+			NewEventstore(&database.DB{Database: new(cockroach.Config)})
+
+			var expectations []mock.Expectation
+			for _, e := range tt.expectations {
+				expectations = append(expectations, e...)
+			}
+			sqlMock := mock.NewSQLMock(t, expectations...)
+			defer sqlMock.Assert(t)
+
+			es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithMetrics(new(testMetrics)), WithoutSingleCommandSavepoint())
+			tx := &fakeTx{queries: sqlMock.DB}
+			es.txBeginner = &fakeTxBeginner{tx: tx}
+
+			ctx, result := WithPushResult(context.Background())
+			events, err := es.Push(ctx, tt.commands...)
+			assert.Equal(t, tt.wantExecs, tx.execs)
+			assert.Equal(t, tt.wantRollback, tx.rolledBack)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, tx.committed)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, events, len(tt.commands))
+			assert.True(t, tx.committed)
+			assert.Equal(t, tt.wantRetries, result.Retries)
+		})
+	}
+}
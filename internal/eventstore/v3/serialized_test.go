@@ -0,0 +1,195 @@
+package eventstore
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/apipb"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func Test_commandToEvent_serialized(t *testing.T) {
+	jsonPayload := map[string]string{"key": "value"}
+	jsonSerialized, err := json.Marshal(jsonPayload)
+	require.NoError(t, err)
+	protoSerialized, err := proto.Marshal(testProtoPayload())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		codec       PayloadCodec
+		payload     any
+		data        []byte
+		contentType eventstore.PayloadContentType
+	}{
+		{
+			name:        "json",
+			codec:       JSONCodec{},
+			payload:     jsonPayload,
+			data:        jsonSerialized,
+			contentType: eventstore.PayloadContentTypeJSON,
+		},
+		{
+			name:        "protobuf",
+			codec:       ProtobufCodec{},
+			payload:     testProtoPayload(),
+			data:        protoSerialized,
+			contentType: eventstore.PayloadContentTypeProtobuf,
+		},
+		{
+			name:        "no payload",
+			codec:       JSONCodec{},
+			contentType: eventstore.PayloadContentTypeJSON,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marshaled, err := commandToEvent(tt.codec, nil, &latestSequence{aggregate: mockAggregate("V3-Sr3dJs")}, &mockCommand{
+				aggregate: mockAggregate("V3-Sr3dJs"),
+				payload:   tt.payload,
+			})
+			require.NoError(t, err)
+
+			serialized, err := commandToEvent(JSONCodec{}, nil, &latestSequence{aggregate: mockAggregate("V3-Sr3dJs")}, &mockSerializedCommand{
+				mockCommand: mockCommand{aggregate: mockAggregate("V3-Sr3dJs")},
+				data:        tt.data,
+				contentType: tt.contentType,
+			})
+			require.NoError(t, err)
+
+			// the serialized payload is stored the same way as the marshaled one, regardless of the codec
+			assert.Equal(t, marshaled, serialized)
+		})
+	}
+}
+
+func Test_commandToEvent_serializedDecode(t *testing.T) {
+	protoSerialized, err := proto.Marshal(testProtoPayload())
+	require.NoError(t, err)
+
+	e, err := commandToEvent(JSONCodec{}, nil, &latestSequence{aggregate: mockAggregate("V3-Sr3dDc")}, &mockSerializedCommand{
+		mockCommand: mockCommand{aggregate: mockAggregate("V3-Sr3dDc")},
+		data:        protoSerialized,
+		contentType: eventstore.PayloadContentTypeProtobuf,
+	})
+	require.NoError(t, err)
+
+	// the read side decodes the payload with its declared content type
+	payload := new(apipb.Method)
+	require.NoError(t, e.Unmarshal(payload))
+	assert.True(t, proto.Equal(testProtoPayload(), payload))
+}
+
+func Test_commandToEvent_serializedRedaction(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      RedactionPolicy
+		data        []byte
+		contentType eventstore.PayloadContentType
+		want        []byte
+	}{
+		{
+			name:        "redacted json",
+			policy:      RedactionPolicy{"event.type": {"email"}},
+			data:        []byte(`{"userName":"alice","email":"alice@example.com"}`),
+			contentType: eventstore.PayloadContentTypeJSON,
+			want:        []byte(`{"userName":"alice"}`),
+		},
+		{
+			name:        "redacted protobuf",
+			policy:      RedactionPolicy{"event.type": {"email"}},
+			data:        []byte{0x0a, 0x01, 0x61},
+			contentType: eventstore.PayloadContentTypeProtobuf,
+		},
+		{
+			name:        "redacted invalid json",
+			policy:      RedactionPolicy{"event.type": {"email"}},
+			data:        []byte(`{"userName":`),
+			contentType: eventstore.PayloadContentTypeJSON,
+		},
+		{
+			name:        "unknown content type",
+			data:        []byte(`{}`),
+			contentType: eventstore.PayloadContentType(42),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := commandToEvent(JSONCodec{}, tt.policy, &latestSequence{aggregate: mockAggregate("V3-Sr3dEr")}, &mockSerializedCommand{
+				mockCommand: mockCommand{aggregate: mockAggregate("V3-Sr3dEr")},
+				data:        tt.data,
+				contentType: tt.contentType,
+			})
+			if tt.want == nil {
+				assert.True(t, zerrors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, string(tt.want), string(got.payload))
+		})
+	}
+}
+
+func Test_mapCommands_serializedValidation(t *testing.T) {
+	es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithPayloadValidation())
+
+	// the serialized payload is validated like a marshaled one
+	_, _, _, err := es.mapCommands(context.Background(), []eventstore.Command{
+		&mockSerializedCommand{
+			mockCommand: mockCommand{aggregate: mockAggregate("V3-Sr3dVl")},
+			data:        []byte(`["not", "an", "object"]`),
+			contentType: eventstore.PayloadContentTypeJSON,
+		},
+	}, []*latestSequence{
+		{aggregate: mockAggregate("V3-Sr3dVl")},
+	})
+	assert.True(t, zerrors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+}
+
+func BenchmarkCommandToEvent_serialized(b *testing.B) {
+	payload := map[string]string{
+		"userName": "alice",
+		"email":    "alice@example.com",
+		"locale":   "en",
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		b.Fatal(err)
+	}
+	commands := []struct {
+		name    string
+		command eventstore.Command
+	}{
+		{
+			name:    "marshal",
+			command: &mockCommand{aggregate: mockAggregate("V3-Sr3dBm"), payload: payload},
+		},
+		{
+			name: "serialized",
+			command: &mockSerializedCommand{
+				mockCommand: mockCommand{aggregate: mockAggregate("V3-Sr3dBm")},
+				data:        data,
+				contentType: eventstore.PayloadContentTypeJSON,
+			},
+		},
+	}
+	for _, c := range commands {
+		b.Run(c.name, func(b *testing.B) {
+			sequence := &latestSequence{aggregate: mockAggregate("V3-Sr3dBm")}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := commandToEvent(JSONCodec{}, nil, sequence, c.command); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/cockroachdb/cockroach-go/v2/crdb"
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// pushTx is the transaction the events of a push are written in, it is implemented by [*sql.Tx]
+type pushTx interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	Commit() error
+	Rollback() error
+}
+
+// txBeginner starts the transactions of [Eventstore.Push].
+// release must be called after the transaction ended.
+type txBeginner interface {
+	beginTx(ctx context.Context) (tx pushTx, release func(), err error)
+}
+
+var _ txBeginner = (*clientTxBeginner)(nil)
+
+// clientTxBeginner starts the transactions on the database client.
+// If an acquire timeout is configured the connection is acquired separately,
+// so that the timeout does not apply to the transaction itself.
+type clientTxBeginner struct {
+	client *database.DB
+	// acquireTimeout limits the time to wait for a connection of the pool, zero waits until ctx is done
+	acquireTimeout time.Duration
+}
+
+func (b *clientTxBeginner) beginTx(ctx context.Context) (_ pushTx, release func(), err error) {
+	if b.acquireTimeout == 0 {
+		tx, err := b.client.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return tx, func() {}, nil
+	}
+
+	acquireCtx, cancel := context.WithTimeout(ctx, b.acquireTimeout)
+	conn, err := b.client.Conn(acquireCtx)
+	cancel()
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+			return nil, nil, zerrors.ThrowUnavailable(ErrPoolExhausted, "V3-Lw4cE", "Errors.Eventstore.PoolExhausted")
+		}
+		return nil, nil, err
+	}
+	release = func() {
+		err := conn.Close()
+		logging.OnError(err).Debug("unable to release connection")
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	return tx, release, nil
+}
+
+var _ crdb.Tx = (*transaction)(nil)
+
+// transaction adapts a [pushTx] to [crdb.ExecuteInTx], which retries the push on serialization failures
+type transaction struct {
+	pushTx
+}
+
+func (t *transaction) Exec(ctx context.Context, query string, args ...any) error {
+	_, err := t.pushTx.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (t *transaction) Commit(context.Context) error {
+	return t.pushTx.Commit()
+}
+
+func (t *transaction) Rollback(context.Context) error {
+	return t.pushTx.Rollback()
+}
@@ -0,0 +1,171 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/database/mock"
+)
+
+var (
+	_ txBeginner = (*fakeTxBeginner)(nil)
+	_ pushTx     = (*fakeTx)(nil)
+)
+
+// fakeTxBeginner begins the fake transaction or fails with err
+type fakeTxBeginner struct {
+	tx       *fakeTx
+	err      error
+	released bool
+}
+
+func (b *fakeTxBeginner) beginTx(context.Context) (pushTx, func(), error) {
+	if b.err != nil {
+		return nil, nil, b.err
+	}
+	return b.tx, func() { b.released = true }, nil
+}
+
+// fakeTx records the statements and the outcome of a transaction.
+// Queries are answered by the queries database, statements fail with the error in execErrs.
+type fakeTx struct {
+	queries  *sql.DB
+	execErrs map[string]error
+
+	execs      []string
+	committed  bool
+	rolledBack bool
+}
+
+func (tx *fakeTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return tx.queries.QueryContext(ctx, query, args...)
+}
+
+func (tx *fakeTx) ExecContext(_ context.Context, query string, _ ...any) (sql.Result, error) {
+	tx.execs = append(tx.execs, query)
+	if err := tx.execErrs[query]; err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.rolledBack = true
+	return nil
+}
+
+func TestEventstore_Push_transaction(t *testing.T) {
+	// sets the [pushPlaceholderFmt] used in the expectations
+	NewEventstore(&database.DB{Database: new(cockroach.Config)})
+
+	aggregate := mockAggregate("V3-Tx4mB")
+	sequenceConditions, _ := sequencesToSql([]*latestSequence{{aggregate: aggregate}})
+	expectSequence := mock.ExpectQuery(
+		fmt.Sprintf(latestSequencesStmt, strings.Join(sequenceConditions, " UNION ALL ")),
+		mock.WithQueryResult(
+			[]string{"instance_id", "owner", "aggregate_type", "aggregate_id", "sequence"},
+			[][]driver.Value{{"instance", "ro", "type", "V3-Tx4mB", uint64(5)}},
+		),
+	)
+	insertStmt := fmt.Sprintf(pushStmt, fmt.Sprintf(pushPlaceholderFmt, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13))
+	errInsert := errors.New("insert failed")
+	errRelease := errors.New("release failed")
+	errBegin := errors.New("begin failed")
+
+	tests := []struct {
+		name           string
+		beginErr       error
+		execErrs       map[string]error
+		expectations   []mock.Expectation
+		wantExecs      []string
+		wantCommitted  bool
+		wantRolledBack bool
+		wantErr        error
+	}{
+		{
+			name: "commit on success",
+			expectations: []mock.Expectation{
+				expectSequence,
+				mock.ExpectQuery(insertStmt, mock.WithQueryResult(
+					[]string{"created_at", "position"},
+					[][]driver.Value{{time.Now(), float64(1)}},
+				)),
+			},
+			wantExecs:     []string{"SAVEPOINT cockroach_restart", "RELEASE SAVEPOINT cockroach_restart"},
+			wantCommitted: true,
+		},
+		{
+			name: "rollback on error",
+			expectations: []mock.Expectation{
+				expectSequence,
+				mock.ExpectQuery(insertStmt, mock.WithQueryErr(errInsert)),
+			},
+			wantExecs:      []string{"SAVEPOINT cockroach_restart"},
+			wantRolledBack: true,
+			wantErr:        errInsert,
+		},
+		{
+			name: "rollback on failed release",
+			execErrs: map[string]error{
+				"RELEASE SAVEPOINT cockroach_restart": errRelease,
+			},
+			expectations: []mock.Expectation{
+				expectSequence,
+				mock.ExpectQuery(insertStmt, mock.WithQueryResult(
+					[]string{"created_at", "position"},
+					[][]driver.Value{{time.Now(), float64(1)}},
+				)),
+			},
+			wantExecs:      []string{"SAVEPOINT cockroach_restart", "RELEASE SAVEPOINT cockroach_restart"},
+			wantRolledBack: true,
+			wantErr:        errRelease,
+		},
+		{
+			name:     "begin failed",
+			beginErr: errBegin,
+			wantErr:  errBegin,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlMock := mock.NewSQLMock(t, tt.expectations...)
+			defer sqlMock.Assert(t)
+
+			tx := &fakeTx{queries: sqlMock.DB, execErrs: tt.execErrs}
+			beginner := &fakeTxBeginner{tx: tx, err: tt.beginErr}
+			es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithMetrics(new(testMetrics)))
+			es.txBeginner = beginner
+
+			events, err := es.Push(context.Background(), &mockCommand{aggregate: aggregate})
+			assert.Equal(t, tt.wantExecs, tx.execs)
+			assert.Equal(t, tt.wantCommitted, tx.committed)
+			assert.Equal(t, tt.wantRolledBack, tx.rolledBack)
+			// the connection is released once the transaction was started
+			assert.Equal(t, tt.beginErr == nil, beginner.released)
+			if tt.wantErr != nil {
+				// crdb wraps errors of the release as ambiguous commit errors
+				assert.ErrorContains(t, err, tt.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			assert.Equal(t, uint64(6), events[0].Sequence())
+		})
+	}
+}
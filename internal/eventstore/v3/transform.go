@@ -0,0 +1,61 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// CommandTransform enriches a command before its event is inserted, e.g. by adding a tag to the metadata.
+// The metadata are initialized with the metadata of the context and can be changed by the transform.
+// The transform must not change the aggregate or the type of the command, otherwise the push fails.
+type CommandTransform func(ctx context.Context, command eventstore.Command, metadata eventstore.Metadata) error
+
+// WithCommandTransform invokes the transform for each command of a push before the events are inserted.
+// The transforms are invoked in the order they were registered, each one receives the metadata of the previous ones.
+func WithCommandTransform(transform CommandTransform) Option {
+	return func(es *Eventstore) {
+		es.transforms = append(es.transforms, transform)
+	}
+}
+
+// transformedCommand is a command with the metadata set by the transforms,
+// which replace the metadata of the context in [Eventstore.mapCommands]
+type transformedCommand struct {
+	eventstore.Command
+	metadata eventstore.Metadata
+}
+
+// transformCommands invokes the transforms for each command,
+// the transforms are invoked once per push and not again if the push is retried
+func (es *Eventstore) transformCommands(ctx context.Context, commands []eventstore.Command) ([]eventstore.Command, error) {
+	if len(es.transforms) == 0 {
+		return commands, nil
+	}
+	contextMetadata := eventstore.MetadataFromContext(ctx)
+	transformed := make([]eventstore.Command, len(commands))
+	for i, command := range commands {
+		aggregate, typ := *command.Aggregate(), command.Type()
+		metadata := maps.Clone(contextMetadata)
+		if metadata == nil {
+			metadata = make(eventstore.Metadata)
+		}
+		for _, transform := range es.transforms {
+			if err := transform(ctx, command, metadata); err != nil {
+				return nil, err
+			}
+		}
+		if *command.Aggregate() != aggregate || command.Type() != typ {
+			return nil, zerrors.ThrowInternal(
+				fmt.Errorf("command %d (type %s of aggregate %s %s) was changed by a transform", i, typ, aggregate.Type, aggregate.ID),
+				"V3-Tf4Cmd",
+				"Errors.Internal",
+			)
+		}
+		transformed[i] = &transformedCommand{Command: command, metadata: metadata}
+	}
+	return transformed, nil
+}
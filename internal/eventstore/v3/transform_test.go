@@ -0,0 +1,92 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/database/cockroach"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestEventstore_transformCommands(t *testing.T) {
+	es := NewEventstore(&database.DB{Database: new(cockroach.Config)},
+		WithCommandTransform(func(_ context.Context, command eventstore.Command, metadata eventstore.Metadata) error {
+			metadata["tenant"] = command.Aggregate().InstanceID
+			metadata["order"] = "first"
+			return nil
+		}),
+		WithCommandTransform(func(_ context.Context, _ eventstore.Command, metadata eventstore.Metadata) error {
+			metadata["order"] += ",second"
+			return nil
+		}),
+	)
+	serialized := &mockSerializedCommand{
+		mockCommand: mockCommand{aggregate: mockAggregate("V3-Tf5Rq")},
+		data:        []byte(`{"name":"name"}`),
+		contentType: eventstore.PayloadContentTypeJSON,
+	}
+	commands := []eventstore.Command{
+		&mockCommand{aggregate: mockAggregate("V3-Tf5Rq")},
+		serialized,
+	}
+	ctx := eventstore.WithMetadata(context.Background(), eventstore.Metadata{"requestID": "request"})
+
+	transformed, err := es.transformCommands(ctx, commands)
+	require.NoError(t, err)
+	events, _, args, err := es.mapCommands(ctx, transformed, []*latestSequence{{aggregate: mockAggregate("V3-Tf5Rq")}})
+	require.NoError(t, err)
+
+	want := eventstore.Metadata{"requestID": "request", "tenant": "instance", "order": "first,second"}
+	for i, e := range events {
+		assert.Equal(t, want, e.(eventstore.MetadataProvider).EventMetadata())
+		read, err := repository.UnmarshalMetadata(args[i*argsPerCommand+10].(Payload))
+		require.NoError(t, err)
+		assert.Equal(t, want, read)
+		// the identity and the sequence of the command are kept
+		assert.Equal(t, *mockAggregate("V3-Tf5Rq"), *e.Aggregate())
+		assert.Equal(t, uint64(i+1), e.Sequence())
+	}
+	// the serialized payload of the wrapped command is still used
+	assert.Equal(t, serialized.data, events[1].DataAsBytes())
+	// the metadata of the context are not changed
+	assert.Equal(t, eventstore.Metadata{"requestID": "request"}, eventstore.MetadataFromContext(ctx))
+}
+
+func TestEventstore_transformCommands_errors(t *testing.T) {
+	errTransform := errors.New("transform failed")
+	tests := []struct {
+		name      string
+		transform CommandTransform
+		wantErr   func(error) bool
+	}{
+		{
+			name: "transform failed",
+			transform: func(context.Context, eventstore.Command, eventstore.Metadata) error {
+				return errTransform
+			},
+			wantErr: func(err error) bool { return errors.Is(err, errTransform) },
+		},
+		{
+			name: "aggregate changed",
+			transform: func(_ context.Context, command eventstore.Command, _ eventstore.Metadata) error {
+				command.Aggregate().ID = "changed"
+				return nil
+			},
+			wantErr: zerrors.IsInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := NewEventstore(&database.DB{Database: new(cockroach.Config)}, WithCommandTransform(tt.transform))
+			_, err := es.transformCommands(context.Background(), []eventstore.Command{&mockCommand{aggregate: mockAggregate("V3-Tf5Rq")}})
+			assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+		})
+	}
+}
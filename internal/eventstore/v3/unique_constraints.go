@@ -2,7 +2,6 @@ package eventstore
 
 import (
 	"context"
-	"database/sql"
 	_ "embed"
 	"errors"
 	"fmt"
@@ -24,7 +23,24 @@ var (
 	addConstraintStmt string
 )
 
-func handleUniqueConstraints(ctx context.Context, tx *sql.Tx, commands []eventstore.Command) error {
+type skipUniqueConstraintsKey struct{}
+
+// WithoutUniqueConstraints returns a context in which [Eventstore.Push] skips the unique constraints of the commands.
+// It is meant for trusted bulk imports like migrations, where the source already guarantees the uniqueness
+// and the handling of the constraints only costs time.
+//
+// It is UNSAFE for untrusted input: the unique constraints of the commands are neither checked nor added or removed,
+// so duplicates are stored without error and later pushes cannot detect duplicates of the imported fields either.
+func WithoutUniqueConstraints(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipUniqueConstraintsKey{}, true)
+}
+
+func uniqueConstraintsSkipped(ctx context.Context) bool {
+	skipped, _ := ctx.Value(skipUniqueConstraintsKey{}).(bool)
+	return skipped
+}
+
+func handleUniqueConstraints(ctx context.Context, tx pushTx, commands []eventstore.Command) error {
 	deletePlaceholders := make([]string, 0)
 	deleteArgs := make([]any, 0)
 
@@ -33,33 +49,29 @@ func handleUniqueConstraints(ctx context.Context, tx *sql.Tx, commands []eventst
 	addConstraints := map[string]*eventstore.UniqueConstraint{}
 	deleteConstraints := map[string]*eventstore.UniqueConstraint{}
 
-	for _, command := range commands {
-		for _, constraint := range command.UniqueConstraints() {
-			instanceID := command.Aggregate().InstanceID
-			if constraint.IsGlobal {
-				instanceID = ""
-			}
-			switch constraint.Action {
-			case eventstore.UniqueConstraintAdd:
-				constraint.UniqueField = strings.ToLower(constraint.UniqueField)
-				addPlaceholders = append(addPlaceholders, fmt.Sprintf("($%d, $%d, $%d)", len(addArgs)+1, len(addArgs)+2, len(addArgs)+3))
-				addArgs = append(addArgs, instanceID, constraint.UniqueType, constraint.UniqueField)
-				addConstraints[fmt.Sprintf(uniqueConstraintPlaceholderFmt, instanceID, constraint.UniqueType, constraint.UniqueField)] = constraint
-			case eventstore.UniqueConstraintRemove:
-				deletePlaceholders = append(deletePlaceholders, fmt.Sprintf(deleteConstraintPlaceholdersStmt, len(deleteArgs)+1, len(deleteArgs)+2, len(deleteArgs)+3))
-				deleteArgs = append(deleteArgs, instanceID, constraint.UniqueType, constraint.UniqueField)
-				deleteConstraints[fmt.Sprintf(uniqueConstraintPlaceholderFmt, instanceID, constraint.UniqueType, constraint.UniqueField)] = constraint
-			case eventstore.UniqueConstraintInstanceRemove:
-				deletePlaceholders = append(deletePlaceholders, fmt.Sprintf("(instance_id = $%d)", len(deleteArgs)+1))
-				deleteArgs = append(deleteArgs, instanceID)
-				deleteConstraints[fmt.Sprintf(uniqueConstraintPlaceholderFmt, instanceID, constraint.UniqueType, constraint.UniqueField)] = constraint
-			}
+	for _, constraint := range instanceUniqueConstraints(commands) {
+		instanceID := constraint.instanceID
+		switch constraint.Action {
+		case eventstore.UniqueConstraintAdd:
+			constraint.UniqueField = strings.ToLower(constraint.UniqueField)
+			addPlaceholders = append(addPlaceholders, fmt.Sprintf("($%d, $%d, $%d)", len(addArgs)+1, len(addArgs)+2, len(addArgs)+3))
+			addArgs = append(addArgs, instanceID, constraint.UniqueType, constraint.UniqueField)
+			addConstraints[fmt.Sprintf(uniqueConstraintPlaceholderFmt, instanceID, constraint.UniqueType, constraint.UniqueField)] = constraint.UniqueConstraint
+		case eventstore.UniqueConstraintRemove:
+			deletePlaceholders = append(deletePlaceholders, fmt.Sprintf(deleteConstraintPlaceholdersStmt, len(deleteArgs)+1, len(deleteArgs)+2, len(deleteArgs)+3))
+			deleteArgs = append(deleteArgs, instanceID, constraint.UniqueType, constraint.UniqueField)
+			deleteConstraints[fmt.Sprintf(uniqueConstraintPlaceholderFmt, instanceID, constraint.UniqueType, constraint.UniqueField)] = constraint.UniqueConstraint
+		case eventstore.UniqueConstraintInstanceRemove:
+			deletePlaceholders = append(deletePlaceholders, fmt.Sprintf("(instance_id = $%d)", len(deleteArgs)+1))
+			deleteArgs = append(deleteArgs, instanceID)
+			deleteConstraints[fmt.Sprintf(uniqueConstraintPlaceholderFmt, instanceID, constraint.UniqueType, constraint.UniqueField)] = constraint.UniqueConstraint
 		}
 	}
 
 	if len(deletePlaceholders) > 0 {
 		_, err := tx.ExecContext(ctx, fmt.Sprintf(deleteConstraintStmt, strings.Join(deletePlaceholders, " OR ")), deleteArgs...)
 		if err != nil {
+			pushFailureLogger(err, commands).Debug("delete unique constraint failed")
 			logging.WithError(err).Warn("delete unique constraint failed")
 			errMessage := "Errors.Internal"
 			if constraint := constraintFromErr(err, deleteConstraints); constraint != nil {
@@ -71,6 +83,7 @@ func handleUniqueConstraints(ctx context.Context, tx *sql.Tx, commands []eventst
 	if len(addPlaceholders) > 0 {
 		_, err := tx.ExecContext(ctx, fmt.Sprintf(addConstraintStmt, strings.Join(addPlaceholders, ", ")), addArgs...)
 		if err != nil {
+			pushFailureLogger(err, commands).Debug("add unique constraint failed")
 			logging.WithError(err).Warn("add unique constraint failed")
 			errMessage := "Errors.Internal"
 			if constraint := constraintFromErr(err, addConstraints); constraint != nil {
@@ -82,6 +95,80 @@ func handleUniqueConstraints(ctx context.Context, tx *sql.Tx, commands []eventst
 	return nil
 }
 
+// instanceUniqueConstraint is a unique constraint with the instance it applies to, empty for global constraints
+type instanceUniqueConstraint struct {
+	instanceID string
+	*eventstore.UniqueConstraint
+}
+
+// conditionalUniqueField identifies the field of a conditional unique constraint
+type conditionalUniqueField struct {
+	instanceID  string
+	uniqueType  string
+	uniqueField string
+}
+
+// conditionalUniqueChange is the net change of the conditional unique constraints of a field within a push
+type conditionalUniqueChange struct {
+	// wasReserved is the state of the condition before the first command of the field within the push
+	wasReserved bool
+	last        *eventstore.UniqueConstraint
+}
+
+// instanceUniqueConstraints returns the unique constraints of the commands with the instance they apply to.
+// The conditional unique constraints are translated to adds and removes of the net change of each field:
+// a field is added if it was not reserved before the push and its last condition is met,
+// it is removed if it was reserved before the push and its last condition is not met.
+func instanceUniqueConstraints(commands []eventstore.Command) []instanceUniqueConstraint {
+	constraints := make([]instanceUniqueConstraint, 0)
+	conditionalFields := make([]conditionalUniqueField, 0)
+	conditionalChanges := make(map[conditionalUniqueField]*conditionalUniqueChange)
+
+	for _, command := range commands {
+		for _, constraint := range command.UniqueConstraints() {
+			instanceID := command.Aggregate().InstanceID
+			if constraint.IsGlobal {
+				instanceID = ""
+			}
+			if constraint.Action != eventstore.UniqueConstraintConditional {
+				constraints = append(constraints, instanceUniqueConstraint{instanceID: instanceID, UniqueConstraint: constraint})
+				continue
+			}
+			field := conditionalUniqueField{
+				instanceID:  instanceID,
+				uniqueType:  constraint.UniqueType,
+				uniqueField: strings.ToLower(constraint.UniqueField),
+			}
+			change, ok := conditionalChanges[field]
+			if !ok {
+				change = &conditionalUniqueChange{wasReserved: constraint.ConditionWasMet}
+				conditionalChanges[field] = change
+				conditionalFields = append(conditionalFields, field)
+			}
+			change.last = constraint
+		}
+	}
+
+	for _, field := range conditionalFields {
+		change := conditionalChanges[field]
+		if change.wasReserved == change.last.ConditionMet {
+			continue
+		}
+		constraint := &eventstore.UniqueConstraint{
+			UniqueType:   change.last.UniqueType,
+			UniqueField:  change.last.UniqueField,
+			ErrorMessage: change.last.ErrorMessage,
+			IsGlobal:     change.last.IsGlobal,
+			Action:       eventstore.UniqueConstraintRemove,
+		}
+		if change.last.ConditionMet {
+			constraint.Action = eventstore.UniqueConstraintAdd
+		}
+		constraints = append(constraints, instanceUniqueConstraint{instanceID: field.instanceID, UniqueConstraint: constraint})
+	}
+	return constraints
+}
+
 func constraintFromErr(err error, constraints map[string]*eventstore.UniqueConstraint) *eventstore.UniqueConstraint {
 	pgErr := new(pgconn.PgError)
 	if !errors.As(err, &pgErr) {
@@ -0,0 +1,16 @@
+package execution
+
+import (
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// ValidateBasicAuth checks the username of the basic authentication of a target,
+// it must not contain a colon as it separates the username from the password (RFC 7617, section 2)
+func ValidateBasicAuth(username string) error {
+	if username == "" || strings.Contains(username, ":") {
+		return zerrors.ThrowInvalidArgument(nil, "EXEC-Ba3Un5", "Errors.Target.InvalidBasicAuth")
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package execution
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestValidateBasicAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		wantErr  error
+	}{
+		{
+			name:     "valid",
+			username: "user",
+		},
+		{
+			name:    "username missing",
+			wantErr: zerrors.ThrowInvalidArgument(nil, "EXEC-Ba3Un5", "Errors.Target.InvalidBasicAuth"),
+		},
+		{
+			name:     "username with colon",
+			username: "us:er",
+			wantErr:  zerrors.ThrowInvalidArgument(nil, "EXEC-Ba3Un5", "Errors.Target.InvalidBasicAuth"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBasicAuth(tt.username)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_authorize_basicAuth(t *testing.T) {
+	tests := []struct {
+		name      string
+		basicAuth *domain.TargetBasicAuthCredentials
+		want      string
+	}{
+		{
+			name: "no credentials",
+		},
+		{
+			name:      "username and password",
+			basicAuth: &domain.TargetBasicAuthCredentials{Username: "user", Password: "pass:word"},
+			want:      "Basic " + base64.StdEncoding.EncodeToString([]byte("user:pass:word")),
+		},
+		{
+			name:      "empty password",
+			basicAuth: &domain.TargetBasicAuthCredentials{Username: "user"},
+			want:      "Basic " + base64.StdEncoding.EncodeToString([]byte("user:")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "https://example.com", nil)
+			err := authorize(context.Background(), &mockTarget{BasicAuthCredentials: tt.basicAuth}, req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, req.Header.Get("Authorization"))
+		})
+	}
+}
+
+func Test_call_basicAuth(t *testing.T) {
+	var (
+		username, password string
+		ok                 bool
+	)
+	targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok = r.BasicAuth()
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer targetServer.Close()
+
+	_, err := call(context.Background(), &mockTarget{
+		Endpoint: targetServer.URL,
+		Timeout:  time.Second,
+		BasicAuthCredentials: &domain.TargetBasicAuthCredentials{
+			Username: "user",
+			Password: "password",
+		},
+	}, []byte("{}"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "user", username)
+	assert.Equal(t, "password", password)
+}
@@ -0,0 +1,90 @@
+package execution
+
+import (
+	"encoding/json"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// MaxBatchSize is the maximum count of payloads delivered to a target in a single call
+const MaxBatchSize = 1000
+
+// ValidateBatchDelivery checks that a batch delivery has a size of at most [MaxBatchSize] and a positive wait,
+// an empty batch delivery delivers every payload on its own
+func ValidateBatchDelivery(batch *domain.TargetBatchDelivery) error {
+	if batch == nil || *batch == (domain.TargetBatchDelivery{}) {
+		return nil
+	}
+	if batch.MaxSize < 1 || batch.MaxSize > MaxBatchSize || batch.MaxWait <= 0 {
+		return zerrors.ThrowInvalidArgument(nil, "EXEC-Bt4dVl", "Errors.Target.InvalidBatchDelivery")
+	}
+	return nil
+}
+
+// payloadBatch are the buffered payloads of a target
+type payloadBatch struct {
+	target   Target
+	payloads []*asyncPayload
+	timer    *clock.Timer
+}
+
+// batch adds the payload to the batch of the target.
+// The batch is delivered as JSON array in a single call, as soon as it reached its max size
+// or its first payload waited for the max wait.
+// The payloads of a batch are delivered in a single call, so the batch succeeds or fails as a whole:
+// if the call fails, all payloads of the batch failed and none of them is delivered again on its own.
+// Batches of targets with ordered delivery are delivered one after the other.
+func (d *asyncDispatcher) batch(target Target, payload *asyncPayload) {
+	delivery := target.GetBatchDelivery()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	id := target.GetTargetID()
+	batch, ok := d.batches[id]
+	if !ok {
+		batch = &payloadBatch{target: target}
+		batch.timer = d.clock.AfterFunc(delivery.MaxWait, func() {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			// the batch might have been sent because it was full
+			if d.batches[id] == batch {
+				d.flush(id)
+			}
+		})
+		d.batches[id] = batch
+	}
+	batch.payloads = append(batch.payloads, payload)
+	if len(batch.payloads) >= delivery.MaxSize {
+		d.flush(id)
+	}
+}
+
+// flush sends the buffered payloads of the target, d.mu must be held
+func (d *asyncDispatcher) flush(id string) {
+	batch := d.batches[id]
+	delete(d.batches, id)
+	batch.timer.Stop()
+	var orderKey string
+	if batch.target.IsOrderedDelivery() {
+		orderKey = id
+	}
+	d.send(batch.target, batch.payloads, true, orderKey)
+}
+
+// payloadsBody returns the payloads as JSON array if batched, otherwise the single payload
+func payloadsBody(payloads []*asyncPayload, batched bool) ([]byte, error) {
+	if !batched {
+		return payloads[0].body, nil
+	}
+	bodies := make([]json.RawMessage, len(payloads))
+	for i, payload := range payloads {
+		bodies[i] = payload.body
+	}
+	body, err := json.Marshal(bodies)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "EXEC-Bt5mJs", "Errors.Internal")
+	}
+	return body, nil
+}
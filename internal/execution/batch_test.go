@@ -0,0 +1,291 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestValidateBatchDelivery(t *testing.T) {
+	tests := []struct {
+		name    string
+		batch   *domain.TargetBatchDelivery
+		wantErr bool
+	}{
+		{
+			name: "no batch delivery",
+		},
+		{
+			name:  "empty, payloads on their own",
+			batch: &domain.TargetBatchDelivery{},
+		},
+		{
+			name:  "valid",
+			batch: &domain.TargetBatchDelivery{MaxSize: 100, MaxWait: time.Second},
+		},
+		{
+			name:    "without wait",
+			batch:   &domain.TargetBatchDelivery{MaxSize: 100},
+			wantErr: true,
+		},
+		{
+			name:    "without size",
+			batch:   &domain.TargetBatchDelivery{MaxWait: time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "too large",
+			batch:   &domain.TargetBatchDelivery{MaxSize: MaxBatchSize + 1, MaxWait: time.Second},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBatchDelivery(tt.batch)
+			if tt.wantErr {
+				assert.True(t, zerrors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// batchReceiver is a target endpoint which passes the received bodies to the channel
+func batchReceiver(t *testing.T, status int) (*httptest.Server, <-chan []byte) {
+	received := make(chan []byte, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- body
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+// newTestDispatcher returns a dispatcher with a mock clock which passes the results to the channel
+func newTestDispatcher(ctx context.Context) (*asyncDispatcher, *clock.Mock, <-chan *TargetExecutionResult) {
+	results := make(chan *TargetExecutionResult, 10)
+	dispatcher := newAsyncDispatcher(ctx, func(result *TargetExecutionResult) {
+		results <- result
+	})
+	mockClock := clock.NewMock()
+	dispatcher.clock = mockClock
+	return dispatcher, mockClock, results
+}
+
+func receiveContents(t *testing.T, received <-chan []byte) []string {
+	t.Helper()
+	select {
+	case body := <-received:
+		var payloads []*mockContextInfoRequest
+		require.NoError(t, json.Unmarshal(body, &payloads))
+		contents := make([]string, len(payloads))
+		for i, payload := range payloads {
+			contents[i] = payload.Request.Request
+		}
+		return contents
+	case <-time.After(5 * time.Second):
+		t.Fatal("batch not received")
+		return nil
+	}
+}
+
+func receiveResult(t *testing.T, results <-chan *TargetExecutionResult) *TargetExecutionResult {
+	t.Helper()
+	select {
+	case result := <-results:
+		return result
+	case <-time.After(5 * time.Second):
+		t.Fatal("result not reported")
+		return nil
+	}
+}
+
+func TestAsyncDispatcher_batchBySize(t *testing.T) {
+	server, received := batchReceiver(t, http.StatusOK)
+	dispatcher, _, results := newTestDispatcher(context.Background())
+	target := &mockTarget{
+		TargetID:      "target",
+		TargetType:    domain.TargetTypeAsync,
+		Endpoint:      server.URL,
+		Timeout:       time.Minute,
+		BatchDelivery: domain.TargetBatchDelivery{MaxSize: 2, MaxWait: time.Hour},
+	}
+
+	dispatcher.dispatch(context.Background(), target, newMockContextInfoRequest("first"))
+	dispatcher.dispatch(context.Background(), target, newMockContextInfoRequest("second"))
+	assert.Equal(t, []string{"first", "second"}, receiveContents(t, received))
+	result := receiveResult(t, results)
+	assert.NoError(t, result.Err)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Equal(t, 2, result.BatchSize)
+
+	// the incomplete batch is delivered on close
+	dispatcher.dispatch(context.Background(), target, newMockContextInfoRequest("third"))
+	dispatcher.close()
+	assert.Equal(t, []string{"third"}, receiveContents(t, received))
+	assert.Equal(t, 1, receiveResult(t, results).BatchSize)
+}
+
+func TestAsyncDispatcher_batchByTime(t *testing.T) {
+	server, received := batchReceiver(t, http.StatusOK)
+	dispatcher, mockClock, results := newTestDispatcher(context.Background())
+	defer dispatcher.close()
+	target := &mockTarget{
+		TargetID:      "target",
+		TargetType:    domain.TargetTypeAsync,
+		Endpoint:      server.URL,
+		Timeout:       time.Minute,
+		BatchDelivery: domain.TargetBatchDelivery{MaxSize: 10, MaxWait: time.Second},
+	}
+
+	dispatcher.dispatch(context.Background(), target, newMockContextInfoRequest("first"))
+	mockClock.Add(500 * time.Millisecond)
+	dispatcher.dispatch(context.Background(), target, newMockContextInfoRequest("second"))
+	select {
+	case <-received:
+		t.Fatal("batch delivered before the max wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// the wait starts with the first payload of the batch
+	mockClock.Add(500 * time.Millisecond)
+	assert.Equal(t, []string{"first", "second"}, receiveContents(t, received))
+	assert.NoError(t, receiveResult(t, results).Err)
+}
+
+func TestAsyncDispatcher_withoutBatchDelivery(t *testing.T) {
+	server, received := batchReceiver(t, http.StatusOK)
+	dispatcher, _, results := newTestDispatcher(context.Background())
+	target := &mockTarget{
+		TargetID:   "target",
+		TargetType: domain.TargetTypeAsync,
+		Endpoint:   server.URL,
+		Timeout:    time.Minute,
+	}
+
+	dispatcher.dispatch(context.Background(), target, newMockContextInfoRequest("content"))
+	dispatcher.close()
+
+	// every payload is delivered on its own as JSON object
+	payload := new(mockContextInfoRequest)
+	require.NoError(t, json.Unmarshal(<-received, payload))
+	assert.Equal(t, "content", payload.Request.Request)
+	assert.Equal(t, 1, receiveResult(t, results).BatchSize)
+	assert.Empty(t, received)
+	assert.Empty(t, results)
+}
+
+func TestAsyncDispatcher_failedBatch(t *testing.T) {
+	server, received := batchReceiver(t, http.StatusInternalServerError)
+	dispatcher, _, results := newTestDispatcher(context.Background())
+	target := &mockTarget{
+		TargetID:      "target",
+		TargetType:    domain.TargetTypeAsync,
+		Endpoint:      server.URL,
+		Timeout:       time.Minute,
+		BatchDelivery: domain.TargetBatchDelivery{MaxSize: 2, MaxWait: time.Hour},
+	}
+
+	dispatcher.dispatch(context.Background(), target, newMockContextInfoRequest("first"))
+	dispatcher.dispatch(context.Background(), target, newMockContextInfoRequest("second"))
+	dispatcher.close()
+
+	// the batch fails as a whole and is not delivered again payload by payload
+	assert.Len(t, receiveContents(t, received), 2)
+	result := receiveResult(t, results)
+	assert.Error(t, result.Err)
+	assert.Equal(t, http.StatusInternalServerError, result.StatusCode)
+	assert.Equal(t, 2, result.BatchSize)
+	assert.Empty(t, received)
+}
+
+func TestAsyncDispatcher_orderedDelivery(t *testing.T) {
+	const payloads = 20
+
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := new(mockContextInfoRequest)
+		_ = json.NewDecoder(r.Body).Decode(payload)
+		// vary the duration of the calls, so that unordered calls overtake each other
+		time.Sleep(time.Duration(rand.Intn(3)) * time.Millisecond)
+		mu.Lock()
+		received = append(received, payload.Request.Request)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	dispatcher, _, _ := newTestDispatcher(WithAsyncPoolSize(context.Background(), 4))
+	target := &mockTarget{
+		TargetID:        "target",
+		TargetType:      domain.TargetTypeAsync,
+		Endpoint:        server.URL,
+		Timeout:         time.Minute,
+		OrderedDelivery: true,
+	}
+	want := make([]string, payloads)
+	for i := range want {
+		want[i] = strconv.Itoa(i)
+		dispatcher.dispatch(context.Background(), target, newMockContextInfoRequest(want[i]))
+	}
+	dispatcher.close()
+
+	assert.Equal(t, want, received)
+	assert.Empty(t, dispatcher.ordered)
+}
+
+func TestAsyncDispatcher_orderedDelivery_parallelTargets(t *testing.T) {
+	release := make(chan struct{})
+	receivedB := make(chan struct{}, 1)
+	blocking := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer blocking.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedB <- struct{}{}
+	}))
+	defer server.Close()
+
+	dispatcher, _, _ := newTestDispatcher(context.Background())
+	dispatcher.dispatch(context.Background(), &mockTarget{
+		TargetID:        "a",
+		TargetType:      domain.TargetTypeAsync,
+		Endpoint:        blocking.URL,
+		Timeout:         time.Minute,
+		OrderedDelivery: true,
+	}, newMockContextInfoRequest("content"))
+	dispatcher.dispatch(context.Background(), &mockTarget{
+		TargetID:        "b",
+		TargetType:      domain.TargetTypeAsync,
+		Endpoint:        server.URL,
+		Timeout:         time.Minute,
+		OrderedDelivery: true,
+	}, newMockContextInfoRequest("content"))
+
+	// the delivery to target b is not blocked by the pending call of target a
+	select {
+	case <-receivedB:
+	case <-time.After(5 * time.Second):
+		t.Fatal("payload of target b blocked by target a")
+	}
+	close(release)
+	dispatcher.close()
+}
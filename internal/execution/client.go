@@ -0,0 +1,155 @@
+package execution
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// maxCachedTransports bounds the amount of transports of targets with their own transport configuration,
+// the transport of an arbitrary configuration is dropped if the limit is reached
+const maxCachedTransports = 1000
+
+// transportKey is the configuration of the transport of a target
+type transportKey struct {
+	fingerprint string
+	caBundle    string
+	timeouts    domain.TargetPhaseTimeouts
+	// timeout limits the phases without own timeout, it is only part of the key if any phase timeout is set
+	timeout time.Duration
+}
+
+func newTransportKey(target Target) transportKey {
+	key := transportKey{
+		fingerprint: target.GetCertificateFingerprint(),
+		caBundle:    target.GetCABundle(),
+		timeouts:    target.GetPhaseTimeouts(),
+	}
+	if !key.timeouts.IsZero() {
+		key.timeout = target.GetTimeout()
+	}
+	return key
+}
+
+// isZero reports if the target uses the shared transport
+func (k transportKey) isZero() bool {
+	return k == transportKey{}
+}
+
+// transportCache holds a transport per distinct configuration of targets,
+// so that the connections to a target are reused across calls
+type transportCache struct {
+	mu         sync.Mutex
+	transports map[transportKey]*http.Transport
+}
+
+var transports = &transportCache{transports: make(map[transportKey]*http.Transport)}
+
+// get returns the transport of the configuration, it is built on the first use
+func (c *transportCache) get(key transportKey) (*http.Transport, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if transport, ok := c.transports[key]; ok {
+		return transport, nil
+	}
+	transport, err := newTransport(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.transports) >= maxCachedTransports {
+		for evicted, cached := range c.transports {
+			cached.CloseIdleConnections()
+			delete(c.transports, evicted)
+			break
+		}
+	}
+	c.transports[key] = transport
+	return transport, nil
+}
+
+// reset drops all transports, e.g. because the host policy changed
+func (c *transportCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, transport := range c.transports {
+		transport.CloseIdleConnections()
+		delete(c.transports, key)
+	}
+}
+
+// newTransport builds the transport of the configuration based on the transport of the host policy
+func newTransport(key transportKey) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if policyTransport != nil {
+		transport = policyTransport.Clone()
+	}
+	if !key.timeouts.IsZero() {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		if hostPolicy != nil {
+			dialer = hostPolicy.dialer()
+		}
+		applyPhaseTimeouts(transport, dialer, key.timeouts, key.timeout)
+		transport.DialContext = dialer.DialContext
+	}
+	if key.caBundle != "" {
+		if err := trustCABundle(transport, key.caBundle); err != nil {
+			return nil, err
+		}
+	}
+	// the pinned certificate replaces the verification against any certificate authorities
+	if key.fingerprint != "" {
+		pinCertificate(transport, key.fingerprint)
+	}
+	return transport, nil
+}
+
+// httpClient returns the client used to call the target, which follows redirects only up to the max redirects of the target.
+// The shared transport of the host policy is used unless the target pins a certificate, trusts its own certificate authorities
+// or limits the phases of the call, those targets share a transport per distinct configuration.
+func httpClient(target Target) (*http.Client, error) {
+	client := &http.Client{
+		Transport:     baseTransport(),
+		CheckRedirect: checkRedirect(target.GetMaxRedirects()),
+	}
+	key := newTransportKey(target)
+	if key.isZero() {
+		return client, nil
+	}
+	transport, err := transports.get(key)
+	if err != nil {
+		return nil, err
+	}
+	client.Transport = transport
+	return client, nil
+}
+
+// doRequest sends the request with the client of the target
+func doRequest(target Target, req *http.Request) (*http.Response, error) {
+	// the policy could have been tightened since the target was saved
+	if err := hostPolicy.checkHost(req.URL.Hostname()); err != nil {
+		return nil, err
+	}
+	client, err := httpClient(target)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if errors.Is(err, errCertificateFingerprintMismatch) {
+		return nil, zerrors.ThrowPermissionDenied(err, "EXEC-Tq7mWb", "Errors.Execution.CertificateFingerprintMismatch")
+	}
+	if errors.Is(err, errHostDenied) {
+		return nil, zerrors.ThrowPermissionDenied(err, "EXEC-Jc8rNw", "Errors.Target.HostDenied")
+	}
+	if errors.Is(err, errTooManyRedirects) {
+		return nil, zerrors.ThrowUnknown(err, "EXEC-Rd2rTm", "Errors.Execution.TooManyRedirects")
+	}
+	if err != nil {
+		return nil, phaseTimeoutError(err)
+	}
+	return resp, nil
+}
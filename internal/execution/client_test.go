@@ -0,0 +1,51 @@
+package execution
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+)
+
+func Test_httpClient_transports(t *testing.T) {
+	setHostPolicy(t, nil)
+	fingerprint := "d41d8cd98f00b204e9800998ecf8427ed41d8cd98f00b204e9800998ecf8427e"
+	timeouts := domain.TargetPhaseTimeouts{Dial: time.Second}
+
+	transport := func(target *mockTarget) any {
+		client, err := httpClient(target)
+		require.NoError(t, err)
+		return client.Transport
+	}
+
+	// targets without own configuration share the base transport
+	assert.Same(t, baseTransport(), transport(&mockTarget{Timeout: time.Minute}))
+
+	pinned := transport(&mockTarget{Timeout: time.Minute, CertificateFingerprint: fingerprint})
+	assert.Same(t, pinned, transport(&mockTarget{Timeout: 2 * time.Minute, CertificateFingerprint: fingerprint}), "same configuration must reuse the transport")
+	assert.NotSame(t, pinned, transport(&mockTarget{Timeout: time.Minute, CertificateFingerprint: "00" + fingerprint[2:]}))
+
+	limited := transport(&mockTarget{Timeout: time.Minute, PhaseTimeouts: timeouts})
+	assert.NotSame(t, pinned, limited)
+	assert.Same(t, limited, transport(&mockTarget{Timeout: time.Minute, PhaseTimeouts: timeouts}))
+	// the overall timeout limits the unset phases
+	assert.NotSame(t, limited, transport(&mockTarget{Timeout: 2 * time.Minute, PhaseTimeouts: timeouts}))
+
+	// the transports are rebuilt on top of a changed host policy
+	setHostPolicy(t, &HostPolicy{DenyInternalIPs: true})
+	assert.NotSame(t, pinned, transport(&mockTarget{Timeout: time.Minute, CertificateFingerprint: fingerprint}))
+}
+
+func Test_transportCache_limit(t *testing.T) {
+	cache := &transportCache{transports: make(map[transportKey]*http.Transport)}
+	for i := 0; i < maxCachedTransports+10; i++ {
+		_, err := cache.get(transportKey{fingerprint: strconv.Itoa(i)})
+		require.NoError(t, err)
+	}
+	assert.Len(t, cache.transports, maxCachedTransports)
+}
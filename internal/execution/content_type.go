@@ -0,0 +1,37 @@
+package execution
+
+import (
+	"mime"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// ValidateResponseContentType checks that the expected response content type of a target is a valid media type.
+// An empty content type means no expectation.
+func ValidateResponseContentType(contentType string) error {
+	if contentType == "" {
+		return nil
+	}
+	if _, _, err := mime.ParseMediaType(contentType); err != nil {
+		return zerrors.ThrowInvalidArgument(err, "EXEC-Qz7nVc", "Errors.Target.InvalidResponseContentType")
+	}
+	return nil
+}
+
+// MatchesResponseContentType reports if the content type of a response matches the expected one.
+// Only the media types are compared, parameters like the charset are ignored.
+// An empty expectation matches every content type.
+func MatchesResponseContentType(expected, actual string) bool {
+	if expected == "" {
+		return true
+	}
+	expectedType, _, err := mime.ParseMediaType(expected)
+	if err != nil {
+		return false
+	}
+	actualType, _, err := mime.ParseMediaType(actual)
+	if err != nil {
+		return false
+	}
+	return expectedType == actualType
+}
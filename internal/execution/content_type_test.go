@@ -0,0 +1,139 @@
+package execution
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestValidateResponseContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		wantErr     bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:        "media type",
+			contentType: "application/json",
+		},
+		{
+			name:        "with parameters",
+			contentType: "application/json; charset=utf-8",
+		},
+		{
+			name:        "invalid",
+			contentType: "application/json;;",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateResponseContentType(tt.contentType)
+			if tt.wantErr {
+				assert.True(t, zerrors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestMatchesResponseContentType(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		actual   string
+		want     bool
+	}{
+		{
+			name:   "no expectation",
+			actual: "text/html",
+			want:   true,
+		},
+		{
+			name:     "equal",
+			expected: "application/json",
+			actual:   "application/json",
+			want:     true,
+		},
+		{
+			name:     "parameters and case ignored",
+			expected: "application/json",
+			actual:   "Application/JSON; charset=utf-8",
+			want:     true,
+		},
+		{
+			name:     "different",
+			expected: "application/json",
+			actual:   "text/html",
+			want:     false,
+		},
+		{
+			name:     "missing",
+			expected: "application/json",
+			actual:   "",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchesResponseContentType(tt.expected, tt.actual))
+		})
+	}
+}
+
+func TestDispatchAsyncTargets_responseContentType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		contentType  string
+		wantMismatch bool
+	}{
+		{
+			name:        "no expectation",
+			contentType: "",
+		},
+		{
+			name:        "matching",
+			contentType: "text/html",
+		},
+		{
+			name:         "mismatching",
+			contentType:  "application/json",
+			wantMismatch: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := DispatchAsyncTargets(context.Background(), []Target{
+				&mockTarget{
+					TargetID:            "target",
+					TargetType:          domain.TargetTypeAsync,
+					Endpoint:            server.URL,
+					Timeout:             time.Second,
+					ResponseContentType: tt.contentType,
+				},
+			}, newMockContextInfoRequest("content"))
+			require.Len(t, results, 1)
+			// the expectation is only used for diagnostics and does not fail the call
+			assert.NoError(t, results[0].Err)
+			assert.Equal(t, "text/html; charset=utf-8", results[0].ContentType)
+			assert.Equal(t, tt.wantMismatch, results[0].ContentTypeMismatch)
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package execution
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+)
+
+const (
+	// DefaultAsyncPoolSize is the amount of async targets called concurrently if the instance does not define a pool size
+	DefaultAsyncPoolSize = 10
+	// MaxAsyncPoolSize is the maximum amount of async targets an instance can call concurrently
+	MaxAsyncPoolSize = 100
+	// settingsTTL is the time the execution settings of an instance are applied to its dispatcher before they are queried again
+	settingsTTL = time.Minute
+)
+
+type asyncPoolSizeKey struct{}
+
+// WithAsyncPoolSize sets the amount of async targets of the instance called concurrently,
+// the default pool size is used if size is 0
+func WithAsyncPoolSize(ctx context.Context, size int) context.Context {
+	return context.WithValue(ctx, asyncPoolSizeKey{}, size)
+}
+
+func asyncPoolSize(ctx context.Context) int {
+	if size, ok := ctx.Value(asyncPoolSizeKey{}).(int); ok && size > 0 {
+		return size
+	}
+	return DefaultAsyncPoolSize
+}
+
+// SettingsQueries provides the execution settings of the instances
+type SettingsQueries interface {
+	// WithExecutionSettings returns the context with the execution settings of the instance in the context
+	WithExecutionSettings(ctx context.Context) (context.Context, error)
+}
+
+// dispatchers are the dispatchers of the async targets of the instances
+var dispatchers = newInstanceDispatchers(nil)
+
+// instanceDispatchers holds a dispatcher per instance, so that all calls of async targets of an instance share its pool.
+// The dispatcher of an instance is created on its first async call,
+// its execution settings are cached for the [settingsTTL] instead of being queried on every call.
+type instanceDispatchers struct {
+	queries SettingsQueries
+	now     func() time.Time
+
+	mu          sync.Mutex
+	dispatchers map[string]*instanceDispatcher
+}
+
+type instanceDispatcher struct {
+	*asyncDispatcher
+	configuredAt time.Time
+}
+
+// newInstanceDispatchers returns the dispatchers configured by the execution settings of the queries,
+// the settings of the context of the first call are used if queries is nil
+func newInstanceDispatchers(queries SettingsQueries) *instanceDispatchers {
+	return &instanceDispatchers{
+		queries:     queries,
+		now:         time.Now,
+		dispatchers: make(map[string]*instanceDispatcher),
+	}
+}
+
+// get returns the dispatcher of the instance of the context,
+// the settings are applied again if they are older than the [settingsTTL]
+func (d *instanceDispatchers) get(ctx context.Context) *asyncDispatcher {
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	dispatcher, ok := d.dispatchers[instanceID]
+	if ok && d.now().Sub(dispatcher.configuredAt) < settingsTTL {
+		return dispatcher.asyncDispatcher
+	}
+	settingsCtx := d.settings(ctx)
+	if !ok {
+		dispatcher = &instanceDispatcher{
+			asyncDispatcher: newAsyncDispatcher(settingsCtx, func(result *TargetExecutionResult) {
+				logAsyncResult(result)
+				recordTargetExecution(authz.WithInstanceID(context.Background(), instanceID), result)
+			}),
+		}
+		d.dispatchers[instanceID] = dispatcher
+	} else {
+		dispatcher.configure(settingsCtx)
+	}
+	dispatcher.configuredAt = d.now()
+	return dispatcher.asyncDispatcher
+}
+
+// settings returns the context with the execution settings of its instance,
+// the context is returned unchanged if the settings cannot be queried, so that the defaults are used
+func (d *instanceDispatchers) settings(ctx context.Context) context.Context {
+	if d.queries == nil {
+		return ctx
+	}
+	settingsCtx, err := d.queries.WithExecutionSettings(ctx)
+	if err != nil {
+		logging.WithError(err).Info("unable to query execution settings, the defaults are used")
+		return ctx
+	}
+	return settingsCtx
+}
+
+// DispatchAsyncTargets calls the targets concurrently, bounded by the pool size of the instance (see [WithAsyncPoolSize]).
+// Failing targets do not abort the others, the outcome of each call is part of the returned results
+// which are in the order of completion.
+func DispatchAsyncTargets(ctx context.Context, targets []Target, info ContextInfoRequest) []*TargetExecutionResult {
+	var (
+		mu      sync.Mutex
+		results = make([]*TargetExecutionResult, 0, len(targets))
+	)
+	dispatcher := newAsyncDispatcher(ctx, func(result *TargetExecutionResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, result)
+	})
+	for _, target := range targets {
+		dispatcher.dispatch(ctx, target, info)
+	}
+	dispatcher.close()
+	return results
+}
+
+// asyncDispatcher calls targets in the background with a bounded concurrency.
+// Payloads for targets with batch delivery are buffered per target and delivered as JSON array in a single call
+// (see [asyncDispatcher.batch]), payloads for targets without batch delivery are delivered each in its own call.
+// For targets with ordered delivery, the payloads are delivered one after the other in the order they were dispatched.
+// The calls share a pool bounded by the pool size of the instance (see [WithAsyncPoolSize]).
+type asyncDispatcher struct {
+	clock clock.Clock
+	// sleep waits before the next attempt of a rate limited target
+	sleep    func(context.Context, time.Duration) error
+	onResult func(*TargetExecutionResult)
+
+	poolMu sync.Mutex
+	pool   chan struct{}
+	// queue bounds the payloads queued for paused targets
+	queue targetQueueConfig
+
+	mu      sync.Mutex
+	batches map[string]*payloadBatch
+	wg      sync.WaitGroup
+
+	// ordered is the last call per target with ordered delivery, it is closed once the call finished
+	orderedMu sync.Mutex
+	ordered   map[string]chan struct{}
+}
+
+// asyncPayload is a payload dispatched to a target
+type asyncPayload struct {
+	// ctx are the values of the dispatching request, the call is not canceled with it
+	ctx  context.Context
+	info any
+	body []byte
+}
+
+// newAsyncDispatcher returns a dispatcher with the pool size and the queue of the context,
+// the outcome of each call is passed to onResult
+func newAsyncDispatcher(ctx context.Context, onResult func(*TargetExecutionResult)) *asyncDispatcher {
+	return &asyncDispatcher{
+		clock:    clock.New(),
+		sleep:    sleepContext,
+		onResult: onResult,
+		pool:     make(chan struct{}, asyncPoolSize(ctx)),
+		queue:    targetQueue(ctx),
+		batches:  make(map[string]*payloadBatch),
+		ordered:  make(map[string]chan struct{}),
+	}
+}
+
+// dispatch queues the call of the target without blocking the caller,
+// the call is not canceled with the context, as it outlives the request which dispatched it
+func (d *asyncDispatcher) dispatch(ctx context.Context, target Target, info ContextInfoRequest) {
+	payload := &asyncPayload{
+		ctx:  context.WithoutCancel(ctx),
+		info: info,
+		body: info.GetHTTPRequestBody(),
+	}
+	if !target.GetBatchDelivery().IsZero() {
+		d.batch(target, payload)
+		return
+	}
+	var orderKey string
+	if target.IsOrderedDelivery() {
+		orderKey = target.GetTargetID()
+	}
+	d.send(target, []*asyncPayload{payload}, false, orderKey)
+}
+
+// send calls the target in the background with the payloads as JSON array if batched, otherwise with the single payload.
+// If orderKey is set, the call waits until the previous call with the same key finished.
+func (d *asyncDispatcher) send(target Target, payloads []*asyncPayload, batched bool, orderKey string) {
+	previous, done := d.enqueueOrdered(orderKey)
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer d.dequeueOrdered(orderKey, done)
+		if previous != nil {
+			<-previous
+		}
+		d.onResult(d.call(target, payloads, batched))
+	}()
+}
+
+// call calls the target with the payloads and waits for the outcome, the endpoint is rendered with the first payload.
+// Rate limited calls are retried after the Retry-After of the target, the slot of the pool is released while waiting,
+// so that waiting calls do not block the pool. The latency is the one of the last attempt.
+func (d *asyncDispatcher) call(target Target, payloads []*asyncPayload, batched bool) *TargetExecutionResult {
+	ctx := payloads[0].ctx
+	result := &TargetExecutionResult{
+		TargetID:  target.GetTargetID(),
+		BatchSize: len(payloads),
+	}
+	body, err := payloadsBody(payloads, batched)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	rendered, err := withRenderedEndpoint(target, payloads[0].info)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	mirrors := make(chan []*MirrorExecutionResult, 1)
+	var mirrored bool
+	resp, err := retryRateLimited(ctx, d.sleep, func() (*targetResponse, error) {
+		release := d.acquire()
+		defer release()
+		// the mirrors are called once alongside the first attempt of the primary endpoint
+		if !mirrored {
+			mirrored = true
+			go func() {
+				mirrors <- callMirrors(ctx, rendered, body)
+			}()
+		}
+		start := time.Now()
+		defer func() { result.Latency = time.Since(start) }()
+		resp, endpoint, err := sendWithFailover(ctx, rendered, body)
+		result.Endpoint = endpoint
+		return resp, err
+	})
+	result.setResponse(resp, err)
+	if resp != nil {
+		result.setContentType(target, resp.contentType)
+	}
+	if mirrored {
+		result.Mirrors = <-mirrors
+	}
+	return result
+}
+
+// acquire takes a slot of the pool and returns the function releasing it
+func (d *asyncDispatcher) acquire() (release func()) {
+	d.poolMu.Lock()
+	pool := d.pool
+	d.poolMu.Unlock()
+	pool <- struct{}{}
+	return func() { <-pool }
+}
+
+// configure applies the pool size and the queue of paused targets of the context (see [WithAsyncPoolSize] and [WithTargetQueue]),
+// calls holding a slot of the previous pool are not interrupted
+func (d *asyncDispatcher) configure(ctx context.Context) {
+	d.poolMu.Lock()
+	defer d.poolMu.Unlock()
+	if size := asyncPoolSize(ctx); size != cap(d.pool) {
+		d.pool = make(chan struct{}, size)
+	}
+	d.queue = targetQueue(ctx)
+}
+
+// queueConfig returns the bound of the payloads queued for paused targets
+func (d *asyncDispatcher) queueConfig() targetQueueConfig {
+	d.poolMu.Lock()
+	defer d.poolMu.Unlock()
+	return d.queue
+}
+
+// close delivers the buffered batches and waits until all calls finished
+func (d *asyncDispatcher) close() {
+	d.mu.Lock()
+	for id := range d.batches {
+		d.flush(id)
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
+
+// enqueueOrdered registers a call with the key,
+// it returns the channel closed after the previous call with the key finished, nil if there is none
+func (d *asyncDispatcher) enqueueOrdered(key string) (previous, done chan struct{}) {
+	if key == "" {
+		return nil, nil
+	}
+	d.orderedMu.Lock()
+	defer d.orderedMu.Unlock()
+	previous = d.ordered[key]
+	done = make(chan struct{})
+	d.ordered[key] = done
+	return previous, done
+}
+
+// dequeueOrdered releases the next call with the key
+func (d *asyncDispatcher) dequeueOrdered(key string, done chan struct{}) {
+	if done == nil {
+		return
+	}
+	close(done)
+	d.orderedMu.Lock()
+	defer d.orderedMu.Unlock()
+	if d.ordered[key] == done {
+		delete(d.ordered, key)
+	}
+}
+
+func logAsyncResult(result *TargetExecutionResult) {
+	logging.WithFields("target", result.TargetID, "status", result.StatusCode, "batch", result.BatchSize).OnError(result.Err).Info("async target failed")
+	logMirrorResults(result.TargetID, result.Mirrors)
+}
@@ -0,0 +1,164 @@
+package execution
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+)
+
+func TestDispatchAsyncTargets(t *testing.T) {
+	tests := []struct {
+		name          string
+		poolSize      int
+		targets       int
+		wantMaxActive int32
+	}{
+		{
+			name:          "bounded by pool size",
+			poolSize:      3,
+			targets:       9,
+			wantMaxActive: 3,
+		},
+		{
+			name:          "less targets than pool size",
+			poolSize:      5,
+			targets:       2,
+			wantMaxActive: 2,
+		},
+		{
+			name:          "default pool size",
+			targets:       DefaultAsyncPoolSize + 2,
+			wantMaxActive: DefaultAsyncPoolSize,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var active, maxActive atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				current := active.Add(1)
+				defer active.Add(-1)
+				for {
+					highest := maxActive.Load()
+					if current <= highest || maxActive.CompareAndSwap(highest, current) {
+						break
+					}
+				}
+				time.Sleep(50 * time.Millisecond)
+			}))
+			defer server.Close()
+
+			targets := make([]Target, tt.targets)
+			for i := range targets {
+				targets[i] = &mockTarget{
+					TargetType: domain.TargetTypeAsync,
+					Endpoint:   server.URL,
+					Timeout:    time.Minute,
+				}
+			}
+			ctx := context.Background()
+			if tt.poolSize > 0 {
+				ctx = WithAsyncPoolSize(ctx, tt.poolSize)
+			}
+
+			results := DispatchAsyncTargets(ctx, targets, newMockContextInfoRequest("content"))
+			assert.Len(t, results, tt.targets)
+			assert.Equal(t, tt.wantMaxActive, maxActive.Load())
+			for _, result := range results {
+				assert.NoError(t, result.Err)
+				assert.Equal(t, http.StatusOK, result.StatusCode)
+			}
+		})
+	}
+}
+
+func TestDispatchAsyncTargets_errorsDoNotAbort(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	targets := []Target{
+		&mockTarget{TargetID: "failing", TargetType: domain.TargetTypeAsync, Endpoint: failing.URL, Timeout: time.Minute},
+		&mockTarget{TargetID: "ok1", TargetType: domain.TargetTypeAsync, Endpoint: server.URL, Timeout: time.Minute},
+		&mockTarget{TargetID: "ok2", TargetType: domain.TargetTypeAsync, Endpoint: server.URL, Timeout: time.Minute},
+	}
+	results := DispatchAsyncTargets(WithAsyncPoolSize(context.Background(), 1), targets, newMockContextInfoRequest("content"))
+
+	assert.Len(t, results, len(targets))
+	for _, result := range results {
+		if result.TargetID == "failing" {
+			assert.Error(t, result.Err)
+			assert.Equal(t, http.StatusInternalServerError, result.StatusCode)
+			continue
+		}
+		assert.NoError(t, result.Err)
+	}
+}
+
+func TestCallTarget_async(t *testing.T) {
+	received := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+	}))
+	defer server.Close()
+
+	// the call outlives the request of the caller, so it is not canceled with its context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resp, err := CallTarget(ctx, &mockTarget{
+		TargetID:   "target",
+		TargetType: domain.TargetTypeAsync,
+		Endpoint:   server.URL,
+		Timeout:    time.Minute,
+	}, newMockContextInfoRequest("content"))
+	assert.NoError(t, err)
+	assert.Nil(t, resp)
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("async target not called")
+	}
+}
+
+type mockSettingsQueries struct {
+	poolSize int
+	calls    int
+}
+
+func (q *mockSettingsQueries) WithExecutionSettings(ctx context.Context) (context.Context, error) {
+	q.calls++
+	return WithAsyncPoolSize(ctx, q.poolSize), nil
+}
+
+func TestInstanceDispatchers_get(t *testing.T) {
+	queries := &mockSettingsQueries{poolSize: 2}
+	dispatchers := newInstanceDispatchers(queries)
+	now := time.Now()
+	dispatchers.now = func() time.Time { return now }
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+
+	// the calls of an instance share its dispatcher, the settings are not queried per call
+	dispatcher := dispatchers.get(ctx)
+	assert.Same(t, dispatcher, dispatchers.get(ctx))
+	assert.Equal(t, 2, cap(dispatcher.pool))
+	assert.Equal(t, 1, queries.calls)
+
+	assert.NotSame(t, dispatcher, dispatchers.get(authz.WithInstanceID(context.Background(), "other")))
+
+	// changed settings are applied to the dispatcher once they are outdated
+	queries.poolSize = 5
+	now = now.Add(settingsTTL)
+	assert.Same(t, dispatcher, dispatchers.get(ctx))
+	assert.Equal(t, 5, cap(dispatcher.pool))
+}
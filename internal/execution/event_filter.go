@@ -0,0 +1,50 @@
+package execution
+
+import (
+	"path"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// ContextInfoEvent is implemented by context infos which are triggered by an event,
+// targets with an event type filter are only called for matching event types
+type ContextInfoEvent interface {
+	GetEventType() string
+}
+
+// ValidateEventTypeFilter checks that every entry of the filter is a valid pattern
+func ValidateEventTypeFilter(filter []string) error {
+	for _, pattern := range filter {
+		if pattern == "" {
+			return zerrors.ThrowInvalidArgument(nil, "EXEC-p4Hn0e", "Errors.Target.InvalidEventTypeFilter")
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return zerrors.ThrowInvalidArgument(err, "EXEC-Vb7m2K", "Errors.Target.InvalidEventTypeFilter")
+		}
+	}
+	return nil
+}
+
+// MatchesEventType checks if the event type is matched by the filter,
+// an empty filter matches all event types.
+// The entries of the filter are either exact event types or patterns with wildcards, e.g. user.*
+func MatchesEventType(filter []string, eventType string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, pattern := range filter {
+		if matched, err := path.Match(pattern, eventType); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldCall checks if the target has to be called for the context info
+func shouldCall(target Target, info any) bool {
+	event, ok := info.(ContextInfoEvent)
+	if !ok {
+		return true
+	}
+	return MatchesEventType(target.GetEventTypeFilter(), event.GetEventType())
+}
@@ -0,0 +1,165 @@
+package execution
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+)
+
+func TestValidateEventTypeFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  []string
+		wantErr bool
+	}{
+		{
+			name: "no filter",
+		},
+		{
+			name:   "exact and wildcard",
+			filter: []string{"user.human.added", "session.*"},
+		},
+		{
+			name:    "empty entry",
+			filter:  []string{"user.human.added", ""},
+			wantErr: true,
+		},
+		{
+			name:    "malformed pattern",
+			filter:  []string{"user.["},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEventTypeFilter(tt.filter)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestMatchesEventType(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    []string
+		eventType string
+		want      bool
+	}{
+		{
+			name:      "empty filter, all events",
+			eventType: "user.human.added",
+			want:      true,
+		},
+		{
+			name:      "exact match",
+			filter:    []string{"session.added", "user.human.added"},
+			eventType: "user.human.added",
+			want:      true,
+		},
+		{
+			name:      "wildcard match",
+			filter:    []string{"user.*"},
+			eventType: "user.human.added",
+			want:      true,
+		},
+		{
+			name:      "no match",
+			filter:    []string{"user.*", "session.added"},
+			eventType: "org.added",
+			want:      false,
+		},
+		{
+			name:      "prefix without wildcard, no match",
+			filter:    []string{"user"},
+			eventType: "user.human.added",
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MatchesEventType(tt.filter, tt.eventType))
+		})
+	}
+}
+
+var _ ContextInfoEvent = (*mockContextInfoEvent)(nil)
+
+type mockContextInfoEvent struct {
+	eventType string
+}
+
+func (c *mockContextInfoEvent) GetHTTPRequestBody() []byte {
+	return []byte(`{}`)
+}
+
+func (c *mockContextInfoEvent) GetContent() interface{} {
+	return nil
+}
+
+func (c *mockContextInfoEvent) SetHTTPResponseBody([]byte) error {
+	return nil
+}
+
+func (c *mockContextInfoEvent) GetEventType() string {
+	return c.eventType
+}
+
+func TestCallTargets_eventTypeFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    []string
+		eventType string
+		wantCalls int32
+	}{
+		{
+			name:      "exact match, called",
+			filter:    []string{"user.human.added"},
+			eventType: "user.human.added",
+			wantCalls: 1,
+		},
+		{
+			name:      "wildcard match, called",
+			filter:    []string{"user.*"},
+			eventType: "user.human.added",
+			wantCalls: 1,
+		},
+		{
+			name:      "no match, skipped",
+			filter:    []string{"user.*"},
+			eventType: "org.added",
+			wantCalls: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+			}))
+			defer server.Close()
+
+			_, err := CallTargets(context.Background(), []Target{
+				&mockTarget{
+					TargetType:      domain.TargetTypeWebhook,
+					Endpoint:        server.URL,
+					Timeout:         time.Minute,
+					EventTypeFilter: tt.filter,
+				},
+			}, &mockContextInfoEvent{eventType: tt.eventType})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCalls, calls.Load())
+		})
+	}
+}
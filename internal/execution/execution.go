@@ -3,7 +3,7 @@ package execution
 import (
 	"bytes"
 	"context"
-	"io"
+	"encoding/json"
 	"net/http"
 	"time"
 
@@ -26,6 +26,46 @@ type Target interface {
 	GetEndpoint() string
 	GetTargetType() domain.TargetType
 	GetTimeout() time.Duration
+	GetSuccessCriteria() *domain.TargetSuccessCriteria
+	GetEventTypeFilter() []string
+	GetCertificateFingerprint() string
+	// GetCABundle returns the PEM encoded certificate authorities the endpoint is verified against,
+	// empty if the certificate authorities of the system are used
+	GetCABundle() string
+	GetResponseContentType() string
+	GetPhaseTimeouts() domain.TargetPhaseTimeouts
+	// GetPayloadFormat returns the format the payload is serialized in, JSON by default
+	GetPayloadFormat() domain.TargetPayloadFormat
+	// GetOAuth2Credentials returns the decrypted client credentials, nil if the calls are not authorized
+	GetOAuth2Credentials() *domain.TargetOAuth2Credentials
+	// GetBasicAuthCredentials returns the decrypted basic authentication, nil if the calls are not authorized
+	GetBasicAuthCredentials() *domain.TargetBasicAuthCredentials
+	// GetMirrorEndpoints returns the endpoints the payload is additionally delivered to best-effort
+	GetMirrorEndpoints() []string
+	// GetResponseSizeLimit returns the limit of the size of the response bodies, zero if the size is not limited
+	GetResponseSizeLimit() domain.TargetResponseSizeLimit
+	// GetBatchDelivery returns how the payloads of an async target are coalesced into batches, zero if every payload is delivered on its own
+	GetBatchDelivery() domain.TargetBatchDelivery
+	// IsOrderedDelivery returns true if the payloads of an async target are delivered one after the other in the order they were dispatched
+	IsOrderedDelivery() bool
+	// IsIncludePayload returns true if the payload of the events is sent, otherwise only their metadata
+	IsIncludePayload() bool
+	// GetJWTDelivery returns how the payload is signed as JWT with the signing key, nil if the payload is sent in its payload format
+	GetJWTDelivery() *domain.TargetJWTDelivery
+	// GetFailoverEndpoints returns the endpoints called in order if the endpoint fails, until one of them succeeds
+	GetFailoverEndpoints() []string
+	// GetMaxRedirects returns the amount of redirects followed, the 3xx response is returned if zero
+	GetMaxRedirects() int
+	// GetRequestIDHeader returns the name of the header the ID of the originating request is sent in, empty if it is not sent
+	GetRequestIDHeader() string
+	// GetSigningKey returns the decrypted primary key the requests are signed with, empty if they are not signed
+	GetSigningKey() string
+	// GetSecondarySigningKey returns the decrypted successor of the primary key and the end of its grace window,
+	// empty if the keys are not rotated
+	GetSecondarySigningKey() (string, time.Time)
+	// IsPaused returns true if the target is paused, it is not called until it is resumed.
+	// The payloads of paused async targets are queued, calls of other paused targets fail.
+	IsPaused() bool
 }
 
 // CallTargets call a list of targets in order with handling of error and responses
@@ -38,6 +78,10 @@ func CallTargets(
 	defer span.EndWithError(err)
 
 	for _, target := range targets {
+		// skip targets not interested in the triggering event
+		if !shouldCall(target, info) {
+			continue
+		}
 		// call the type of target
 		resp, err := CallTarget(ctx, target, info)
 		// handle error if interrupt is set
@@ -67,56 +111,199 @@ func CallTarget(
 	ctx, span := tracing.NewSpan(ctx)
 	defer span.EndWithError(err)
 
+	// async targets are called in the background through the pool of the instance, the results are logged and recorded.
+	// The payloads of paused async targets are queued until the target is resumed.
+	if target.GetTargetType() == domain.TargetTypeAsync {
+		dispatcher := dispatchers.get(ctx)
+		if target.IsPaused() {
+			dispatcher.queuePaused(ctx, target, info)
+			return nil, nil
+		}
+		dispatcher.dispatch(ctx, target, info)
+		return nil, nil
+	}
+	// the caller waits for the response of other targets, so they can not be queued while paused
+	if target.IsPaused() {
+		return nil, zerrors.ThrowUnavailable(nil, "EXEC-Qu4Psd", "Errors.Execution.TargetPaused")
+	}
+
+	target, err = withRenderedEndpoint(target, info)
+	if err != nil {
+		return nil, err
+	}
+	// mirrors never influence the outcome of the call, only the primary endpoint does
+	mirror(ctx, target, info.GetHTTPRequestBody())
+
 	switch target.GetTargetType() {
 	// get request, ignore response and return request and error for handling in list of targets
 	case domain.TargetTypeWebhook:
-		return nil, webhook(ctx, target.GetEndpoint(), target.GetTimeout(), info.GetHTTPRequestBody())
+		return nil, webhook(ctx, target, info.GetHTTPRequestBody())
 	// get request, return response and error
 	case domain.TargetTypeCall:
-		return call(ctx, target.GetEndpoint(), target.GetTimeout(), info.GetHTTPRequestBody())
-	case domain.TargetTypeAsync:
-		go func(target Target, info ContextInfoRequest) {
-			if _, err := call(ctx, target.GetEndpoint(), target.GetTimeout(), info.GetHTTPRequestBody()); err != nil {
-				logging.WithFields("target", target.GetTargetID()).OnError(err).Info(err)
-			}
-		}(target, info)
-		return nil, nil
+		return call(ctx, target, info.GetHTTPRequestBody())
 	default:
 		return nil, zerrors.ThrowInternal(nil, "EXEC-auqnansr2m", "Errors.Execution.Unknown")
 	}
 }
 
 // webhook call a webhook, ignore the response but return the errror
-func webhook(ctx context.Context, url string, timeout time.Duration, body []byte) error {
-	_, err := call(ctx, url, timeout, body)
+func webhook(ctx context.Context, target Target, body []byte) error {
+	_, err := call(ctx, target, body)
 	return err
 }
 
-// call function to do a post HTTP request to a desired url with timeout
-func call(ctx context.Context, url string, timeout time.Duration, body []byte) (_ []byte, err error) {
-	ctx, cancel := context.WithTimeout(ctx, timeout)
+// call function to do a post HTTP request to the endpoint of the target with its timeout,
+// the failover endpoints of the target are called if the endpoint fails.
+// The outcome is recorded in the background, so that it does not delay the caller.
+func call(ctx context.Context, target Target, body []byte) ([]byte, error) {
+	start := time.Now()
+	resp, endpoint, err := sendWithFailover(ctx, target, body)
+	result := &TargetExecutionResult{
+		TargetID: target.GetTargetID(),
+		Endpoint: endpoint,
+		Latency:  time.Since(start),
+	}
+	result.setResponse(resp, err)
+	go recordTargetExecution(context.WithoutCancel(ctx), result)
+	if err != nil {
+		return nil, err
+	}
+	return resp.body, nil
+}
+
+// targetResponse is the outcome of a request to a target
+type targetResponse struct {
+	statusCode  int
+	contentType string
+	body        []byte
+	// succeeded is set if the response matched the success criteria of the target
+	succeeded bool
+}
+
+// send does a post HTTP request to the endpoint of the target with its timeout.
+// The response is returned as soon as the target responded, even if the call is not successful.
+func send(ctx context.Context, target Target, body []byte) (_ *targetResponse, err error) {
+	ctx, cancel := context.WithTimeout(ctx, target.GetTimeout())
 	ctx, span := tracing.NewSpan(ctx)
 	defer func() {
 		cancel()
 		span.EndWithError(err)
 	}()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	req, err := newRequest(ctx, target, body)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
+	if err := authorize(ctx, target, req); err != nil {
+		return nil, err
+	}
 
-	client := http.DefaultClient
-	resp, err := client.Do(req)
+	started := time.Now()
+	var statusCode int
+	defer func() {
+		emitTargetExecution(ctx, target, req.ContentLength, statusCode, started, err)
+	}()
+	resp, err := doRequest(target, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	statusCode = resp.StatusCode
 
-	// Check for success between 200 and 299, redirect 300 to 399 is handled by the client, return error with statusCode >= 400
-	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
-		return io.ReadAll(resp.Body)
+	response := &targetResponse{
+		statusCode:  resp.StatusCode,
+		contentType: resp.Header.Get("Content-Type"),
+	}
+	// the expected content type is only used for diagnostics and does not influence the outcome of the call
+	if !MatchesResponseContentType(target.GetResponseContentType(), response.contentType) {
+		logging.WithFields("target", target.GetTargetID(), "expected", target.GetResponseContentType(), "actual", response.contentType).Warn("unexpected response content type of target")
+	}
+
+	respBody, err := readResponseBody(target, resp.Body)
+	if err != nil {
+		return response, err
+	}
+	// Check the response against the success criteria of the target, by default a status between 200 and 299,
+	// redirects 300 to 399 are only followed by the client up to the max redirects of the target
+	if response.succeeded = isSuccess(target.GetSuccessCriteria(), resp.StatusCode, respBody); response.succeeded {
+		response.body = respBody
+		return response, nil
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return response, throwRateLimited(resp.Header)
+	}
+	return response, zerrors.ThrowUnknown(nil, "EXEC-dra6yamk98", "Errors.Execution.Failed")
+}
+
+// newRequest creates the POST HTTP request sent to a target,
+// the JSON body is serialized in the payload format of the target or signed as JWT if the target has a JWT delivery
+func newRequest(ctx context.Context, target Target, body []byte) (*http.Request, error) {
+	payload, contentType, err := requestPayload(target, body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.GetEndpoint(), bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	setRequestIDHeader(ctx, target, req)
+	setSignatureHeader(target, req, payload, time.Now())
+	return req, nil
+}
+
+// requestPayload returns the body of the request to the target and its content type,
+// targets which exclude the payload only receive the metadata of the events
+func requestPayload(target Target, body []byte) (_ []byte, _ string, err error) {
+	if !target.IsIncludePayload() {
+		if body, err = withoutEventPayload(body); err != nil {
+			return nil, "", err
+		}
+	}
+	if delivery := target.GetJWTDelivery(); !delivery.IsZero() {
+		token, err := signPayload(target.GetTargetID(), delivery, target.GetSigningKey(), body, time.Now())
+		return token, jwtContentType, err
+	}
+	format := target.GetPayloadFormat()
+	payload, err := encodePayload(format, body)
+	return payload, format.ContentType(), err
+}
+
+// eventPayloadField is the field of the events which holds their payload
+const eventPayloadField = "eventPayload"
+
+// withoutEventPayload removes the payload from the event or from each event of a batch in the body,
+// bodies which contain no events are returned unchanged
+func withoutEventPayload(body []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return body, nil
+	}
+	switch trimmed[0] {
+	case '{':
+		event := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(trimmed, &event); err != nil {
+			return nil, zerrors.ThrowInternal(err, "EXEC-Ip4xLd", "Errors.Internal")
+		}
+		if _, ok := event[eventPayloadField]; !ok {
+			return body, nil
+		}
+		delete(event, eventPayloadField)
+		return json.Marshal(event)
+	case '[':
+		var events []json.RawMessage
+		if err := json.Unmarshal(trimmed, &events); err != nil {
+			return nil, zerrors.ThrowInternal(err, "EXEC-Ip5yMe", "Errors.Internal")
+		}
+		for i, event := range events {
+			stripped, err := withoutEventPayload(event)
+			if err != nil {
+				return nil, err
+			}
+			events[i] = stripped
+		}
+		return json.Marshal(events)
+	default:
+		return body, nil
 	}
-	return nil, zerrors.ThrowUnknown(nil, "EXEC-dra6yamk98", "Errors.Execution.Failed")
 }
@@ -18,13 +18,35 @@ import (
 var _ Target = &mockTarget{}
 
 type mockTarget struct {
-	InstanceID       string
-	ExecutionID      string
-	TargetID         string
-	TargetType       domain.TargetType
-	Endpoint         string
-	Timeout          time.Duration
-	InterruptOnError bool
+	InstanceID             string
+	ExecutionID            string
+	TargetID               string
+	TargetType             domain.TargetType
+	Endpoint               string
+	Timeout                time.Duration
+	InterruptOnError       bool
+	SuccessCriteria        *domain.TargetSuccessCriteria
+	EventTypeFilter        []string
+	CertificateFingerprint string
+	CABundle               string
+	ResponseContentType    string
+	PhaseTimeouts          domain.TargetPhaseTimeouts
+	PayloadFormat          domain.TargetPayloadFormat
+	OAuth2Credentials      *domain.TargetOAuth2Credentials
+	BasicAuthCredentials   *domain.TargetBasicAuthCredentials
+	MirrorEndpoints        []string
+	ResponseSizeLimit      domain.TargetResponseSizeLimit
+	BatchDelivery          domain.TargetBatchDelivery
+	OrderedDelivery        bool
+	ExcludePayload         bool
+	JWTDelivery            *domain.TargetJWTDelivery
+	FailoverEndpoints      []string
+	MaxRedirects           int
+	RequestIDHeader        string
+	SigningKey             string
+	SecondarySigningKey    string
+	SecondaryValidUntil    time.Time
+	Paused                 bool
 }
 
 func (e *mockTarget) GetTargetID() string {
@@ -42,6 +64,69 @@ func (e *mockTarget) GetTargetType() domain.TargetType {
 func (e *mockTarget) GetTimeout() time.Duration {
 	return e.Timeout
 }
+func (e *mockTarget) GetSuccessCriteria() *domain.TargetSuccessCriteria {
+	return e.SuccessCriteria
+}
+func (e *mockTarget) GetEventTypeFilter() []string {
+	return e.EventTypeFilter
+}
+func (e *mockTarget) GetCertificateFingerprint() string {
+	return e.CertificateFingerprint
+}
+func (e *mockTarget) GetCABundle() string {
+	return e.CABundle
+}
+func (e *mockTarget) GetResponseContentType() string {
+	return e.ResponseContentType
+}
+func (e *mockTarget) GetPhaseTimeouts() domain.TargetPhaseTimeouts {
+	return e.PhaseTimeouts
+}
+func (e *mockTarget) GetPayloadFormat() domain.TargetPayloadFormat {
+	return e.PayloadFormat
+}
+func (e *mockTarget) GetOAuth2Credentials() *domain.TargetOAuth2Credentials {
+	return e.OAuth2Credentials
+}
+func (e *mockTarget) GetBasicAuthCredentials() *domain.TargetBasicAuthCredentials {
+	return e.BasicAuthCredentials
+}
+func (e *mockTarget) GetMirrorEndpoints() []string {
+	return e.MirrorEndpoints
+}
+func (e *mockTarget) GetResponseSizeLimit() domain.TargetResponseSizeLimit {
+	return e.ResponseSizeLimit
+}
+func (e *mockTarget) GetBatchDelivery() domain.TargetBatchDelivery {
+	return e.BatchDelivery
+}
+func (e *mockTarget) IsOrderedDelivery() bool {
+	return e.OrderedDelivery
+}
+func (e *mockTarget) IsIncludePayload() bool {
+	return !e.ExcludePayload
+}
+func (e *mockTarget) GetJWTDelivery() *domain.TargetJWTDelivery {
+	return e.JWTDelivery
+}
+func (e *mockTarget) GetFailoverEndpoints() []string {
+	return e.FailoverEndpoints
+}
+func (e *mockTarget) GetMaxRedirects() int {
+	return e.MaxRedirects
+}
+func (e *mockTarget) GetRequestIDHeader() string {
+	return e.RequestIDHeader
+}
+func (e *mockTarget) GetSigningKey() string {
+	return e.SigningKey
+}
+func (e *mockTarget) GetSecondarySigningKey() (string, time.Time) {
+	return e.SecondarySigningKey, e.SecondaryValidUntil
+}
+func (e *mockTarget) IsPaused() bool {
+	return e.Paused
+}
 
 func Test_Call(t *testing.T) {
 	type args struct {
@@ -131,7 +216,7 @@ func Test_Call(t *testing.T) {
 
 func testCall(ctx context.Context, timeout time.Duration, body []byte) func(string) ([]byte, error) {
 	return func(url string) ([]byte, error) {
-		return call(ctx, url, timeout, body)
+		return call(ctx, &mockTarget{Endpoint: url, Timeout: timeout}, body)
 	}
 }
 
@@ -345,3 +430,45 @@ func Test_CallTarget(t *testing.T) {
 		})
 	}
 }
+
+func Test_requestPayload_excludePayload(t *testing.T) {
+	tests := []struct {
+		name   string
+		target *mockTarget
+		body   string
+		want   string
+	}{
+		{
+			name:   "payload included",
+			target: &mockTarget{},
+			body:   `{"eventType":"user.human.added","eventPayload":{"userName":"alice"}}`,
+			want:   `{"eventType":"user.human.added","eventPayload":{"userName":"alice"}}`,
+		},
+		{
+			name:   "event, payload excluded",
+			target: &mockTarget{ExcludePayload: true},
+			body:   `{"eventType":"user.human.added","eventPayload":{"userName":"alice"}}`,
+			want:   `{"eventType":"user.human.added"}`,
+		},
+		{
+			name:   "batch, payload excluded",
+			target: &mockTarget{ExcludePayload: true},
+			body:   `[{"eventType":"user.human.added","eventPayload":{"userName":"alice"}},{"eventType":"session.terminated"}]`,
+			want:   `[{"eventType":"user.human.added"},{"eventType":"session.terminated"}]`,
+		},
+		{
+			name:   "request, payload excluded",
+			target: &mockTarget{ExcludePayload: true},
+			body:   `{"fullMethod":"/zitadel.session.v2.SessionService/SetSession","request":{"sessionId":"session1"}}`,
+			want:   `{"fullMethod":"/zitadel.session.v2.SessionService/SetSession","request":{"sessionId":"session1"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, contentType, err := requestPayload(tt.target, []byte(tt.body))
+			require.NoError(t, err)
+			assert.Equal(t, "application/json", contentType)
+			assert.JSONEq(t, tt.want, string(payload))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package execution
+
+import (
+	"context"
+
+	"github.com/zitadel/logging"
+)
+
+// ValidateFailoverEndpoints checks that the failover endpoints of a target are HTTP URLs allowed by the host policy
+func ValidateFailoverEndpoints(endpoints []string) error {
+	return validateEndpoints(endpoints, "EXEC-Fo4vRl", "Errors.Target.InvalidFailoverEndpoint")
+}
+
+// failoverTarget is a failover endpoint of a target, it is called like the target
+type failoverTarget struct {
+	Target
+	endpoint string
+}
+
+func (t *failoverTarget) GetEndpoint() string {
+	return t.endpoint
+}
+
+// sendWithFailover sends the body to the endpoint of the target and, if the call fails,
+// to the failover endpoints of the target in their order until one of them succeeds.
+// Unlike mirrors, the body is delivered to a single endpoint, which is returned with its response.
+// If all endpoints fail, the response and the error of the last endpoint are returned.
+func sendWithFailover(ctx context.Context, target Target, body []byte) (*targetResponse, string, error) {
+	endpoint := target.GetEndpoint()
+	resp, err := send(ctx, target, body)
+	for _, failover := range target.GetFailoverEndpoints() {
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+		logging.WithFields("target", target.GetTargetID(), "endpoint", endpoint, "failover", failover).WithError(err).Info("endpoint of target failed, calling failover endpoint")
+		endpoint = failover
+		resp, err = send(ctx, &failoverTarget{Target: target, endpoint: failover}, body)
+	}
+	return resp, endpoint, err
+}
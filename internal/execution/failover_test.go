@@ -0,0 +1,118 @@
+package execution
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestValidateFailoverEndpoints(t *testing.T) {
+	assert.NoError(t, ValidateFailoverEndpoints(nil))
+	assert.NoError(t, ValidateFailoverEndpoints([]string{"https://failover.example.com/hook", "http://localhost:8080"}))
+	assert.ErrorIs(t, ValidateFailoverEndpoints([]string{"https://failover.example.com", "failover"}), zerrors.ThrowInvalidArgument(nil, "EXEC-Fo4vRl", "Errors.Target.InvalidFailoverEndpoint"))
+}
+
+func TestCallTarget_failover(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	failover := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	defer failover.Close()
+	// the remaining failover endpoints must not be called once an endpoint succeeded
+	unused := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("failover endpoint called after success")
+	}))
+	defer unused.Close()
+
+	info := newMockContextInfoRequest("content")
+	resp, err := CallTarget(context.Background(), &mockTarget{
+		TargetType:        domain.TargetTypeCall,
+		Endpoint:          failing.URL,
+		Timeout:           time.Minute,
+		InterruptOnError:  true,
+		FailoverEndpoints: []string{failing.URL, failover.URL, unused.URL},
+	}, info)
+	require.NoError(t, err)
+	assert.Equal(t, info.GetHTTPRequestBody(), resp)
+}
+
+func TestCallTargets_failoverExhausted(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	_, err := CallTargets(context.Background(), []Target{
+		&mockTarget{
+			TargetType:        domain.TargetTypeWebhook,
+			Endpoint:          failing.URL,
+			Timeout:           time.Minute,
+			InterruptOnError:  true,
+			FailoverEndpoints: []string{failing.URL},
+		},
+	}, &mockContextInfoEvent{})
+	assert.ErrorIs(t, err, zerrors.ThrowUnknown(nil, "EXEC-dra6yamk98", "Errors.Execution.Failed"))
+}
+
+func TestDispatchAsyncTargets_failover(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer primary.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	failover := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer failover.Close()
+
+	tests := []struct {
+		name           string
+		endpoint       string
+		wantEndpoint   string
+		wantStatusCode int
+	}{
+		{
+			name:           "primary succeeds",
+			endpoint:       primary.URL,
+			wantEndpoint:   primary.URL,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "primary fails, failover succeeds",
+			endpoint:       failing.URL,
+			wantEndpoint:   failover.URL,
+			wantStatusCode: http.StatusAccepted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := DispatchAsyncTargets(context.Background(), []Target{
+				&mockTarget{
+					TargetID:          "target",
+					TargetType:        domain.TargetTypeAsync,
+					Endpoint:          tt.endpoint,
+					Timeout:           time.Minute,
+					FailoverEndpoints: []string{failover.URL},
+				},
+			}, newMockContextInfoRequest("content"))
+			require.Len(t, results, 1)
+			assert.NoError(t, results[0].Err)
+			assert.Equal(t, tt.wantEndpoint, results[0].Endpoint)
+			assert.Equal(t, tt.wantStatusCode, results[0].StatusCode)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// ErrHeaderInjection is the parent of the error returned by [ValidateHeaders]
+// if a header name or value contains a line break, which would allow to inject additional headers.
+var ErrHeaderInjection = errors.New("header contains line break")
+
+// ValidateHeaders checks the custom headers of a target.
+// It must be used when the headers are stored and before they are sent.
+func ValidateHeaders(headers map[string]string) error {
+	for name, value := range headers {
+		if containsLineBreak(name) {
+			return zerrors.ThrowInvalidArgument(fmt.Errorf("%w: name %q", ErrHeaderInjection, name), "EXEC-h3CRlf", "Errors.Target.InvalidHeader")
+		}
+		if containsLineBreak(value) {
+			return zerrors.ThrowInvalidArgument(fmt.Errorf("%w: value of %q", ErrHeaderInjection, name), "EXEC-h3CRlf", "Errors.Target.InvalidHeader")
+		}
+	}
+	return nil
+}
+
+func containsLineBreak(s string) bool {
+	return strings.ContainsAny(s, "\r\n")
+}
@@ -0,0 +1,56 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		wantErr bool
+	}{
+		{
+			name: "no headers",
+		},
+		{
+			name: "clean headers",
+			headers: map[string]string{
+				"X-Custom":      "value",
+				"Authorization": "Bearer token",
+			},
+		},
+		{
+			name:    "crlf in name",
+			headers: map[string]string{"X-Custom\r\nX-Injected": "value"},
+			wantErr: true,
+		},
+		{
+			name:    "crlf in value",
+			headers: map[string]string{"X-Custom": "value\r\nX-Injected: injected"},
+			wantErr: true,
+		},
+		{
+			name:    "lf in value",
+			headers: map[string]string{"X-Custom": "value\nX-Injected: injected"},
+			wantErr: true,
+		},
+		{
+			name:    "cr in name",
+			headers: map[string]string{"X-Custom\r": "value"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHeaders(tt.headers)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrHeaderInjection)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package execution
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+var errHostDenied = errors.New("host denied")
+
+type Config struct {
+	HostPolicy HostPolicy
+	// Queue configures the delivery of the payloads queued for paused targets
+	Queue QueueConfig
+}
+
+// HostPolicy restricts the hosts targets are allowed to call.
+// Entries are domains, wildcard sub domains (e.g. *.example.com), IPs or networks in CIDR notation.
+type HostPolicy struct {
+	// AllowList allows only hosts matching an entry, all hosts are allowed if empty
+	AllowList []string
+	// DenyList denies hosts matching an entry
+	DenyList []string
+	// DenyInternalIPs denies loopback, private, link local and unspecified IPs
+	DenyInternalIPs bool
+}
+
+var (
+	// hostPolicy is nil if no host is restricted
+	hostPolicy *HostPolicy
+	// policyTransport is the transport of the calls if a host policy is set,
+	// it is built once so that its connections are reused across calls
+	policyTransport *http.Transport
+)
+
+// SetConfig sets the configuration of the executions of the system.
+// The host policy is a system setting of the operator, it applies to all instances
+// so that instance administrators cannot loosen the restrictions of the network the system runs in.
+func SetConfig(config *Config) {
+	if config == nil {
+		applyHostPolicy(nil)
+		return
+	}
+	applyHostPolicy(&config.HostPolicy)
+}
+
+// applyHostPolicy sets the policy and builds its transport, an empty policy restricts nothing and is handled as nil
+func applyHostPolicy(policy *HostPolicy) {
+	// the transports of the targets are based on the transport of the policy
+	defer transports.reset()
+	if policy.isZero() {
+		hostPolicy = nil
+		policyTransport = nil
+		return
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = policy.dialer().DialContext
+	hostPolicy = policy
+	policyTransport = transport
+}
+
+// isZero reports if the policy does not restrict any host
+func (p *HostPolicy) isZero() bool {
+	return p == nil || len(p.AllowList) == 0 && len(p.DenyList) == 0 && !p.DenyInternalIPs
+}
+
+// baseTransport returns the transport shared by the calls of targets without their own transport configuration
+func baseTransport() http.RoundTripper {
+	if policyTransport == nil {
+		return http.DefaultTransport
+	}
+	return policyTransport
+}
+
+// CheckTargetHost checks the host of the endpoint against the configured host policy
+func CheckTargetHost(endpoint string) error {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return zerrors.ThrowInvalidArgument(err, "EXEC-Wm3bZd", "Errors.Target.InvalidURL")
+	}
+	return hostPolicy.checkHost(parsed.Hostname())
+}
+
+func (p *HostPolicy) checkHost(host string) error {
+	if p == nil {
+		return nil
+	}
+	if len(p.AllowList) > 0 && !matchesHost(p.AllowList, host) {
+		return zerrors.ThrowPermissionDenied(errHostDenied, "EXEC-Ss9fQa", "Errors.Target.HostDenied")
+	}
+	if matchesHost(p.DenyList, host) {
+		return zerrors.ThrowPermissionDenied(errHostDenied, "EXEC-n6TkVe", "Errors.Target.HostDenied")
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return p.checkIP(ip)
+	}
+	return nil
+}
+
+// checkIP checks the IP against the deny rules, allow list entries are checked by host only,
+// because the resolved IP of an allowed domain is not part of the allow list
+func (p *HostPolicy) checkIP(ip net.IP) error {
+	if p.DenyInternalIPs && isInternalIP(ip) {
+		return zerrors.ThrowPermissionDenied(errHostDenied, "EXEC-Y2oLqc", "Errors.Target.HostDenied")
+	}
+	if matchesHost(p.DenyList, ip.String()) {
+		return zerrors.ThrowPermissionDenied(errHostDenied, "EXEC-rE5hXu", "Errors.Target.HostDenied")
+	}
+	return nil
+}
+
+// dialer checks the resolved IP before connecting, which prevents to bypass the policy using DNS
+func (p *HostPolicy) dialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip != nil {
+				return p.checkIP(ip)
+			}
+			return nil
+		},
+	}
+}
+
+func matchesHost(entries []string, host string) bool {
+	for _, entry := range entries {
+		if matchesHostEntry(strings.TrimSpace(entry), host) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesHostEntry(entry, host string) bool {
+	if _, network, err := net.ParseCIDR(entry); err == nil {
+		ip := net.ParseIP(host)
+		return ip != nil && network.Contains(ip)
+	}
+	if entryIP := net.ParseIP(entry); entryIP != nil {
+		return entryIP.Equal(net.ParseIP(host))
+	}
+	if suffix, ok := strings.CutPrefix(entry, "*"); ok {
+		return strings.HasSuffix(strings.ToLower(host), strings.ToLower(suffix))
+	}
+	return strings.EqualFold(entry, host)
+}
+
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified()
+}
@@ -0,0 +1,169 @@
+package execution
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func setHostPolicy(t *testing.T, policy *HostPolicy) {
+	previous := hostPolicy
+	applyHostPolicy(policy)
+	t.Cleanup(func() {
+		applyHostPolicy(previous)
+	})
+}
+
+func TestCheckTargetHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   *HostPolicy
+		endpoint string
+		wantErr  bool
+	}{
+		{
+			name:     "no policy",
+			endpoint: "http://127.0.0.1:8080/hook",
+		},
+		{
+			name: "allowed",
+			policy: &HostPolicy{
+				AllowList: []string{"*.example.com"},
+			},
+			endpoint: "https://hooks.example.com/hook",
+		},
+		{
+			name: "not in allow list",
+			policy: &HostPolicy{
+				AllowList: []string{"*.example.com"},
+			},
+			endpoint: "https://example.org/hook",
+			wantErr:  true,
+		},
+		{
+			name: "denied by list, domain",
+			policy: &HostPolicy{
+				DenyList: []string{"metadata.internal"},
+			},
+			endpoint: "http://Metadata.Internal/computeMetadata",
+			wantErr:  true,
+		},
+		{
+			name: "denied by list, network",
+			policy: &HostPolicy{
+				DenyList: []string{"169.254.0.0/16"},
+			},
+			endpoint: "http://169.254.169.254/latest/meta-data",
+			wantErr:  true,
+		},
+		{
+			name: "internal ip",
+			policy: &HostPolicy{
+				DenyInternalIPs: true,
+			},
+			endpoint: "http://10.1.2.3/hook",
+			wantErr:  true,
+		},
+		{
+			name: "internal ip, ipv6 loopback",
+			policy: &HostPolicy{
+				DenyInternalIPs: true,
+			},
+			endpoint: "http://[::1]:8080/hook",
+			wantErr:  true,
+		},
+		{
+			name: "public ip",
+			policy: &HostPolicy{
+				DenyInternalIPs: true,
+			},
+			endpoint: "https://203.0.113.10/hook",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setHostPolicy(t, tt.policy)
+			err := CheckTargetHost(tt.endpoint)
+			if tt.wantErr {
+				assert.True(t, zerrors.IsPermissionDenied(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCallTarget_hostPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		policy   *HostPolicy
+		endpoint string
+		wantErr  bool
+	}{
+		{
+			name:     "allowed",
+			policy:   &HostPolicy{DenyList: []string{"10.0.0.0/8"}},
+			endpoint: server.URL,
+		},
+		{
+			name:     "policy tightened after save",
+			policy:   &HostPolicy{DenyList: []string{serverURL.Hostname()}},
+			endpoint: server.URL,
+			wantErr:  true,
+		},
+		{
+			name:     "domain resolving to internal ip",
+			policy:   &HostPolicy{DenyInternalIPs: true},
+			endpoint: "http://localhost:" + serverURL.Port(),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setHostPolicy(t, tt.policy)
+			_, err := CallTarget(context.Background(), &mockTarget{
+				TargetType: domain.TargetTypeWebhook,
+				Endpoint:   tt.endpoint,
+				Timeout:    time.Minute,
+			}, newMockContextInfoRequest("content"))
+			if tt.wantErr {
+				assert.True(t, zerrors.IsPermissionDenied(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSetConfig_transport(t *testing.T) {
+	setHostPolicy(t, nil)
+
+	SetConfig(&Config{})
+	assert.Nil(t, hostPolicy, "empty policy must not restrict")
+	assert.Same(t, http.DefaultTransport, baseTransport())
+
+	SetConfig(&Config{HostPolicy: HostPolicy{DenyInternalIPs: true}})
+	require.NotNil(t, hostPolicy)
+	transport := baseTransport()
+	assert.NotSame(t, http.DefaultTransport, transport)
+
+	// the transport of the policy is shared by all calls
+	client, err := httpClient(&mockTarget{Timeout: time.Minute})
+	require.NoError(t, err)
+	assert.Same(t, transport, client.Transport)
+	assert.Same(t, transport, tokenClient().Transport)
+}
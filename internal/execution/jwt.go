@@ -0,0 +1,95 @@
+package execution
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const (
+	// jwtContentType is the content type of the payloads delivered as JWT
+	jwtContentType = "application/jwt"
+	// jwtPayloadClaim holds payloads which are no JSON object, e.g. the events of a batch
+	jwtPayloadClaim = "payload"
+)
+
+// ValidateJWTDelivery checks the algorithm and the issuer of the JWT delivery of a target,
+// nil or an empty delivery sends the payload in its payload format
+func ValidateJWTDelivery(delivery *domain.TargetJWTDelivery) error {
+	if delivery.IsZero() {
+		return nil
+	}
+	if !delivery.Algorithm.Valid() || delivery.Issuer == "" {
+		return zerrors.ThrowInvalidArgument(nil, "EXEC-Jw1Dlv", "Errors.Target.InvalidJWTDelivery")
+	}
+	return nil
+}
+
+// signPayload returns the JSON body as compact JWT signed with the primary signing key of the target.
+// The fields of a JSON object are the claims, other bodies are set as payload claim.
+// The iss, iat and jti claims are set in addition and replace fields with the same name.
+// The jti is derived from the target and the body, so repeated deliveries of the same payload can be deduplicated.
+func signPayload(targetID string, delivery *domain.TargetJWTDelivery, signingKey string, body []byte, now time.Time) ([]byte, error) {
+	if signingKey == "" {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "EXEC-Jw4Key", "Errors.Target.NoSigningKey")
+	}
+	claims, err := jwtClaims(body)
+	if err != nil {
+		return nil, err
+	}
+	jti := sha256.Sum256(append([]byte(targetID+"\x00"), body...))
+	for claim, value := range map[string]any{
+		"iss": delivery.Issuer,
+		"iat": now.Unix(),
+		"jti": base64.RawURLEncoding.EncodeToString(jti[:]),
+	} {
+		if claims[claim], err = json.Marshal(value); err != nil {
+			return nil, zerrors.ThrowInternal(err, "EXEC-Jw6Clm", "Errors.Execution.PayloadEncoding")
+		}
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "EXEC-Jw6Clm", "Errors.Execution.PayloadEncoding")
+	}
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.SignatureAlgorithm(delivery.Algorithm), Key: []byte(signingKey)},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "EXEC-Jw5Sgn", "Errors.Execution.PayloadEncoding")
+	}
+	signature, err := signer.Sign(payload)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "EXEC-Jw5Sgn", "Errors.Execution.PayloadEncoding")
+	}
+	token, err := signature.CompactSerialize()
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "EXEC-Jw5Sgn", "Errors.Execution.PayloadEncoding")
+	}
+	return []byte(token), nil
+}
+
+// jwtClaims returns the fields of a JSON object as claims, any other body is set as payload claim
+func jwtClaims(body []byte) (map[string]json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		claims := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(trimmed, &claims); err != nil {
+			return nil, zerrors.ThrowInternal(err, "EXEC-Jw6Clm", "Errors.Execution.PayloadEncoding")
+		}
+		return claims, nil
+	}
+	claims := make(map[string]json.RawMessage, 4)
+	if len(trimmed) > 0 {
+		claims[jwtPayloadClaim] = trimmed
+	}
+	return claims, nil
+}
@@ -0,0 +1,138 @@
+package execution
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const testHS256Key = "0123456789abcdef0123456789abcdef"
+
+func TestValidateJWTDelivery(t *testing.T) {
+	tests := []struct {
+		name     string
+		delivery *domain.TargetJWTDelivery
+		wantErr  bool
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:     "empty",
+			delivery: &domain.TargetJWTDelivery{},
+		},
+		{
+			name:     "hs256",
+			delivery: &domain.TargetJWTDelivery{Algorithm: domain.TargetJWTAlgorithmHS256, Issuer: "zitadel"},
+		},
+		{
+			name:     "unknown algorithm",
+			delivery: &domain.TargetJWTDelivery{Algorithm: "RS256", Issuer: "zitadel"},
+			wantErr:  true,
+		},
+		{
+			name:     "no issuer",
+			delivery: &domain.TargetJWTDelivery{Algorithm: domain.TargetJWTAlgorithmHS256},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJWTDelivery(tt.delivery)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, zerrors.ThrowInvalidArgument(nil, "EXEC-Jw1Dlv", "Errors.Target.InvalidJWTDelivery"))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_signPayload(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	delivery := &domain.TargetJWTDelivery{
+		Algorithm: domain.TargetJWTAlgorithmHS256,
+		Issuer:    "zitadel",
+	}
+	tests := []struct {
+		name       string
+		body       string
+		wantClaims map[string]any
+	}{
+		{
+			name: "object",
+			body: `{"event_type":"user.added","iss":"replaced"}`,
+			wantClaims: map[string]any{
+				"event_type": "user.added",
+			},
+		},
+		{
+			name: "array",
+			body: `[{"event_type":"user.added"}]`,
+			wantClaims: map[string]any{
+				"payload": []any{map[string]any{"event_type": "user.added"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := signPayload("target", delivery, testHS256Key, []byte(tt.body), now)
+			require.NoError(t, err)
+
+			signed, err := jose.ParseSigned(string(token), []jose.SignatureAlgorithm{jose.HS256})
+			require.NoError(t, err)
+			require.Len(t, signed.Signatures, 1)
+			assert.Equal(t, "JWT", signed.Signatures[0].Header.ExtraHeaders[jose.HeaderType])
+			// the target verifies the JWT with the same key it verifies the signature header with
+			payload, err := signed.Verify([]byte(testHS256Key))
+			require.NoError(t, err)
+
+			claims := make(map[string]any)
+			require.NoError(t, json.Unmarshal(payload, &claims))
+			jti := sha256.Sum256([]byte("target\x00" + tt.body))
+			tt.wantClaims["iss"] = "zitadel"
+			tt.wantClaims["iat"] = float64(now.Unix())
+			tt.wantClaims["jti"] = base64.RawURLEncoding.EncodeToString(jti[:])
+			assert.Equal(t, tt.wantClaims, claims)
+		})
+	}
+}
+
+func Test_signPayload_noSigningKey(t *testing.T) {
+	_, err := signPayload("target", &domain.TargetJWTDelivery{Algorithm: domain.TargetJWTAlgorithmHS256, Issuer: "zitadel"}, "", []byte(`{}`), time.Now())
+	assert.ErrorIs(t, err, zerrors.ThrowPreconditionFailed(nil, "EXEC-Jw4Key", "Errors.Target.NoSigningKey"))
+}
+
+func Test_newRequest_jwt(t *testing.T) {
+	target := &mockTarget{
+		TargetID:      "target",
+		Endpoint:      "https://example.com",
+		PayloadFormat: domain.TargetPayloadFormatJSON,
+		JWTDelivery: &domain.TargetJWTDelivery{
+			Algorithm: domain.TargetJWTAlgorithmHS256,
+			Issuer:    "zitadel",
+		},
+		SigningKey: testHS256Key,
+	}
+	req, err := newRequest(context.Background(), target, []byte(`{"request":"body"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "application/jwt", req.Header.Get("Content-Type"))
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+
+	signed, err := jose.ParseSigned(string(body), []jose.SignatureAlgorithm{jose.HS256})
+	require.NoError(t, err)
+	_, err = signed.Verify([]byte(testHS256Key))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,36 @@
+package execution
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/logstore"
+	"github.com/zitadel/zitadel/internal/logstore/record"
+)
+
+var logstoreService *logstore.Service[*record.ExecutionLog]
+
+// SetLogstoreService sets the service the calls of targets are emitted to,
+// so their latency counts into the same usage as the runs of actions.
+// The calls are not emitted if no service is set.
+func SetLogstoreService(svc *logstore.Service[*record.ExecutionLog]) {
+	logstoreService = svc
+}
+
+// emitTargetExecution emits the size of the payload sent to the target and the latency of its response
+func emitTargetExecution(ctx context.Context, target Target, payloadBytes int64, statusCode int, started time.Time, err error) {
+	if logstoreService == nil || !logstoreService.Enabled() {
+		return
+	}
+	now := time.Now()
+	logstoreService.Handle(ctx, record.NewTargetExecutionLog(
+		now,
+		authz.GetInstance(ctx).InstanceID(),
+		target.GetTargetID(),
+		payloadBytes,
+		statusCode,
+		now.Sub(started),
+		err,
+	))
+}
@@ -0,0 +1,65 @@
+package execution
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/logstore"
+	"github.com/zitadel/zitadel/internal/logstore/record"
+)
+
+// recordingEmitter keeps the emitted execution logs
+type recordingEmitter struct {
+	mu      sync.Mutex
+	records []*record.ExecutionLog
+}
+
+func (e *recordingEmitter) Emit(_ context.Context, bulk []*record.ExecutionLog) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.records = append(e.records, bulk...)
+	return nil
+}
+
+func Test_emitTargetExecution(t *testing.T) {
+	emitter := new(recordingEmitter)
+	sink, err := logstore.NewEmitter[*record.ExecutionLog](context.Background(), clock.New(), &logstore.EmitterConfig{Enabled: true}, emitter)
+	require.NoError(t, err)
+	SetLogstoreService(logstore.New[*record.ExecutionLog](nil, nil, sink))
+	t.Cleanup(func() { SetLogstoreService(nil) })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+	target := &mockTarget{
+		TargetID:   "target",
+		TargetType: domain.TargetTypeWebhook,
+		Endpoint:   server.URL,
+		Timeout:    time.Minute,
+	}
+	body := []byte(`{"request":"body"}`)
+
+	_, err = call(authz.WithInstanceID(context.Background(), "instance"), target, body)
+	require.Error(t, err)
+
+	require.Len(t, emitter.records, 1)
+	got := emitter.records[0]
+	assert.Equal(t, record.ExecutionKindTarget, got.Kind)
+	assert.Equal(t, "instance", got.InstanceID)
+	assert.Equal(t, "target", got.TargetID)
+	assert.Equal(t, int64(len(body)), got.PayloadBytes)
+	assert.Equal(t, http.StatusBadRequest, got.StatusCode)
+	assert.Positive(t, got.Took)
+	assert.NotEmpty(t, got.Message, "the error of the call is logged")
+}
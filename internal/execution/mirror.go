@@ -0,0 +1,103 @@
+package execution
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// MirrorExecutionResult describes the outcome of the call to a mirror endpoint of a target
+type MirrorExecutionResult struct {
+	Endpoint   string
+	StatusCode int
+	Latency    time.Duration
+	// Err is set if the call of the mirror endpoint failed
+	Err error
+}
+
+// ValidateMirrorEndpoints checks that the mirror endpoints of a target are HTTP URLs allowed by the host policy
+func ValidateMirrorEndpoints(endpoints []string) error {
+	return validateEndpoints(endpoints, "EXEC-Mr3nQx", "Errors.Target.InvalidMirrorEndpoint")
+}
+
+// validateEndpoints checks that the additional endpoints of a target are HTTP URLs allowed by the host policy,
+// invalid URLs are reported with the id and the message
+func validateEndpoints(endpoints []string, id, message string) error {
+	for _, endpoint := range endpoints {
+		parsed, err := url.Parse(endpoint)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return zerrors.ThrowInvalidArgument(err, id, message)
+		}
+		if err := hostPolicy.checkHost(parsed.Hostname()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mirrorTarget is a mirror endpoint of a target, it is called like the target but never has mirrors itself
+type mirrorTarget struct {
+	Target
+	endpoint string
+}
+
+func (t *mirrorTarget) GetEndpoint() string {
+	return t.endpoint
+}
+
+func (t *mirrorTarget) GetMirrorEndpoints() []string {
+	return nil
+}
+
+// callMirrors sends the body to all mirror endpoints of the target concurrently and waits for their outcome.
+// The calls are not canceled with ctx, as mirrors are delivered independent of the caller.
+// The results are in the order of the mirror endpoints.
+func callMirrors(ctx context.Context, target Target, body []byte) []*MirrorExecutionResult {
+	endpoints := target.GetMirrorEndpoints()
+	if len(endpoints) == 0 {
+		return nil
+	}
+	ctx = context.WithoutCancel(ctx)
+	results := make([]*MirrorExecutionResult, len(endpoints))
+	var wg sync.WaitGroup
+	wg.Add(len(endpoints))
+	for i, endpoint := range endpoints {
+		go func(i int, endpoint string) {
+			defer wg.Done()
+			start := time.Now()
+			resp, err := send(ctx, &mirrorTarget{Target: target, endpoint: endpoint}, body)
+			results[i] = &MirrorExecutionResult{
+				Endpoint: endpoint,
+				Latency:  time.Since(start),
+				Err:      err,
+			}
+			if resp != nil {
+				results[i].StatusCode = resp.statusCode
+			}
+		}(i, endpoint)
+	}
+	wg.Wait()
+	return results
+}
+
+// mirror delivers the body to the mirror endpoints of the target best-effort in the background,
+// failures are only logged and never influence the call of the target
+func mirror(ctx context.Context, target Target, body []byte) {
+	if len(target.GetMirrorEndpoints()) == 0 {
+		return
+	}
+	go func() {
+		logMirrorResults(target.GetTargetID(), callMirrors(ctx, target, body))
+	}()
+}
+
+func logMirrorResults(targetID string, results []*MirrorExecutionResult) {
+	for _, result := range results {
+		logging.WithFields("target", targetID, "mirror", result.Endpoint, "status", result.StatusCode).OnError(result.Err).Info("mirror of target failed")
+	}
+}
@@ -0,0 +1,153 @@
+package execution
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestValidateMirrorEndpoints(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints []string
+		wantErr   bool
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:      "valid",
+			endpoints: []string{"https://example.com/hook", "http://localhost:8080"},
+		},
+		{
+			name:      "empty",
+			endpoints: []string{"https://example.com/hook", ""},
+			wantErr:   true,
+		},
+		{
+			name:      "relative",
+			endpoints: []string{"/hook"},
+			wantErr:   true,
+		},
+		{
+			name:      "unsupported scheme",
+			endpoints: []string{"ftp://example.com"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMirrorEndpoints(tt.endpoints)
+			if tt.wantErr {
+				assert.True(t, zerrors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCallTargets_mirrors(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer primary.Close()
+
+	received := make(chan []byte, 1)
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- body
+	}))
+	defer mirror.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	// the blocking mirror only responds after the targets are called
+	unblock := make(chan struct{})
+	blocking := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer blocking.Close()
+	defer close(unblock)
+
+	_, err := CallTargets(context.Background(), []Target{
+		&mockTarget{
+			TargetType:       domain.TargetTypeWebhook,
+			Endpoint:         primary.URL,
+			Timeout:          time.Minute,
+			InterruptOnError: true,
+			MirrorEndpoints:  []string{failing.URL, blocking.URL, mirror.URL},
+		},
+	}, &mockContextInfoEvent{})
+	require.NoError(t, err)
+
+	select {
+	case body := <-received:
+		assert.Equal(t, []byte(`{}`), body)
+	case <-time.After(5 * time.Second):
+		t.Fatal("mirror not called")
+	}
+}
+
+func TestCallTargets_failingPrimaryInterrupts(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer mirror.Close()
+
+	_, err := CallTargets(context.Background(), []Target{
+		&mockTarget{
+			TargetType:       domain.TargetTypeWebhook,
+			Endpoint:         failing.URL,
+			Timeout:          time.Minute,
+			InterruptOnError: true,
+			MirrorEndpoints:  []string{mirror.URL},
+		},
+	}, &mockContextInfoEvent{})
+	assert.Error(t, err)
+}
+
+func TestDispatchAsyncTargets_mirrors(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer primary.Close()
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer mirror.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	results := DispatchAsyncTargets(context.Background(), []Target{
+		&mockTarget{
+			TargetID:        "target",
+			TargetType:      domain.TargetTypeAsync,
+			Endpoint:        primary.URL,
+			Timeout:         time.Minute,
+			MirrorEndpoints: []string{mirror.URL, failing.URL},
+		},
+	}, newMockContextInfoRequest("content"))
+	require.Len(t, results, 1)
+	result := results[0]
+	assert.NoError(t, result.Err)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+
+	require.Len(t, result.Mirrors, 2)
+	assert.Equal(t, mirror.URL, result.Mirrors[0].Endpoint)
+	assert.Equal(t, http.StatusAccepted, result.Mirrors[0].StatusCode)
+	assert.NoError(t, result.Mirrors[0].Err)
+	assert.Equal(t, failing.URL, result.Mirrors[1].Endpoint)
+	assert.Equal(t, http.StatusInternalServerError, result.Mirrors[1].StatusCode)
+	assert.Error(t, result.Mirrors[1].Err)
+}
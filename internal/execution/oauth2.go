@@ -0,0 +1,212 @@
+package execution
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// ErrTokenFetch is the cause of the errors returned if the access token for a target could not be fetched.
+// It distinguishes a failing token endpoint from a failing target.
+var ErrTokenFetch = errors.New("unable to fetch access token")
+
+const (
+	// tokenExpirySkew renews tokens shortly before they expire, so they do not expire in transit
+	tokenExpirySkew = 10 * time.Second
+	// tokenResponseLimit is the maximum amount of bytes read from the response of a token endpoint
+	tokenResponseLimit = 1 << 20
+	// tokenMaxIdle evicts the tokens of targets which were not called for this duration
+	tokenMaxIdle = time.Hour
+	// tokenSweepInterval is the minimum interval between the evictions of the cached tokens
+	tokenSweepInterval = time.Minute
+)
+
+// ValidateOAuth2 checks the token endpoint and the client id of the client credentials of a target
+func ValidateOAuth2(tokenEndpoint, clientID string) error {
+	parsed, err := url.Parse(tokenEndpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return zerrors.ThrowInvalidArgument(err, "EXEC-Oa2Ep7", "Errors.Target.InvalidOAuth2")
+	}
+	if clientID == "" {
+		return zerrors.ThrowInvalidArgument(nil, "EXEC-Oa2Ci4", "Errors.Target.InvalidOAuth2")
+	}
+	return CheckTargetHost(tokenEndpoint)
+}
+
+type oauth2Token struct {
+	accessToken string
+	// credentials is the hash of the credentials the token was issued for
+	credentials [sha256.Size]byte
+	// expiresAt is zero if the token endpoint did not return an expiry
+	expiresAt time.Time
+	lastUsed  time.Time
+}
+
+func (t *oauth2Token) valid(now time.Time) bool {
+	return t.expiresAt.IsZero() || now.Add(tokenExpirySkew).Before(t.expiresAt)
+}
+
+// evictable reports if the token is expired or was not used for [tokenMaxIdle]
+func (t *oauth2Token) evictable(now time.Time) bool {
+	return !t.valid(now) || now.Sub(t.lastUsed) > tokenMaxIdle
+}
+
+// hashCredentials returns the hash the cached tokens are compared by, so that the secret itself is not kept in the cache
+func hashCredentials(credentials *domain.TargetOAuth2Credentials) [sha256.Size]byte {
+	return sha256.Sum256([]byte(credentials.TokenEndpoint + "\x00" + credentials.ClientID + "\x00" + credentials.ClientSecret))
+}
+
+// oauth2TokenCache holds the access token of each target until shortly before it expires.
+// The token is only used for the credentials it was issued for, so changed credentials fetch a new token which replaces it.
+// Expired tokens and tokens of targets which are not called anymore are evicted.
+type oauth2TokenCache struct {
+	mux       sync.Mutex
+	now       func() time.Time
+	tokens    map[string]*oauth2Token
+	lastSweep time.Time
+}
+
+func newOAuth2TokenCache(now func() time.Time) *oauth2TokenCache {
+	return &oauth2TokenCache{
+		now:    now,
+		tokens: make(map[string]*oauth2Token),
+	}
+}
+
+var tokenCache = newOAuth2TokenCache(time.Now)
+
+// token returns the cached access token of the target or fetches a new one
+func (c *oauth2TokenCache) token(ctx context.Context, targetID string, credentials *domain.TargetOAuth2Credentials) (string, error) {
+	hash := hashCredentials(credentials)
+	now := c.now()
+	c.mux.Lock()
+	c.sweep(now, targetID)
+	cached, ok := c.tokens[targetID]
+	if ok && cached.credentials == hash && cached.valid(now) {
+		cached.lastUsed = now
+		c.mux.Unlock()
+		return cached.accessToken, nil
+	}
+	c.mux.Unlock()
+
+	token, err := fetchToken(ctx, credentials, now)
+	if err != nil {
+		return "", err
+	}
+	token.credentials = hash
+	token.lastUsed = now
+	c.mux.Lock()
+	c.tokens[targetID] = token
+	c.mux.Unlock()
+	return token.accessToken, nil
+}
+
+// sweep evicts the evictable tokens of other targets at most every [tokenSweepInterval], c.mux must be held
+func (c *oauth2TokenCache) sweep(now time.Time, targetID string) {
+	if now.Sub(c.lastSweep) < tokenSweepInterval {
+		return
+	}
+	c.lastSweep = now
+	for id, token := range c.tokens {
+		if id != targetID && token.evictable(now) {
+			delete(c.tokens, id)
+		}
+	}
+}
+
+// authorize sets the basic authentication or the access token of the client credentials of the target on the request,
+// requests to targets without credentials are not changed
+func authorize(ctx context.Context, target Target, req *http.Request) error {
+	if basicAuth := target.GetBasicAuthCredentials(); basicAuth != nil && basicAuth.Username != "" {
+		req.SetBasicAuth(basicAuth.Username, basicAuth.Password)
+		return nil
+	}
+	credentials := target.GetOAuth2Credentials()
+	if credentials == nil || credentials.TokenEndpoint == "" {
+		return nil
+	}
+	token, err := tokenCache.token(ctx, target.GetTargetID(), credentials)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// fetchToken requests an access token using the client credentials grant (RFC 6749, section 4.4)
+func fetchToken(ctx context.Context, credentials *domain.TargetOAuth2Credentials, now time.Time) (*oauth2Token, error) {
+	// the policy could have been tightened since the target was saved
+	parsed, err := url.Parse(credentials.TokenEndpoint)
+	if err != nil {
+		return nil, tokenFetchError(err)
+	}
+	if err := hostPolicy.checkHost(parsed.Hostname()); err != nil {
+		return nil, tokenFetchError(err)
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, credentials.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, tokenFetchError(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// the credentials are form encoded before they are used for basic authentication (RFC 6749, section 2.3.1)
+	req.SetBasicAuth(url.QueryEscape(credentials.ClientID), url.QueryEscape(credentials.ClientSecret))
+
+	resp, err := tokenClient().Do(req)
+	if err != nil {
+		return nil, tokenFetchError(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, tokenResponseLimit))
+	if err != nil {
+		return nil, tokenFetchError(err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, tokenFetchError(fmt.Errorf("unexpected status code %d", resp.StatusCode))
+	}
+	response := new(tokenResponse)
+	if err := json.Unmarshal(body, response); err != nil {
+		return nil, tokenFetchError(err)
+	}
+	if response.AccessToken == "" {
+		return nil, tokenFetchError(errors.New("no access token returned"))
+	}
+	if response.TokenType != "" && !strings.EqualFold(response.TokenType, "bearer") {
+		return nil, tokenFetchError(fmt.Errorf("unsupported token type %q", response.TokenType))
+	}
+	token := &oauth2Token{accessToken: response.AccessToken}
+	if response.ExpiresIn > 0 {
+		token.expiresAt = now.Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// tokenClient returns the client used to call token endpoints, they are subject to the host policy like the targets
+// and their redirects are not followed
+func tokenClient() *http.Client {
+	return &http.Client{Transport: baseTransport(), CheckRedirect: checkRedirect(0)}
+}
+
+func tokenFetchError(err error) error {
+	return zerrors.ThrowUnavailable(fmt.Errorf("%w: %w", ErrTokenFetch, err), "EXEC-Tk5FqE", "Errors.Execution.TokenFetchFailed")
+}
@@ -0,0 +1,272 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestValidateOAuth2(t *testing.T) {
+	tests := []struct {
+		name          string
+		tokenEndpoint string
+		clientID      string
+		wantErr       error
+	}{
+		{
+			name:          "valid",
+			tokenEndpoint: "https://auth.example.com/oauth/token",
+			clientID:      "client",
+		},
+		{
+			name:          "relative token endpoint",
+			tokenEndpoint: "/oauth/token",
+			clientID:      "client",
+			wantErr:       zerrors.ThrowInvalidArgument(nil, "EXEC-Oa2Ep7", "Errors.Target.InvalidOAuth2"),
+		},
+		{
+			name:          "unsupported scheme",
+			tokenEndpoint: "ftp://auth.example.com/oauth/token",
+			clientID:      "client",
+			wantErr:       zerrors.ThrowInvalidArgument(nil, "EXEC-Oa2Ep7", "Errors.Target.InvalidOAuth2"),
+		},
+		{
+			name:          "client id missing",
+			tokenEndpoint: "https://auth.example.com/oauth/token",
+			wantErr:       zerrors.ThrowInvalidArgument(nil, "EXEC-Oa2Ci4", "Errors.Target.InvalidOAuth2"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateOAuth2(tt.tokenEndpoint, tt.clientID)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+// newTokenServer returns a token endpoint issuing a new token on each request and the count of requests
+func newTokenServer(t *testing.T, expiresIn int64) (*httptest.Server, *atomic.Int32) {
+	calls := new(atomic.Int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := calls.Add(1)
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok || clientID != "client" || clientSecret != "secret" || r.PostFormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(&tokenResponse{
+			AccessToken: "token" + strconv.Itoa(int(call)),
+			TokenType:   "Bearer",
+			ExpiresIn:   expiresIn,
+		})
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+	return server, calls
+}
+
+func Test_oauth2TokenCache(t *testing.T) {
+	server, calls := newTokenServer(t, 60)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newOAuth2TokenCache(func() time.Time { return now })
+	credentials := &domain.TargetOAuth2Credentials{
+		TokenEndpoint: server.URL,
+		ClientID:      "client",
+		ClientSecret:  "secret",
+	}
+
+	// fetch
+	token, err := cache.token(context.Background(), "target", credentials)
+	require.NoError(t, err)
+	assert.Equal(t, "token1", token)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// cached until shortly before the expiry
+	now = now.Add(45 * time.Second)
+	token, err = cache.token(context.Background(), "target", credentials)
+	require.NoError(t, err)
+	assert.Equal(t, "token1", token)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// refreshed on expiry
+	now = now.Add(10 * time.Second)
+	token, err = cache.token(context.Background(), "target", credentials)
+	require.NoError(t, err)
+	assert.Equal(t, "token2", token)
+	assert.Equal(t, int32(2), calls.Load())
+
+	// other credentials do not share the token
+	_, err = cache.token(context.Background(), "target", &domain.TargetOAuth2Credentials{
+		TokenEndpoint: server.URL,
+		ClientID:      "client",
+		ClientSecret:  "changed",
+	})
+	assert.ErrorIs(t, err, ErrTokenFetch)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func Test_oauth2TokenCache_withoutExpiry(t *testing.T) {
+	server, calls := newTokenServer(t, 0)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newOAuth2TokenCache(func() time.Time { return now })
+	credentials := &domain.TargetOAuth2Credentials{
+		TokenEndpoint: server.URL,
+		ClientID:      "client",
+		ClientSecret:  "secret",
+	}
+
+	for i := 0; i < 3; i++ {
+		token, err := cache.token(context.Background(), "target", credentials)
+		require.NoError(t, err)
+		assert.Equal(t, "token1", token)
+		now = now.Add(24 * time.Hour)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func Test_oauth2TokenCache_eviction(t *testing.T) {
+	server, calls := newTokenServer(t, 60)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newOAuth2TokenCache(func() time.Time { return now })
+	credentials := &domain.TargetOAuth2Credentials{
+		TokenEndpoint: server.URL,
+		ClientID:      "client",
+		ClientSecret:  "secret",
+	}
+
+	_, err := cache.token(context.Background(), "target1", credentials)
+	require.NoError(t, err)
+	_, err = cache.token(context.Background(), "target2", credentials)
+	require.NoError(t, err)
+	assert.Len(t, cache.tokens, 2)
+	// the secret is not part of the cache
+	for id, token := range cache.tokens {
+		assert.NotContains(t, id, "secret")
+		assert.Equal(t, hashCredentials(credentials), token.credentials)
+	}
+
+	// changed credentials do not use the token issued for the previous ones
+	_, err = cache.token(context.Background(), "target1", &domain.TargetOAuth2Credentials{
+		TokenEndpoint: server.URL,
+		ClientID:      "client",
+		ClientSecret:  "changed",
+	})
+	assert.ErrorIs(t, err, ErrTokenFetch)
+	assert.Len(t, cache.tokens, 2)
+
+	// the expired token of target2 is evicted on the next call of another target
+	now = now.Add(2 * time.Minute)
+	_, err = cache.token(context.Background(), "target1", credentials)
+	require.NoError(t, err)
+	assert.Len(t, cache.tokens, 1)
+	assert.Contains(t, cache.tokens, "target1")
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func Test_fetchToken_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "unauthorized",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+		},
+		{
+			name: "invalid response",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("token"))
+			},
+		},
+		{
+			name: "no access token",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"token_type":"Bearer"}`))
+			},
+		},
+		{
+			name: "unsupported token type",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"access_token":"token","token_type":"mac"}`))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			_, err := fetchToken(context.Background(), &domain.TargetOAuth2Credentials{
+				TokenEndpoint: server.URL,
+				ClientID:      "client",
+				ClientSecret:  "secret",
+			}, time.Now())
+			assert.ErrorIs(t, err, ErrTokenFetch)
+			assert.ErrorIs(t, err, zerrors.ThrowUnavailable(nil, "EXEC-Tk5FqE", "Errors.Execution.TokenFetchFailed"))
+		})
+	}
+}
+
+func Test_call_oauth2(t *testing.T) {
+	defer func(cache *oauth2TokenCache) {
+		tokenCache = cache
+	}(tokenCache)
+	tokenCache = newOAuth2TokenCache(time.Now)
+
+	tokenServer, _ := newTokenServer(t, 3600)
+	failingTokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failingTokenServer.Close()
+
+	var authorization string
+	targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer targetServer.Close()
+
+	_, err := call(context.Background(), &mockTarget{
+		Endpoint: targetServer.URL,
+		Timeout:  time.Second,
+		OAuth2Credentials: &domain.TargetOAuth2Credentials{
+			TokenEndpoint: tokenServer.URL,
+			ClientID:      "client",
+			ClientSecret:  "secret",
+		},
+	}, []byte("{}"))
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token1", authorization)
+
+	// the target is not called if no token could be fetched
+	authorization = ""
+	_, err = call(context.Background(), &mockTarget{
+		Endpoint: targetServer.URL,
+		Timeout:  time.Second,
+		OAuth2Credentials: &domain.TargetOAuth2Credentials{
+			TokenEndpoint: failingTokenServer.URL,
+			ClientID:      "client",
+			ClientSecret:  "secret",
+		},
+	}, []byte("{}"))
+	assert.ErrorIs(t, err, ErrTokenFetch)
+	assert.Empty(t, authorization)
+}
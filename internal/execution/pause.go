@@ -0,0 +1,306 @@
+package execution
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/id"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const (
+	// DefaultTargetQueueDepth is the amount of payloads queued for a paused target if the instance does not define a depth
+	DefaultTargetQueueDepth = 1000
+	// MaxTargetQueueDepth is the maximum amount of payloads an instance can queue per paused target
+	MaxTargetQueueDepth = 100000
+)
+
+const (
+	// queuePayloadStmt only inserts the payload if the queue of the target is not full,
+	// concurrent inserts might exceed the depth by the amount of concurrent calls
+	queuePayloadStmt = `INSERT INTO execution.target_deliveries (instance_id, target_id, id, created_at, payload, url_variables)` +
+		` SELECT $1, $2, $3, $4, $5, $6` +
+		` WHERE (SELECT COUNT(*) FROM execution.target_deliveries WHERE instance_id = $1 AND target_id = $2) < $7`
+	dropPayloadStmt = `INSERT INTO execution.target_queues (instance_id, target_id, dropped) VALUES ($1, $2, 1)` +
+		` ON CONFLICT (instance_id, target_id) DO UPDATE SET dropped = target_queues.dropped + 1`
+	queuedTargetsStmt      = `SELECT DISTINCT instance_id, target_id FROM execution.target_deliveries`
+	lockQueuedPayloadsStmt = `SELECT id, payload, url_variables FROM execution.target_deliveries` +
+		` WHERE instance_id = $1 AND target_id = $2 ORDER BY created_at, id LIMIT $3 FOR UPDATE`
+	deleteQueuedPayloadsStmt = `DELETE FROM execution.target_deliveries WHERE instance_id = $1 AND target_id = $2 AND id = ANY($3)`
+	discardQueueStmt         = `DELETE FROM execution.target_deliveries WHERE instance_id = $1 AND target_id = $2`
+	discardQueueCounterStmt  = `DELETE FROM execution.target_queues WHERE instance_id = $1 AND target_id = $2`
+)
+
+type targetQueueKey struct{}
+
+type targetQueueConfig struct {
+	maxDepth int
+	overflow domain.TargetQueueOverflow
+}
+
+// WithTargetQueue sets the amount of payloads queued per paused target of the instance
+// and what happens to the payloads exceeding it
+func WithTargetQueue(ctx context.Context, maxDepth int, overflow domain.TargetQueueOverflow) context.Context {
+	return context.WithValue(ctx, targetQueueKey{}, targetQueueConfig{maxDepth: maxDepth, overflow: overflow})
+}
+
+func targetQueue(ctx context.Context) targetQueueConfig {
+	config, _ := ctx.Value(targetQueueKey{}).(targetQueueConfig)
+	if config.maxDepth < 1 {
+		config.maxDepth = DefaultTargetQueueDepth
+	}
+	return config
+}
+
+// TargetQueueState is the state of the delivery to a target
+type TargetQueueState struct {
+	Paused bool
+	// Depth is the amount of payloads queued until the target is resumed
+	Depth int
+	// Dropped is the amount of payloads dropped because the queue of the paused target was full
+	Dropped uint64
+}
+
+// QueueConfig configures the delivery of the payloads queued for paused targets
+type QueueConfig struct {
+	// Interval is the time between the deliveries of the payloads queued for resumed targets
+	Interval time.Duration
+	// BulkLimit is the amount of queued payloads delivered within one transaction
+	BulkLimit uint16
+}
+
+// Queries are the queries used by the delivery to the targets
+type Queries interface {
+	SettingsQueries
+	// ExecutionTargetByID returns the target of the instance in the context
+	ExecutionTargetByID(ctx context.Context, id string) (Target, error)
+}
+
+// Start stores the executions of the targets in the database and queues the payloads of paused async targets,
+// the queued payloads are delivered in the background once their target is resumed.
+// The execution settings of the instances are queried by the dispatchers of their async targets.
+func Start(ctx context.Context, config QueueConfig, dbClient *database.DB, queries Queries) {
+	client = dbClient
+	dispatchers = newInstanceDispatchers(queries)
+	worker := &queueWorker{
+		config:  config,
+		queries: queries,
+	}
+	go worker.run(ctx)
+}
+
+// queuePaused queues the payload until the paused target is resumed.
+// Payloads exceeding the depth of the instance (see [WithTargetQueue]) are dropped and counted
+// or reported as failed execution, depending on the configured overflow.
+// The payload is reported as failed if there is no database to queue it in.
+func (d *asyncDispatcher) queuePaused(ctx context.Context, target Target, info ContextInfoRequest) {
+	if err := queuePayload(ctx, target.GetTargetID(), info, d.queueConfig()); err != nil {
+		d.onResult(&TargetExecutionResult{TargetID: target.GetTargetID(), BatchSize: 1, Err: err})
+	}
+}
+
+func queuePayload(ctx context.Context, targetID string, info ContextInfoRequest, config targetQueueConfig) error {
+	if client == nil {
+		return zerrors.ThrowUnavailable(nil, "EXEC-Qu4Psd", "Errors.Execution.TargetPaused")
+	}
+	var variables []byte
+	if provider, ok := info.(ContextInfoURLVariables); ok {
+		var err error
+		if variables, err = json.Marshal(provider.GetURLVariables()); err != nil {
+			return zerrors.ThrowInternal(err, "EXEC-Qu5Vrs", "Errors.Internal")
+		}
+	}
+	payloadID, err := id.SonyFlakeGenerator().Next()
+	if err != nil {
+		return zerrors.ThrowInternal(err, "EXEC-Qu5Id1", "Errors.Internal")
+	}
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	res, err := client.ExecContext(ctx, queuePayloadStmt, instanceID, targetID, payloadID, time.Now(), info.GetHTTPRequestBody(), variables, config.maxDepth)
+	if err != nil {
+		return zerrors.ThrowInternal(err, "EXEC-Qu5Ins", "Errors.Internal")
+	}
+	if queued, err := res.RowsAffected(); err != nil || queued > 0 {
+		return err
+	}
+	if config.overflow != domain.TargetQueueOverflowDrop {
+		return zerrors.ThrowResourceExhausted(nil, "EXEC-Qu3Ovf", "Errors.Execution.QueueFull")
+	}
+	if _, err = client.ExecContext(ctx, dropPayloadStmt, instanceID, targetID); err != nil {
+		return zerrors.ThrowInternal(err, "EXEC-Qu5Drp", "Errors.Internal")
+	}
+	return nil
+}
+
+// queuedPayload is a payload read from the queue of a target
+type queuedPayload struct {
+	body      []byte
+	variables map[string]string
+}
+
+func (p *queuedPayload) GetHTTPRequestBody() []byte {
+	return p.body
+}
+
+func (p *queuedPayload) GetURLVariables() map[string]string {
+	return p.variables
+}
+
+// queueWorker delivers the payloads queued for paused targets once they are resumed
+type queueWorker struct {
+	config  QueueConfig
+	queries Queries
+}
+
+func (w *queueWorker) run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.deliver(ctx)
+		}
+	}
+}
+
+// deliver delivers the queued payloads of the resumed targets and removes the executions older than the [TargetExecutionRetention]
+func (w *queueWorker) deliver(ctx context.Context) {
+	err := pruneTargetExecutions(ctx, time.Now().Add(-TargetExecutionRetention))
+	logging.OnError(err).Warn("unable to remove expired executions of targets")
+
+	type queue struct{ instanceID, targetID string }
+	queues := make([]queue, 0)
+	err = client.QueryContext(ctx, func(rows *sql.Rows) error {
+		for rows.Next() {
+			var q queue
+			if err := rows.Scan(&q.instanceID, &q.targetID); err != nil {
+				return err
+			}
+			queues = append(queues, q)
+		}
+		return rows.Err()
+	}, queuedTargetsStmt)
+	if err != nil {
+		logging.WithError(err).Warn("unable to query targets with queued payloads")
+		return
+	}
+	for _, q := range queues {
+		err = w.drain(authz.WithInstanceID(ctx, q.instanceID), q.targetID)
+		logging.WithFields("instance", q.instanceID, "target", q.targetID).OnError(err).Warn("unable to deliver queued payloads to target")
+	}
+}
+
+// drain delivers the queued payloads of the target in the order they were queued, if the target is not paused anymore.
+// Payloads dispatched after the resume might be delivered before the queued ones.
+// The queue of a removed target is discarded.
+func (w *queueWorker) drain(ctx context.Context, targetID string) error {
+	target, err := w.queries.ExecutionTargetByID(ctx, targetID)
+	if zerrors.IsNotFound(err) {
+		return discardQueue(ctx, targetID)
+	}
+	if err != nil || target.IsPaused() {
+		return err
+	}
+	dispatcher := dispatchers.get(ctx)
+	for {
+		delivered, err := w.deliverBulk(ctx, dispatcher, target)
+		if err != nil || delivered < int(w.config.BulkLimit) {
+			return err
+		}
+	}
+}
+
+// deliverBulk delivers the next payloads of the queue of the target and returns their amount.
+// The payloads are locked during the delivery and removed in the same transaction after they were delivered,
+// so that they are not delivered concurrently and delivered again if the system stops during the delivery.
+func (w *queueWorker) deliverBulk(ctx context.Context, dispatcher *asyncDispatcher, target Target) (_ int, err error) {
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	tx, err := client.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, zerrors.ThrowInternal(err, "EXEC-Qu6Tx1", "Errors.Internal")
+	}
+	defer func() {
+		if err != nil {
+			logging.OnError(tx.Rollback()).Debug("unable to rollback delivery of queued payloads")
+		}
+	}()
+
+	ids := make(database.TextArray[string], 0, w.config.BulkLimit)
+	payloads := make([]*asyncPayload, 0, w.config.BulkLimit)
+	rows, err := tx.QueryContext(ctx, lockQueuedPayloadsStmt, instanceID, target.GetTargetID(), w.config.BulkLimit)
+	if err != nil {
+		return 0, zerrors.ThrowInternal(err, "EXEC-Qu6Lck", "Errors.Internal")
+	}
+	for rows.Next() {
+		var (
+			payloadID string
+			payload   = new(queuedPayload)
+			variables []byte
+		)
+		if err = rows.Scan(&payloadID, &payload.body, &variables); err != nil {
+			rows.Close()
+			return 0, zerrors.ThrowInternal(err, "EXEC-Qu6Scn", "Errors.Internal")
+		}
+		if len(variables) > 0 {
+			if err = json.Unmarshal(variables, &payload.variables); err != nil {
+				rows.Close()
+				return 0, zerrors.ThrowInternal(err, "EXEC-Qu6Vrs", "Errors.Internal")
+			}
+		}
+		ids = append(ids, payloadID)
+		payloads = append(payloads, &asyncPayload{ctx: ctx, info: payload, body: payload.body})
+	}
+	if err = errors.Join(rows.Err(), rows.Close()); err != nil {
+		return 0, zerrors.ThrowInternal(err, "EXEC-Qu6Cls", "Errors.Query.CloseRows")
+	}
+	if len(payloads) == 0 {
+		return 0, tx.Commit()
+	}
+
+	for _, call := range queuedCalls(target, payloads) {
+		dispatcher.onResult(dispatcher.call(target, call, !target.GetBatchDelivery().IsZero()))
+	}
+	if _, err = tx.ExecContext(ctx, deleteQueuedPayloadsStmt, instanceID, target.GetTargetID(), ids); err != nil {
+		return 0, zerrors.ThrowInternal(err, "EXEC-Qu6Del", "Errors.Internal")
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, zerrors.ThrowInternal(err, "EXEC-Qu6Cmt", "Errors.Internal")
+	}
+	return len(payloads), nil
+}
+
+// queuedCalls splits the queued payloads into the calls of the target,
+// a call per payload or per batch of the max size of the batch delivery of the target
+func queuedCalls(target Target, payloads []*asyncPayload) [][]*asyncPayload {
+	size := 1
+	if batch := target.GetBatchDelivery(); !batch.IsZero() {
+		size = batch.MaxSize
+	}
+	calls := make([][]*asyncPayload, 0, len(payloads)/size+1)
+	for len(payloads) > size {
+		calls = append(calls, payloads[:size])
+		payloads = payloads[size:]
+	}
+	return append(calls, payloads)
+}
+
+// discardQueue removes the queued payloads and the counter of the dropped payloads of the target
+func discardQueue(ctx context.Context, targetID string) error {
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	if _, err := client.ExecContext(ctx, discardQueueStmt, instanceID, targetID); err != nil {
+		return zerrors.ThrowInternal(err, "EXEC-Qu7Dsc", "Errors.Internal")
+	}
+	if _, err := client.ExecContext(ctx, discardQueueCounterStmt, instanceID, targetID); err != nil {
+		return zerrors.ThrowInternal(err, "EXEC-Qu7Cnt", "Errors.Internal")
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package execution
+
+import (
+	"context"
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestQueuePayload(t *testing.T) {
+	tests := []struct {
+		name     string
+		overflow domain.TargetQueueOverflow
+		queued   bool
+		wantErr  func(error) bool
+	}{
+		{
+			name:   "queued",
+			queued: true,
+		},
+		{
+			name:     "full, dropped",
+			overflow: domain.TargetQueueOverflowDrop,
+		},
+		{
+			name:     "full, failed",
+			overflow: domain.TargetQueueOverflowFail,
+			wantErr:  zerrors.IsResourceExhausted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := withMockClient(t)
+			var affected int64
+			if tt.queued {
+				affected = 1
+			}
+			mock.ExpectExec(regexp.QuoteMeta(queuePayloadStmt)).
+				WithArgs("instance", "target", sqlmock.AnyArg(), sqlmock.AnyArg(), []byte(`{"request":{"request":"content"}}`), sqlmock.AnyArg(), 1).
+				WillReturnResult(sqlmock.NewResult(0, affected))
+			if !tt.queued && tt.overflow == domain.TargetQueueOverflowDrop {
+				mock.ExpectExec(regexp.QuoteMeta(dropPayloadStmt)).
+					WithArgs("instance", "target").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			err := queuePayload(authz.WithInstanceID(context.Background(), "instance"), "target", newMockContextInfoRequest("content"), targetQueueConfig{maxDepth: 1, overflow: tt.overflow})
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestQueuePayload_withoutClient(t *testing.T) {
+	err := queuePayload(context.Background(), "target", newMockContextInfoRequest("content"), targetQueue(context.Background()))
+	assert.True(t, zerrors.IsUnavailable(err), "unexpected error: %v", err)
+}
+
+func TestCallTargets_paused(t *testing.T) {
+	tests := []struct {
+		name             string
+		interruptOnError bool
+		wantErr          bool
+		wantCalls        int32
+	}{
+		{
+			name:      "paused target skipped",
+			wantCalls: 1,
+		},
+		{
+			name:             "paused target interrupts",
+			interruptOnError: true,
+			wantErr:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+			}))
+			defer server.Close()
+
+			_, err := CallTargets(context.Background(), []Target{
+				&mockTarget{
+					TargetType:       domain.TargetTypeWebhook,
+					Endpoint:         server.URL,
+					Timeout:          time.Minute,
+					InterruptOnError: tt.interruptOnError,
+					Paused:           true,
+				},
+				&mockTarget{
+					TargetType: domain.TargetTypeWebhook,
+					Endpoint:   server.URL,
+					Timeout:    time.Minute,
+				},
+			}, &mockContextInfoEvent{eventType: "user.human.added"})
+			if tt.wantErr {
+				assert.True(t, zerrors.IsUnavailable(err), "unexpected error: %v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCalls, calls.Load())
+		})
+	}
+}
+
+type mockQueries struct {
+	target Target
+	err    error
+}
+
+func (q *mockQueries) WithExecutionSettings(ctx context.Context) (context.Context, error) {
+	return ctx, nil
+}
+
+func (q *mockQueries) ExecutionTargetByID(context.Context, string) (Target, error) {
+	return q.target, q.err
+}
+
+func TestQueueWorker_drain(t *testing.T) {
+	server, received := batchReceiver(t, http.StatusOK)
+	target := &mockTarget{
+		TargetID:      "target",
+		TargetType:    domain.TargetTypeAsync,
+		Endpoint:      server.URL + "/{aggregateID}",
+		Timeout:       time.Minute,
+		BatchDelivery: domain.TargetBatchDelivery{MaxSize: 2, MaxWait: time.Hour},
+	}
+	mock := withMockClient(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockQueuedPayloadsStmt)).
+		WithArgs("instance", "target", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "payload", "url_variables"}).
+			AddRow("1", []byte(`{"request":{"request":"first"}}`), []byte(`{"aggregateID":"user1"}`)).
+			AddRow("2", []byte(`{"request":{"request":"second"}}`), []byte(`{"aggregateID":"user1"}`)).
+			AddRow("3", []byte(`{"request":{"request":"third"}}`), []byte(`{"aggregateID":"user1"}`)))
+	// the execution of each call is recorded
+	for range 2 {
+		mock.ExpectExec(regexp.QuoteMeta(insertTargetExecutionStmt)).
+			WithArgs("instance", "target", sqlmock.AnyArg(), sqlmock.AnyArg(), http.StatusOK, sqlmock.AnyArg(), "", true).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectExec(regexp.QuoteMeta(deleteQueuedPayloadsStmt)).
+		WithArgs("instance", "target", database.TextArray[string]{"1", "2", "3"}).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+	// the second bulk is empty
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(lockQueuedPayloadsStmt)).
+		WithArgs("instance", "target", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "payload", "url_variables"}))
+	mock.ExpectCommit()
+
+	worker := &queueWorker{config: QueueConfig{BulkLimit: 3}, queries: &mockQueries{target: target}}
+	require.NoError(t, worker.drain(authz.WithInstanceID(context.Background(), "instance"), "target"))
+
+	// the queued payloads are delivered in the batches of the target in the order they were queued
+	assert.Equal(t, []string{"first", "second"}, receiveContents(t, received))
+	assert.Equal(t, []string{"third"}, receiveContents(t, received))
+}
+
+func TestQueueWorker_drainPaused(t *testing.T) {
+	withMockClient(t)
+	worker := &queueWorker{config: QueueConfig{BulkLimit: 3}, queries: &mockQueries{target: &mockTarget{TargetID: "target", Paused: true}}}
+	// the queue of a paused target is kept
+	assert.NoError(t, worker.drain(authz.WithInstanceID(context.Background(), "instance"), "target"))
+}
+
+func TestQueueWorker_drainRemoved(t *testing.T) {
+	mock := withMockClient(t)
+	mock.ExpectExec(regexp.QuoteMeta(discardQueueStmt)).
+		WithArgs("instance", "target").
+		WillReturnResult(driver.RowsAffected(2))
+	mock.ExpectExec(regexp.QuoteMeta(discardQueueCounterStmt)).
+		WithArgs("instance", "target").
+		WillReturnResult(driver.RowsAffected(1))
+
+	worker := &queueWorker{config: QueueConfig{BulkLimit: 3}, queries: &mockQueries{err: zerrors.ThrowNotFound(nil, "QUERY-Tg1Nf", "Errors.Target.NotFound")}}
+	assert.NoError(t, worker.drain(authz.WithInstanceID(context.Background(), "instance"), "target"))
+}
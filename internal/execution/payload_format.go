@@ -0,0 +1,179 @@
+package execution
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const (
+	// xmlRootElement wraps the payload serialized as XML
+	xmlRootElement = "payload"
+	// xmlArrayElement wraps each value of an array serialized as XML
+	xmlArrayElement = "item"
+	// xmlEntryElement is used for keys which are no valid XML names, the key is set as attribute
+	xmlEntryElement = "entry"
+)
+
+// ValidatePayloadFormat checks that the payload format of a target is supported
+func ValidatePayloadFormat(format domain.TargetPayloadFormat) error {
+	if !format.Valid() {
+		return zerrors.ThrowInvalidArgument(nil, "EXEC-Pf4mTz", "Errors.Target.InvalidPayloadFormat")
+	}
+	return nil
+}
+
+// encodePayload serializes the JSON body into the format.
+// JSON is sent unchanged, the other formats require the body to be a JSON object.
+func encodePayload(format domain.TargetPayloadFormat, body []byte) ([]byte, error) {
+	switch format {
+	case domain.TargetPayloadFormatJSON:
+		return body, nil
+	case domain.TargetPayloadFormatForm:
+		return encodeForm(body)
+	case domain.TargetPayloadFormatXML:
+		return encodeXML(body)
+	default:
+		return nil, zerrors.ThrowInvalidArgument(nil, "EXEC-Pf4mTz", "Errors.Target.InvalidPayloadFormat")
+	}
+}
+
+// decodeObject decodes the JSON object of the body, numbers are kept as they are
+func decodeObject(body []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	object := make(map[string]any)
+	if err := decoder.Decode(&object); err != nil {
+		return nil, zerrors.ThrowInternal(err, "EXEC-Pf7sDe", "Errors.Execution.PayloadEncoding")
+	}
+	return object, nil
+}
+
+// encodeForm serializes the fields of the JSON object as form values,
+// strings are set unquoted, null as empty value and all other values as compact JSON
+func encodeForm(body []byte) ([]byte, error) {
+	object := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(body, &object); err != nil {
+		return nil, zerrors.ThrowInternal(err, "EXEC-Pf2fMn", "Errors.Execution.PayloadEncoding")
+	}
+	values := make(url.Values, len(object))
+	for key, raw := range object {
+		value, err := formValue(raw)
+		if err != nil {
+			return nil, zerrors.ThrowInternal(err, "EXEC-Pf2fMn", "Errors.Execution.PayloadEncoding")
+		}
+		values.Set(key, value)
+	}
+	// url.Values sorts the keys, so the same payload always results in the same body
+	return []byte(values.Encode()), nil
+}
+
+func formValue(raw json.RawMessage) (string, error) {
+	switch {
+	case bytes.Equal(raw, []byte("null")):
+		return "", nil
+	case len(raw) > 0 && raw[0] == '"':
+		var value string
+		err := json.Unmarshal(raw, &value)
+		return value, err
+	default:
+		compacted := new(bytes.Buffer)
+		err := json.Compact(compacted, raw)
+		return compacted.String(), err
+	}
+}
+
+// encodeXML serializes the JSON object as elements of the [xmlRootElement].
+// The fields of objects are written ordered by their keys, the values of arrays as [xmlArrayElement]s and null as empty element.
+func encodeXML(body []byte) ([]byte, error) {
+	object, err := decodeObject(body)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(buf)
+	if err := encodeXMLValue(encoder, xml.StartElement{Name: xml.Name{Local: xmlRootElement}}, object); err != nil {
+		return nil, zerrors.ThrowInternal(err, "EXEC-Pf9xWq", "Errors.Execution.PayloadEncoding")
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, zerrors.ThrowInternal(err, "EXEC-Pf9xWq", "Errors.Execution.PayloadEncoding")
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeXMLValue(encoder *xml.Encoder, start xml.StartElement, value any) error {
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case nil:
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			if err := encodeXMLValue(encoder, xmlFieldElement(key), v[key]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if err := encodeXMLValue(encoder, xml.StartElement{Name: xml.Name{Local: xmlArrayElement}}, item); err != nil {
+				return err
+			}
+		}
+	case string:
+		if err := encoder.EncodeToken(xml.CharData(v)); err != nil {
+			return err
+		}
+	case json.Number:
+		if err := encoder.EncodeToken(xml.CharData(v.String())); err != nil {
+			return err
+		}
+	case bool:
+		if err := encoder.EncodeToken(xml.CharData(strconv.FormatBool(v))); err != nil {
+			return err
+		}
+	default:
+		return errors.New("unsupported value")
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+// xmlFieldElement returns the element of a field of an object,
+// keys which are no valid XML names (e.g. @type) are set as attribute of an [xmlEntryElement]
+func xmlFieldElement(key string) xml.StartElement {
+	if isXMLName(key) {
+		return xml.StartElement{Name: xml.Name{Local: key}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: xmlEntryElement},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+}
+
+// isXMLName is a conservative check of the XML name production, namespaces are not allowed
+func isXMLName(name string) bool {
+	first, _ := utf8.DecodeRuneInString(name)
+	if name == "" || !(unicode.IsLetter(first) || first == '_') {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,176 @@
+package execution
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const testPayloadEvent = `{"event":"user.added","userID":"123","sequence":5,"active":true,"org":null,"roles":["admin","viewer"],"details":{"@type":"type.googleapis.com/x","name":"a & b"}}`
+
+func TestValidatePayloadFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  domain.TargetPayloadFormat
+		wantErr bool
+	}{
+		{
+			name:   "json",
+			format: domain.TargetPayloadFormatJSON,
+		},
+		{
+			name:   "form",
+			format: domain.TargetPayloadFormatForm,
+		},
+		{
+			name:   "xml",
+			format: domain.TargetPayloadFormatXML,
+		},
+		{
+			name:    "unsupported",
+			format:  domain.TargetPayloadFormat(99),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePayloadFormat(tt.format)
+			if tt.wantErr {
+				assert.True(t, zerrors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_encodePayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  domain.TargetPayloadFormat
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "json",
+			format: domain.TargetPayloadFormatJSON,
+			body:   testPayloadEvent,
+			want:   testPayloadEvent,
+		},
+		{
+			name:   "form",
+			format: domain.TargetPayloadFormatForm,
+			body:   testPayloadEvent,
+			want: "active=true" +
+				"&details=%7B%22%40type%22%3A%22type.googleapis.com%2Fx%22%2C%22name%22%3A%22a+%26+b%22%7D" +
+				"&event=user.added" +
+				"&org=" +
+				"&roles=%5B%22admin%22%2C%22viewer%22%5D" +
+				"&sequence=5" +
+				"&userID=123",
+		},
+		{
+			name:   "xml",
+			format: domain.TargetPayloadFormatXML,
+			body:   testPayloadEvent,
+			want: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<payload>` +
+				`<active>true</active>` +
+				`<details><entry key="@type">type.googleapis.com/x</entry><name>a &amp; b</name></details>` +
+				`<event>user.added</event>` +
+				`<org></org>` +
+				`<roles><item>admin</item><item>viewer</item></roles>` +
+				`<sequence>5</sequence>` +
+				`<userID>123</userID>` +
+				`</payload>`,
+		},
+		{
+			name:    "form, no object",
+			format:  domain.TargetPayloadFormatForm,
+			body:    `["admin"]`,
+			wantErr: true,
+		},
+		{
+			name:    "xml, no object",
+			format:  domain.TargetPayloadFormatXML,
+			body:    `["admin"]`,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported",
+			format:  domain.TargetPayloadFormat(99),
+			body:    testPayloadEvent,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encodePayload(tt.format, []byte(tt.body))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_call_payloadFormat(t *testing.T) {
+	var (
+		contentType string
+		body        []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name            string
+		format          domain.TargetPayloadFormat
+		wantContentType string
+	}{
+		{
+			name:            "json",
+			format:          domain.TargetPayloadFormatJSON,
+			wantContentType: "application/json",
+		},
+		{
+			name:            "form",
+			format:          domain.TargetPayloadFormatForm,
+			wantContentType: "application/x-www-form-urlencoded",
+		},
+		{
+			name:            "xml",
+			format:          domain.TargetPayloadFormatXML,
+			wantContentType: "application/xml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := call(context.Background(), &mockTarget{
+				Endpoint:      server.URL,
+				Timeout:       time.Second,
+				PayloadFormat: tt.format,
+			}, []byte(testPayloadEvent))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantContentType, contentType)
+
+			want, err := encodePayload(tt.format, []byte(testPayloadEvent))
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(body))
+		})
+	}
+}
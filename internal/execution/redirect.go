@@ -0,0 +1,38 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// MaxTargetRedirects is the highest amount of redirects a target can follow
+const MaxTargetRedirects = 10
+
+var errTooManyRedirects = errors.New("too many redirects")
+
+// ValidateMaxRedirects checks that the amount of redirects a target follows is within [0, MaxTargetRedirects]
+func ValidateMaxRedirects(maxRedirects int) error {
+	if maxRedirects < 0 || maxRedirects > MaxTargetRedirects {
+		return zerrors.ThrowInvalidArgument(nil, "EXEC-Rd1rMx", "Errors.Target.InvalidMaxRedirects")
+	}
+	return nil
+}
+
+// checkRedirect returns the redirect policy of the client of a target.
+// Redirects are not followed by default, the 3xx response is returned as the response of the target.
+// Otherwise at most maxRedirects redirects are followed and the host of each hop is checked against the host policy,
+// the IPs are checked by the dialer of the policy.
+func checkRedirect(maxRedirects int) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if maxRedirects <= 0 {
+			return http.ErrUseLastResponse
+		}
+		if len(via) > maxRedirects {
+			return fmt.Errorf("%w: stopped after %d redirects", errTooManyRedirects, maxRedirects)
+		}
+		return hostPolicy.checkHost(req.URL.Hostname())
+	}
+}
@@ -0,0 +1,122 @@
+package execution
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestValidateMaxRedirects(t *testing.T) {
+	assert.NoError(t, ValidateMaxRedirects(0))
+	assert.NoError(t, ValidateMaxRedirects(MaxTargetRedirects))
+	assert.ErrorIs(t, ValidateMaxRedirects(-1), zerrors.ThrowInvalidArgument(nil, "EXEC-Rd1rMx", "Errors.Target.InvalidMaxRedirects"))
+	assert.ErrorIs(t, ValidateMaxRedirects(MaxTargetRedirects+1), zerrors.ThrowInvalidArgument(nil, "EXEC-Rd1rMx", "Errors.Target.InvalidMaxRedirects"))
+}
+
+// redirectServer redirects /hop/n to /hop/n-1 until /hop/0 is reached, which responds with 200
+func redirectServer(t *testing.T, calls *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		hop, err := strconv.Atoi(r.URL.Path[len("/hop/"):])
+		require.NoError(t, err)
+		if hop == 0 {
+			assert.Equal(t, http.MethodPost, r.Method)
+			return
+		}
+		http.Redirect(w, r, "/hop/"+strconv.Itoa(hop-1), http.StatusTemporaryRedirect)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func Test_send_redirects(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxRedirects   int
+		hops           int
+		wantStatusCode int
+		wantCalls      int32
+		wantErr        func(error) bool
+	}{
+		{
+			name:           "not followed by default",
+			hops:           1,
+			wantStatusCode: http.StatusTemporaryRedirect,
+			wantCalls:      1,
+			wantErr:        zerrors.IsUnknown,
+		},
+		{
+			name:           "followed within max redirects",
+			maxRedirects:   2,
+			hops:           2,
+			wantStatusCode: http.StatusOK,
+			wantCalls:      3,
+		},
+		{
+			name:         "capped at max redirects",
+			maxRedirects: 2,
+			hops:         3,
+			wantCalls:    3,
+			wantErr:      zerrors.IsUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := new(atomic.Int32)
+			server := redirectServer(t, calls)
+
+			resp, err := send(context.Background(), &mockTarget{
+				TargetType:   domain.TargetTypeCall,
+				Endpoint:     server.URL + "/hop/" + strconv.Itoa(tt.hops),
+				Timeout:      time.Minute,
+				MaxRedirects: tt.maxRedirects,
+			}, []byte(`{"request":"body"}`))
+			assert.Equal(t, tt.wantCalls, calls.Load())
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+			if tt.wantStatusCode != 0 {
+				require.NotNil(t, resp)
+				assert.Equal(t, tt.wantStatusCode, resp.statusCode)
+			}
+		})
+	}
+}
+
+func Test_send_redirectHostPolicy(t *testing.T) {
+	redirected := new(atomic.Int32)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected.Add(1)
+	}))
+	defer target.Close()
+	targetURL, err := url.Parse(target.URL)
+	require.NoError(t, err)
+	// the redirect to localhost bypasses the check of the endpoint, which is an IP
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://localhost:"+targetURL.Port(), http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+	setHostPolicy(t, &HostPolicy{DenyList: []string{"localhost"}})
+
+	_, err = send(context.Background(), &mockTarget{
+		TargetType:   domain.TargetTypeCall,
+		Endpoint:     server.URL,
+		Timeout:      time.Minute,
+		MaxRedirects: 3,
+	}, []byte(`{"request":"body"}`))
+	assert.True(t, zerrors.IsPermissionDenied(err), "unexpected error: %v", err)
+	assert.Zero(t, redirected.Load())
+}
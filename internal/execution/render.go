@@ -0,0 +1,64 @@
+package execution
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// EventData is the event which triggers the call of a target
+type EventData struct {
+	AggregateID   string          `json:"aggregateID"`
+	AggregateType string          `json:"aggregateType"`
+	ResourceOwner string          `json:"resourceOwner"`
+	InstanceID    string          `json:"instanceID"`
+	Version       string          `json:"version"`
+	Sequence      uint64          `json:"sequence"`
+	EventType     string          `json:"eventType"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	UserID        string          `json:"userID"`
+	EventPayload  json.RawMessage `json:"eventPayload,omitempty"`
+}
+
+var _ ContextInfoEvent = (*EventData)(nil)
+
+// GetEventType implements [ContextInfoEvent]
+func (e *EventData) GetEventType() string {
+	return e.EventType
+}
+
+var _ ContextInfoURLVariables = (*EventData)(nil)
+
+// GetURLVariables implements [ContextInfoURLVariables]
+func (e *EventData) GetURLVariables() map[string]string {
+	return map[string]string{
+		"aggregateID":   e.AggregateID,
+		"aggregateType": e.AggregateType,
+		"resourceOwner": e.ResourceOwner,
+		"instanceID":    e.InstanceID,
+		"eventType":     e.EventType,
+		"userID":        e.UserID,
+	}
+}
+
+// RenderTargetPayload returns the exact body which would be sent to the target for the event,
+// without calling the target. It is intended for previews and does not require a reachable endpoint.
+// The body is serialized in the payload format of the target or signed as JWT if the target has a JWT delivery.
+func RenderTargetPayload(target Target, event *EventData) ([]byte, error) {
+	if !shouldCall(target, event) {
+		return nil, zerrors.ThrowPreconditionFailed(nil, "EXEC-w3Jq8s", "Errors.Execution.EventTypeNotMatched")
+	}
+	if len(event.EventPayload) > 0 && !json.Valid(event.EventPayload) {
+		return nil, zerrors.ThrowInvalidArgument(nil, "EXEC-Hd6kLt", "Errors.Execution.InvalidEventPayload")
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "EXEC-Za9pQe", "Errors.Internal")
+	}
+	payload, _, err := requestPayload(target, body)
+	if err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
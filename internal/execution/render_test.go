@@ -0,0 +1,191 @@
+package execution
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestRenderTargetPayload(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	type args struct {
+		target Target
+		event  *EventData
+	}
+	tests := []struct {
+		name     string
+		args     args
+		want     string
+		wantForm url.Values
+		wantErr  func(error) bool
+	}{
+		{
+			name: "event with payload",
+			args: args{
+				target: &mockTarget{TargetType: domain.TargetTypeWebhook},
+				event: &EventData{
+					AggregateID:   "user1",
+					AggregateType: "user",
+					ResourceOwner: "org1",
+					InstanceID:    "instance1",
+					Version:       "v2",
+					Sequence:      3,
+					EventType:     "user.human.added",
+					CreatedAt:     createdAt,
+					UserID:        "editor1",
+					EventPayload:  json.RawMessage(`{"userName":"alice"}`),
+				},
+			},
+			want: `{"aggregateID":"user1","aggregateType":"user","resourceOwner":"org1","instanceID":"instance1","version":"v2","sequence":3,"eventType":"user.human.added","createdAt":"2024-05-01T12:00:00Z","userID":"editor1","eventPayload":{"userName":"alice"}}`,
+		},
+		{
+			name: "event with payload, payload excluded",
+			args: args{
+				target: &mockTarget{TargetType: domain.TargetTypeWebhook, ExcludePayload: true},
+				event: &EventData{
+					AggregateID:   "user1",
+					AggregateType: "user",
+					ResourceOwner: "org1",
+					InstanceID:    "instance1",
+					Version:       "v2",
+					Sequence:      3,
+					EventType:     "user.human.added",
+					CreatedAt:     createdAt,
+					UserID:        "editor1",
+					EventPayload:  json.RawMessage(`{"userName":"alice"}`),
+				},
+			},
+			want: `{"aggregateID":"user1","aggregateType":"user","resourceOwner":"org1","instanceID":"instance1","version":"v2","sequence":3,"eventType":"user.human.added","createdAt":"2024-05-01T12:00:00Z","userID":"editor1"}`,
+		},
+		{
+			name: "event without payload",
+			args: args{
+				target: &mockTarget{TargetType: domain.TargetTypeWebhook},
+				event: &EventData{
+					AggregateID:   "session1",
+					AggregateType: "session",
+					ResourceOwner: "instance1",
+					InstanceID:    "instance1",
+					Version:       "v1",
+					Sequence:      1,
+					EventType:     "session.terminated",
+					CreatedAt:     createdAt,
+				},
+			},
+			want: `{"aggregateID":"session1","aggregateType":"session","resourceOwner":"instance1","instanceID":"instance1","version":"v1","sequence":1,"eventType":"session.terminated","createdAt":"2024-05-01T12:00:00Z","userID":""}`,
+		},
+		{
+			name: "event with payload, form payload format",
+			args: args{
+				target: &mockTarget{TargetType: domain.TargetTypeWebhook, PayloadFormat: domain.TargetPayloadFormatForm},
+				event: &EventData{
+					AggregateID:   "user1",
+					AggregateType: "user",
+					EventType:     "user.human.added",
+					CreatedAt:     createdAt,
+					EventPayload:  json.RawMessage(`{"userName":"alice"}`),
+				},
+			},
+			wantForm: url.Values{
+				"aggregateID":   {"user1"},
+				"aggregateType": {"user"},
+				"resourceOwner": {""},
+				"instanceID":    {""},
+				"version":       {""},
+				"sequence":      {"0"},
+				"eventType":     {"user.human.added"},
+				"createdAt":     {"2024-05-01T12:00:00Z"},
+				"userID":        {""},
+				"eventPayload":  {`{"userName":"alice"}`},
+			},
+		},
+		{
+			name: "matched by event type filter",
+			args: args{
+				target: &mockTarget{EventTypeFilter: []string{"user.*"}},
+				event: &EventData{
+					EventType:    "user.human.added",
+					CreatedAt:    createdAt,
+					EventPayload: json.RawMessage(`{}`),
+				},
+			},
+			want: `{"aggregateID":"","aggregateType":"","resourceOwner":"","instanceID":"","version":"","sequence":0,"eventType":"user.human.added","createdAt":"2024-05-01T12:00:00Z","userID":"","eventPayload":{}}`,
+		},
+		{
+			name: "not matched by event type filter",
+			args: args{
+				target: &mockTarget{EventTypeFilter: []string{"user.*"}},
+				event: &EventData{
+					EventType: "org.added",
+				},
+			},
+			wantErr: zerrors.IsPreconditionFailed,
+		},
+		{
+			name: "invalid payload",
+			args: args{
+				target: &mockTarget{},
+				event: &EventData{
+					EventType:    "user.human.added",
+					EventPayload: json.RawMessage(`{"userName":`),
+				},
+			},
+			wantErr: zerrors.IsErrorInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTargetPayload(tt.args.target, tt.args.event)
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantForm != nil {
+				form, err := url.ParseQuery(string(got))
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantForm, form)
+				return
+			}
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestRenderTargetPayload_jwt(t *testing.T) {
+	target := &mockTarget{
+		TargetID:   "target",
+		TargetType: domain.TargetTypeWebhook,
+		JWTDelivery: &domain.TargetJWTDelivery{
+			Algorithm: domain.TargetJWTAlgorithmHS256,
+			Issuer:    "zitadel",
+		},
+		SigningKey: testHS256Key,
+	}
+	event := &EventData{
+		AggregateID:  "user1",
+		EventType:    "user.human.added",
+		CreatedAt:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		EventPayload: json.RawMessage(`{"userName":"alice"}`),
+	}
+	got, err := RenderTargetPayload(target, event)
+	require.NoError(t, err)
+
+	signed, err := jose.ParseSigned(string(got), []jose.SignatureAlgorithm{jose.HS256})
+	require.NoError(t, err)
+	payload, err := signed.Verify([]byte(testHS256Key))
+	require.NoError(t, err)
+	claims := make(map[string]any)
+	require.NoError(t, json.Unmarshal(payload, &claims))
+	assert.Equal(t, "zitadel", claims["iss"])
+	assert.Equal(t, "user.human.added", claims["eventType"])
+	assert.Equal(t, map[string]any{"userName": "alice"}, claims["eventPayload"])
+}
@@ -0,0 +1,48 @@
+package execution
+
+import (
+	"context"
+	"net/http"
+
+	api_trace "go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/http/httpguts"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// ValidateRequestIDHeader checks that the name of the header the request ID is sent in is a valid header name,
+// an empty name does not send the request ID
+func ValidateRequestIDHeader(name string) error {
+	if name == "" {
+		return nil
+	}
+	if !httpguts.ValidHeaderFieldName(name) {
+		return zerrors.ThrowInvalidArgument(nil, "EXEC-Rq1dHd", "Errors.Target.InvalidRequestIDHeader")
+	}
+	return nil
+}
+
+// setRequestIDHeader sets the ID of the request the call originates from in the request ID header of the target.
+// The header is omitted if the target has no request ID header or the context has no request ID.
+func setRequestIDHeader(ctx context.Context, target Target, req *http.Request) {
+	name := target.GetRequestIDHeader()
+	if name == "" {
+		return
+	}
+	if id := requestID(ctx); id != "" {
+		req.Header.Set(name, id)
+	}
+}
+
+// requestID returns the correlation ID of the context, which identifies the originating request,
+// otherwise the ID of the trace, empty if there is neither
+func requestID(ctx context.Context) string {
+	if id := eventstore.CorrelationIDFromContext(ctx); id != "" {
+		return id
+	}
+	if spanContext := api_trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		return spanContext.TraceID().String()
+	}
+	return ""
+}
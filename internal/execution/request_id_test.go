@@ -0,0 +1,72 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	api_trace "go.opentelemetry.io/otel/trace"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestValidateRequestIDHeader(t *testing.T) {
+	assert.NoError(t, ValidateRequestIDHeader(""))
+	assert.NoError(t, ValidateRequestIDHeader("X-Request-Id"))
+	assert.ErrorIs(t, ValidateRequestIDHeader("X Request-Id"), zerrors.ThrowInvalidArgument(nil, "EXEC-Rq1dHd", "Errors.Target.InvalidRequestIDHeader"))
+	assert.ErrorIs(t, ValidateRequestIDHeader("X-Request-Id:"), zerrors.ThrowInvalidArgument(nil, "EXEC-Rq1dHd", "Errors.Target.InvalidRequestIDHeader"))
+}
+
+func Test_newRequest_requestID(t *testing.T) {
+	traceID := api_trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	traceCtx := api_trace.ContextWithSpanContext(context.Background(), api_trace.NewSpanContext(api_trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  api_trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+	}))
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		header     string
+		wantHeader string
+	}{
+		{
+			name:       "correlation id",
+			ctx:        eventstore.WithCorrelationID(traceCtx, "request"),
+			header:     "X-Request-Id",
+			wantHeader: "request",
+		},
+		{
+			name:       "trace id",
+			ctx:        traceCtx,
+			header:     "X-Request-Id",
+			wantHeader: traceID.String(),
+		},
+		{
+			name:   "no request id, omitted",
+			ctx:    context.Background(),
+			header: "X-Request-Id",
+		},
+		{
+			name: "no header, omitted",
+			ctx:  eventstore.WithCorrelationID(context.Background(), "request"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := newRequest(tt.ctx, &mockTarget{
+				Endpoint:        "https://example.com",
+				PayloadFormat:   domain.TargetPayloadFormatJSON,
+				RequestIDHeader: tt.header,
+			}, []byte(`{"request":"body"}`))
+			require.NoError(t, err)
+			if tt.wantHeader == "" {
+				assert.NotContains(t, req.Header, "X-Request-Id")
+				return
+			}
+			assert.Equal(t, tt.wantHeader, req.Header.Get(tt.header))
+		})
+	}
+}
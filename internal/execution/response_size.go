@@ -0,0 +1,42 @@
+package execution
+
+import (
+	"io"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// ValidateResponseSizeLimit checks that the response size limit of a target is not negative and its policy is known
+func ValidateResponseSizeLimit(limit *domain.TargetResponseSizeLimit) error {
+	if limit == nil {
+		return nil
+	}
+	if limit.MaxBytes < 0 || !limit.Policy.Valid() {
+		return zerrors.ThrowInvalidArgument(nil, "EXEC-Rs5zLm", "Errors.Target.InvalidResponseSizeLimit")
+	}
+	return nil
+}
+
+// readResponseBody reads the response body of the target within its response size limit.
+// At most one byte more than the limit is read, so oversized bodies are detected without reading them completely.
+func readResponseBody(target Target, body io.Reader) ([]byte, error) {
+	limit := target.GetResponseSizeLimit()
+	if limit.IsZero() {
+		return io.ReadAll(body)
+	}
+	data, err := io.ReadAll(io.LimitReader(body, limit.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) <= limit.MaxBytes {
+		return data, nil
+	}
+	if limit.Policy == domain.TargetResponseSizePolicyTruncate {
+		logging.WithFields("target", target.GetTargetID(), "limit", limit.MaxBytes).Info("response of target truncated")
+		return data[:limit.MaxBytes], nil
+	}
+	return nil, zerrors.ThrowUnknown(nil, "EXEC-Rs6zEx", "Errors.Execution.ResponseTooLarge")
+}
@@ -0,0 +1,105 @@
+package execution
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestValidateResponseSizeLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   *domain.TargetResponseSizeLimit
+		wantErr bool
+	}{
+		{
+			name: "no limit",
+		},
+		{
+			name:  "truncate",
+			limit: &domain.TargetResponseSizeLimit{MaxBytes: 1024, Policy: domain.TargetResponseSizePolicyTruncate},
+		},
+		{
+			name:    "negative size",
+			limit:   &domain.TargetResponseSizeLimit{MaxBytes: -1},
+			wantErr: true,
+		},
+		{
+			name:    "unknown policy",
+			limit:   &domain.TargetResponseSizeLimit{MaxBytes: 1024, Policy: 99},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateResponseSizeLimit(tt.limit)
+			if tt.wantErr {
+				assert.True(t, zerrors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_call_responseSizeLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		limit    domain.TargetResponseSizeLimit
+		want     []byte
+		wantErr  bool
+	}{
+		{
+			name:     "no limit",
+			response: `{"name":"large response"}`,
+			want:     []byte(`{"name":"large response"}`),
+		},
+		{
+			name:     "within limit",
+			response: `{"name":"ok"}`,
+			limit:    domain.TargetResponseSizeLimit{MaxBytes: 13},
+			want:     []byte(`{"name":"ok"}`),
+		},
+		{
+			name:     "too large, error",
+			response: `{"name":"large response"}`,
+			limit:    domain.TargetResponseSizeLimit{MaxBytes: 13},
+			wantErr:  true,
+		},
+		{
+			name:     "too large, truncate",
+			response: `{"name":"large response"}`,
+			limit:    domain.TargetResponseSizeLimit{MaxBytes: 13, Policy: domain.TargetResponseSizePolicyTruncate},
+			want:     []byte(`{"name":"larg`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			got, err := call(context.Background(), &mockTarget{
+				TargetType:        domain.TargetTypeCall,
+				Endpoint:          server.URL,
+				Timeout:           time.Minute,
+				ResponseSizeLimit: tt.limit,
+			}, []byte(`{}`))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const (
+	// maxRateLimitedAttempts is the maximum count of calls of an async target which responds with 429 Too Many Requests
+	maxRateLimitedAttempts = 3
+	// defaultRateLimitBackoff is the wait before the next call of a rate limited async target without Retry-After header,
+	// it is doubled with every attempt
+	defaultRateLimitBackoff = time.Second
+	// maxRetryAfter is the longest wait for a rate limited async target, the call is not retried if the target asks to wait longer
+	maxRetryAfter = time.Minute
+)
+
+// ErrRateLimited is the cause of the errors returned if a target responded with 429 Too Many Requests.
+type ErrRateLimited struct {
+	// RetryAfter is the duration the target asked to wait before the next call,
+	// zero if the Retry-After header is missing or invalid
+	RetryAfter time.Duration
+}
+
+func (e *ErrRateLimited) Error() string {
+	return fmt.Sprintf("target rate limited, retry after %s", e.RetryAfter)
+}
+
+// throwRateLimited returns the error for a response with 429 Too Many Requests including its Retry-After header
+func throwRateLimited(header http.Header) error {
+	return zerrors.ThrowResourceExhausted(
+		&ErrRateLimited{RetryAfter: parseRetryAfter(header.Get("Retry-After"), time.Now())},
+		"EXEC-Rl4tAf", "Errors.Execution.RateLimited",
+	)
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a delay in seconds or an HTTP date.
+// Invalid values and dates in the past result in zero.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	date, err := http.ParseTime(value)
+	if err != nil || !date.After(now) {
+		return 0
+	}
+	return date.Sub(now)
+}
+
+// retryRateLimited calls the target until it is not rate limited anymore, at most [maxRateLimitedAttempts] times.
+// The next attempt is scheduled after the Retry-After of the target, or after the default backoff if the target did not send one.
+// The response and error of the last attempt are returned.
+func retryRateLimited(ctx context.Context, sleep func(context.Context, time.Duration) error, send func() (*targetResponse, error)) (*targetResponse, error) {
+	backoff := defaultRateLimitBackoff
+	for attempt := 1; ; attempt++ {
+		resp, err := send()
+		var rateLimited *ErrRateLimited
+		if err == nil || attempt >= maxRateLimitedAttempts || !errors.As(err, &rateLimited) {
+			return resp, err
+		}
+		wait := backoff
+		if rateLimited.RetryAfter > 0 {
+			wait = rateLimited.RetryAfter
+		}
+		if wait > maxRetryAfter {
+			return resp, err
+		}
+		if sleepErr := sleep(ctx, wait); sleepErr != nil {
+			return resp, err
+		}
+		backoff *= 2
+	}
+}
+
+// sleepContext waits for the duration, it returns early with the error of the context if it is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,206 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{
+			name: "missing",
+		},
+		{
+			name:  "seconds",
+			value: "120",
+			want:  2 * time.Minute,
+		},
+		{
+			name:  "negative seconds",
+			value: "-1",
+		},
+		{
+			name:  "date",
+			value: "Wed, 01 May 2024 12:00:30 GMT",
+			want:  30 * time.Second,
+		},
+		{
+			name:  "date in the past",
+			value: "Wed, 01 May 2024 11:59:00 GMT",
+		},
+		{
+			name:  "invalid",
+			value: "soon",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseRetryAfter(tt.value, now))
+		})
+	}
+}
+
+// rateLimitedServer responds with 429 Too Many Requests and the Retry-After of the header func for the first calls, then with 200 OK
+func rateLimitedServer(t *testing.T, rateLimitedCalls int32, retryAfter func() string) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) > rateLimitedCalls {
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		if value := retryAfter(); value != "" {
+			w.Header().Set("Retry-After", value)
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestCallTarget_rateLimited(t *testing.T) {
+	tests := []struct {
+		name           string
+		retryAfter     func() string
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:           "seconds",
+			retryAfter:     func() string { return "2" },
+			wantRetryAfter: 2 * time.Second,
+		},
+		{
+			name: "date",
+			retryAfter: func() string {
+				return time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+			},
+			wantRetryAfter: time.Minute,
+		},
+		{
+			name:       "without retry after",
+			retryAfter: func() string { return "" },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := rateLimitedServer(t, 1, tt.retryAfter)
+
+			_, err := CallTarget(context.Background(), &mockTarget{
+				TargetType: domain.TargetTypeCall,
+				Endpoint:   server.URL,
+				Timeout:    time.Minute,
+			}, newMockContextInfoRequest("content"))
+
+			assert.True(t, zerrors.IsResourceExhausted(err), "unexpected error: %v", err)
+			var rateLimited *ErrRateLimited
+			require.True(t, errors.As(err, &rateLimited))
+			// the HTTP date has a precision of seconds
+			assert.InDelta(t, tt.wantRetryAfter, rateLimited.RetryAfter, float64(time.Second))
+			// sync targets are not retried
+			assert.Equal(t, int32(1), calls.Load())
+		})
+	}
+}
+
+func Test_asyncDispatcher_rateLimited(t *testing.T) {
+	tests := []struct {
+		name             string
+		rateLimitedCalls int32
+		retryAfter       func() string
+		wantSleeps       []time.Duration
+		wantCalls        int32
+		wantRateLimited  bool
+	}{
+		{
+			name:             "retry after seconds",
+			rateLimitedCalls: 1,
+			retryAfter:       func() string { return "7" },
+			wantSleeps:       []time.Duration{7 * time.Second},
+			wantCalls:        2,
+		},
+		{
+			name:             "retry after date",
+			rateLimitedCalls: 1,
+			retryAfter: func() string {
+				return time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+			},
+			wantSleeps: []time.Duration{30 * time.Second},
+			wantCalls:  2,
+		},
+		{
+			name:             "default backoff without retry after",
+			rateLimitedCalls: 2,
+			retryAfter:       func() string { return "" },
+			wantSleeps:       []time.Duration{defaultRateLimitBackoff, 2 * defaultRateLimitBackoff},
+			wantCalls:        3,
+		},
+		{
+			name:             "max attempts",
+			rateLimitedCalls: maxRateLimitedAttempts,
+			retryAfter:       func() string { return "1" },
+			wantSleeps:       []time.Duration{time.Second, time.Second},
+			wantCalls:        maxRateLimitedAttempts,
+			wantRateLimited:  true,
+		},
+		{
+			name:             "retry after too long",
+			rateLimitedCalls: 1,
+			retryAfter:       func() string { return "3600" },
+			wantCalls:        1,
+			wantRateLimited:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := rateLimitedServer(t, tt.rateLimitedCalls, tt.retryAfter)
+			var sleeps []time.Duration
+			var results []*TargetExecutionResult
+			dispatcher := newAsyncDispatcher(WithAsyncPoolSize(context.Background(), 1), func(result *TargetExecutionResult) {
+				results = append(results, result)
+			})
+			dispatcher.sleep = func(_ context.Context, d time.Duration) error {
+				sleeps = append(sleeps, d)
+				return nil
+			}
+
+			dispatcher.dispatch(context.Background(), &mockTarget{
+				TargetID:   "target",
+				TargetType: domain.TargetTypeAsync,
+				Endpoint:   server.URL,
+				Timeout:    time.Minute,
+			}, newMockContextInfoRequest("content"))
+			dispatcher.close()
+
+			require.Len(t, results, 1)
+			assert.Equal(t, tt.wantCalls, calls.Load())
+			require.Len(t, sleeps, len(tt.wantSleeps))
+			for i, want := range tt.wantSleeps {
+				// the HTTP date has a precision of seconds
+				assert.InDelta(t, want, sleeps[i], float64(time.Second))
+			}
+			if tt.wantRateLimited {
+				var rateLimited *ErrRateLimited
+				assert.True(t, errors.As(results[0].Err, &rateLimited), "unexpected error: %v", results[0].Err)
+				assert.Equal(t, http.StatusTooManyRequests, results[0].StatusCode)
+				return
+			}
+			assert.NoError(t, results[0].Err)
+			assert.Equal(t, http.StatusOK, results[0].StatusCode)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package execution
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const (
+	// SignatureHeader holds the signature of the requests to targets with a signing key
+	SignatureHeader = "ZITADEL-Signature"
+	// minSigningKeyLength is the minimum length of signing keys, which must be at least as long as the hash
+	minSigningKeyLength = sha256.Size
+)
+
+// ValidateSigningKey checks that the key the requests are signed with is long enough,
+// an empty key does not sign the requests
+func ValidateSigningKey(key string) error {
+	if key == "" {
+		return nil
+	}
+	if len(key) < minSigningKeyLength {
+		return zerrors.ThrowInvalidArgument(nil, "EXEC-Sg1Key", "Errors.Target.InvalidSigningKey")
+	}
+	return nil
+}
+
+// setSignatureHeader signs the payload of the request with the primary signing key of the target.
+// During the grace window of the secondary key the payload is additionally signed with the secondary key,
+// so the target can roll over to it before it is promoted. After the window only the primary key signs.
+// The header is omitted if the target has no signing key.
+func setSignatureHeader(target Target, req *http.Request, payload []byte, now time.Time) {
+	key := target.GetSigningKey()
+	if key == "" {
+		return
+	}
+	signature := computeSignature(key, payload, now)
+	if secondary, validUntil := target.GetSecondarySigningKey(); secondary != "" && now.Before(validUntil) {
+		signature += ",v1=" + computeHMAC(secondary, payload, now)
+	}
+	req.Header.Set(SignatureHeader, signature)
+}
+
+// computeSignature returns the signature in the format t=<unix timestamp>,v1=<hex encoded HMAC SHA-256>.
+// The timestamp is signed together with the payload, so the target can reject replayed requests.
+func computeSignature(key string, payload []byte, now time.Time) string {
+	return "t=" + strconv.FormatInt(now.Unix(), 10) + ",v1=" + computeHMAC(key, payload, now)
+}
+
+// computeHMAC returns the hex encoded HMAC SHA-256 of the timestamp and the payload
+func computeHMAC(key string, payload []byte, now time.Time) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(strconv.FormatInt(now.Unix(), 10) + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,111 @@
+package execution
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestValidateSigningKey(t *testing.T) {
+	assert.NoError(t, ValidateSigningKey(""))
+	assert.NoError(t, ValidateSigningKey(strings.Repeat("k", minSigningKeyLength)))
+	assert.ErrorIs(t, ValidateSigningKey("short"), zerrors.ThrowInvalidArgument(nil, "EXEC-Sg1Key", "Errors.Target.InvalidSigningKey"))
+}
+
+func Test_computeSignature(t *testing.T) {
+	now := time.Unix(1704067200, 0)
+	mac := hmac.New(sha256.New, []byte("key"))
+	mac.Write([]byte(`1704067200.{"request":"body"}`))
+
+	assert.Equal(t,
+		"t=1704067200,v1="+hex.EncodeToString(mac.Sum(nil)),
+		computeSignature("key", []byte(`{"request":"body"}`), now),
+	)
+	assert.NotEqual(t,
+		computeSignature("key", []byte(`{"request":"body"}`), now),
+		computeSignature("other", []byte(`{"request":"body"}`), now),
+	)
+	assert.NotEqual(t,
+		computeSignature("key", []byte(`{"request":"body"}`), now),
+		computeSignature("key", []byte(`{"request":"body"}`), now.Add(time.Second)),
+	)
+}
+
+func Test_newRequest_signature(t *testing.T) {
+	tests := []struct {
+		name       string
+		signingKey string
+	}{
+		{
+			name:       "signing key, signed",
+			signingKey: "primary-signing-key-0123456789abcdef",
+		},
+		{
+			name: "no signing key, not signed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := newRequest(context.Background(), &mockTarget{
+				Endpoint:      "https://example.com",
+				PayloadFormat: domain.TargetPayloadFormatJSON,
+				SigningKey:    tt.signingKey,
+			}, []byte(`{"request":"body"}`))
+			require.NoError(t, err)
+			if tt.signingKey == "" {
+				assert.NotContains(t, req.Header, SignatureHeader)
+				return
+			}
+			timestamp, signature, ok := strings.Cut(strings.TrimPrefix(req.Header.Get(SignatureHeader), "t="), ",v1=")
+			require.True(t, ok)
+			mac := hmac.New(sha256.New, []byte(tt.signingKey))
+			mac.Write([]byte(timestamp + `.{"request":"body"}`))
+			assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), signature)
+		})
+	}
+}
+
+func Test_setSignatureHeader_rotation(t *testing.T) {
+	now := time.Unix(1704067200, 0)
+	payload := []byte(`{"request":"body"}`)
+	primary := computeSignature("primary-signing-key-0123456789abcdef", payload, now)
+	tests := []struct {
+		name       string
+		validUntil time.Time
+		want       string
+	}{
+		{
+			name:       "within grace window, signed with both keys",
+			validUntil: now.Add(time.Hour),
+			want:       primary + ",v1=" + computeHMAC("secondary-signing-key-0123456789abcdef", payload, now),
+		},
+		{
+			name:       "grace window ended, signed with primary key",
+			validUntil: now,
+			want:       primary,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "https://example.com", nil)
+			require.NoError(t, err)
+			setSignatureHeader(&mockTarget{
+				SigningKey:          "primary-signing-key-0123456789abcdef",
+				SecondarySigningKey: "secondary-signing-key-0123456789abcdef",
+				SecondaryValidUntil: tt.validUntil,
+			}, req, payload, now)
+			assert.Equal(t, tt.want, req.Header.Get(SignatureHeader))
+		})
+	}
+}
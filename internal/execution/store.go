@@ -0,0 +1,125 @@
+package execution
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/logging"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/id"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// The executions of the targets and the payloads queued for paused targets are stored in tables outside of the projections,
+// they are not derived from events, so they are not removed by a reset of the projections.
+const (
+	TargetExecutionTable         = "execution.target_executions"
+	TargetExecutionInstanceIDCol = "instance_id"
+	TargetExecutionTargetIDCol   = "target_id"
+	TargetExecutionIDCol         = "id"
+	TargetExecutionDateCol       = "execution_date"
+	TargetExecutionStatusCodeCol = "status_code"
+	TargetExecutionLatencyCol    = "latency"
+	TargetExecutionErrorCol      = "error"
+	TargetExecutionSucceededCol  = "succeeded"
+
+	TargetDeliveryTable         = "execution.target_deliveries"
+	TargetDeliveryInstanceIDCol = "instance_id"
+	TargetDeliveryTargetIDCol   = "target_id"
+
+	TargetQueueTable         = "execution.target_queues"
+	TargetQueueInstanceIDCol = "instance_id"
+	TargetQueueTargetIDCol   = "target_id"
+	TargetQueueDroppedCol    = "dropped"
+
+	// TargetExecutionRetention is the time the executions of a target are kept
+	TargetExecutionRetention = 30 * 24 * time.Hour
+)
+
+const (
+	insertTargetExecutionStmt = `INSERT INTO execution.target_executions` +
+		` (instance_id, target_id, id, execution_date, status_code, latency, error, succeeded)` +
+		` VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	pruneTargetExecutionsStmt = `DELETE FROM execution.target_executions WHERE execution_date < $1`
+)
+
+// client stores the executions of the targets and the payloads queued for paused targets (see [Start]),
+// nothing is stored if it is not set
+var client *database.DB
+
+// TargetExecution is the recorded outcome of a call of a target
+type TargetExecution struct {
+	InstanceID string
+	TargetID   string
+	ID         string
+	// ExecutionDate is the time the call finished
+	ExecutionDate time.Time
+	// StatusCode is the HTTP status the target responded with, 0 if it did not respond
+	StatusCode int
+	Latency    time.Duration
+	// Error is the message of the error of a failed call, empty if the call succeeded
+	Error string
+	// Succeeded is true if the target responded according to its success criteria
+	Succeeded bool
+}
+
+// newTargetExecution returns the execution of the result for the instance of the context
+func newTargetExecution(ctx context.Context, result *TargetExecutionResult) (*TargetExecution, error) {
+	executionID, err := id.SonyFlakeGenerator().Next()
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "EXEC-Te3nId", "Errors.Internal")
+	}
+	execution := &TargetExecution{
+		InstanceID:    authz.GetInstance(ctx).InstanceID(),
+		TargetID:      result.TargetID,
+		ID:            executionID,
+		ExecutionDate: time.Now(),
+		StatusCode:    result.StatusCode,
+		Latency:       result.Latency,
+		Succeeded:     result.Succeeded,
+	}
+	if result.Err != nil {
+		execution.Error = result.Err.Error()
+	}
+	return execution, nil
+}
+
+// recordTargetExecution stores the outcome of the call of the target for the instance of the context,
+// failures are only logged, as the call already happened
+func recordTargetExecution(ctx context.Context, result *TargetExecutionResult) {
+	if client == nil {
+		return
+	}
+	execution, err := newTargetExecution(ctx, result)
+	if err == nil {
+		err = storeTargetExecution(ctx, execution)
+	}
+	logging.WithFields("target", result.TargetID).OnError(err).Warn("unable to record execution of target")
+}
+
+func storeTargetExecution(ctx context.Context, execution *TargetExecution) error {
+	_, err := client.ExecContext(ctx, insertTargetExecutionStmt,
+		execution.InstanceID,
+		execution.TargetID,
+		execution.ID,
+		execution.ExecutionDate,
+		execution.StatusCode,
+		execution.Latency,
+		execution.Error,
+		execution.Succeeded,
+	)
+	if err != nil {
+		return zerrors.ThrowInternal(err, "EXEC-Te4rQw", "Errors.Internal")
+	}
+	return nil
+}
+
+// pruneTargetExecutions removes the executions of all instances recorded before the time
+func pruneTargetExecutions(ctx context.Context, before time.Time) error {
+	if _, err := client.ExecContext(ctx, pruneTargetExecutionsStmt, before); err != nil {
+		return zerrors.ThrowInternal(err, "EXEC-Te5pRn", "Errors.Internal")
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package execution
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestNewTargetExecution(t *testing.T) {
+	tests := []struct {
+		name          string
+		criteria      *domain.TargetSuccessCriteria
+		statusCode    int
+		body          []byte
+		wantSucceeded bool
+	}{
+		{
+			name:          "2xx without criteria",
+			statusCode:    http.StatusOK,
+			wantSucceeded: true,
+		},
+		{
+			name:       "non 2xx without criteria",
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:          "status of criteria",
+			criteria:      &domain.TargetSuccessCriteria{StatusCodes: []int{http.StatusAccepted}},
+			statusCode:    http.StatusAccepted,
+			wantSucceeded: true,
+		},
+		{
+			name:       "status not in criteria",
+			criteria:   &domain.TargetSuccessCriteria{StatusCodes: []int{http.StatusAccepted}},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "body not matching criteria",
+			criteria:   &domain.TargetSuccessCriteria{BodyPath: "status", BodyValue: "ok"},
+			statusCode: http.StatusOK,
+			body:       []byte(`{"status":"failed"}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write(tt.body)
+			}))
+			defer server.Close()
+			target := &mockTarget{
+				TargetID:        "target",
+				TargetType:      domain.TargetTypeCall,
+				Endpoint:        server.URL,
+				Timeout:         time.Minute,
+				SuccessCriteria: tt.criteria,
+			}
+
+			resp, err := send(context.Background(), target, nil)
+			result := &TargetExecutionResult{TargetID: target.TargetID}
+			result.setResponse(resp, err)
+
+			got, err := newTargetExecution(authz.WithInstanceID(context.Background(), "instance"), result)
+			require.NoError(t, err)
+			assert.Equal(t, "instance", got.InstanceID)
+			assert.Equal(t, tt.statusCode, got.StatusCode)
+			assert.Equal(t, tt.wantSucceeded, got.Succeeded)
+			assert.Equal(t, !tt.wantSucceeded, got.Error != "")
+		})
+	}
+}
+
+func TestNewTargetExecution_unreachable(t *testing.T) {
+	result := &TargetExecutionResult{TargetID: "target"}
+	result.setResponse(nil, zerrors.ThrowUnavailable(nil, "EXEC-test", "Errors.Execution.Failed"))
+
+	got, err := newTargetExecution(authz.WithInstanceID(context.Background(), "instance"), result)
+	require.NoError(t, err)
+	assert.Zero(t, got.StatusCode)
+	assert.False(t, got.Succeeded)
+	assert.NotEmpty(t, got.Error)
+}
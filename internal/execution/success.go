@@ -0,0 +1,143 @@
+package execution
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// ValidateSuccessCriteria checks that the status codes are valid HTTP status codes
+// and the body path is a supported JSONPath
+func ValidateSuccessCriteria(criteria *domain.TargetSuccessCriteria) error {
+	if criteria == nil {
+		return nil
+	}
+	for _, code := range criteria.StatusCodes {
+		if code < 100 || code > 599 {
+			return zerrors.ThrowInvalidArgument(nil, "EXEC-3xk0Sd", "Errors.Target.InvalidSuccessStatusCode")
+		}
+	}
+	if criteria.BodyPath == "" {
+		return nil
+	}
+	if _, err := parseJSONPath(criteria.BodyPath); err != nil {
+		return err
+	}
+	return nil
+}
+
+// isSuccess checks the response against the success criteria of the target,
+// without criteria every 2xx status code is a success
+func isSuccess(criteria *domain.TargetSuccessCriteria, statusCode int, body []byte) bool {
+	if criteria == nil {
+		return statusCode >= 200 && statusCode <= 299
+	}
+	if !isSuccessStatusCode(criteria.StatusCodes, statusCode) {
+		return false
+	}
+	if criteria.BodyPath == "" {
+		return true
+	}
+	value, ok := lookupJSONPath(criteria.BodyPath, body)
+	return ok && value == criteria.BodyValue
+}
+
+func isSuccessStatusCode(codes []int, statusCode int) bool {
+	if len(codes) == 0 {
+		return statusCode >= 200 && statusCode <= 299
+	}
+	for _, code := range codes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonPathSegment is either a key of an object or an index of an array
+type jsonPathSegment struct {
+	key   string
+	index int
+}
+
+// parseJSONPath parses the supported subset of JSONPath:
+// the root $ followed by object keys (.key) and array indexes ([0])
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, zerrors.ThrowInvalidArgument(nil, "EXEC-Kq0vRn", "Errors.Target.InvalidJSONPath")
+	}
+	segments := make([]jsonPathSegment, 0)
+	rest := path[1:]
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, zerrors.ThrowInvalidArgument(nil, "EXEC-7VfzLp", "Errors.Target.InvalidJSONPath")
+			}
+			segments = append(segments, jsonPathSegment{key: key, index: -1})
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, zerrors.ThrowInvalidArgument(nil, "EXEC-bJ4sWm", "Errors.Target.InvalidJSONPath")
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, zerrors.ThrowInvalidArgument(err, "EXEC-Yp2cUo", "Errors.Target.InvalidJSONPath")
+			}
+			segments = append(segments, jsonPathSegment{index: index})
+			rest = rest[end+1:]
+		default:
+			return nil, zerrors.ThrowInvalidArgument(nil, "EXEC-Fh8dNe", "Errors.Target.InvalidJSONPath")
+		}
+	}
+	return segments, nil
+}
+
+// lookupJSONPath returns the value at the path in the JSON document,
+// strings are returned unquoted, all other values as their JSON representation
+func lookupJSONPath(path string, document []byte) (string, bool) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return "", false
+	}
+	var value any
+	if err := json.Unmarshal(document, &value); err != nil {
+		return "", false
+	}
+	for _, segment := range segments {
+		switch typed := value.(type) {
+		case map[string]any:
+			if segment.index >= 0 {
+				return "", false
+			}
+			var ok bool
+			if value, ok = typed[segment.key]; !ok {
+				return "", false
+			}
+		case []any:
+			if segment.index < 0 || segment.index >= len(typed) {
+				return "", false
+			}
+			value = typed[segment.index]
+		default:
+			return "", false
+		}
+	}
+	if s, ok := value.(string); ok {
+		return s, true
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+	return string(encoded), true
+}
@@ -0,0 +1,210 @@
+package execution
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+)
+
+func TestValidateSuccessCriteria(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria *domain.TargetSuccessCriteria
+		wantErr  bool
+	}{
+		{
+			name:     "no criteria",
+			criteria: nil,
+		},
+		{
+			name: "valid",
+			criteria: &domain.TargetSuccessCriteria{
+				StatusCodes: []int{200, 202},
+				BodyPath:    "$.result[0].status",
+				BodyValue:   "ok",
+			},
+		},
+		{
+			name: "invalid status code",
+			criteria: &domain.TargetSuccessCriteria{
+				StatusCodes: []int{200, 999},
+			},
+			wantErr: true,
+		},
+		{
+			name: "path without root",
+			criteria: &domain.TargetSuccessCriteria{
+				BodyPath: "result.status",
+			},
+			wantErr: true,
+		},
+		{
+			name: "path with empty key",
+			criteria: &domain.TargetSuccessCriteria{
+				BodyPath: "$.result..status",
+			},
+			wantErr: true,
+		},
+		{
+			name: "path with invalid index",
+			criteria: &domain.TargetSuccessCriteria{
+				BodyPath: "$.result[a]",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSuccessCriteria(tt.criteria)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_isSuccess(t *testing.T) {
+	type args struct {
+		criteria   *domain.TargetSuccessCriteria
+		statusCode int
+		body       []byte
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "default 2xx",
+			args: args{
+				statusCode: http.StatusAccepted,
+			},
+			want: true,
+		},
+		{
+			name: "default not 2xx",
+			args: args{
+				statusCode: http.StatusBadRequest,
+			},
+			want: false,
+		},
+		{
+			name: "custom status codes",
+			args: args{
+				criteria: &domain.TargetSuccessCriteria{
+					StatusCodes: []int{http.StatusOK, http.StatusConflict},
+				},
+				statusCode: http.StatusConflict,
+			},
+			want: true,
+		},
+		{
+			name: "custom status codes, not contained",
+			args: args{
+				criteria: &domain.TargetSuccessCriteria{
+					StatusCodes: []int{http.StatusOK},
+				},
+				statusCode: http.StatusAccepted,
+			},
+			want: false,
+		},
+		{
+			name: "body assertion",
+			args: args{
+				criteria: &domain.TargetSuccessCriteria{
+					BodyPath:  "$.result[1].status",
+					BodyValue: "ok",
+				},
+				statusCode: http.StatusOK,
+				body:       []byte(`{"result":[{"status":"error"},{"status":"ok"}]}`),
+			},
+			want: true,
+		},
+		{
+			name: "body assertion, non string value",
+			args: args{
+				criteria: &domain.TargetSuccessCriteria{
+					BodyPath:  "$.success",
+					BodyValue: "true",
+				},
+				statusCode: http.StatusOK,
+				body:       []byte(`{"success":true}`),
+			},
+			want: true,
+		},
+		{
+			name: "body assertion, logical error in 200 response",
+			args: args{
+				criteria: &domain.TargetSuccessCriteria{
+					BodyPath:  "$.status",
+					BodyValue: "ok",
+				},
+				statusCode: http.StatusOK,
+				body:       []byte(`{"status":"error"}`),
+			},
+			want: false,
+		},
+		{
+			name: "body assertion, path not found",
+			args: args{
+				criteria: &domain.TargetSuccessCriteria{
+					BodyPath:  "$.result.status",
+					BodyValue: "ok",
+				},
+				statusCode: http.StatusOK,
+				body:       []byte(`{"status":"ok"}`),
+			},
+			want: false,
+		},
+		{
+			name: "body assertion, no json",
+			args: args{
+				criteria: &domain.TargetSuccessCriteria{
+					BodyPath:  "$.status",
+					BodyValue: "ok",
+				},
+				statusCode: http.StatusOK,
+				body:       []byte(`ok`),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isSuccess(tt.args.criteria, tt.args.statusCode, tt.args.body))
+		})
+	}
+}
+
+func TestCallTarget_successCriteria(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := io.WriteString(w, `{"status":"error"}`)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	target := &mockTarget{
+		TargetType: domain.TargetTypeCall,
+		Endpoint:   server.URL,
+		Timeout:    time.Minute,
+	}
+	_, err := CallTarget(context.Background(), target, newMockContextInfoRequest("content"))
+	assert.NoError(t, err)
+
+	target.SuccessCriteria = &domain.TargetSuccessCriteria{
+		BodyPath:  "$.status",
+		BodyValue: "ok",
+	}
+	_, err = CallTarget(context.Background(), target, newMockContextInfoRequest("content"))
+	assert.Error(t, err)
+}
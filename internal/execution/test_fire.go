@@ -0,0 +1,143 @@
+package execution
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const (
+	// TestHeader marks requests sent by [TestFireTarget], so downstreams are able to ignore them
+	TestHeader = "X-Zitadel-Test"
+	// responseSnippetLength is the maximum amount of bytes of the response body returned in a [TargetExecutionResult]
+	responseSnippetLength = 1024
+)
+
+// TargetExecutionResult describes the outcome of a single call to a target
+type TargetExecutionResult struct {
+	TargetID string
+	// Endpoint is the endpoint which served the request, one of the failover endpoints if the endpoint of the target failed
+	Endpoint   string
+	StatusCode int
+	Latency    time.Duration
+	// ResponseSnippet contains the beginning of the response body
+	ResponseSnippet []byte
+	// Err is set if the call of the target failed
+	Err error
+	// Succeeded is set if the target responded without error and according to its success criteria
+	Succeeded bool
+	// ContentType is the content type of the response
+	ContentType string
+	// ContentTypeMismatch is set if the content type of the response differs from the expected content type of the target
+	ContentTypeMismatch bool
+	// Mirrors contains the outcome of the calls to the mirror endpoints of the target in their order
+	Mirrors []*MirrorExecutionResult
+	// BatchSize is the amount of payloads delivered in the call of an async target
+	BatchSize int
+}
+
+// TestFireTarget sends the sample payload to the target to verify its configuration before going live.
+// The request is marked with the [TestHeader] and the result is returned independent of the status code.
+// No state is changed and the call does not count towards any quota.
+func TestFireTarget(ctx context.Context, target Target, samplePayload []byte) (_ *TargetExecutionResult, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer span.EndWithError(err)
+
+	ctx, cancel := context.WithTimeout(ctx, target.GetTimeout())
+	defer cancel()
+
+	// the sample payload does not provide variables, so templated endpoints can not be resolved
+	target, err = withRenderedEndpoint(target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req, err := newRequest(ctx, target, samplePayload)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(TestHeader, "true")
+	if err := authorize(ctx, target, req); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := doRequest(target, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	snippet, err := io.ReadAll(io.LimitReader(resp.Body, responseSnippetLength))
+	if err != nil {
+		return nil, err
+	}
+	result := &TargetExecutionResult{
+		TargetID:        target.GetTargetID(),
+		StatusCode:      resp.StatusCode,
+		Latency:         time.Since(start),
+		ResponseSnippet: snippet,
+		Succeeded:       isSuccess(target.GetSuccessCriteria(), resp.StatusCode, snippet),
+	}
+	result.setContentType(target, resp.Header.Get("Content-Type"))
+	return result, nil
+}
+
+// setResponse records the outcome of the call, the call succeeded if the target responded without error
+// and its response matched the success criteria of the target
+func (r *TargetExecutionResult) setResponse(resp *targetResponse, err error) {
+	r.Err = err
+	if resp == nil {
+		return
+	}
+	r.StatusCode = resp.statusCode
+	r.Succeeded = err == nil && resp.succeeded
+}
+
+// setContentType records the content type of the response and if it differs from the one expected by the target
+func (r *TargetExecutionResult) setContentType(target Target, contentType string) {
+	r.ContentType = contentType
+	r.ContentTypeMismatch = !MatchesResponseContentType(target.GetResponseContentType(), contentType)
+}
+
+// verificationPayload is sent to the target by [VerifyTarget]
+var verificationPayload = []byte("{}")
+
+// VerifyTarget calls the target with an empty payload marked by the [TestHeader]
+// and records the outcome as execution of the target, the latest one is the last verification of the target.
+// The verification fails if the target does not respond with a status between 200 and 299 or not according to its success criteria.
+// An unreachable or failing target is not returned as error, the error of the call is part of the recorded execution.
+func VerifyTarget(ctx context.Context, target Target) (_ *TargetExecution, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer span.EndWithError(err)
+
+	start := time.Now()
+	result, err := TestFireTarget(ctx, target, verificationPayload)
+	if err != nil {
+		result = &TargetExecutionResult{
+			TargetID: target.GetTargetID(),
+			Latency:  time.Since(start),
+			Err:      err,
+		}
+	} else if result.Succeeded = isVerified(target, result); !result.Succeeded {
+		result.Err = zerrors.ThrowUnknown(nil, "EXEC-dra6yamk98", "Errors.Execution.Failed")
+	}
+	execution, err := newTargetExecution(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return execution, nil
+	}
+	if err = storeTargetExecution(ctx, execution); err != nil {
+		return nil, err
+	}
+	return execution, nil
+}
+
+func isVerified(target Target, result *TargetExecutionResult) bool {
+	return result.StatusCode >= 200 && result.StatusCode <= 299 &&
+		isSuccess(target.GetSuccessCriteria(), result.StatusCode, result.ResponseSnippet)
+}
@@ -0,0 +1,216 @@
+package execution
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/database"
+	db_mock "github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/domain"
+)
+
+func TestTestFireTarget(t *testing.T) {
+	type args struct {
+		target     *mockTarget
+		payload    []byte
+		statusCode int
+		respBody   string
+	}
+	type res struct {
+		statusCode int
+		snippet    []byte
+		succeeded  bool
+	}
+	tests := []struct {
+		name string
+		args args
+		res  res
+	}{
+		{
+			"ok",
+			args{
+				target: &mockTarget{
+					TargetID:   "target",
+					TargetType: domain.TargetTypeWebhook,
+					Timeout:    time.Minute,
+				},
+				payload:    []byte("{\"request\":\"sample\"}"),
+				statusCode: http.StatusOK,
+				respBody:   "{\"response\":\"sample\"}",
+			},
+			res{
+				statusCode: http.StatusOK,
+				snippet:    []byte("{\"response\":\"sample\"}"),
+				succeeded:  true,
+			},
+		},
+		{
+			"error status is returned as result",
+			args{
+				target: &mockTarget{
+					TargetID:   "target",
+					TargetType: domain.TargetTypeCall,
+					Timeout:    time.Minute,
+				},
+				payload:    []byte("{\"request\":\"sample\"}"),
+				statusCode: http.StatusBadRequest,
+				respBody:   "bad request",
+			},
+			res{
+				statusCode: http.StatusBadRequest,
+				snippet:    []byte("bad request"),
+			},
+		},
+		{
+			"response is truncated",
+			args{
+				target: &mockTarget{
+					TargetID:   "target",
+					TargetType: domain.TargetTypeCall,
+					Timeout:    time.Minute,
+				},
+				payload:    []byte("{\"request\":\"sample\"}"),
+				statusCode: http.StatusOK,
+				respBody:   strings.Repeat("a", responseSnippetLength+1),
+			},
+			res{
+				statusCode: http.StatusOK,
+				snippet:    []byte(strings.Repeat("a", responseSnippetLength)),
+				succeeded:  true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				checkRequest(t, r, http.MethodPost, tt.args.payload)
+				assert.Equal(t, "true", r.Header.Get(TestHeader))
+
+				w.WriteHeader(tt.args.statusCode)
+				_, err := io.WriteString(w, tt.args.respBody)
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+			tt.args.target.Endpoint = server.URL
+
+			got, err := TestFireTarget(context.Background(), tt.args.target, tt.args.payload)
+			require.NoError(t, err)
+			assert.Equal(t, tt.args.target.TargetID, got.TargetID)
+			assert.Equal(t, tt.res.statusCode, got.StatusCode)
+			assert.Equal(t, tt.res.snippet, got.ResponseSnippet)
+			assert.Equal(t, tt.res.succeeded, got.Succeeded)
+			assert.Positive(t, got.Latency)
+		})
+	}
+}
+
+func TestTestFireTarget_unreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	_, err := TestFireTarget(context.Background(), &mockTarget{
+		Endpoint: server.URL,
+		Timeout:  time.Second,
+	}, nil)
+	assert.Error(t, err)
+}
+
+func TestVerifyTarget(t *testing.T) {
+	unreachable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	unreachable.Close()
+
+	tests := []struct {
+		name            string
+		statusCode      int
+		respBody        string
+		successCriteria *domain.TargetSuccessCriteria
+		unreachable     bool
+		wantSucceeded   bool
+	}{
+		{
+			name:          "verified",
+			statusCode:    http.StatusNoContent,
+			wantSucceeded: true,
+		},
+		{
+			name:       "client error",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "server error",
+			statusCode: http.StatusBadGateway,
+		},
+		{
+			name:            "success criteria not met",
+			statusCode:      http.StatusOK,
+			respBody:        `{"status":"down"}`,
+			successCriteria: &domain.TargetSuccessCriteria{BodyPath: "$.status", BodyValue: "up"},
+		},
+		{
+			name:            "non 2xx accepted by the success criteria",
+			statusCode:      http.StatusFound,
+			successCriteria: &domain.TargetSuccessCriteria{StatusCodes: []int{http.StatusFound}},
+		},
+		{
+			name:        "unreachable",
+			unreachable: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "true", r.Header.Get(TestHeader))
+				w.WriteHeader(tt.statusCode)
+				_, _ = io.WriteString(w, tt.respBody)
+			}))
+			defer server.Close()
+			endpoint := server.URL
+			if tt.unreachable {
+				endpoint = unreachable.URL
+			}
+			mock := withMockClient(t)
+			mock.ExpectExec(regexp.QuoteMeta(insertTargetExecutionStmt)).
+				WithArgs("instance", "target", sqlmock.AnyArg(), sqlmock.AnyArg(), tt.statusCode, sqlmock.AnyArg(), sqlmock.AnyArg(), tt.wantSucceeded).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			got, err := VerifyTarget(authz.WithInstanceID(context.Background(), "instance"), &mockTarget{
+				TargetID:        "target",
+				TargetType:      domain.TargetTypeWebhook,
+				Endpoint:        endpoint,
+				Timeout:         time.Minute,
+				SuccessCriteria: tt.successCriteria,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "instance", got.InstanceID)
+			assert.Equal(t, "target", got.TargetID)
+			assert.Equal(t, tt.statusCode, got.StatusCode)
+			assert.Equal(t, tt.wantSucceeded, got.Succeeded)
+			assert.Equal(t, !tt.wantSucceeded, got.Error != "")
+		})
+	}
+}
+
+// withMockClient sets the client storing the executions and queues to a mock for the duration of the test
+func withMockClient(t *testing.T) sqlmock.Sqlmock {
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+	require.NoError(t, err)
+	previous := client
+	client = &database.DB{DB: db}
+	t.Cleanup(func() {
+		client = previous
+		assert.NoError(t, mock.ExpectationsWereMet())
+		db.Close()
+	})
+	return mock
+}
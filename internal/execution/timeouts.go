@@ -0,0 +1,73 @@
+package execution
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// ValidatePhaseTimeouts checks that no phase timeout is negative, 0 means the phase is not limited separately
+func ValidatePhaseTimeouts(timeouts *domain.TargetPhaseTimeouts) error {
+	if timeouts == nil {
+		return nil
+	}
+	if timeouts.Dial < 0 || timeouts.TLSHandshake < 0 || timeouts.ResponseHeader < 0 {
+		return zerrors.ThrowInvalidArgument(nil, "EXEC-Ph4sTo", "Errors.Target.InvalidPhaseTimeout")
+	}
+	return nil
+}
+
+// applyPhaseTimeouts limits the phases of the transport, unset phases are limited by the overall timeout of the target
+func applyPhaseTimeouts(transport *http.Transport, dialer *net.Dialer, timeouts domain.TargetPhaseTimeouts, timeout time.Duration) {
+	dialer.Timeout = phaseTimeout(timeouts.Dial, timeout)
+	transport.TLSHandshakeTimeout = phaseTimeout(timeouts.TLSHandshake, timeout)
+	transport.ResponseHeaderTimeout = phaseTimeout(timeouts.ResponseHeader, timeout)
+}
+
+func phaseTimeout(phase, timeout time.Duration) time.Duration {
+	if phase > 0 {
+		return phase
+	}
+	return timeout
+}
+
+const (
+	phaseDial           = "dial"
+	phaseTLSHandshake   = "tls handshake"
+	phaseResponseHeader = "response header"
+)
+
+// timeoutPhase returns the phase of the call which timed out, empty if the error is not a phase timeout
+func timeoutPhase(err error) string {
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		return ""
+	}
+	var opErr *net.OpError
+	switch {
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return phaseDial
+	// the transport does not export the errors of the phases
+	case strings.Contains(err.Error(), "TLS handshake timeout"):
+		return phaseTLSHandshake
+	case strings.Contains(err.Error(), "timeout awaiting response headers"):
+		return phaseResponseHeader
+	default:
+		return ""
+	}
+}
+
+// phaseTimeoutError classifies timeouts of the phases, other errors are returned unchanged
+func phaseTimeoutError(err error) error {
+	phase := timeoutPhase(err)
+	if phase == "" {
+		return err
+	}
+	return zerrors.ThrowDeadlineExceeded(fmt.Errorf("%s phase: %w", phase, err), "EXEC-Ti9mEo", "Errors.Execution.Timeout")
+}
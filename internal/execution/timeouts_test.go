@@ -0,0 +1,208 @@
+package execution
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestValidatePhaseTimeouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts *domain.TargetPhaseTimeouts
+		wantErr  bool
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:     "empty",
+			timeouts: &domain.TargetPhaseTimeouts{},
+		},
+		{
+			name: "all phases",
+			timeouts: &domain.TargetPhaseTimeouts{
+				Dial:           time.Second,
+				TLSHandshake:   time.Second,
+				ResponseHeader: time.Second,
+			},
+		},
+		{
+			name:     "negative dial",
+			timeouts: &domain.TargetPhaseTimeouts{Dial: -time.Second},
+			wantErr:  true,
+		},
+		{
+			name:     "negative tls handshake",
+			timeouts: &domain.TargetPhaseTimeouts{TLSHandshake: -time.Second},
+			wantErr:  true,
+		},
+		{
+			name:     "negative response header",
+			timeouts: &domain.TargetPhaseTimeouts{ResponseHeader: -time.Second},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePhaseTimeouts(tt.timeouts)
+			if tt.wantErr {
+				assert.True(t, zerrors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_applyPhaseTimeouts(t *testing.T) {
+	tests := []struct {
+		name               string
+		timeouts           domain.TargetPhaseTimeouts
+		wantDial           time.Duration
+		wantTLSHandshake   time.Duration
+		wantResponseHeader time.Duration
+	}{
+		{
+			name: "all phases",
+			timeouts: domain.TargetPhaseTimeouts{
+				Dial:           1 * time.Second,
+				TLSHandshake:   2 * time.Second,
+				ResponseHeader: 3 * time.Second,
+			},
+			wantDial:           1 * time.Second,
+			wantTLSHandshake:   2 * time.Second,
+			wantResponseHeader: 3 * time.Second,
+		},
+		{
+			name:               "dial only, fallback to timeout",
+			timeouts:           domain.TargetPhaseTimeouts{Dial: time.Second},
+			wantDial:           time.Second,
+			wantTLSHandshake:   time.Minute,
+			wantResponseHeader: time.Minute,
+		},
+		{
+			name:               "tls handshake only, fallback to timeout",
+			timeouts:           domain.TargetPhaseTimeouts{TLSHandshake: time.Second},
+			wantDial:           time.Minute,
+			wantTLSHandshake:   time.Second,
+			wantResponseHeader: time.Minute,
+		},
+		{
+			name:               "response header only, fallback to timeout",
+			timeouts:           domain.TargetPhaseTimeouts{ResponseHeader: time.Second},
+			wantDial:           time.Minute,
+			wantTLSHandshake:   time.Minute,
+			wantResponseHeader: time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := new(http.Transport)
+			dialer := new(net.Dialer)
+			applyPhaseTimeouts(transport, dialer, tt.timeouts, time.Minute)
+			assert.Equal(t, tt.wantDial, dialer.Timeout)
+			assert.Equal(t, tt.wantTLSHandshake, transport.TLSHandshakeTimeout)
+			assert.Equal(t, tt.wantResponseHeader, transport.ResponseHeaderTimeout)
+		})
+	}
+}
+
+func Test_timeoutPhase(t *testing.T) {
+	assert.Equal(t, phaseDial, timeoutPhase(&net.OpError{Op: "dial", Err: os.ErrDeadlineExceeded}))
+	assert.Empty(t, timeoutPhase(&net.OpError{Op: "dial", Err: os.ErrNotExist}))
+	assert.Empty(t, timeoutPhase(&net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}))
+	assert.Empty(t, timeoutPhase(os.ErrClosed))
+}
+
+func TestCall_phaseTimeouts(t *testing.T) {
+	// accepts connections but never answers the TLS handshake
+	silentListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer silentListener.Close()
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			conn, err := silentListener.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+
+	// answers after the client gave up
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slowServer.Close()
+
+	// answers after a short delay
+	delayedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer delayedServer.Close()
+
+	tests := []struct {
+		name      string
+		endpoint  string
+		timeouts  domain.TargetPhaseTimeouts
+		wantPhase string
+	}{
+		{
+			name:      "tls handshake timeout",
+			endpoint:  "https://" + silentListener.Addr().String(),
+			timeouts:  domain.TargetPhaseTimeouts{TLSHandshake: 50 * time.Millisecond},
+			wantPhase: phaseTLSHandshake,
+		},
+		{
+			name:      "response header timeout",
+			endpoint:  slowServer.URL,
+			timeouts:  domain.TargetPhaseTimeouts{ResponseHeader: 50 * time.Millisecond},
+			wantPhase: phaseResponseHeader,
+		},
+		{
+			name:     "tls handshake timeout does not limit the response",
+			endpoint: delayedServer.URL,
+			timeouts: domain.TargetPhaseTimeouts{TLSHandshake: 50 * time.Millisecond},
+		},
+		{
+			name:     "dial timeout does not limit the response",
+			endpoint: delayedServer.URL,
+			timeouts: domain.TargetPhaseTimeouts{Dial: 50 * time.Millisecond},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			_, err := call(context.Background(), &mockTarget{
+				Endpoint:      tt.endpoint,
+				Timeout:       10 * time.Second,
+				PhaseTimeouts: tt.timeouts,
+			}, []byte("{}"))
+			// the overall timeout must not have been reached
+			assert.Less(t, time.Since(start), 5*time.Second)
+			if tt.wantPhase == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, zerrors.IsDeadlineExceeded(err), "unexpected error: %v", err)
+			assert.ErrorContains(t, err, tt.wantPhase+" phase")
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package execution
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+var errCertificateFingerprintMismatch = errors.New("certificate fingerprint mismatch")
+
+// ValidateCertificateFingerprint checks that the fingerprint is a hex encoded SHA-256 hash,
+// the bytes are optionally separated by colons
+func ValidateCertificateFingerprint(fingerprint string) error {
+	if fingerprint == "" {
+		return nil
+	}
+	decoded, err := hex.DecodeString(normalizeFingerprint(fingerprint))
+	if err != nil || len(decoded) != sha256.Size {
+		return zerrors.ThrowInvalidArgument(err, "EXEC-Pn4vXc", "Errors.Target.InvalidCertificateFingerprint")
+	}
+	return nil
+}
+
+func normalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+}
+
+// pinCertificate replaces the verification against the certificate authorities by the pinned certificate,
+// the connection is refused if the certificate presented by the endpoint does not match the fingerprint
+func pinCertificate(transport *http.Transport, fingerprint string) {
+	transport.TLSClientConfig = &tls.Config{
+		// the certificate is verified against the pinned fingerprint in VerifyConnection
+		InsecureSkipVerify: true,
+		VerifyConnection:   verifyCertificateFingerprint(normalizeFingerprint(fingerprint)),
+	}
+}
+
+func verifyCertificateFingerprint(fingerprint string) func(tls.ConnectionState) error {
+	return func(state tls.ConnectionState) error {
+		if len(state.PeerCertificates) == 0 {
+			return errCertificateFingerprintMismatch
+		}
+		sum := sha256.Sum256(state.PeerCertificates[0].Raw)
+		if hex.EncodeToString(sum[:]) != fingerprint {
+			return errCertificateFingerprintMismatch
+		}
+		return nil
+	}
+}
+
+// ValidateCABundle checks that the bundle consists of PEM encoded certificates only,
+// an empty bundle verifies against the certificate authorities of the system
+func ValidateCABundle(bundle string) error {
+	if bundle == "" {
+		return nil
+	}
+	if _, err := parseCABundle(bundle); err != nil {
+		return zerrors.ThrowInvalidArgument(err, "EXEC-Ca7bNd", "Errors.Target.InvalidCABundle")
+	}
+	return nil
+}
+
+// parseCABundle returns the pool of the PEM encoded certificates of the bundle
+func parseCABundle(bundle string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	rest := []byte(bundle)
+	var count int
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block %q", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		pool.AddCert(cert)
+		count++
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return nil, errors.New("invalid PEM data")
+	}
+	if count == 0 {
+		return nil, errors.New("no certificate")
+	}
+	return pool, nil
+}
+
+// trustCABundle verifies the certificate of the endpoint against the certificate authorities of the bundle
+// instead of the ones of the system, the verification itself is never disabled
+func trustCABundle(transport *http.Transport, bundle string) error {
+	pool, err := parseCABundle(bundle)
+	if err != nil {
+		return zerrors.ThrowInternal(err, "EXEC-Ca8pRs", "Errors.Target.InvalidCABundle")
+	}
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	return nil
+}
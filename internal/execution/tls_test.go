@@ -0,0 +1,206 @@
+package execution
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestValidateCertificateFingerprint(t *testing.T) {
+	tests := []struct {
+		name        string
+		fingerprint string
+		wantErr     bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:        "hex",
+			fingerprint: "D41D8CD98F00B204E9800998ECF8427ED41D8CD98F00B204E9800998ECF8427E",
+		},
+		{
+			name:        "colon separated",
+			fingerprint: "d4:1d:8c:d9:8f:00:b2:04:e9:80:09:98:ec:f8:42:7e:d4:1d:8c:d9:8f:00:b2:04:e9:80:09:98:ec:f8:42:7e",
+		},
+		{
+			name:        "too short",
+			fingerprint: "d41d8cd98f00b204e9800998ecf8427e",
+			wantErr:     true,
+		},
+		{
+			name:        "no hex",
+			fingerprint: strings.Repeat("zz", sha256.Size),
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCertificateFingerprint(tt.fingerprint)
+			if tt.wantErr {
+				assert.True(t, zerrors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCallTarget_certificateFingerprint(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	sum := sha256.Sum256(server.Certificate().Raw)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name        string
+		fingerprint string
+		wantErr     func(error) bool
+	}{
+		{
+			name:        "matching fingerprint",
+			fingerprint: fingerprint,
+		},
+		{
+			name:        "matching fingerprint, upper case",
+			fingerprint: strings.ToUpper(fingerprint),
+		},
+		{
+			name:        "mismatching fingerprint",
+			fingerprint: strings.Repeat("00", sha256.Size),
+			wantErr:     zerrors.IsPermissionDenied,
+		},
+		{
+			name: "no fingerprint, verified against certificate authorities",
+			wantErr: func(err error) bool {
+				return err != nil && !zerrors.IsPermissionDenied(err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CallTarget(context.Background(), &mockTarget{
+				TargetType:             domain.TargetTypeWebhook,
+				Endpoint:               server.URL,
+				Timeout:                time.Minute,
+				CertificateFingerprint: tt.fingerprint,
+			}, newMockContextInfoRequest("content"))
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// caBundle returns the PEM encoded certificate of the server, which is self-signed and therefore its own certificate authority
+func caBundle(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+func TestValidateCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		bundle  string
+		wantErr bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:   "certificate",
+			bundle: caBundle(server),
+		},
+		{
+			name:   "multiple certificates",
+			bundle: caBundle(server) + caBundle(server),
+		},
+		{
+			name:    "no PEM",
+			bundle:  "certificate",
+			wantErr: true,
+		},
+		{
+			name:    "malformed certificate",
+			bundle:  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("malformed")})),
+			wantErr: true,
+		},
+		{
+			name:    "private key",
+			bundle:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")})),
+			wantErr: true,
+		},
+		{
+			name:    "trailing garbage",
+			bundle:  caBundle(server) + "garbage",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCABundle(tt.bundle)
+			if tt.wantErr {
+				assert.True(t, zerrors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCallTarget_caBundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		bundle  string
+		wantErr func(error) bool
+	}{
+		{
+			name:   "custom certificate authority",
+			bundle: caBundle(server),
+		},
+		{
+			name: "no bundle, verified against the system certificate authorities",
+			wantErr: func(err error) bool {
+				return err != nil
+			},
+		},
+		{
+			name:    "malformed bundle",
+			bundle:  "certificate",
+			wantErr: zerrors.IsInternal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CallTarget(context.Background(), &mockTarget{
+				TargetType: domain.TargetTypeWebhook,
+				Endpoint:   server.URL,
+				Timeout:    time.Minute,
+				CABundle:   tt.bundle,
+			}, newMockContextInfoRequest("content"))
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package execution
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// ContextInfoURLVariables is implemented by context infos which provide the variables of templated endpoints,
+// e.g. https://example.com/tenants/{resourceOwner}/hook
+type ContextInfoURLVariables interface {
+	GetURLVariables() map[string]string
+}
+
+// URLVariables are the variables allowed in the endpoint of a target, they are resolved from the triggering event
+var URLVariables = []string{
+	"aggregateID",
+	"aggregateType",
+	"resourceOwner",
+	"instanceID",
+	"eventType",
+	"userID",
+}
+
+var urlVariablePattern = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// ValidateEndpointTemplate checks that the endpoint only contains known variables ([URLVariables]),
+// endpoints without variables are always valid
+func ValidateEndpointTemplate(endpoint string) error {
+	for _, match := range urlVariablePattern.FindAllStringSubmatch(endpoint, -1) {
+		if !slices.Contains(URLVariables, match[1]) {
+			return zerrors.ThrowInvalidArgument(nil, "EXEC-Tm9pLv", "Errors.Target.InvalidURLTemplate")
+		}
+	}
+	if strings.ContainsAny(urlVariablePattern.ReplaceAllString(endpoint, ""), "{}") {
+		return zerrors.ThrowInvalidArgument(nil, "EXEC-Br4cEs", "Errors.Target.InvalidURLTemplate")
+	}
+	return nil
+}
+
+// renderEndpoint replaces the variables of the endpoint by the path escaped values of the context info.
+// The call must fail if a variable is not resolved, so that no request is sent to a malformed URL.
+func renderEndpoint(endpoint string, info any) (string, error) {
+	if !strings.ContainsAny(endpoint, "{}") {
+		return endpoint, nil
+	}
+	if err := ValidateEndpointTemplate(endpoint); err != nil {
+		return "", err
+	}
+	provider, ok := info.(ContextInfoURLVariables)
+	if !ok {
+		return "", zerrors.ThrowPreconditionFailed(nil, "EXEC-Qw3nRv", "Errors.Execution.URLTemplateUnresolved")
+	}
+	variables := provider.GetURLVariables()
+	var unresolved bool
+	rendered := urlVariablePattern.ReplaceAllStringFunc(endpoint, func(match string) string {
+		value := variables[match[1:len(match)-1]]
+		if value == "" {
+			unresolved = true
+		}
+		return url.PathEscape(value)
+	})
+	if unresolved {
+		return "", zerrors.ThrowPreconditionFailed(nil, "EXEC-Qw3nRv", "Errors.Execution.URLTemplateUnresolved")
+	}
+	return rendered, nil
+}
+
+// renderedTarget is a target with the rendered endpoint
+type renderedTarget struct {
+	Target
+	endpoint string
+}
+
+func (t *renderedTarget) GetEndpoint() string {
+	return t.endpoint
+}
+
+// withRenderedEndpoint returns the target with the variables of the endpoint resolved from the context info,
+// targets with a static endpoint are returned unchanged
+func withRenderedEndpoint(target Target, info any) (Target, error) {
+	endpoint, err := renderEndpoint(target.GetEndpoint(), info)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == target.GetEndpoint() {
+		return target, nil
+	}
+	return &renderedTarget{Target: target, endpoint: endpoint}, nil
+}
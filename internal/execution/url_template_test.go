@@ -0,0 +1,156 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+func TestValidateEndpointTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		wantErr  bool
+	}{
+		{
+			name:     "static",
+			endpoint: "https://example.com/hook",
+		},
+		{
+			name:     "known variables",
+			endpoint: "https://example.com/tenants/{resourceOwner}/hook?aggregate={aggregateID}&type={eventType}",
+		},
+		{
+			name:     "unknown variable",
+			endpoint: "https://example.com/tenants/{tenantID}/hook",
+			wantErr:  true,
+		},
+		{
+			name:     "empty variable",
+			endpoint: "https://example.com/tenants/{}/hook",
+			wantErr:  true,
+		},
+		{
+			name:     "unclosed variable",
+			endpoint: "https://example.com/tenants/{resourceOwner/hook",
+			wantErr:  true,
+		},
+		{
+			name:     "nested variable",
+			endpoint: "https://example.com/tenants/{{resourceOwner}}/hook",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEndpointTemplate(tt.endpoint)
+			if tt.wantErr {
+				assert.True(t, zerrors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_renderEndpoint(t *testing.T) {
+	event := &EventData{
+		AggregateID:   "user1",
+		AggregateType: "user",
+		ResourceOwner: "org 1",
+		InstanceID:    "instance1",
+		EventType:     "user.human.added",
+	}
+	tests := []struct {
+		name     string
+		endpoint string
+		info     any
+		want     string
+		wantErr  func(error) bool
+	}{
+		{
+			name:     "static, no variables required",
+			endpoint: "https://example.com/hook",
+			want:     "https://example.com/hook",
+		},
+		{
+			name:     "variables are escaped",
+			endpoint: "https://example.com/tenants/{resourceOwner}/{aggregateType}/{aggregateID}",
+			info:     event,
+			want:     "https://example.com/tenants/org%201/user/user1",
+		},
+		{
+			name:     "no variables provided",
+			endpoint: "https://example.com/tenants/{resourceOwner}/hook",
+			info:     newMockContextInfoRequest("content"),
+			wantErr:  zerrors.IsPreconditionFailed,
+		},
+		{
+			name:     "empty variable",
+			endpoint: "https://example.com/users/{userID}/hook",
+			info:     event,
+			wantErr:  zerrors.IsPreconditionFailed,
+		},
+		{
+			name:     "unknown variable",
+			endpoint: "https://example.com/tenants/{tenantID}/hook",
+			info:     event,
+			wantErr:  zerrors.IsErrorInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderEndpoint(tt.endpoint, tt.info)
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type mockEventContextInfo struct {
+	*EventData
+}
+
+func (c *mockEventContextInfo) GetHTTPRequestBody() []byte {
+	data, _ := json.Marshal(c.EventData)
+	return data
+}
+
+func TestCallTarget_templatedEndpoint(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+	}))
+	defer server.Close()
+
+	_, err := CallTarget(context.Background(), &mockTarget{
+		TargetType: domain.TargetTypeWebhook,
+		Endpoint:   server.URL + "/tenants/{resourceOwner}/hook",
+		Timeout:    time.Minute,
+	}, &mockEventContextInfo{&EventData{ResourceOwner: "org1", EventType: "user.human.added"}})
+	require.NoError(t, err)
+	assert.Equal(t, "/tenants/org1/hook", path)
+
+	// no request is sent to an unresolved endpoint
+	path = ""
+	_, err = CallTarget(context.Background(), &mockTarget{
+		TargetType: domain.TargetTypeWebhook,
+		Endpoint:   server.URL + "/users/{userID}/hook",
+		Timeout:    time.Minute,
+	}, &mockEventContextInfo{&EventData{ResourceOwner: "org1", EventType: "user.human.added"}})
+	assert.True(t, zerrors.IsPreconditionFailed(err), "unexpected error: %v", err)
+	assert.Empty(t, path)
+}
@@ -41,7 +41,8 @@ func (l LogEmitterFunc[T]) Emit(ctx context.Context, bulk []T) error {
 }
 
 type LogCleanupper[T LogRecord[T]] interface {
-	Cleanup(ctx context.Context, keep time.Duration) error
+	// Cleanup removes the records older than keep and returns the amount of removed records
+	Cleanup(ctx context.Context, keep time.Duration) (removed uint64, err error)
 	LogEmitter[T]
 }
 
@@ -8,6 +8,7 @@ import (
 	"github.com/benbjohnson/clock"
 
 	"github.com/zitadel/zitadel/internal/logstore"
+	"github.com/zitadel/zitadel/internal/logstore/record"
 	"github.com/zitadel/zitadel/internal/query"
 	"github.com/zitadel/zitadel/internal/repository/quota"
 )
@@ -15,6 +16,8 @@ import (
 var _ logstore.UsageStorer[*Record] = (*InmemLogStorage)(nil)
 var _ logstore.LogCleanupper[*Record] = (*InmemLogStorage)(nil)
 var _ logstore.Queries = (*InmemLogStorage)(nil)
+var _ logstore.UsageByDayQuerier = (*InmemLogStorage)(nil)
+var _ logstore.EmitCounter = (*InmemLogStorage)(nil)
 
 type InmemLogStorage struct {
 	mux     sync.Mutex
@@ -22,15 +25,99 @@ type InmemLogStorage struct {
 	emitted []*Record
 	bulks   []int
 	quota   *query.Quota
+
+	maxRecords int
+	dropped    uint64
+
+	identity    func(*Record) string
+	dedupWindow time.Duration
+	seen        map[string]time.Time
+
+	stampOnEmit bool
+
+	skewTolerance time.Duration
+	skewPolicy    SkewPolicy
+	rejected      uint64
+
+	emittedCount   uint64
+	persistedCount uint64
+
+	// usage counts the stored records per instance, so usage queries don't scan the records
+	usage map[string]*instanceUsage
+}
+
+// instanceUsage is the running usage of an instance
+type instanceUsage struct {
+	// records is the amount of stored records of the instance
+	records uint64
+	// oldest is at or before the timestamp of the oldest stored record of the instance
+	oldest time.Time
+}
+
+type InmemOption func(*InmemLogStorage)
+
+// WithMaxRecords caps the amount of stored records,
+// if the cap is exceeded the oldest records are dropped.
+// 0 means unbounded
+func WithMaxRecords(maxRecords int) InmemOption {
+	return func(l *InmemLogStorage) {
+		l.maxRecords = maxRecords
+	}
+}
+
+// WithDeduplication ignores emitted records if a record with the same identity
+// was already emitted within the window, like emitters guarding against at-least-once delivery.
+// A window of 0 ignores duplicates independent of their age.
+func WithDeduplication(identity func(*Record) string, window time.Duration) InmemOption {
+	return func(l *InmemLogStorage) {
+		l.identity = identity
+		l.dedupWindow = window
+		l.seen = make(map[string]time.Time)
+	}
+}
+
+// WithEmitTimestamps sets the timestamp of emitted records without timestamp to the time of the clock at emit,
+// so that callers don't have to pass the clock to each record.
+// Records with a timestamp keep it.
+func WithEmitTimestamps() InmemOption {
+	return func(l *InmemLogStorage) {
+		l.stampOnEmit = true
+	}
+}
+
+// SkewPolicy decides what happens to records with a timestamp beyond the skew tolerance
+type SkewPolicy int
+
+const (
+	// SkewReject drops the records and counts them as [InmemLogStorage.Rejected]
+	SkewReject SkewPolicy = iota
+	// SkewClamp sets the timestamp of the records to the time of the clock at emit
+	SkewClamp
+)
+
+// WithSkewTolerance handles records with a timestamp more than tolerance after the time of the clock
+// according to the policy, so clock skew or malicious input can't distort the usage and the cleanup.
+// Records in the past are accepted.
+// A tolerance of 0 accepts all timestamps.
+func WithSkewTolerance(tolerance time.Duration, policy SkewPolicy) InmemOption {
+	return func(l *InmemLogStorage) {
+		l.skewTolerance = tolerance
+		l.skewPolicy = policy
+	}
 }
 
-func NewInMemoryStorage(clock clock.Clock, quota *query.Quota) *InmemLogStorage {
-	return &InmemLogStorage{
+func NewInMemoryStorage(clock clock.Clock, quota *query.Quota, opts ...InmemOption) *InmemLogStorage {
+	l := &InmemLogStorage{
 		clock:   clock,
 		emitted: make([]*Record, 0),
 		bulks:   make([]int, 0),
 		quota:   quota,
+		usage:   make(map[string]*instanceUsage),
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 func (l *InmemLogStorage) QuotaUnit() quota.Unit {
@@ -43,37 +130,205 @@ func (l *InmemLogStorage) Emit(_ context.Context, bulk []*Record) error {
 	}
 	l.mux.Lock()
 	defer l.mux.Unlock()
-	l.emitted = append(l.emitted, bulk...)
+	l.stamp(bulk)
+	records := l.deduplicate(l.checkSkew(bulk))
+	l.emitted = append(l.emitted, records...)
+	for _, r := range records {
+		l.countRecord(r)
+	}
+	l.emittedCount += uint64(len(bulk))
+	l.persistedCount += uint64(len(records))
 	l.bulks = append(l.bulks, len(bulk))
+	l.evict()
 	return nil
 }
 
-func (l *InmemLogStorage) QueryUsage(_ context.Context, _ string, start time.Time) (uint64, error) {
+// stamp sets the current time on the records without timestamp if enabled
+func (l *InmemLogStorage) stamp(bulk []*Record) {
+	if !l.stampOnEmit {
+		return
+	}
+	now := l.clock.Now()
+	for _, r := range bulk {
+		if r.ts.IsZero() {
+			r.ts = now
+		}
+	}
+}
+
+// checkSkew returns the records of the bulk with a timestamp within the skew tolerance,
+// records beyond are rejected or clamped according to the skew policy
+func (l *InmemLogStorage) checkSkew(bulk []*Record) []*Record {
+	if l.skewTolerance <= 0 {
+		return bulk
+	}
+	now := l.clock.Now()
+	latest := now.Add(l.skewTolerance)
+	records := make([]*Record, 0, len(bulk))
+	for _, r := range bulk {
+		if !r.ts.After(latest) {
+			records = append(records, r)
+			continue
+		}
+		if l.skewPolicy == SkewClamp {
+			r.ts = now
+			records = append(records, r)
+			continue
+		}
+		l.rejected++
+	}
+	return records
+}
+
+// deduplicate returns the records of the bulk not emitted within the dedup window
+func (l *InmemLogStorage) deduplicate(bulk []*Record) []*Record {
+	if l.identity == nil {
+		return bulk
+	}
+	now := l.clock.Now()
+	if l.dedupWindow > 0 {
+		for key, emitted := range l.seen {
+			if now.Sub(emitted) >= l.dedupWindow {
+				delete(l.seen, key)
+			}
+		}
+	}
+	records := make([]*Record, 0, len(bulk))
+	for _, r := range bulk {
+		key := l.identity(r)
+		if _, ok := l.seen[key]; ok {
+			continue
+		}
+		l.seen[key] = now
+		records = append(records, r)
+	}
+	return records
+}
+
+// evict drops the oldest records exceeding maxRecords
+// the records are moved in place so the underlying array doesn't grow
+func (l *InmemLogStorage) evict() {
+	if l.maxRecords <= 0 || len(l.emitted) <= l.maxRecords {
+		return
+	}
+	drop := len(l.emitted) - l.maxRecords
+	for _, r := range l.emitted[:drop] {
+		l.uncountRecord(r)
+	}
+	copy(l.emitted, l.emitted[drop:])
+	clear(l.emitted[l.maxRecords:])
+	l.emitted = l.emitted[:l.maxRecords]
+	l.dropped += uint64(drop)
+}
+
+// countRecord adds the stored record to the usage of its instance
+func (l *InmemLogStorage) countRecord(r *Record) {
+	usage, ok := l.usage[r.instanceID]
+	if !ok {
+		usage = &instanceUsage{oldest: r.ts}
+		l.usage[r.instanceID] = usage
+	}
+	usage.records++
+	if r.ts.Before(usage.oldest) {
+		usage.oldest = r.ts
+	}
+}
+
+// uncountRecord removes the dropped record from the usage of its instance,
+// the oldest timestamp is kept as it is still at or before the oldest stored record
+func (l *InmemLogStorage) uncountRecord(r *Record) {
+	usage := l.usage[r.instanceID]
+	usage.records--
+	if usage.records == 0 {
+		delete(l.usage, r.instanceID)
+	}
+}
+
+// QueryUsage counts the records of the instance after start.
+// If start is before all records of the instance, like the start of a quota period, the running usage is returned,
+// otherwise the records are scanned.
+func (l *InmemLogStorage) QueryUsage(_ context.Context, instanceID string, start time.Time) (uint64, error) {
 	l.mux.Lock()
 	defer l.mux.Unlock()
 
+	usage, ok := l.usage[instanceID]
+	if !ok {
+		return 0, nil
+	}
+	if start.Before(usage.oldest) {
+		return usage.records, nil
+	}
+	return l.scanUsage(instanceID, start), nil
+}
+
+// scanUsage counts the records of the instance after start by scanning all records
+func (l *InmemLogStorage) scanUsage(instanceID string, start time.Time) uint64 {
 	var count uint64
 	for _, r := range l.emitted {
-		if r.ts.After(start) {
+		if r.instanceID == instanceID && r.ts.After(start) {
 			count++
 		}
 	}
-	return count, nil
+	return count
+}
+
+// TargetExecutionUsage is the usage of the calls of targets
+type TargetExecutionUsage struct {
+	Calls        uint64
+	PayloadBytes int64
+	Took         time.Duration
+}
+
+// QueryTargetExecutionUsage sums the records of calls of targets of the instance after start
+func (l *InmemLogStorage) QueryTargetExecutionUsage(_ context.Context, instanceID string, start time.Time) TargetExecutionUsage {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	var usage TargetExecutionUsage
+	for _, r := range l.emitted {
+		if r.kind != record.ExecutionKindTarget || r.instanceID != instanceID || !r.ts.After(start) {
+			continue
+		}
+		usage.Calls++
+		usage.PayloadBytes += r.payloadBytes
+		usage.Took += r.took
+	}
+	return usage
+}
+
+// QueryUsageByDay implements [logstore.UsageByDayQuerier], days without records are omitted
+func (l *InmemLogStorage) QueryUsageByDay(_ context.Context, instanceID string, start, end time.Time) (map[time.Time]uint64, error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	usage := make(map[time.Time]uint64)
+	for _, r := range l.emitted {
+		if r.instanceID != instanceID || r.ts.Before(start) || !r.ts.Before(end) {
+			continue
+		}
+		ts := r.ts.UTC()
+		usage[time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.UTC)]++
+	}
+	return usage, nil
 }
 
-func (l *InmemLogStorage) Cleanup(_ context.Context, keep time.Duration) error {
+func (l *InmemLogStorage) Cleanup(_ context.Context, keep time.Duration) (uint64, error) {
 	l.mux.Lock()
 	defer l.mux.Unlock()
 
 	clean := make([]*Record, 0)
 	from := l.clock.Now().Add(-(keep + 1))
+	// the usage is counted again from the kept records, which also moves the oldest timestamps forward
+	clear(l.usage)
 	for _, r := range l.emitted {
 		if r.ts.After(from) {
 			clean = append(clean, r)
+			l.countRecord(r)
 		}
 	}
+	removed := len(l.emitted) - len(clean)
 	l.emitted = clean
-	return nil
+	return uint64(removed), nil
 }
 
 func (l *InmemLogStorage) Bulks() []int {
@@ -83,6 +338,31 @@ func (l *InmemLogStorage) Bulks() []int {
 	return l.bulks
 }
 
+// Dropped returns the amount of records evicted because maxRecords was exceeded
+func (l *InmemLogStorage) Dropped() uint64 {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	return l.dropped
+}
+
+// Rejected returns the amount of records rejected because their timestamp exceeded the skew tolerance
+func (l *InmemLogStorage) Rejected() uint64 {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	return l.rejected
+}
+
+// EmitCounts implements [logstore.EmitCounter],
+// records rejected by the skew tolerance or ignored by the deduplication are not persisted
+func (l *InmemLogStorage) EmitCounts() (emitted, persisted uint64) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	return l.emittedCount, l.persistedCount
+}
+
 func (l *InmemLogStorage) Len() int {
 	l.mux.Lock()
 	defer l.mux.Unlock()
@@ -95,7 +375,7 @@ func (l *InmemLogStorage) GetQuota(ctx context.Context, instanceID string, unit
 }
 
 func (l *InmemLogStorage) GetQuotaUsage(ctx context.Context, instanceID string, unit quota.Unit, periodStart time.Time) (usage uint64, err error) {
-	return uint64(l.Len()), nil
+	return l.QueryUsage(ctx, instanceID, periodStart)
 }
 
 func (l *InmemLogStorage) GetRemainingQuotaUsage(ctx context.Context, instanceID string, unit quota.Unit) (remaining *uint64, err error) {
@@ -0,0 +1,569 @@
+package mock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/query"
+	"github.com/zitadel/zitadel/internal/repository/quota"
+)
+
+func TestInmemLogStorage_QueryUsage(t *testing.T) {
+	ctx := context.Background()
+	clock := clock.NewMock()
+	start := clock.Now()
+	clock.Add(time.Second)
+
+	storage := NewInMemoryStorage(clock, new(query.Quota))
+	require.NoError(t, storage.Emit(ctx, []*Record{
+		NewInstanceRecord(clock, "instance1"),
+		NewInstanceRecord(clock, "instance1"),
+		NewInstanceRecord(clock, "instance2"),
+	}))
+	require.NoError(t, storage.Emit(ctx, []*Record{
+		NewInstanceRecord(clock, "instance1"),
+	}))
+
+	tests := []struct {
+		name       string
+		instanceID string
+		want       uint64
+	}{
+		{
+			name:       "instance1",
+			instanceID: "instance1",
+			want:       3,
+		},
+		{
+			name:       "instance2",
+			instanceID: "instance2",
+			want:       1,
+		},
+		{
+			name:       "unknown instance",
+			instanceID: "instance3",
+			want:       0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := storage.QueryUsage(ctx, tt.instanceID, start)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			got, err = storage.GetQuotaUsage(ctx, tt.instanceID, quota.Unimplemented, start)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInmemLogStorage_QueryUsageByDay(t *testing.T) {
+	ctx := context.Background()
+	clock := clock.NewMock()
+	day1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day4 := day1.AddDate(0, 0, 3)
+
+	storage := NewInMemoryStorage(clock, new(query.Quota))
+	emit := func(ts time.Time, instanceID string) {
+		clock.Set(ts)
+		require.NoError(t, storage.Emit(ctx, []*Record{NewInstanceRecord(clock, instanceID)}))
+	}
+	emit(day1, "instance1")
+	emit(day1.Add(23*time.Hour), "instance1")
+	emit(day1.Add(time.Hour), "instance2")
+	emit(day2.Add(12*time.Hour), "instance1")
+	// day 3 has no usage
+	emit(day4.Add(time.Hour), "instance1")
+	emit(day4.AddDate(0, 0, 1), "instance1")
+
+	tests := []struct {
+		name       string
+		instanceID string
+		start, end time.Time
+		want       map[time.Time]uint64
+	}{
+		{
+			name:       "multiple days",
+			instanceID: "instance1",
+			start:      day1,
+			end:        day4.AddDate(0, 0, 1),
+			want: map[time.Time]uint64{
+				day1: 2,
+				day2: 1,
+				day4: 1,
+			},
+		},
+		{
+			name:       "start within day",
+			instanceID: "instance1",
+			start:      day1.Add(time.Hour),
+			end:        day2,
+			want: map[time.Time]uint64{
+				day1: 1,
+			},
+		},
+		{
+			name:       "other instance",
+			instanceID: "instance2",
+			start:      day1,
+			end:        day4,
+			want: map[time.Time]uint64{
+				day1: 1,
+			},
+		},
+		{
+			name:       "no usage",
+			instanceID: "instance1",
+			start:      day2.AddDate(0, 0, 1),
+			end:        day4,
+			want:       map[time.Time]uint64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := storage.QueryUsageByDay(ctx, tt.instanceID, tt.start, tt.end)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInmemLogStorage_MaxRecords(t *testing.T) {
+	ctx := context.Background()
+	clock := clock.NewMock()
+	start := clock.Now()
+
+	tests := []struct {
+		name        string
+		maxRecords  int
+		emits       int
+		wantLen     int
+		wantDropped uint64
+	}{
+		{
+			name:       "unbounded",
+			maxRecords: 0,
+			emits:      10,
+			wantLen:    10,
+		},
+		{
+			name:       "below cap",
+			maxRecords: 10,
+			emits:      5,
+			wantLen:    5,
+		},
+		{
+			name:        "exceeds cap",
+			maxRecords:  4,
+			emits:       10,
+			wantLen:     4,
+			wantDropped: 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock.Set(start)
+			storage := NewInMemoryStorage(clock, new(query.Quota), WithMaxRecords(tt.maxRecords))
+			for i := 0; i < tt.emits; i++ {
+				clock.Add(time.Second)
+				require.NoError(t, storage.Emit(ctx, []*Record{NewInstanceRecord(clock, "instance")}))
+			}
+			assert.Equal(t, tt.wantLen, storage.Len())
+			assert.Equal(t, tt.wantDropped, storage.Dropped())
+
+			// the oldest records are dropped, so the usage only counts the latest
+			usage, err := storage.QueryUsage(ctx, "instance", start)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(tt.wantLen), usage)
+			oldest := start.Add(time.Duration(tt.wantDropped+1) * time.Second)
+			assert.Equal(t, oldest, storage.emitted[0].ts)
+
+			// cleanup only removes the remaining records older than keep
+			removed, err := storage.Cleanup(ctx, time.Duration(tt.wantLen/2)*time.Second)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLen/2+1, storage.Len())
+			assert.Equal(t, uint64(tt.wantLen-(tt.wantLen/2+1)), removed)
+		})
+	}
+}
+
+func TestInmemLogStorage_Cleanup(t *testing.T) {
+	ctx := context.Background()
+	clock := clock.NewMock()
+
+	tests := []struct {
+		name        string
+		emits       int
+		keep        time.Duration
+		wantRemoved uint64
+		wantLen     int
+	}{
+		{
+			name:        "empty",
+			keep:        time.Second,
+			wantRemoved: 0,
+			wantLen:     0,
+		},
+		{
+			name:        "nothing older than keep",
+			emits:       3,
+			keep:        time.Minute,
+			wantRemoved: 0,
+			wantLen:     3,
+		},
+		{
+			name:        "older than keep",
+			emits:       10,
+			keep:        3 * time.Second,
+			wantRemoved: 6,
+			wantLen:     4,
+		},
+		{
+			name:        "all older than keep",
+			emits:       5,
+			keep:        0,
+			wantRemoved: 4,
+			wantLen:     1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := NewInMemoryStorage(clock, new(query.Quota))
+			for i := 0; i < tt.emits; i++ {
+				clock.Add(time.Second)
+				require.NoError(t, storage.Emit(ctx, []*Record{NewInstanceRecord(clock, "instance")}))
+			}
+			removed, err := storage.Cleanup(ctx, tt.keep)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRemoved, removed)
+			assert.Equal(t, tt.wantLen, storage.Len())
+		})
+	}
+}
+
+func TestInmemLogStorage_Deduplication(t *testing.T) {
+	ctx := context.Background()
+	clock := clock.NewMock()
+	start := clock.Now()
+	identity := func(r *Record) string {
+		return r.instanceID + r.ts.String()
+	}
+
+	tests := []struct {
+		name      string
+		opts      []InmemOption
+		wantUsage uint64
+	}{
+		{
+			name:      "no dedup",
+			wantUsage: 5,
+		},
+		{
+			name:      "dedup within window",
+			opts:      []InmemOption{WithDeduplication(identity, time.Minute)},
+			wantUsage: 2,
+		},
+		{
+			name:      "dedup window elapsed",
+			opts:      []InmemOption{WithDeduplication(identity, time.Second)},
+			wantUsage: 3,
+		},
+		{
+			name:      "dedup without window",
+			opts:      []InmemOption{WithDeduplication(identity, 0)},
+			wantUsage: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock.Set(start)
+			storage := NewInMemoryStorage(clock, new(query.Quota), tt.opts...)
+
+			clock.Add(time.Second)
+			first := NewInstanceRecord(clock, "instance")
+			second := NewInstanceRecord(clock, "instance2")
+			require.NoError(t, storage.Emit(ctx, []*Record{first, second}))
+			// retry of the whole bulk
+			require.NoError(t, storage.Emit(ctx, []*Record{first, second}))
+			// retry of a single record after a while
+			clock.Add(2 * time.Second)
+			require.NoError(t, storage.Emit(ctx, []*Record{first}))
+
+			usage, err := storage.QueryUsage(ctx, "instance", start)
+			require.NoError(t, err)
+			usage2, err := storage.QueryUsage(ctx, "instance2", start)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUsage, usage+usage2)
+			assert.Equal(t, []int{2, 2, 1}, storage.Bulks())
+		})
+	}
+}
+
+func TestInmemLogStorage_EmitCounts(t *testing.T) {
+	ctx := context.Background()
+	clock := clock.NewMock()
+	identity := func(r *Record) string {
+		return r.instanceID
+	}
+
+	tests := []struct {
+		name          string
+		opts          []InmemOption
+		wantPersisted uint64
+	}{
+		{
+			name:          "no dedup",
+			wantPersisted: 5,
+		},
+		{
+			name:          "dedup",
+			opts:          []InmemOption{WithDeduplication(identity, 0)},
+			wantPersisted: 2,
+		},
+		{
+			// evicted records were persisted
+			name:          "dedup and max records",
+			opts:          []InmemOption{WithDeduplication(identity, 0), WithMaxRecords(1)},
+			wantPersisted: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := NewInMemoryStorage(clock, new(query.Quota), tt.opts...)
+			emitted, persisted := storage.EmitCounts()
+			assert.Zero(t, emitted)
+			assert.Zero(t, persisted)
+
+			first := NewInstanceRecord(clock, "instance")
+			second := NewInstanceRecord(clock, "instance2")
+			require.NoError(t, storage.Emit(ctx, []*Record{first, second}))
+			// retries of at-least-once delivery
+			require.NoError(t, storage.Emit(ctx, []*Record{first, second}))
+			require.NoError(t, storage.Emit(ctx, []*Record{first}))
+
+			emitted, persisted = storage.EmitCounts()
+			assert.Equal(t, uint64(5), emitted)
+			assert.Equal(t, tt.wantPersisted, persisted)
+			assert.GreaterOrEqual(t, emitted, persisted)
+		})
+	}
+}
+
+func TestInmemLogStorage_EmitTimestamps(t *testing.T) {
+	ctx := context.Background()
+	clock := clock.NewMock()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	explicit := start.Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		opts         []InmemOption
+		wantStamped  time.Time
+		wantExplicit time.Time
+		wantUsage    uint64
+	}{
+		{
+			name:         "not stamped",
+			wantExplicit: explicit,
+		},
+		{
+			name:         "stamped on emit",
+			opts:         []InmemOption{WithEmitTimestamps()},
+			wantStamped:  start.Add(time.Minute),
+			wantExplicit: explicit,
+			wantUsage:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock.Set(start)
+			storage := NewInMemoryStorage(clock, new(query.Quota), tt.opts...)
+
+			unstamped := NewUnstampedRecord("instance")
+			withTimestamp := NewInstanceRecord(clock, "instance")
+			withTimestamp.ts = explicit
+			clock.Add(time.Minute)
+			require.NoError(t, storage.Emit(ctx, []*Record{unstamped, withTimestamp}))
+
+			assert.Equal(t, tt.wantStamped, unstamped.Timestamp())
+			assert.Equal(t, tt.wantExplicit, withTimestamp.Timestamp())
+
+			usage, err := storage.QueryUsage(ctx, "instance", start)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUsage, usage)
+
+			// the stamped record is kept by the cleanup, the explicit one is older
+			clock.Add(time.Minute)
+			removed, err := storage.Cleanup(ctx, 30*time.Minute)
+			require.NoError(t, err)
+			assert.Equal(t, 2-tt.wantUsage, removed)
+		})
+	}
+}
+
+func TestInmemLogStorage_SkewTolerance(t *testing.T) {
+	ctx := context.Background()
+	clock := clock.NewMock()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	present := now
+	nearFuture := now.Add(30 * time.Second)
+	farFuture := now.Add(365 * 24 * time.Hour)
+
+	tests := []struct {
+		name           string
+		opts           []InmemOption
+		wantTimestamps []time.Time
+		wantRejected   uint64
+		wantRemaining  int
+	}{
+		{
+			name:           "lenient by default",
+			wantTimestamps: []time.Time{past, present, nearFuture, farFuture},
+			wantRemaining:  1,
+		},
+		{
+			name:           "reject",
+			opts:           []InmemOption{WithSkewTolerance(time.Minute, SkewReject)},
+			wantTimestamps: []time.Time{past, present, nearFuture},
+			wantRejected:   1,
+		},
+		{
+			name:           "clamp",
+			opts:           []InmemOption{WithSkewTolerance(time.Minute, SkewClamp)},
+			wantTimestamps: []time.Time{past, present, nearFuture, now},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock.Set(now)
+			storage := NewInMemoryStorage(clock, new(query.Quota), tt.opts...)
+
+			records := make([]*Record, 0, 4)
+			for _, ts := range []time.Time{past, present, nearFuture, farFuture} {
+				records = append(records, &Record{ts: ts, instanceID: "instance"})
+			}
+			require.NoError(t, storage.Emit(ctx, records))
+
+			timestamps := make([]time.Time, 0, storage.Len())
+			for _, r := range storage.emitted {
+				timestamps = append(timestamps, r.Timestamp())
+			}
+			assert.Equal(t, tt.wantTimestamps, timestamps)
+			assert.Equal(t, tt.wantRejected, storage.Rejected())
+
+			// only records within the tolerance are counted in the current period
+			usage, err := storage.QueryUsage(ctx, "instance", past.Add(-time.Second))
+			require.NoError(t, err)
+			assert.Equal(t, uint64(len(tt.wantTimestamps)), usage)
+
+			// records in the far future are not cleaned up
+			clock.Add(48 * time.Hour)
+			_, err = storage.Cleanup(ctx, time.Hour)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRemaining, storage.Len())
+		})
+	}
+}
+
+func TestInmemLogStorage_TargetExecutions(t *testing.T) {
+	ctx := context.Background()
+	clock := clock.NewMock()
+	start := clock.Now()
+	clock.Add(time.Second)
+
+	storage := NewInMemoryStorage(clock, new(query.Quota))
+	require.NoError(t, storage.Emit(ctx, []*Record{
+		NewInstanceRecord(clock, "instance1"),
+		NewTargetExecutionRecord(clock, "instance1", 512, 100*time.Millisecond),
+		NewTargetExecutionRecord(clock, "instance1", 1024, 300*time.Millisecond),
+		NewTargetExecutionRecord(clock, "instance2", 256, time.Second),
+	}))
+
+	// the calls of targets are counted like the other records
+	usage, err := storage.QueryUsage(ctx, "instance1", start)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), usage)
+	emitted, persisted := storage.EmitCounts()
+	assert.Equal(t, uint64(4), emitted)
+	assert.Equal(t, uint64(4), persisted)
+
+	assert.Equal(t, TargetExecutionUsage{
+		Calls:        2,
+		PayloadBytes: 1536,
+		Took:         400 * time.Millisecond,
+	}, storage.QueryTargetExecutionUsage(ctx, "instance1", start))
+	assert.Equal(t, TargetExecutionUsage{
+		Calls:        1,
+		PayloadBytes: 256,
+		Took:         time.Second,
+	}, storage.QueryTargetExecutionUsage(ctx, "instance2", start))
+	assert.Equal(t, TargetExecutionUsage{}, storage.QueryTargetExecutionUsage(ctx, "instance1", clock.Now()))
+}
+
+func TestInmemLogStorage_RunningUsage(t *testing.T) {
+	ctx := context.Background()
+	instances := []string{"instance1", "instance2", "instance3"}
+	tests := []struct {
+		name string
+		opts []InmemOption
+	}{
+		{
+			name: "unbounded",
+		},
+		{
+			name: "max records",
+			opts: []InmemOption{WithMaxRecords(50)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := clock.NewMock()
+			periodStart := clock.Now()
+			storage := NewInMemoryStorage(clock, new(query.Quota), tt.opts...)
+
+			assertUsage := func(t *testing.T) {
+				t.Helper()
+				starts := []time.Time{periodStart, clock.Now().Add(-30 * time.Second), clock.Now().Add(-time.Second), clock.Now()}
+				for _, instanceID := range instances {
+					for _, start := range starts {
+						got, err := storage.QueryUsage(ctx, instanceID, start)
+						require.NoError(t, err)
+						assert.Equal(t, storage.scanUsage(instanceID, start), got, "instance %s after %v", instanceID, start)
+					}
+					// the running counter is used if the start precedes all records
+					if usage, ok := storage.usage[instanceID]; ok {
+						assert.Equal(t, storage.scanUsage(instanceID, periodStart), usage.records, "running usage of %s", instanceID)
+					}
+				}
+			}
+
+			for i := 0; i < 200; i++ {
+				clock.Add(time.Second)
+				bulk := make([]*Record, 0, 3)
+				for j := 0; j <= i%3; j++ {
+					bulk = append(bulk, NewInstanceRecord(clock, instances[(i+j)%len(instances)]))
+				}
+				require.NoError(t, storage.Emit(ctx, bulk))
+				if i%10 == 9 {
+					assertUsage(t)
+				}
+				if i%40 == 39 {
+					_, err := storage.Cleanup(ctx, 25*time.Second)
+					require.NoError(t, err)
+					assertUsage(t)
+				}
+			}
+			_, err := storage.Cleanup(ctx, 0)
+			require.NoError(t, err)
+			assertUsage(t)
+		})
+	}
+}
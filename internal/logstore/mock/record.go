@@ -6,6 +6,7 @@ import (
 	"github.com/benbjohnson/clock"
 
 	"github.com/zitadel/zitadel/internal/logstore"
+	"github.com/zitadel/zitadel/internal/logstore/record"
 )
 
 var _ logstore.LogRecord[*Record] = (*Record)(nil)
@@ -14,12 +15,50 @@ func NewRecord(clock clock.Clock) *Record {
 	return &Record{ts: clock.Now()}
 }
 
+// NewInstanceRecord creates a record which is only counted for the usage of the given instance
+func NewInstanceRecord(clock clock.Clock, instanceID string) *Record {
+	return &Record{ts: clock.Now(), instanceID: instanceID}
+}
+
+// NewUnstampedRecord creates a record of the instance without timestamp,
+// which is set on emit if the storage is created with [WithEmitTimestamps]
+func NewUnstampedRecord(instanceID string) *Record {
+	return &Record{instanceID: instanceID}
+}
+
+// NewTargetExecutionRecord creates a record of a call of a target of the instance,
+// which is counted for the usage like the other records
+func NewTargetExecutionRecord(clock clock.Clock, instanceID string, payloadBytes int64, took time.Duration) *Record {
+	return &Record{
+		ts:           clock.Now(),
+		instanceID:   instanceID,
+		kind:         record.ExecutionKindTarget,
+		payloadBytes: payloadBytes,
+		took:         took,
+	}
+}
+
 type Record struct {
-	ts       time.Time
-	redacted bool
+	ts         time.Time
+	instanceID string
+	redacted   bool
+
+	kind         record.ExecutionKind
+	payloadBytes int64
+	took         time.Duration
 }
 
 func (r Record) Normalize() *Record {
 	r.redacted = true
 	return &r
 }
+
+// Timestamp returns the time the record is counted at
+func (r *Record) Timestamp() time.Time {
+	return r.ts
+}
+
+// Kind returns if the record is a call of a target
+func (r *Record) Kind() record.ExecutionKind {
+	return r.kind
+}
@@ -6,6 +6,16 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// ExecutionKind distinguishes the runs of actions from the calls of targets
+type ExecutionKind uint8
+
+const (
+	// ExecutionKindAction is the log of the run of an action
+	ExecutionKindAction ExecutionKind = iota
+	// ExecutionKindTarget is the call of a target
+	ExecutionKindTarget
+)
+
 type ExecutionLog struct {
 	LogDate    time.Time              `json:"logDate"`
 	Took       time.Duration          `json:"took"`
@@ -14,6 +24,33 @@ type ExecutionLog struct {
 	InstanceID string                 `json:"instanceId"`
 	ActionID   string                 `json:"actionId,omitempty"`
 	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	Kind       ExecutionKind          `json:"kind"`
+	// TargetID is the called target of an [ExecutionKindTarget] log
+	TargetID string `json:"targetId,omitempty"`
+	// PayloadBytes is the size of the payload sent to the target of an [ExecutionKindTarget] log
+	PayloadBytes int64 `json:"payloadBytes,omitempty"`
+	// StatusCode is the HTTP status the target responded with, 0 if it did not respond
+	StatusCode int `json:"statusCode,omitempty"`
+}
+
+// NewTargetExecutionLog returns the log of a call of a target which took the duration,
+// the error of a failed call is the message of the log
+func NewTargetExecutionLog(logDate time.Time, instanceID, targetID string, payloadBytes int64, statusCode int, took time.Duration, err error) *ExecutionLog {
+	log := &ExecutionLog{
+		LogDate:      logDate,
+		Took:         took,
+		LogLevel:     logrus.InfoLevel,
+		InstanceID:   instanceID,
+		Kind:         ExecutionKindTarget,
+		TargetID:     targetID,
+		PayloadBytes: payloadBytes,
+		StatusCode:   statusCode,
+	}
+	if err != nil {
+		log.Message = err.Error()
+		log.LogLevel = logrus.WarnLevel
+	}
+	return log
 }
 
 func (e ExecutionLog) Normalize() *ExecutionLog {
@@ -2,6 +2,7 @@ package logstore
 
 import (
 	"context"
+	"time"
 
 	"github.com/zitadel/logging"
 
@@ -25,6 +26,21 @@ type Queries interface {
 	GetRemainingQuotaUsage(ctx context.Context, instanceID string, unit quota.Unit) (remaining *uint64, err error)
 }
 
+// UsageByDayQuerier returns the usage of an instance in daily buckets, e.g. for usage dashboards.
+// Records between start (inclusive) and end (exclusive) are counted per UTC day,
+// each bucket is keyed by midnight UTC of its day.
+// Days without usage are omitted, callers which need continuous series must fill the gaps with zero.
+type UsageByDayQuerier interface {
+	QueryUsageByDay(ctx context.Context, instanceID string, start, end time.Time) (map[time.Time]uint64, error)
+}
+
+// EmitCounter reports the amount of records handed to Emit and the amount of them which were persisted,
+// e.g. to monitor the records ignored by the deduplication or sampling of at-least-once emitters.
+// Records removed after they were persisted, e.g. by the cleanup, still count as persisted.
+type EmitCounter interface {
+	EmitCounts() (emitted, persisted uint64)
+}
+
 func New[T LogRecord[T]](queries Queries, usageQuerierSink *emitter[T], additionalSink ...*emitter[T]) *Service[T] {
 	var usageStorer UsageStorer[T]
 	if usageQuerierSink != nil {
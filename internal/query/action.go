@@ -17,7 +17,8 @@ import (
 )
 
 const (
-	maxTimeout = 20 * time.Second
+	// DefaultMaxActionTimeout is the maximum duration an action runs, longer and unset timeouts are clamped to it
+	DefaultMaxActionTimeout = 20 * time.Second
 )
 
 var (
@@ -94,11 +95,12 @@ type Action struct {
 	AllowedToFail bool
 }
 
+// Timeout returns the timeout of the action clamped to [DefaultMaxActionTimeout]
 func (a *Action) Timeout() time.Duration {
-	if a.timeout > 0 && a.timeout < maxTimeout {
+	if a.timeout > 0 && a.timeout < DefaultMaxActionTimeout {
 		return a.timeout
 	}
-	return maxTimeout
+	return DefaultMaxActionTimeout
 }
 
 type ActionSearchQueries struct {
@@ -293,3 +293,33 @@ func Test_ActionPrepares(t *testing.T) {
 		})
 	}
 }
+
+func TestAction_Timeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{
+			name: "unset, default max",
+			want: DefaultMaxActionTimeout,
+		},
+		{
+			name:    "below max",
+			timeout: 5 * time.Second,
+			want:    5 * time.Second,
+		},
+		{
+			name:    "above max, clamped",
+			timeout: DefaultMaxActionTimeout + time.Second,
+			want:    DefaultMaxActionTimeout,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (&Action{timeout: tt.timeout}).Timeout(); got != tt.want {
+				t.Errorf("Timeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -11,8 +11,10 @@ import (
 	sq "github.com/Masterminds/squirrel"
 
 	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/execution"
 	"github.com/zitadel/zitadel/internal/query/projection"
 	exec "github.com/zitadel/zitadel/internal/repository/execution"
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
@@ -67,6 +69,8 @@ var (
 	TargetsByExecutionIDQuery string
 	//go:embed targets_by_execution_ids.sql
 	TargetsByExecutionIDsQuery string
+	//go:embed execution_target_by_id.sql
+	executionTargetByIDQuery string
 )
 
 type Executions struct {
@@ -170,7 +174,7 @@ func (q *Queries) TargetsByExecutionID(ctx context.Context, ids []string) (execu
 
 	err = q.client.QueryContext(ctx,
 		func(rows *sql.Rows) error {
-			execution, err = scanExecutionTargets(rows)
+			execution, err = scanExecutionTargets(rows, q.idpConfigEncryption)
 			return err
 		},
 		TargetsByExecutionIDQuery,
@@ -199,7 +203,7 @@ func (q *Queries) TargetsByExecutionIDs(ctx context.Context, ids1, ids2 []string
 
 	err = q.client.QueryContext(ctx,
 		func(rows *sql.Rows) error {
-			execution, err = scanExecutionTargets(rows)
+			execution, err = scanExecutionTargets(rows, q.idpConfigEncryption)
 			return err
 		},
 		TargetsByExecutionIDsQuery,
@@ -210,6 +214,31 @@ func (q *Queries) TargetsByExecutionIDs(ctx context.Context, ids1, ids2 []string
 	return execution, err
 }
 
+// ExecutionTargetByID returns the target of the instance in the context with the configuration it is called with,
+// it is used to deliver the payloads queued for the target
+func (q *Queries) ExecutionTargetByID(ctx context.Context, id string) (_ execution.Target, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	var targets []*ExecutionTarget
+	err = q.client.QueryContext(ctx,
+		func(rows *sql.Rows) error {
+			targets, err = scanExecutionTargets(rows, q.idpConfigEncryption)
+			return err
+		},
+		executionTargetByIDQuery,
+		authz.GetInstance(ctx).InstanceID(),
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, zerrors.ThrowNotFound(nil, "QUERY-Et6kNf", "Errors.Target.NotFound")
+	}
+	return targets[0], nil
+}
+
 func prepareExecutionQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(row *sql.Row) (*Execution, error)) {
 	return sq.Select(
 			ExecutionColumnInstanceID.identifier(),
@@ -354,6 +383,48 @@ type ExecutionTarget struct {
 	Endpoint         string
 	Timeout          time.Duration
 	InterruptOnError bool
+	SuccessCriteria  *domain.TargetSuccessCriteria
+	EventTypeFilter  []string
+	// CertificateFingerprint pins the certificate of the endpoint, empty if not pinned
+	CertificateFingerprint string
+	// CABundle are the PEM encoded certificate authorities the endpoint is verified against, empty for the system ones
+	CABundle string
+	// ResponseContentType is the expected content type of the responses, empty if there is no expectation
+	ResponseContentType string
+	// PayloadFormat is the format the payload is serialized in
+	PayloadFormat domain.TargetPayloadFormat
+	// PhaseTimeouts limit the phases of the calls, unset phases are 0
+	PhaseTimeouts domain.TargetPhaseTimeouts
+	// OAuth2Credentials are the decrypted client credentials, nil if the calls are not authorized
+	OAuth2Credentials *domain.TargetOAuth2Credentials
+	// BasicAuthCredentials are the decrypted basic authentication, nil if the calls are not authorized
+	BasicAuthCredentials *domain.TargetBasicAuthCredentials
+	// JWTDelivery signs the payload as JWT with the signing key, nil if the payload is delivered as it is
+	JWTDelivery *domain.TargetJWTDelivery
+	// MirrorEndpoints additionally receive the payload best-effort
+	MirrorEndpoints []string
+	// ResponseSizeLimit limits the size of the response bodies, the size is not limited if MaxBytes is 0
+	ResponseSizeLimit domain.TargetResponseSizeLimit
+	// BatchDelivery coalesces the payloads delivered to an async target into batches, the payloads are delivered one by one if MaxSize is 0
+	BatchDelivery domain.TargetBatchDelivery
+	// OrderedDelivery delivers the payloads of an async target one after the other
+	OrderedDelivery bool
+	// IncludePayload sends the payload of the events to the target, otherwise only their metadata
+	IncludePayload bool
+	// FailoverEndpoints are called in order if the endpoint fails, until one of them succeeds
+	FailoverEndpoints []string
+	// MaxRedirects is the amount of redirects followed, redirects are not followed if zero
+	MaxRedirects int
+	// RequestIDHeader is the name of the header the ID of the originating request is sent in, empty if it is not sent
+	RequestIDHeader string
+	// SigningKey is the decrypted primary key the requests are signed with, empty if they are not signed
+	SigningKey string
+	// SecondarySigningKey is the decrypted successor of the primary key, empty if the keys are not rotated
+	SecondarySigningKey string
+	// SecondarySigningKeyValidUntil ends the grace window of the secondary key
+	SecondarySigningKeyValidUntil time.Time
+	// Paused is true while the target is inactive, it is not called until it is resumed
+	Paused bool
 }
 
 func (e *ExecutionTarget) GetExecutionID() string {
@@ -374,8 +445,72 @@ func (e *ExecutionTarget) GetTargetType() domain.TargetType {
 func (e *ExecutionTarget) GetTimeout() time.Duration {
 	return e.Timeout
 }
+func (e *ExecutionTarget) GetSuccessCriteria() *domain.TargetSuccessCriteria {
+	return e.SuccessCriteria
+}
+func (e *ExecutionTarget) GetEventTypeFilter() []string {
+	return e.EventTypeFilter
+}
+func (e *ExecutionTarget) GetCertificateFingerprint() string {
+	return e.CertificateFingerprint
+}
+func (e *ExecutionTarget) GetCABundle() string {
+	return e.CABundle
+}
+func (e *ExecutionTarget) GetResponseContentType() string {
+	return e.ResponseContentType
+}
+func (e *ExecutionTarget) GetPhaseTimeouts() domain.TargetPhaseTimeouts {
+	return e.PhaseTimeouts
+}
+func (e *ExecutionTarget) GetPayloadFormat() domain.TargetPayloadFormat {
+	return e.PayloadFormat
+}
+func (e *ExecutionTarget) GetOAuth2Credentials() *domain.TargetOAuth2Credentials {
+	return e.OAuth2Credentials
+}
+func (e *ExecutionTarget) GetBasicAuthCredentials() *domain.TargetBasicAuthCredentials {
+	return e.BasicAuthCredentials
+}
+func (e *ExecutionTarget) GetJWTDelivery() *domain.TargetJWTDelivery {
+	return e.JWTDelivery
+}
+func (e *ExecutionTarget) GetMirrorEndpoints() []string {
+	return e.MirrorEndpoints
+}
+func (e *ExecutionTarget) GetResponseSizeLimit() domain.TargetResponseSizeLimit {
+	return e.ResponseSizeLimit
+}
+func (e *ExecutionTarget) GetBatchDelivery() domain.TargetBatchDelivery {
+	return e.BatchDelivery
+}
+func (e *ExecutionTarget) IsOrderedDelivery() bool {
+	return e.OrderedDelivery
+}
+func (e *ExecutionTarget) IsIncludePayload() bool {
+	return e.IncludePayload
+}
+func (e *ExecutionTarget) GetFailoverEndpoints() []string {
+	return e.FailoverEndpoints
+}
+func (e *ExecutionTarget) GetMaxRedirects() int {
+	return e.MaxRedirects
+}
+func (e *ExecutionTarget) GetRequestIDHeader() string {
+	return e.RequestIDHeader
+}
+func (e *ExecutionTarget) GetSigningKey() string {
+	return e.SigningKey
+}
+func (e *ExecutionTarget) GetSecondarySigningKey() (string, time.Time) {
+	return e.SecondarySigningKey, e.SecondarySigningKeyValidUntil
+}
+func (e *ExecutionTarget) IsPaused() bool {
+	return e.Paused
+}
 
-func scanExecutionTargets(rows *sql.Rows) ([]*ExecutionTarget, error) {
+// scanExecutionTargets scans the targets to call, the signing keys, client secrets and passwords are decrypted with the secretCrypto
+func scanExecutionTargets(rows *sql.Rows, secretCrypto crypto.EncryptionAlgorithm) ([]*ExecutionTarget, error) {
 	targets := make([]*ExecutionTarget, 0)
 	for rows.Next() {
 		target := new(ExecutionTarget)
@@ -388,6 +523,30 @@ func scanExecutionTargets(rows *sql.Rows) ([]*ExecutionTarget, error) {
 			endpoint         = &sql.NullString{}
 			timeout          = &sql.NullInt64{}
 			interruptOnError = &sql.NullBool{}
+			successCriteria  []byte
+			eventTypeFilter  database.TextArray[string]
+			fingerprint      = &sql.NullString{}
+			contentType      = &sql.NullString{}
+			dialTimeout      = &sql.NullInt64{}
+			tlsTimeout       = &sql.NullInt64{}
+			headerTimeout    = &sql.NullInt64{}
+			oauth2           []byte
+			basicAuth        []byte
+			payloadFormat    = &sql.NullInt32{}
+			mirrorEndpoints  database.TextArray[string]
+			sizeLimit        = &sql.NullInt64{}
+			sizePolicy       = &sql.NullInt32{}
+			batchMaxSize     = &sql.NullInt64{}
+			batchMaxWait     = &sql.NullInt64{}
+			orderedDelivery  = &sql.NullBool{}
+			includePayload   = &sql.NullBool{}
+			caBundle         = &sql.NullString{}
+			jwtDelivery      []byte
+			failovers        database.TextArray[string]
+			maxRedirects     = &sql.NullInt64{}
+			requestIDHeader  = &sql.NullString{}
+			state            = &sql.NullInt32{}
+			signingKeys      []byte
 		)
 
 		err := rows.Scan(
@@ -398,8 +557,55 @@ func scanExecutionTargets(rows *sql.Rows) ([]*ExecutionTarget, error) {
 			endpoint,
 			timeout,
 			interruptOnError,
+			&successCriteria,
+			&eventTypeFilter,
+			fingerprint,
+			contentType,
+			dialTimeout,
+			tlsTimeout,
+			headerTimeout,
+			&oauth2,
+			&basicAuth,
+			payloadFormat,
+			&mirrorEndpoints,
+			sizeLimit,
+			sizePolicy,
+			batchMaxSize,
+			batchMaxWait,
+			orderedDelivery,
+			includePayload,
+			caBundle,
+			&jwtDelivery,
+			&failovers,
+			maxRedirects,
+			requestIDHeader,
+			state,
+			&signingKeys,
 		)
 
+		if err != nil {
+			return nil, err
+		}
+		target.SuccessCriteria, err = unmarshalSuccessCriteria(successCriteria)
+		if err != nil {
+			return nil, err
+		}
+		target.OAuth2Credentials, err = decryptOAuth2(oauth2, secretCrypto)
+		if err != nil {
+			return nil, err
+		}
+		target.BasicAuthCredentials, err = decryptBasicAuth(basicAuth, secretCrypto)
+		if err != nil {
+			return nil, err
+		}
+		delivery, err := unmarshalJWTDelivery(jwtDelivery)
+		if err != nil {
+			return nil, err
+		}
+		if !delivery.IsZero() {
+			target.JWTDelivery = delivery
+		}
+		target.SigningKey, target.SecondarySigningKey, target.SecondarySigningKeyValidUntil, err = decryptSigningKeys(signingKeys, secretCrypto)
 		if err != nil {
 			return nil, err
 		}
@@ -408,9 +614,35 @@ func scanExecutionTargets(rows *sql.Rows) ([]*ExecutionTarget, error) {
 		target.ExecutionID = executionID.String
 		target.TargetID = targetID.String
 		target.TargetType = domain.TargetType(targetType.Int32)
+		target.PayloadFormat = domain.TargetPayloadFormat(payloadFormat.Int32)
 		target.Endpoint = endpoint.String
 		target.Timeout = time.Duration(timeout.Int64)
 		target.InterruptOnError = interruptOnError.Bool
+		target.EventTypeFilter = eventTypeFilter
+		target.MirrorEndpoints = mirrorEndpoints
+		target.FailoverEndpoints = failovers
+		target.MaxRedirects = int(maxRedirects.Int64)
+		target.RequestIDHeader = requestIDHeader.String
+		target.Paused = domain.TargetState(state.Int32) == domain.TargetInactive
+		target.ResponseSizeLimit = domain.TargetResponseSizeLimit{
+			MaxBytes: sizeLimit.Int64,
+			Policy:   domain.TargetResponseSizePolicy(sizePolicy.Int32),
+		}
+		target.BatchDelivery = domain.TargetBatchDelivery{
+			MaxSize: int(batchMaxSize.Int64),
+			MaxWait: time.Duration(batchMaxWait.Int64),
+		}
+		target.OrderedDelivery = orderedDelivery.Bool
+		// the payload is included by default
+		target.IncludePayload = !includePayload.Valid || includePayload.Bool
+		target.CertificateFingerprint = fingerprint.String
+		target.CABundle = caBundle.String
+		target.ResponseContentType = contentType.String
+		target.PhaseTimeouts = domain.TargetPhaseTimeouts{
+			Dial:           time.Duration(dialTimeout.Int64),
+			TLSHandshake:   time.Duration(tlsTimeout.Int64),
+			ResponseHeader: time.Duration(headerTimeout.Int64),
+		}
 
 		targets = append(targets, target)
 	}
@@ -421,3 +653,61 @@ func scanExecutionTargets(rows *sql.Rows) ([]*ExecutionTarget, error) {
 
 	return targets, nil
 }
+
+// decryptOAuth2 returns the client credentials of a target with the decrypted client secret
+func decryptOAuth2(data []byte, secretCrypto crypto.EncryptionAlgorithm) (*domain.TargetOAuth2Credentials, error) {
+	oauth2, err := unmarshalOAuth2(data)
+	if err != nil || oauth2.IsZero() {
+		return nil, err
+	}
+	credentials := &domain.TargetOAuth2Credentials{
+		TokenEndpoint: oauth2.TokenEndpoint,
+		ClientID:      oauth2.ClientID,
+	}
+	if oauth2.ClientSecret != nil {
+		credentials.ClientSecret, err = crypto.DecryptString(oauth2.ClientSecret, secretCrypto)
+		if err != nil {
+			return nil, zerrors.ThrowInternal(err, "QUERY-Oa2Dc3", "Errors.Internal")
+		}
+	}
+	return credentials, nil
+}
+
+// decryptBasicAuth returns the basic authentication of a target with the decrypted password
+func decryptBasicAuth(data []byte, secretCrypto crypto.EncryptionAlgorithm) (*domain.TargetBasicAuthCredentials, error) {
+	basicAuth, err := unmarshalBasicAuth(data)
+	if err != nil || basicAuth.IsZero() {
+		return nil, err
+	}
+	credentials := &domain.TargetBasicAuthCredentials{
+		Username: basicAuth.Username,
+	}
+	if basicAuth.Password != nil {
+		credentials.Password, err = crypto.DecryptString(basicAuth.Password, secretCrypto)
+		if err != nil {
+			return nil, zerrors.ThrowInternal(err, "QUERY-Ba6Dc4", "Errors.Internal")
+		}
+	}
+	return credentials, nil
+}
+
+// decryptSigningKeys returns the signing keys of a target decrypted, the primary key is empty if the requests are not signed.
+// The secondary key is returned with the end of its grace window, the window is checked when the requests are signed.
+func decryptSigningKeys(data []byte, secretCrypto crypto.EncryptionAlgorithm) (primary, secondary string, secondaryValidUntil time.Time, err error) {
+	keys, err := unmarshalSigningKeys(data)
+	if err != nil || keys.IsZero() {
+		return "", "", time.Time{}, err
+	}
+	primary, err = crypto.DecryptString(keys.Primary, secretCrypto)
+	if err != nil {
+		return "", "", time.Time{}, zerrors.ThrowInternal(err, "QUERY-Sk6Dc2", "Errors.Internal")
+	}
+	if keys.Secondary == nil {
+		return primary, "", time.Time{}, nil
+	}
+	secondary, err = crypto.DecryptString(keys.Secondary, secretCrypto)
+	if err != nil {
+		return "", "", time.Time{}, zerrors.ThrowInternal(err, "QUERY-Sk6Dc3", "Errors.Internal")
+	}
+	return primary, secondary, keys.SecondaryValidUntil, nil
+}
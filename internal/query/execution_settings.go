@@ -0,0 +1,85 @@
+package query
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/execution"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+)
+
+// ExecutionSettings are the settings of the instance for the calls of its targets
+type ExecutionSettings struct {
+	Details *domain.ObjectDetails
+	// AsyncPoolSize is the amount of async targets called concurrently, 0 if the default pool size is used
+	AsyncPoolSize int
+	// TargetQueueDepth is the amount of payloads queued per paused async target, 0 if the default depth is used
+	TargetQueueDepth int
+	// TargetQueueOverflow defines what happens to the events exceeding the queue of a paused target
+	TargetQueueOverflow domain.TargetQueueOverflow
+}
+
+type executionSettingsReadModel struct {
+	*eventstore.ReadModel
+	settings *ExecutionSettings
+}
+
+func newExecutionSettingsReadModel(ctx context.Context) *executionSettingsReadModel {
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	return &executionSettingsReadModel{
+		ReadModel: &eventstore.ReadModel{
+			AggregateID:   instanceID,
+			ResourceOwner: instanceID,
+		},
+		settings: new(ExecutionSettings),
+	}
+}
+
+func (m *executionSettingsReadModel) Reduce() error {
+	for _, event := range m.Events {
+		if e, ok := event.(*instance.ExecutionSettingsSetEvent); ok {
+			m.settings.AsyncPoolSize = e.AsyncPoolSize
+			m.settings.TargetQueueDepth = e.TargetQueueDepth
+			m.settings.TargetQueueOverflow = e.TargetQueueOverflow
+		}
+	}
+	return m.ReadModel.Reduce()
+}
+
+func (m *executionSettingsReadModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AwaitOpenTransactions().
+		ResourceOwner(m.ResourceOwner).
+		AddQuery().
+		AggregateTypes(instance.AggregateType).
+		AggregateIDs(m.AggregateID).
+		EventTypes(instance.ExecutionSettingsSetEventType).
+		Builder()
+}
+
+// GetExecutionSettings returns the execution settings of the instance in the context
+func (q *Queries) GetExecutionSettings(ctx context.Context) (_ *ExecutionSettings, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	m := newExecutionSettingsReadModel(ctx)
+	if err = q.eventstore.FilterToQueryReducer(ctx, m); err != nil {
+		return nil, err
+	}
+	m.settings.Details = readModelToObjectDetails(m.ReadModel)
+	return m.settings, nil
+}
+
+// WithExecutionSettings returns the context with the execution settings of the instance in the context,
+// which are applied to the calls of its targets
+func (q *Queries) WithExecutionSettings(ctx context.Context) (context.Context, error) {
+	settings, err := q.GetExecutionSettings(ctx)
+	if err != nil {
+		return ctx, err
+	}
+	ctx = execution.WithAsyncPoolSize(ctx, settings.AsyncPoolSize)
+	return execution.WithTargetQueue(ctx, settings.TargetQueueDepth, settings.TargetQueueOverflow), nil
+}
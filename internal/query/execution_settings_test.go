@@ -0,0 +1,73 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+func TestQueries_GetExecutionSettings(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance1")
+	aggregate := &instance.NewAggregate("instance1").Aggregate
+
+	tests := []struct {
+		name       string
+		eventstore func(*testing.T) *eventstore.Eventstore
+		want       *ExecutionSettings
+		wantErr    error
+	}{
+		{
+			name: "filter error",
+			eventstore: expectEventstore(
+				expectFilterError(io.ErrClosedPipe),
+			),
+			wantErr: io.ErrClosedPipe,
+		},
+		{
+			name: "no settings set",
+			eventstore: expectEventstore(
+				expectFilter(),
+			),
+			want: &ExecutionSettings{
+				Details: &domain.ObjectDetails{
+					ResourceOwner: "instance1",
+				},
+			},
+		},
+		{
+			name: "latest settings",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(instance.NewExecutionSettingsSetEvent(ctx, aggregate, &domain.ExecutionSettings{AsyncPoolSize: 1})),
+					eventFromEventPusher(instance.NewExecutionSettingsSetEvent(ctx, aggregate, &domain.ExecutionSettings{AsyncPoolSize: 5, TargetQueueDepth: 20, TargetQueueOverflow: domain.TargetQueueOverflowFail})),
+				),
+			),
+			want: &ExecutionSettings{
+				Details: &domain.ObjectDetails{
+					ResourceOwner: "instance1",
+				},
+				AsyncPoolSize:       5,
+				TargetQueueDepth:    20,
+				TargetQueueOverflow: domain.TargetQueueOverflowFail,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &Queries{
+				eventstore: tt.eventstore(t),
+			}
+			got, err := q.GetExecutionSettings(ctx)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
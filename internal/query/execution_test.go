@@ -7,7 +7,13 @@ import (
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zitadel/zitadel/internal/crypto"
 	"github.com/zitadel/zitadel/internal/domain"
 	exec "github.com/zitadel/zitadel/internal/repository/execution"
 	"github.com/zitadel/zitadel/internal/zerrors"
@@ -267,3 +273,69 @@ func Test_ExecutionPrepares(t *testing.T) {
 		})
 	}
 }
+
+func Test_decryptOAuth2(t *testing.T) {
+	secretCrypto := crypto.CreateMockEncryptionAlg(gomock.NewController(t))
+
+	credentials, err := decryptOAuth2(nil, secretCrypto)
+	require.NoError(t, err)
+	assert.Nil(t, credentials)
+
+	credentials, err = decryptOAuth2([]byte(`{"tokenEndpoint":"https://auth.example.com/oauth/token","clientID":"client","clientSecret":{"CryptoType":0,"Algorithm":"enc","KeyID":"id","Crypted":"c2VjcmV0"}}`), secretCrypto)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.TargetOAuth2Credentials{
+		TokenEndpoint: "https://auth.example.com/oauth/token",
+		ClientID:      "client",
+		ClientSecret:  "secret",
+	}, credentials)
+
+	_, err = decryptOAuth2([]byte(`{"tokenEndpoint":"https://auth.example.com/oauth/token","clientID":"client","clientSecret":{"CryptoType":0,"Algorithm":"enc","KeyID":"unknown","Crypted":"c2VjcmV0"}}`), secretCrypto)
+	assert.ErrorIs(t, err, zerrors.ThrowInternal(nil, "QUERY-Oa2Dc3", "Errors.Internal"))
+}
+
+func Test_decryptBasicAuth(t *testing.T) {
+	secretCrypto := crypto.CreateMockEncryptionAlg(gomock.NewController(t))
+
+	credentials, err := decryptBasicAuth(nil, secretCrypto)
+	require.NoError(t, err)
+	assert.Nil(t, credentials)
+
+	credentials, err = decryptBasicAuth([]byte(`{"username":"user","password":{"CryptoType":0,"Algorithm":"enc","KeyID":"id","Crypted":"cGFzc3dvcmQ="}}`), secretCrypto)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.TargetBasicAuthCredentials{
+		Username: "user",
+		Password: "password",
+	}, credentials)
+
+	_, err = decryptBasicAuth([]byte(`{"username":"user","password":{"CryptoType":0,"Algorithm":"enc","KeyID":"unknown","Crypted":"cGFzc3dvcmQ="}}`), secretCrypto)
+	assert.ErrorIs(t, err, zerrors.ThrowInternal(nil, "QUERY-Ba6Dc4", "Errors.Internal"))
+}
+
+func Test_decryptSigningKeys(t *testing.T) {
+	secretCrypto := crypto.CreateMockEncryptionAlg(gomock.NewController(t))
+
+	primary, secondary, validUntil, err := decryptSigningKeys(nil, secretCrypto)
+	require.NoError(t, err)
+	assert.Empty(t, primary)
+	assert.Empty(t, secondary)
+	assert.True(t, validUntil.IsZero())
+
+	primary, secondary, validUntil, err = decryptSigningKeys([]byte(`{"primary":{"CryptoType":0,"Algorithm":"enc","KeyID":"id","Crypted":"cHJpbWFyeQ=="}}`), secretCrypto)
+	require.NoError(t, err)
+	assert.Equal(t, "primary", primary)
+	assert.Empty(t, secondary)
+	assert.True(t, validUntil.IsZero())
+
+	// the secondary key is returned with its grace window during the rotation
+	primary, secondary, validUntil, err = decryptSigningKeys([]byte(`{"primary":{"CryptoType":0,"Algorithm":"enc","KeyID":"id","Crypted":"cHJpbWFyeQ=="},"secondary":{"CryptoType":0,"Algorithm":"enc","KeyID":"id","Crypted":"c2Vjb25kYXJ5"},"secondaryValidUntil":"2024-01-01T01:00:00Z"}`), secretCrypto)
+	require.NoError(t, err)
+	assert.Equal(t, "primary", primary)
+	assert.Equal(t, "secondary", secondary)
+	assert.Equal(t, time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC), validUntil.UTC())
+
+	_, _, _, err = decryptSigningKeys([]byte(`{"primary":{"CryptoType":0,"Algorithm":"enc","KeyID":"unknown","Crypted":"cHJpbWFyeQ=="}}`), secretCrypto)
+	assert.ErrorIs(t, err, zerrors.ThrowInternal(nil, "QUERY-Sk6Dc2", "Errors.Internal"))
+
+	_, _, _, err = decryptSigningKeys([]byte(`{"primary":{"CryptoType":0,"Algorithm":"enc","KeyID":"id","Crypted":"cHJpbWFyeQ=="},"secondary":{"CryptoType":0,"Algorithm":"enc","KeyID":"unknown","Crypted":"c2Vjb25kYXJ5"},"secondaryValidUntil":"2024-01-01T01:00:00Z"}`), secretCrypto)
+	assert.ErrorIs(t, err, zerrors.ThrowInternal(nil, "QUERY-Sk6Dc3", "Errors.Internal"))
+}
@@ -3,6 +3,9 @@ package projection
 import (
 	"context"
 
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	old_handler "github.com/zitadel/zitadel/internal/eventstore/handler"
 	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
@@ -11,18 +14,42 @@ import (
 )
 
 const (
-	TargetTable               = "projections.targets1"
-	TargetIDCol               = "id"
-	TargetCreationDateCol     = "creation_date"
-	TargetChangeDateCol       = "change_date"
-	TargetResourceOwnerCol    = "resource_owner"
-	TargetInstanceIDCol       = "instance_id"
-	TargetSequenceCol         = "sequence"
-	TargetNameCol             = "name"
-	TargetTargetType          = "target_type"
-	TargetEndpointCol         = "endpoint"
-	TargetTimeoutCol          = "timeout"
-	TargetInterruptOnErrorCol = "interrupt_on_error"
+	TargetTable                     = "projections.targets2"
+	TargetIDCol                     = "id"
+	TargetCreationDateCol           = "creation_date"
+	TargetChangeDateCol             = "change_date"
+	TargetResourceOwnerCol          = "resource_owner"
+	TargetInstanceIDCol             = "instance_id"
+	TargetSequenceCol               = "sequence"
+	TargetNameCol                   = "name"
+	TargetTargetType                = "target_type"
+	TargetEndpointCol               = "endpoint"
+	TargetTimeoutCol                = "timeout"
+	TargetInterruptOnErrorCol       = "interrupt_on_error"
+	TargetSuccessCriteriaCol        = "success_criteria"
+	TargetEventTypeFilterCol        = "event_type_filter"
+	TargetCertificateFingerprintCol = "certificate_fingerprint"
+	TargetResponseContentTypeCol    = "response_content_type"
+	TargetDialTimeoutCol            = "dial_timeout"
+	TargetTLSHandshakeTimeoutCol    = "tls_handshake_timeout"
+	TargetResponseHeaderTimeoutCol  = "response_header_timeout"
+	TargetOAuth2Col                 = "oauth2"
+	TargetBasicAuthCol              = "basic_auth"
+	TargetPayloadFormatCol          = "payload_format"
+	TargetMirrorEndpointsCol        = "mirror_endpoints"
+	TargetResponseSizeLimitCol      = "response_size_limit"
+	TargetResponseSizePolicyCol     = "response_size_policy"
+	TargetBatchMaxSizeCol           = "batch_max_size"
+	TargetBatchMaxWaitCol           = "batch_max_wait"
+	TargetOrderedDeliveryCol        = "ordered_delivery"
+	TargetIncludePayloadCol         = "include_payload"
+	TargetCABundleCol               = "ca_bundle"
+	TargetJWTDeliveryCol            = "jwt_delivery"
+	TargetFailoverEndpointsCol      = "failover_endpoints"
+	TargetMaxRedirectsCol           = "max_redirects"
+	TargetRequestIDHeaderCol        = "request_id_header"
+	TargetStateCol                  = "state"
+	TargetSigningKeysCol            = "signing_keys"
 )
 
 type targetProjection struct{}
@@ -31,6 +58,14 @@ func newTargetProjection(ctx context.Context, config handler.Config) *handler.Ha
 	return handler.NewHandler(ctx, &config, new(targetProjection))
 }
 
+// ReprojectTarget rebuilds the row of the target of the instance in the context from its events
+func ReprojectTarget(ctx context.Context, id string) error {
+	return TargetProjection.ReprojectAggregate(ctx, target.AggregateType, id, []handler.Condition{
+		handler.NewCond(TargetInstanceIDCol, authz.GetInstance(ctx).InstanceID()),
+		handler.NewCond(TargetIDCol, id),
+	})
+}
+
 func (*targetProjection) Name() string {
 	return TargetTable
 }
@@ -49,6 +84,30 @@ func (*targetProjection) Init() *old_handler.Check {
 			handler.NewColumn(TargetEndpointCol, handler.ColumnTypeText),
 			handler.NewColumn(TargetTimeoutCol, handler.ColumnTypeInt64),
 			handler.NewColumn(TargetInterruptOnErrorCol, handler.ColumnTypeBool),
+			handler.NewColumn(TargetSuccessCriteriaCol, handler.ColumnTypeJSONB, handler.Nullable()),
+			handler.NewColumn(TargetEventTypeFilterCol, handler.ColumnTypeTextArray, handler.Nullable()),
+			handler.NewColumn(TargetCertificateFingerprintCol, handler.ColumnTypeText, handler.Default("")),
+			handler.NewColumn(TargetResponseContentTypeCol, handler.ColumnTypeText, handler.Default("")),
+			handler.NewColumn(TargetDialTimeoutCol, handler.ColumnTypeInt64, handler.Default(0)),
+			handler.NewColumn(TargetTLSHandshakeTimeoutCol, handler.ColumnTypeInt64, handler.Default(0)),
+			handler.NewColumn(TargetResponseHeaderTimeoutCol, handler.ColumnTypeInt64, handler.Default(0)),
+			handler.NewColumn(TargetOAuth2Col, handler.ColumnTypeJSONB, handler.Nullable()),
+			handler.NewColumn(TargetBasicAuthCol, handler.ColumnTypeJSONB, handler.Nullable()),
+			handler.NewColumn(TargetPayloadFormatCol, handler.ColumnTypeEnum, handler.Default(0)),
+			handler.NewColumn(TargetMirrorEndpointsCol, handler.ColumnTypeTextArray, handler.Nullable()),
+			handler.NewColumn(TargetResponseSizeLimitCol, handler.ColumnTypeInt64, handler.Default(0)),
+			handler.NewColumn(TargetResponseSizePolicyCol, handler.ColumnTypeEnum, handler.Default(0)),
+			handler.NewColumn(TargetBatchMaxSizeCol, handler.ColumnTypeInt64, handler.Default(0)),
+			handler.NewColumn(TargetBatchMaxWaitCol, handler.ColumnTypeInt64, handler.Default(0)),
+			handler.NewColumn(TargetOrderedDeliveryCol, handler.ColumnTypeBool, handler.Default(false)),
+			handler.NewColumn(TargetIncludePayloadCol, handler.ColumnTypeBool, handler.Default(true)),
+			handler.NewColumn(TargetCABundleCol, handler.ColumnTypeText, handler.Default("")),
+			handler.NewColumn(TargetJWTDeliveryCol, handler.ColumnTypeJSONB, handler.Nullable()),
+			handler.NewColumn(TargetFailoverEndpointsCol, handler.ColumnTypeTextArray, handler.Nullable()),
+			handler.NewColumn(TargetMaxRedirectsCol, handler.ColumnTypeInt64, handler.Default(0)),
+			handler.NewColumn(TargetRequestIDHeaderCol, handler.ColumnTypeText, handler.Default("")),
+			handler.NewColumn(TargetStateCol, handler.ColumnTypeEnum, handler.Default(domain.TargetActive)),
+			handler.NewColumn(TargetSigningKeysCol, handler.ColumnTypeJSONB, handler.Nullable()),
 		},
 			handler.NewPrimaryKey(TargetInstanceIDCol, TargetIDCol),
 		),
@@ -72,6 +131,14 @@ func (p *targetProjection) Reducers() []handler.AggregateReducer {
 					Event:  target.RemovedEventType,
 					Reduce: p.reduceTargetRemoved,
 				},
+				{
+					Event:  target.PausedEventType,
+					Reduce: p.reduceTargetPaused,
+				},
+				{
+					Event:  target.ResumedEventType,
+					Reduce: p.reduceTargetResumed,
+				},
 			},
 		},
 		{
@@ -91,22 +158,40 @@ func (p *targetProjection) reduceTargetAdded(event eventstore.Event) (*handler.S
 	if err != nil {
 		return nil, err
 	}
-	return handler.NewCreateStatement(
-		e,
-		[]handler.Column{
-			handler.NewCol(TargetInstanceIDCol, e.Aggregate().InstanceID),
-			handler.NewCol(TargetResourceOwnerCol, e.Aggregate().ResourceOwner),
-			handler.NewCol(TargetIDCol, e.Aggregate().ID),
-			handler.NewCol(TargetCreationDateCol, e.CreationDate()),
-			handler.NewCol(TargetChangeDateCol, e.CreationDate()),
-			handler.NewCol(TargetSequenceCol, e.Sequence()),
-			handler.NewCol(TargetNameCol, e.Name),
-			handler.NewCol(TargetEndpointCol, e.Endpoint),
-			handler.NewCol(TargetTargetType, e.TargetType),
-			handler.NewCol(TargetTimeoutCol, e.Timeout),
-			handler.NewCol(TargetInterruptOnErrorCol, e.InterruptOnError),
-		},
-	), nil
+	columns := []handler.Column{
+		handler.NewCol(TargetInstanceIDCol, e.Aggregate().InstanceID),
+		handler.NewCol(TargetResourceOwnerCol, e.Aggregate().ResourceOwner),
+		handler.NewCol(TargetIDCol, e.Aggregate().ID),
+		handler.NewCol(TargetCreationDateCol, e.CreationDate()),
+		handler.NewCol(TargetChangeDateCol, e.CreationDate()),
+		handler.NewCol(TargetSequenceCol, e.Sequence()),
+		handler.NewCol(TargetNameCol, e.Name),
+		handler.NewCol(TargetEndpointCol, e.Endpoint),
+		handler.NewCol(TargetTargetType, e.TargetType),
+		handler.NewCol(TargetTimeoutCol, e.Timeout),
+		handler.NewCol(TargetInterruptOnErrorCol, e.InterruptOnError),
+		handler.NewCol(TargetSuccessCriteriaCol, successCriteriaValue(e.SuccessCriteria)),
+		handler.NewCol(TargetEventTypeFilterCol, database.TextArray[string](e.EventTypeFilter)),
+		handler.NewCol(TargetCertificateFingerprintCol, e.CertificateFingerprint),
+		handler.NewCol(TargetResponseContentTypeCol, e.ResponseContentType),
+		handler.NewCol(TargetOAuth2Col, oauth2Value(e.OAuth2)),
+		handler.NewCol(TargetBasicAuthCol, basicAuthValue(e.BasicAuth)),
+		handler.NewCol(TargetPayloadFormatCol, e.PayloadFormat),
+		handler.NewCol(TargetMirrorEndpointsCol, database.TextArray[string](e.MirrorEndpoints)),
+		handler.NewCol(TargetOrderedDeliveryCol, e.OrderedDelivery),
+		handler.NewCol(TargetIncludePayloadCol, e.PayloadIncluded()),
+		handler.NewCol(TargetCABundleCol, e.CABundle),
+		handler.NewCol(TargetJWTDeliveryCol, jwtDeliveryValue(e.JWTDelivery)),
+		handler.NewCol(TargetFailoverEndpointsCol, database.TextArray[string](e.FailoverEndpoints)),
+		handler.NewCol(TargetMaxRedirectsCol, e.MaxRedirects),
+		handler.NewCol(TargetRequestIDHeaderCol, e.RequestIDHeader),
+		handler.NewCol(TargetStateCol, domain.TargetActive),
+		handler.NewCol(TargetSigningKeysCol, signingKeysValue(e.SigningKeys)),
+	}
+	columns = append(columns, phaseTimeoutColumns(e.PhaseTimeouts)...)
+	columns = append(columns, responseSizeLimitColumns(e.ResponseSizeLimit)...)
+	columns = append(columns, batchDeliveryColumns(e.BatchDelivery)...)
+	return handler.NewCreateStatement(e, columns), nil
 }
 
 func (p *targetProjection) reduceTargetChanged(event eventstore.Event) (*handler.Statement, error) {
@@ -134,6 +219,63 @@ func (p *targetProjection) reduceTargetChanged(event eventstore.Event) (*handler
 	if e.InterruptOnError != nil {
 		values = append(values, handler.NewCol(TargetInterruptOnErrorCol, *e.InterruptOnError))
 	}
+	if e.SuccessCriteria != nil {
+		values = append(values, handler.NewCol(TargetSuccessCriteriaCol, successCriteriaValue(e.SuccessCriteria)))
+	}
+	if e.EventTypeFilter != nil {
+		values = append(values, handler.NewCol(TargetEventTypeFilterCol, database.TextArray[string](*e.EventTypeFilter)))
+	}
+	if e.CertificateFingerprint != nil {
+		values = append(values, handler.NewCol(TargetCertificateFingerprintCol, *e.CertificateFingerprint))
+	}
+	if e.ResponseContentType != nil {
+		values = append(values, handler.NewCol(TargetResponseContentTypeCol, *e.ResponseContentType))
+	}
+	if e.PhaseTimeouts != nil {
+		values = append(values, phaseTimeoutColumns(e.PhaseTimeouts)...)
+	}
+	if e.OAuth2 != nil {
+		values = append(values, handler.NewCol(TargetOAuth2Col, oauth2Value(e.OAuth2)))
+	}
+	if e.BasicAuth != nil {
+		values = append(values, handler.NewCol(TargetBasicAuthCol, basicAuthValue(e.BasicAuth)))
+	}
+	if e.PayloadFormat != nil {
+		values = append(values, handler.NewCol(TargetPayloadFormatCol, *e.PayloadFormat))
+	}
+	if e.MirrorEndpoints != nil {
+		values = append(values, handler.NewCol(TargetMirrorEndpointsCol, database.TextArray[string](*e.MirrorEndpoints)))
+	}
+	if e.ResponseSizeLimit != nil {
+		values = append(values, responseSizeLimitColumns(e.ResponseSizeLimit)...)
+	}
+	if e.BatchDelivery != nil {
+		values = append(values, batchDeliveryColumns(e.BatchDelivery)...)
+	}
+	if e.OrderedDelivery != nil {
+		values = append(values, handler.NewCol(TargetOrderedDeliveryCol, *e.OrderedDelivery))
+	}
+	if e.IncludePayload != nil {
+		values = append(values, handler.NewCol(TargetIncludePayloadCol, *e.IncludePayload))
+	}
+	if e.CABundle != nil {
+		values = append(values, handler.NewCol(TargetCABundleCol, *e.CABundle))
+	}
+	if e.JWTDelivery != nil {
+		values = append(values, handler.NewCol(TargetJWTDeliveryCol, jwtDeliveryValue(e.JWTDelivery)))
+	}
+	if e.FailoverEndpoints != nil {
+		values = append(values, handler.NewCol(TargetFailoverEndpointsCol, database.TextArray[string](*e.FailoverEndpoints)))
+	}
+	if e.MaxRedirects != nil {
+		values = append(values, handler.NewCol(TargetMaxRedirectsCol, *e.MaxRedirects))
+	}
+	if e.RequestIDHeader != nil {
+		values = append(values, handler.NewCol(TargetRequestIDHeaderCol, *e.RequestIDHeader))
+	}
+	if e.SigningKeys != nil {
+		values = append(values, handler.NewCol(TargetSigningKeysCol, signingKeysValue(e.SigningKeys)))
+	}
 	return handler.NewUpdateStatement(
 		e,
 		values,
@@ -157,3 +299,108 @@ func (p *targetProjection) reduceTargetRemoved(event eventstore.Event) (*handler
 		},
 	), nil
 }
+
+func (p *targetProjection) reduceTargetPaused(event eventstore.Event) (*handler.Statement, error) {
+	e, err := assertEvent[*target.PausedEvent](event)
+	if err != nil {
+		return nil, err
+	}
+	return targetStateStatement(e, domain.TargetInactive), nil
+}
+
+func (p *targetProjection) reduceTargetResumed(event eventstore.Event) (*handler.Statement, error) {
+	e, err := assertEvent[*target.ResumedEvent](event)
+	if err != nil {
+		return nil, err
+	}
+	return targetStateStatement(e, domain.TargetActive), nil
+}
+
+func targetStateStatement(e eventstore.Event, state domain.TargetState) *handler.Statement {
+	return handler.NewUpdateStatement(
+		e,
+		[]handler.Column{
+			handler.NewCol(TargetChangeDateCol, e.CreatedAt()),
+			handler.NewCol(TargetSequenceCol, e.Sequence()),
+			handler.NewCol(TargetStateCol, state),
+		},
+		[]handler.Condition{
+			handler.NewCond(TargetInstanceIDCol, e.Aggregate().InstanceID),
+			handler.NewCond(TargetIDCol, e.Aggregate().ID),
+		},
+	)
+}
+
+// successCriteriaValue maps empty criteria to NULL
+func successCriteriaValue(criteria *domain.TargetSuccessCriteria) any {
+	if criteria == nil || (len(criteria.StatusCodes) == 0 && criteria.BodyPath == "") {
+		return nil
+	}
+	return criteria
+}
+
+// phaseTimeoutColumns returns the columns of all phases, unset phases are stored as 0
+func phaseTimeoutColumns(timeouts *domain.TargetPhaseTimeouts) []handler.Column {
+	if timeouts == nil {
+		timeouts = new(domain.TargetPhaseTimeouts)
+	}
+	return []handler.Column{
+		handler.NewCol(TargetDialTimeoutCol, timeouts.Dial),
+		handler.NewCol(TargetTLSHandshakeTimeoutCol, timeouts.TLSHandshake),
+		handler.NewCol(TargetResponseHeaderTimeoutCol, timeouts.ResponseHeader),
+	}
+}
+
+// responseSizeLimitColumns returns the columns of the response size limit, no limit is stored as 0
+func responseSizeLimitColumns(limit *domain.TargetResponseSizeLimit) []handler.Column {
+	if limit.IsZero() {
+		limit = new(domain.TargetResponseSizeLimit)
+	}
+	return []handler.Column{
+		handler.NewCol(TargetResponseSizeLimitCol, limit.MaxBytes),
+		handler.NewCol(TargetResponseSizePolicyCol, limit.Policy),
+	}
+}
+
+// batchDeliveryColumns returns the columns of the batch delivery, delivering every event on its own is stored as 0
+func batchDeliveryColumns(batch *domain.TargetBatchDelivery) []handler.Column {
+	if batch.IsZero() {
+		batch = new(domain.TargetBatchDelivery)
+	}
+	return []handler.Column{
+		handler.NewCol(TargetBatchMaxSizeCol, batch.MaxSize),
+		handler.NewCol(TargetBatchMaxWaitCol, batch.MaxWait),
+	}
+}
+
+// oauth2Value maps empty client credentials to NULL
+func oauth2Value(oauth2 *domain.TargetOAuth2) any {
+	if oauth2.IsZero() {
+		return nil
+	}
+	return oauth2
+}
+
+// basicAuthValue maps an empty basic authentication to NULL
+func basicAuthValue(basicAuth *domain.TargetBasicAuth) any {
+	if basicAuth.IsZero() {
+		return nil
+	}
+	return basicAuth
+}
+
+// jwtDeliveryValue maps an empty JWT delivery to NULL
+func jwtDeliveryValue(delivery *domain.TargetJWTDelivery) any {
+	if delivery.IsZero() {
+		return nil
+	}
+	return delivery
+}
+
+// signingKeysValue maps signing keys without primary key to NULL
+func signingKeysValue(keys *domain.TargetSigningKeys) any {
+	if keys.IsZero() {
+		return nil
+	}
+	return keys
+}
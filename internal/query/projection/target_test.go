@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/eventstore"
 	"github.com/zitadel/zitadel/internal/eventstore/handler/v2"
@@ -29,7 +31,7 @@ func TestTargetProjection_reduces(t *testing.T) {
 					testEvent(
 						target.AddedEventType,
 						target.AggregateType,
-						[]byte(`{"name": "name", "targetType":0, "endpoint":"https://example.com", "timeout": 3000000000, "async": true, "interruptOnError": true}`),
+						[]byte(`{"name": "name", "targetType":0, "endpoint":"https://example.com", "timeout": 3000000000, "async": true, "interruptOnError": true, "successCriteria": {"statusCodes": [200, 202], "bodyPath": "$.status", "bodyValue": "ok"}, "eventTypeFilter": ["user.*"], "certificateFingerprint": "d41d8cd98f00b204e9800998ecf8427ed41d8cd98f00b204e9800998ecf8427e", "responseContentType": "application/json", "payloadFormat": 2, "phaseTimeouts": {"dial": 1000000000, "responseHeader": 2000000000}, "oauth2": {"tokenEndpoint": "https://auth.example.com/oauth/token", "clientID": "client", "clientSecret": {"CryptoType": 0, "Algorithm": "enc", "KeyID": "id", "Crypted": "c2VjcmV0"}}, "mirrorEndpoints": ["https://mirror.example.com"], "responseSizeLimit": {"maxBytes": 1024, "policy": 1}, "batchDelivery": {"maxSize": 100, "maxWait": 1000000000}, "orderedDelivery": true, "caBundle": "bundle", "jwtDelivery": {"algorithm": "HS256", "issuer": "zitadel"}, "failoverEndpoints": ["https://failover.example.com"], "maxRedirects": 3, "requestIDHeader": "X-Request-Id"}`),
 					),
 					eventstore.GenericEventMapper[target.AddedEvent],
 				),
@@ -41,7 +43,7 @@ func TestTargetProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "INSERT INTO projections.targets1 (instance_id, resource_owner, id, creation_date, change_date, sequence, name, endpoint, target_type, timeout, interrupt_on_error) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)",
+							expectedStmt: "INSERT INTO projections.targets2 (instance_id, resource_owner, id, creation_date, change_date, sequence, name, endpoint, target_type, timeout, interrupt_on_error, success_criteria, event_type_filter, certificate_fingerprint, response_content_type, oauth2, basic_auth, payload_format, mirror_endpoints, ordered_delivery, include_payload, ca_bundle, jwt_delivery, failover_endpoints, max_redirects, request_id_header, state, signing_keys, dial_timeout, tls_handshake_timeout, response_header_timeout, response_size_limit, response_size_policy, batch_max_size, batch_max_wait) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35)",
 							expectedArgs: []interface{}{
 								"instance-id",
 								"ro-id",
@@ -54,6 +56,46 @@ func TestTargetProjection_reduces(t *testing.T) {
 								domain.TargetTypeWebhook,
 								3 * time.Second,
 								true,
+								&domain.TargetSuccessCriteria{
+									StatusCodes: []int{200, 202},
+									BodyPath:    "$.status",
+									BodyValue:   "ok",
+								},
+								database.TextArray[string]{"user.*"},
+								"d41d8cd98f00b204e9800998ecf8427ed41d8cd98f00b204e9800998ecf8427e",
+								"application/json",
+								&domain.TargetOAuth2{
+									TokenEndpoint: "https://auth.example.com/oauth/token",
+									ClientID:      "client",
+									ClientSecret: &crypto.CryptoValue{
+										CryptoType: crypto.TypeEncryption,
+										Algorithm:  "enc",
+										KeyID:      "id",
+										Crypted:    []byte("secret"),
+									},
+								},
+								nil,
+								domain.TargetPayloadFormatXML,
+								database.TextArray[string]{"https://mirror.example.com"},
+								true,
+								true,
+								"bundle",
+								&domain.TargetJWTDelivery{
+									Algorithm: domain.TargetJWTAlgorithmHS256,
+									Issuer:    "zitadel",
+								},
+								database.TextArray[string]{"https://failover.example.com"},
+								3,
+								"X-Request-Id",
+								domain.TargetActive,
+								nil,
+								time.Second,
+								time.Duration(0),
+								2 * time.Second,
+								int64(1024),
+								domain.TargetResponseSizePolicyTruncate,
+								100,
+								time.Second,
 							},
 						},
 					},
@@ -67,7 +109,7 @@ func TestTargetProjection_reduces(t *testing.T) {
 					testEvent(
 						target.ChangedEventType,
 						target.AggregateType,
-						[]byte(`{"name": "name2", "targetType":0, "endpoint":"https://example.com", "timeout": 3000000000, "async": true, "interruptOnError": true}`),
+						[]byte(`{"name": "name2", "targetType":0, "endpoint":"https://example.com", "timeout": 3000000000, "async": true, "interruptOnError": true, "eventTypeFilter": ["user.*", "session.added"], "certificateFingerprint": "", "payloadFormat": 1, "phaseTimeouts": {"tlsHandshake": 500000000}, "oauth2": {}, "basicAuth": {"username": "user", "password": {"CryptoType": 0, "Algorithm": "enc", "KeyID": "id", "Crypted": "cGFzc3dvcmQ="}}, "mirrorEndpoints": [], "responseSizeLimit": {}, "batchDelivery": {}, "orderedDelivery": false, "includePayload": false, "caBundle": "", "jwtDelivery": {}, "failoverEndpoints": [], "maxRedirects": 0, "requestIDHeader": ""}`),
 					),
 					eventstore.GenericEventMapper[target.ChangedEvent],
 				),
@@ -79,7 +121,7 @@ func TestTargetProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "UPDATE projections.targets1 SET (change_date, sequence, resource_owner, name, target_type, endpoint, timeout, interrupt_on_error) = ($1, $2, $3, $4, $5, $6, $7, $8) WHERE (instance_id = $9) AND (id = $10)",
+							expectedStmt: "UPDATE projections.targets2 SET (change_date, sequence, resource_owner, name, target_type, endpoint, timeout, interrupt_on_error, event_type_filter, certificate_fingerprint, dial_timeout, tls_handshake_timeout, response_header_timeout, oauth2, basic_auth, payload_format, mirror_endpoints, response_size_limit, response_size_policy, batch_max_size, batch_max_wait, ordered_delivery, include_payload, ca_bundle, jwt_delivery, failover_endpoints, max_redirects, request_id_header) = ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28) WHERE (instance_id = $29) AND (id = $30)",
 							expectedArgs: []interface{}{
 								anyArg{},
 								uint64(15),
@@ -89,6 +131,81 @@ func TestTargetProjection_reduces(t *testing.T) {
 								"https://example.com",
 								3 * time.Second,
 								true,
+								database.TextArray[string]{"user.*", "session.added"},
+								"",
+								time.Duration(0),
+								500 * time.Millisecond,
+								time.Duration(0),
+								nil,
+								&domain.TargetBasicAuth{
+									Username: "user",
+									Password: &crypto.CryptoValue{
+										CryptoType: crypto.TypeEncryption,
+										Algorithm:  "enc",
+										KeyID:      "id",
+										Crypted:    []byte("password"),
+									},
+								},
+								domain.TargetPayloadFormatForm,
+								database.TextArray[string]{},
+								int64(0),
+								domain.TargetResponseSizePolicyError,
+								0,
+								time.Duration(0),
+								false,
+								false,
+								"",
+								nil,
+								database.TextArray[string]{},
+								0,
+								"",
+								"instance-id",
+								"agg-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceTargetChanged signing keys",
+			args: args{
+				event: getEvent(
+					testEvent(
+						target.ChangedEventType,
+						target.AggregateType,
+						[]byte(`{"signingKeys": {"primary": {"CryptoType": 0, "Algorithm": "enc", "KeyID": "id", "Crypted": "cHJpbWFyeQ=="}, "secondary": {"CryptoType": 0, "Algorithm": "enc", "KeyID": "id", "Crypted": "c2Vjb25kYXJ5"}, "secondaryValidUntil": "2024-01-01T01:00:00Z"}}`),
+					),
+					eventstore.GenericEventMapper[target.ChangedEvent],
+				),
+			},
+			reduce: (&targetProjection{}).reduceTargetChanged,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("target"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "UPDATE projections.targets2 SET (change_date, sequence, resource_owner, signing_keys) = ($1, $2, $3, $4) WHERE (instance_id = $5) AND (id = $6)",
+							expectedArgs: []interface{}{
+								anyArg{},
+								uint64(15),
+								"ro-id",
+								&domain.TargetSigningKeys{
+									Primary: &crypto.CryptoValue{
+										CryptoType: crypto.TypeEncryption,
+										Algorithm:  "enc",
+										KeyID:      "id",
+										Crypted:    []byte("primary"),
+									},
+									Secondary: &crypto.CryptoValue{
+										CryptoType: crypto.TypeEncryption,
+										Algorithm:  "enc",
+										KeyID:      "id",
+										Crypted:    []byte("secondary"),
+									},
+									SecondaryValidUntil: time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC),
+								},
 								"instance-id",
 								"agg-id",
 							},
@@ -116,7 +233,7 @@ func TestTargetProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.targets1 WHERE (instance_id = $1) AND (id = $2)",
+							expectedStmt: "DELETE FROM projections.targets2 WHERE (instance_id = $1) AND (id = $2)",
 							expectedArgs: []interface{}{
 								"instance-id",
 								"agg-id",
@@ -126,6 +243,70 @@ func TestTargetProjection_reduces(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "reduceTargetPaused",
+			args: args{
+				event: getEvent(
+					testEvent(
+						target.PausedEventType,
+						target.AggregateType,
+						nil,
+					),
+					eventstore.GenericEventMapper[target.PausedEvent],
+				),
+			},
+			reduce: (&targetProjection{}).reduceTargetPaused,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("target"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "UPDATE projections.targets2 SET (change_date, sequence, state) = ($1, $2, $3) WHERE (instance_id = $4) AND (id = $5)",
+							expectedArgs: []interface{}{
+								anyArg{},
+								uint64(15),
+								domain.TargetInactive,
+								"instance-id",
+								"agg-id",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reduceTargetResumed",
+			args: args{
+				event: getEvent(
+					testEvent(
+						target.ResumedEventType,
+						target.AggregateType,
+						nil,
+					),
+					eventstore.GenericEventMapper[target.ResumedEvent],
+				),
+			},
+			reduce: (&targetProjection{}).reduceTargetResumed,
+			want: wantReduce{
+				aggregateType: eventstore.AggregateType("target"),
+				sequence:      15,
+				executer: &testExecuter{
+					executions: []execution{
+						{
+							expectedStmt: "UPDATE projections.targets2 SET (change_date, sequence, state) = ($1, $2, $3) WHERE (instance_id = $4) AND (id = $5)",
+							expectedArgs: []interface{}{
+								anyArg{},
+								uint64(15),
+								domain.TargetActive,
+								"instance-id",
+								"agg-id",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "reduceInstanceRemoved",
 			args: args{
@@ -145,7 +326,7 @@ func TestTargetProjection_reduces(t *testing.T) {
 				executer: &testExecuter{
 					executions: []execution{
 						{
-							expectedStmt: "DELETE FROM projections.targets1 WHERE (instance_id = $1)",
+							expectedStmt: "DELETE FROM projections.targets2 WHERE (instance_id = $1)",
 							expectedArgs: []interface{}{
 								"agg-id",
 							},
@@ -3,14 +3,22 @@ package query
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 
 	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/api/call"
+	"github.com/zitadel/zitadel/internal/database"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/query/projection"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
@@ -63,11 +71,111 @@ var (
 		name:  projection.TargetInterruptOnErrorCol,
 		table: targetTable,
 	}
+	TargetColumnSuccessCriteria = Column{
+		name:  projection.TargetSuccessCriteriaCol,
+		table: targetTable,
+	}
+	TargetColumnEventTypeFilter = Column{
+		name:  projection.TargetEventTypeFilterCol,
+		table: targetTable,
+	}
+	TargetColumnCertificateFingerprint = Column{
+		name:  projection.TargetCertificateFingerprintCol,
+		table: targetTable,
+	}
+	TargetColumnResponseContentType = Column{
+		name:  projection.TargetResponseContentTypeCol,
+		table: targetTable,
+	}
+	TargetColumnDialTimeout = Column{
+		name:  projection.TargetDialTimeoutCol,
+		table: targetTable,
+	}
+	TargetColumnTLSHandshakeTimeout = Column{
+		name:  projection.TargetTLSHandshakeTimeoutCol,
+		table: targetTable,
+	}
+	TargetColumnResponseHeaderTimeout = Column{
+		name:  projection.TargetResponseHeaderTimeoutCol,
+		table: targetTable,
+	}
+	TargetColumnOAuth2 = Column{
+		name:  projection.TargetOAuth2Col,
+		table: targetTable,
+	}
+	TargetColumnBasicAuth = Column{
+		name:  projection.TargetBasicAuthCol,
+		table: targetTable,
+	}
+	TargetColumnPayloadFormat = Column{
+		name:  projection.TargetPayloadFormatCol,
+		table: targetTable,
+	}
+	TargetColumnMirrorEndpoints = Column{
+		name:  projection.TargetMirrorEndpointsCol,
+		table: targetTable,
+	}
+	TargetColumnResponseSizeLimit = Column{
+		name:  projection.TargetResponseSizeLimitCol,
+		table: targetTable,
+	}
+	TargetColumnResponseSizePolicy = Column{
+		name:  projection.TargetResponseSizePolicyCol,
+		table: targetTable,
+	}
+	TargetColumnBatchMaxSize = Column{
+		name:  projection.TargetBatchMaxSizeCol,
+		table: targetTable,
+	}
+	TargetColumnBatchMaxWait = Column{
+		name:  projection.TargetBatchMaxWaitCol,
+		table: targetTable,
+	}
+	TargetColumnOrderedDelivery = Column{
+		name:  projection.TargetOrderedDeliveryCol,
+		table: targetTable,
+	}
+	TargetColumnIncludePayload = Column{
+		name:  projection.TargetIncludePayloadCol,
+		table: targetTable,
+	}
+	TargetColumnCABundle = Column{
+		name:  projection.TargetCABundleCol,
+		table: targetTable,
+	}
+	TargetColumnJWTDelivery = Column{
+		name:  projection.TargetJWTDeliveryCol,
+		table: targetTable,
+	}
+	TargetColumnFailoverEndpoints = Column{
+		name:  projection.TargetFailoverEndpointsCol,
+		table: targetTable,
+	}
+	TargetColumnMaxRedirects = Column{
+		name:  projection.TargetMaxRedirectsCol,
+		table: targetTable,
+	}
+	TargetColumnRequestIDHeader = Column{
+		name:  projection.TargetRequestIDHeaderCol,
+		table: targetTable,
+	}
+	TargetColumnState = Column{
+		name:  projection.TargetStateCol,
+		table: targetTable,
+	}
+	TargetColumnSigningKeys = Column{
+		name:  projection.TargetSigningKeysCol,
+		table: targetTable,
+	}
 )
 
 type Targets struct {
 	SearchResponse
 	Targets []*Target
+	// NextCursor continues a keyset pagination after the last target of the result,
+	// it is empty on the last page and for searches without keyset pagination (see [TargetSearchQueries.Keyset]).
+	// The count of a keyset page only includes the targets from its cursor on.
+	NextCursor string
 }
 
 func (t *Targets) SetState(s *State) {
@@ -83,11 +191,101 @@ type Target struct {
 	Endpoint         string
 	Timeout          time.Duration
 	InterruptOnError bool
+	SuccessCriteria  *domain.TargetSuccessCriteria
+	EventTypeFilter  database.TextArray[string]
+	// CertificateFingerprint pins the certificate of the endpoint, empty if not pinned
+	CertificateFingerprint string
+	// CABundle are the PEM encoded certificate authorities the endpoint is verified against, empty for the system ones
+	CABundle string
+	// ResponseContentType is the expected content type of the responses, empty if there is no expectation
+	ResponseContentType string
+	// PayloadFormat is the format the payload is serialized in
+	PayloadFormat domain.TargetPayloadFormat
+	// PhaseTimeouts limit the phases of the calls, unset phases are 0
+	PhaseTimeouts domain.TargetPhaseTimeouts
+	// OAuth2 are the client credentials used to authorize the calls, nil if the calls are not authorized
+	OAuth2 *TargetOAuth2
+	// BasicAuth is the basic authentication used to authorize the calls, nil if the calls are not authorized
+	BasicAuth *TargetBasicAuth
+	// JWTDelivery signs the payload as JWT, nil if the payload is delivered as it is
+	JWTDelivery *domain.TargetJWTDelivery
+	// MirrorEndpoints additionally receive the payload best-effort
+	MirrorEndpoints database.TextArray[string]
+	// ResponseSizeLimit limits the size of the response bodies, the size is not limited if MaxBytes is 0
+	ResponseSizeLimit domain.TargetResponseSizeLimit
+	// BatchDelivery coalesces the payloads delivered to an async target into batches, the payloads are delivered one by one if MaxSize is 0
+	BatchDelivery domain.TargetBatchDelivery
+	// OrderedDelivery delivers the payloads of an async target one after the other
+	OrderedDelivery bool
+	// IncludePayload sends the payload of the events to the target, otherwise only their metadata
+	IncludePayload bool
+	// FailoverEndpoints are called in order if the endpoint fails, until one of them succeeds
+	FailoverEndpoints database.TextArray[string]
+	// MaxRedirects is the amount of redirects followed, redirects are not followed if zero
+	MaxRedirects int
+	// RequestIDHeader is the name of the header the ID of the originating request is sent in, empty if it is not sent
+	RequestIDHeader string
+	// State is inactive while the target is paused
+	State domain.TargetState
+}
+
+// maskedSecret replaces the client secret and the password of a target, they are never returned
+const maskedSecret = "********"
+
+// TargetOAuth2 are the client credentials of a target with a masked client secret
+type TargetOAuth2 struct {
+	TokenEndpoint string
+	ClientID      string
+	// MaskedClientSecret is set if a client secret is stored, it does not reveal any part of the secret
+	MaskedClientSecret string
+}
+
+func maskOAuth2(oauth2 *domain.TargetOAuth2) *TargetOAuth2 {
+	if oauth2.IsZero() {
+		return nil
+	}
+	masked := &TargetOAuth2{
+		TokenEndpoint: oauth2.TokenEndpoint,
+		ClientID:      oauth2.ClientID,
+	}
+	if oauth2.ClientSecret != nil {
+		masked.MaskedClientSecret = maskedSecret
+	}
+	return masked
+}
+
+// TargetBasicAuth is the basic authentication of a target with a masked password
+type TargetBasicAuth struct {
+	Username string
+	// MaskedPassword is set if a password is stored, it does not reveal any part of the password
+	MaskedPassword string
+}
+
+func maskBasicAuth(basicAuth *domain.TargetBasicAuth) *TargetBasicAuth {
+	if basicAuth.IsZero() {
+		return nil
+	}
+	masked := &TargetBasicAuth{
+		Username: basicAuth.Username,
+	}
+	if basicAuth.Password != nil {
+		masked.MaskedPassword = maskedSecret
+	}
+	return masked
 }
 
 type TargetSearchQueries struct {
 	SearchRequest
 	Queries []SearchQuery
+	// StronglyConsistent reads the current targets instead of slightly stale data,
+	// e.g. to read a target which was just written
+	StronglyConsistent bool
+	// Keyset pages through the targets ordered by their ID instead of the offset and the sorting column,
+	// so targets added or removed in the meantime do not shift the pages.
+	// The first page is searched without cursor, the following ones with the NextCursor of the previous page.
+	Keyset bool
+	// Cursor is the NextCursor of the previous page of a keyset pagination, a search with a cursor is always paged by keyset
+	Cursor string
 }
 
 func (q *TargetSearchQueries) toQuery(query sq.SelectBuilder) sq.SelectBuilder {
@@ -98,12 +296,103 @@ func (q *TargetSearchQueries) toQuery(query sq.SelectBuilder) sq.SelectBuilder {
 	return query
 }
 
+func (q *TargetSearchQueries) isKeyset() bool {
+	return q.Keyset || q.Cursor != ""
+}
+
+// toKeysetQuery applies the search queries and the limit, the targets are ordered by their ID
+func (q *TargetSearchQueries) toKeysetQuery(query sq.SelectBuilder) sq.SelectBuilder {
+	if q.Limit > 0 {
+		query = query.Limit(q.Limit)
+	}
+	query = query.OrderBy(TargetColumnID.identifier())
+	for _, q := range q.Queries {
+		query = q.toQuery(query)
+	}
+	return query
+}
+
+// encodeTargetCursor returns the opaque cursor continuing after the target
+func encodeTargetCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// decodeTargetCursor returns the ID of the target the pagination continues after, empty for the first page
+func decodeTargetCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	id, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(id) == 0 {
+		return "", zerrors.ThrowInvalidArgument(err, "QUERY-Tc5rSr", "Errors.Query.InvalidRequest")
+	}
+	return string(id), nil
+}
+
 func (q *Queries) SearchTargets(ctx context.Context, queries *TargetSearchQueries) (targets *Targets, err error) {
 	eq := sq.Eq{
 		TargetColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
 	}
+	return q.searchTargets(ctx, queries, eq)
+}
+
+// SearchTargetsOfResourceOwners searches the targets of all the resource owners of the instance in a single query,
+// e.g. for views across several organizations. At least one resource owner is required.
+func (q *Queries) SearchTargetsOfResourceOwners(ctx context.Context, resourceOwners []string, queries *TargetSearchQueries) (targets *Targets, err error) {
+	if len(resourceOwners) == 0 {
+		return nil, zerrors.ThrowInvalidArgument(nil, "QUERY-Tr5oWn", "Errors.Query.InvalidRequest")
+	}
+	return q.searchTargets(ctx, queries, targetsOfResourceOwnersCondition(authz.GetInstance(ctx).InstanceID(), resourceOwners))
+}
+
+// SearchTargetIDs returns the IDs of the targets of the resource owner matching the queries.
+// Only the ID column is selected, which is cheaper than [Queries.SearchTargets] if the targets themselves are not needed.
+func (q *Queries) SearchTargetIDs(ctx context.Context, queries *TargetSearchQueries, resourceOwner string) (_ []string, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	eq := sq.Eq{
+		TargetColumnResourceOwner.identifier(): resourceOwner,
+		TargetColumnInstanceID.identifier():    authz.GetInstance(ctx).InstanceID(),
+	}
+	query, scan := prepareTargetIDsQuery(ctx, q.client)
+	if !queries.StronglyConsistent {
+		query = query.From(targetTable.identifier() + q.client.Timetravel(call.Took(ctx)))
+	}
+	return genericRowsQuery[[]string](ctx, q.client, combineToWhereStmt(query, queries.toQuery, eq), scan)
+}
+
+func targetsOfResourceOwnersCondition(instanceID string, resourceOwners []string) sq.Eq {
+	return sq.Eq{
+		TargetColumnResourceOwner.identifier(): resourceOwners,
+		TargetColumnInstanceID.identifier():    instanceID,
+	}
+}
+
+func (q *Queries) searchTargets(ctx context.Context, queries *TargetSearchQueries, eq sq.Eq) (targets *Targets, err error) {
 	query, scan := prepareTargetsQuery(ctx, q.client)
-	return genericRowsQueryWithState[*Targets](ctx, q.client, targetTable, combineToWhereStmt(query, queries.toQuery, eq), scan)
+	if !queries.StronglyConsistent {
+		query = query.From(targetTable.identifier() + q.client.Timetravel(call.Took(ctx)))
+	}
+	if !queries.isKeyset() {
+		targets, err = genericRowsQueryWithState[*Targets](ctx, q.client, targetTable, combineToWhereStmt(query, queries.toQuery, eq), scan)
+		if err != nil {
+			return nil, err
+		}
+		// the cursor only continues pages ordered by ID
+		targets.NextCursor = ""
+		return targets, nil
+	}
+
+	afterID, err := decodeTargetCursor(queries.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	var where sq.Sqlizer = eq
+	if afterID != "" {
+		where = sq.And{eq, sq.Gt{TargetColumnID.identifier(): afterID}}
+	}
+	return genericRowsQueryWithState[*Targets](ctx, q.client, targetTable, combineToWhereStmt(query, queries.toKeysetQuery, where), scan)
 }
 
 func (q *Queries) GetTargetByID(ctx context.Context, id string) (target *Target, err error) {
@@ -115,26 +404,322 @@ func (q *Queries) GetTargetByID(ctx context.Context, id string) (target *Target,
 	return genericRowQuery[*Target](ctx, q.client, query.Where(eq), scan)
 }
 
+// LatestChangedTarget returns the most recently changed target of the resource owner,
+// NotFound if the resource owner has no targets
+func (q *Queries) LatestChangedTarget(ctx context.Context, resourceOwner string) (target *Target, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	eq := sq.Eq{
+		TargetColumnResourceOwner.identifier(): resourceOwner,
+		TargetColumnInstanceID.identifier():    authz.GetInstance(ctx).InstanceID(),
+	}
+	query, scan := prepareTargetQuery(ctx, q.client)
+	return genericRowQuery[*Target](ctx, q.client, query.Where(eq).OrderBy(TargetColumnChangeDate.identifier()+" DESC").Limit(1), scan)
+}
+
+// ReprojectTarget deletes the row of the target in the projection and derives it again from the events of the target.
+// It is a maintenance tool for a single corrupt row, which does not require to reproject all targets.
+func (q *Queries) ReprojectTarget(ctx context.Context, id string) (err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	if id == "" {
+		return zerrors.ThrowInvalidArgument(nil, "QUERY-Rp6Id", "Errors.IDMissing")
+	}
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	if err := q.checkPermission(ctx, domain.PermissionTargetWrite, instanceID, id); err != nil {
+		return err
+	}
+	return projection.ReprojectTarget(ctx, id)
+}
+
+// FindDuplicateTargetURLs returns the endpoints which are used by more than one target of the resource owner,
+// mapped to the IDs of these targets.
+// The endpoints are normalized, so that trailing slashes and the case of the host are ignored.
+func (q *Queries) FindDuplicateTargetURLs(ctx context.Context, resourceOwner string) (_ map[string][]string, err error) {
+	eq := sq.Eq{
+		TargetColumnResourceOwner.identifier(): resourceOwner,
+		TargetColumnInstanceID.identifier():    authz.GetInstance(ctx).InstanceID(),
+	}
+	query, scan := prepareTargetEndpointsQuery(ctx, q.client)
+	endpoints, err := genericRowsQuery[map[string][]string](ctx, q.client, query.Where(eq), scan)
+	if err != nil {
+		return nil, err
+	}
+	for endpoint, ids := range endpoints {
+		if len(ids) < 2 {
+			delete(endpoints, endpoint)
+		}
+	}
+	return endpoints, nil
+}
+
+// DistinctTargetTypes returns the types of the targets of the resource owner, each type only once
+func (q *Queries) DistinctTargetTypes(ctx context.Context, resourceOwner string) (_ []domain.TargetType, err error) {
+	eq := sq.Eq{
+		TargetColumnResourceOwner.identifier(): resourceOwner,
+		TargetColumnInstanceID.identifier():    authz.GetInstance(ctx).InstanceID(),
+	}
+	query, scan := prepareDistinctTargetTypesQuery(ctx, q.client)
+	return genericRowsQuery[[]domain.TargetType](ctx, q.client, query.Where(eq), scan)
+}
+
+// CountTargetsByState returns the count of the active and the inactive (paused) targets of the resource owner,
+// states without targets are counted as 0.
+// Removed targets are deleted from the projection, so they are not part of the result.
+func (q *Queries) CountTargetsByState(ctx context.Context, resourceOwner string) (_ map[domain.TargetState]uint64, err error) {
+	eq := sq.Eq{
+		TargetColumnResourceOwner.identifier(): resourceOwner,
+		TargetColumnInstanceID.identifier():    authz.GetInstance(ctx).InstanceID(),
+	}
+	query, scan := prepareTargetStateCountsQuery(ctx, q.client)
+	return genericRowsQuery[map[domain.TargetState]uint64](ctx, q.client, query.Where(eq), scan)
+}
+
+// TargetsChangedSince returns the targets of the resource owner changed after since, ordered by their change date.
+// The current state of the projection is part of the result, so that pollers know up to which point the targets are processed.
+// Removed targets are deleted from the projection and therefore not returned.
+func (q *Queries) TargetsChangedSince(ctx context.Context, resourceOwner string, since time.Time) (_ *Targets, err error) {
+	query, scan := prepareTargetsChangedSinceQuery(ctx, q.client)
+	where := targetsChangedSinceCondition(authz.GetInstance(ctx).InstanceID(), resourceOwner, since)
+	return genericRowsQueryWithState[*Targets](ctx, q.client, targetTable, query.Where(where), scan)
+}
+
+func targetsChangedSinceCondition(instanceID, resourceOwner string, since time.Time) sq.Sqlizer {
+	return sq.And{
+		sq.Eq{
+			TargetColumnResourceOwner.identifier(): resourceOwner,
+			TargetColumnInstanceID.identifier():    instanceID,
+		},
+		sq.Gt{
+			TargetColumnChangeDate.identifier(): since,
+		},
+	}
+}
+
+// TargetsByIDMap returns the targets of the resource owner mapped by their ID.
+// It uses the same query as the search of targets by IDs, IDs without a target are not part of the map.
+func (q *Queries) TargetsByIDMap(ctx context.Context, ids []string, resourceOwner string) (_ map[string]*Target, err error) {
+	if len(ids) == 0 {
+		return map[string]*Target{}, nil
+	}
+	query, scan := prepareTargetsQuery(ctx, q.client)
+	where := targetsByIDsCondition(authz.GetInstance(ctx).InstanceID(), resourceOwner, ids)
+	targets, err := genericRowsQuery[*Targets](ctx, q.client, query.Where(where), scan)
+	if err != nil {
+		return nil, err
+	}
+	return targetsByID(targets.Targets), nil
+}
+
+func targetsByIDsCondition(instanceID, resourceOwner string, ids []string) sq.Sqlizer {
+	return sq.Eq{
+		TargetColumnID.identifier():            ids,
+		TargetColumnResourceOwner.identifier(): resourceOwner,
+		TargetColumnInstanceID.identifier():    instanceID,
+	}
+}
+
+func targetsByID(targets []*Target) map[string]*Target {
+	byID := make(map[string]*Target, len(targets))
+	for _, target := range targets {
+		byID[target.ID] = target
+	}
+	return byID
+}
+
+// forEachTargetBatchSize is the amount of targets read at once by [Queries.ForEachTarget]
+const forEachTargetBatchSize = 100
+
+// ForEachTarget calls fn for each target of the resource owner ordered by their ID and stops on the first error returned by fn.
+// The targets are read in batches, each batch continues after the ID of the last target of the previous one,
+// so targets added or removed in the meantime do not shift the batches and no target is visited twice.
+func (q *Queries) ForEachTarget(ctx context.Context, resourceOwner string, fn func(*Target) error) (err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	return forEachTarget(ctx, forEachTargetBatchSize, func(ctx context.Context, afterID string, limit uint64) ([]*Target, error) {
+		query, scan := prepareTargetsQuery(ctx, q.client)
+		query = query.Where(targetsAfterIDCondition(instanceID, resourceOwner, afterID)).
+			OrderBy(TargetColumnID.identifier()).
+			Limit(limit)
+		targets, err := genericRowsQuery[*Targets](ctx, q.client, query, scan)
+		if err != nil {
+			return nil, err
+		}
+		return targets.Targets, nil
+	}, fn)
+}
+
+// forEachTarget pages through the targets returned by fetch, a batch smaller than the batch size is the last one
+func forEachTarget(
+	ctx context.Context,
+	batchSize uint64,
+	fetch func(ctx context.Context, afterID string, limit uint64) ([]*Target, error),
+	fn func(*Target) error,
+) error {
+	var afterID string
+	for {
+		targets, err := fetch(ctx, afterID, batchSize)
+		if err != nil {
+			return err
+		}
+		for _, target := range targets {
+			if err := fn(target); err != nil {
+				return err
+			}
+		}
+		if uint64(len(targets)) < batchSize {
+			return nil
+		}
+		afterID = targets[len(targets)-1].ID
+	}
+}
+
+func targetsAfterIDCondition(instanceID, resourceOwner, afterID string) sq.Sqlizer {
+	return sq.And{
+		sq.Eq{
+			TargetColumnResourceOwner.identifier(): resourceOwner,
+			TargetColumnInstanceID.identifier():    instanceID,
+		},
+		sq.Gt{
+			TargetColumnID.identifier(): afterID,
+		},
+	}
+}
+
 func NewTargetNameSearchQuery(method TextComparison, value string) (SearchQuery, error) {
 	return NewTextQuery(TargetColumnName, value, method)
 }
 
+// NewTargetTimeoutSearchQuery returns a query for the targets with a timeout compared to d,
+// e.g. [NumberGreater] for the targets which could stall a flow for longer than d.
+// [NumberListContains] is rejected with [ErrInvalidCompare], as a target has a single timeout.
+func NewTargetTimeoutSearchQuery(method NumberComparison, d time.Duration) (SearchQuery, error) {
+	if method == NumberListContains {
+		return nil, ErrInvalidCompare
+	}
+	query, err := NewNumberQuery(TargetColumnTimeout, int64(d), method)
+	if err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// NewTargetInIDsSearchQuery returns a query for the targets with one of the IDs.
+// Empty IDs are rejected with [ErrEmptyValues], as the query would either match no or all targets.
 func NewTargetInIDsSearchQuery(values []string) (SearchQuery, error) {
-	return NewInTextQuery(TargetColumnID, values)
+	query, err := NewInTextQuery(TargetColumnID, values)
+	if err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// NewTargetInTypesSearchQuery returns a query for the targets with one of the types.
+// Empty types are rejected with [ErrEmptyValues], as the query would either match no or all targets.
+func NewTargetInTypesSearchQuery(types []domain.TargetType) (SearchQuery, error) {
+	if len(types) == 0 {
+		return nil, ErrEmptyValues
+	}
+	query, err := NewListQuery(TargetColumnTargetType, types, ListIn)
+	if err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// NewTargetHasSigningKeySearchQuery returns a query for the targets which sign their requests if has is true,
+// otherwise for the targets which send them unsigned
+func NewTargetHasSigningKeySearchQuery(has bool) (SearchQuery, error) {
+	if has {
+		return NewNotNullQuery(TargetColumnSigningKeys)
+	}
+	return NewIsNullQuery(TargetColumnSigningKeys)
+}
+
+// targetColumns returns the columns selected for a target,
+// the order must match [targetScanDestinations]
+func targetColumns() []string {
+	return []string{
+		TargetColumnID.identifier(),
+		TargetColumnChangeDate.identifier(),
+		TargetColumnResourceOwner.identifier(),
+		TargetColumnSequence.identifier(),
+		TargetColumnName.identifier(),
+		TargetColumnTargetType.identifier(),
+		TargetColumnTimeout.identifier(),
+		TargetColumnURL.identifier(),
+		TargetColumnInterruptOnError.identifier(),
+		TargetColumnSuccessCriteria.identifier(),
+		TargetColumnEventTypeFilter.identifier(),
+		TargetColumnCertificateFingerprint.identifier(),
+		TargetColumnResponseContentType.identifier(),
+		TargetColumnDialTimeout.identifier(),
+		TargetColumnTLSHandshakeTimeout.identifier(),
+		TargetColumnResponseHeaderTimeout.identifier(),
+		TargetColumnOAuth2.identifier(),
+		TargetColumnBasicAuth.identifier(),
+		TargetColumnPayloadFormat.identifier(),
+		TargetColumnMirrorEndpoints.identifier(),
+		TargetColumnResponseSizeLimit.identifier(),
+		TargetColumnResponseSizePolicy.identifier(),
+		TargetColumnBatchMaxSize.identifier(),
+		TargetColumnBatchMaxWait.identifier(),
+		TargetColumnOrderedDelivery.identifier(),
+		TargetColumnIncludePayload.identifier(),
+		TargetColumnCABundle.identifier(),
+		TargetColumnJWTDelivery.identifier(),
+		TargetColumnFailoverEndpoints.identifier(),
+		TargetColumnMaxRedirects.identifier(),
+		TargetColumnRequestIDHeader.identifier(),
+		TargetColumnState.identifier(),
+	}
+}
+
+// targetScanDestinations returns the scan destinations for the columns of [targetColumns]
+func targetScanDestinations(target *Target, successCriteria, oauth2, basicAuth, jwtDelivery *[]byte) []any {
+	return []any{
+		&target.ID,
+		&target.EventDate,
+		&target.ResourceOwner,
+		&target.Sequence,
+		&target.Name,
+		&target.TargetType,
+		&target.Timeout,
+		&target.Endpoint,
+		&target.InterruptOnError,
+		successCriteria,
+		&target.EventTypeFilter,
+		&target.CertificateFingerprint,
+		&target.ResponseContentType,
+		&target.PhaseTimeouts.Dial,
+		&target.PhaseTimeouts.TLSHandshake,
+		&target.PhaseTimeouts.ResponseHeader,
+		oauth2,
+		basicAuth,
+		&target.PayloadFormat,
+		&target.MirrorEndpoints,
+		&target.ResponseSizeLimit.MaxBytes,
+		&target.ResponseSizeLimit.Policy,
+		&target.BatchDelivery.MaxSize,
+		&target.BatchDelivery.MaxWait,
+		&target.OrderedDelivery,
+		&target.IncludePayload,
+		&target.CABundle,
+		jwtDelivery,
+		&target.FailoverEndpoints,
+		&target.MaxRedirects,
+		&target.RequestIDHeader,
+		&target.State,
+	}
 }
 
 func prepareTargetsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(rows *sql.Rows) (*Targets, error)) {
 	return sq.Select(
-			TargetColumnID.identifier(),
-			TargetColumnChangeDate.identifier(),
-			TargetColumnResourceOwner.identifier(),
-			TargetColumnSequence.identifier(),
-			TargetColumnName.identifier(),
-			TargetColumnTargetType.identifier(),
-			TargetColumnTimeout.identifier(),
-			TargetColumnURL.identifier(),
-			TargetColumnInterruptOnError.identifier(),
-			countColumn.identifier(),
+			append(targetColumns(), countColumn.identifier())...,
 		).From(targetTable.identifier()).
 			PlaceholderFormat(sq.Dollar),
 		func(rows *sql.Rows) (*Targets, error) {
@@ -142,21 +727,16 @@ func prepareTargetsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuil
 			var count uint64
 			for rows.Next() {
 				target := new(Target)
+				var successCriteria, oauth2, basicAuth, jwtDelivery []byte
 				err := rows.Scan(
-					&target.ID,
-					&target.EventDate,
-					&target.ResourceOwner,
-					&target.Sequence,
-					&target.Name,
-					&target.TargetType,
-					&target.Timeout,
-					&target.Endpoint,
-					&target.InterruptOnError,
-					&count,
+					append(targetScanDestinations(target, &successCriteria, &oauth2, &basicAuth, &jwtDelivery), &count)...,
 				)
 				if err != nil {
 					return nil, err
 				}
+				if err = decodeTarget(ctx, target, successCriteria, oauth2, basicAuth, jwtDelivery); err != nil {
+					return nil, err
+				}
 				targets = append(targets, target)
 			}
 
@@ -164,40 +744,36 @@ func prepareTargetsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuil
 				return nil, zerrors.ThrowInternal(err, "QUERY-fzwi6cgxos", "Errors.Query.CloseRows")
 			}
 
-			return &Targets{
+			result := &Targets{
 				Targets: targets,
 				SearchResponse: SearchResponse{
 					Count: count,
 				},
-			}, nil
+			}
+			// the count includes the targets after the limit, so there is a next page if not all of them were returned
+			if len(targets) > 0 && uint64(len(targets)) < count {
+				result.NextCursor = encodeTargetCursor(targets[len(targets)-1].ID)
+			}
+			return result, nil
 		}
 }
 
+// prepareTargetsChangedSinceQuery selects the targets ordered by their change date, oldest first
+func prepareTargetsChangedSinceQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(rows *sql.Rows) (*Targets, error)) {
+	query, scan := prepareTargetsQuery(ctx, db)
+	return query.OrderBy(TargetColumnChangeDate.identifier()), scan
+}
+
 func prepareTargetQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(row *sql.Row) (*Target, error)) {
 	return sq.Select(
-			TargetColumnID.identifier(),
-			TargetColumnChangeDate.identifier(),
-			TargetColumnResourceOwner.identifier(),
-			TargetColumnSequence.identifier(),
-			TargetColumnName.identifier(),
-			TargetColumnTargetType.identifier(),
-			TargetColumnTimeout.identifier(),
-			TargetColumnURL.identifier(),
-			TargetColumnInterruptOnError.identifier(),
+			targetColumns()...,
 		).From(targetTable.identifier()).
 			PlaceholderFormat(sq.Dollar),
 		func(row *sql.Row) (*Target, error) {
 			target := new(Target)
+			var successCriteria, oauth2, basicAuth, jwtDelivery []byte
 			err := row.Scan(
-				&target.ID,
-				&target.EventDate,
-				&target.ResourceOwner,
-				&target.Sequence,
-				&target.Name,
-				&target.TargetType,
-				&target.Timeout,
-				&target.Endpoint,
-				&target.InterruptOnError,
+				targetScanDestinations(target, &successCriteria, &oauth2, &basicAuth, &jwtDelivery)...,
 			)
 			if err != nil {
 				if errors.Is(err, sql.ErrNoRows) {
@@ -205,6 +781,223 @@ func prepareTargetQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuild
 				}
 				return nil, zerrors.ThrowInternal(err, "QUERY-5qhc19sc49", "Errors.Internal")
 			}
+			if err = decodeTarget(ctx, target, successCriteria, oauth2, basicAuth, jwtDelivery); err != nil {
+				return nil, err
+			}
 			return target, nil
 		}
 }
+
+// decodeTarget sets the fields of the target which are scanned as JSON, the secrets of the credentials are masked
+func decodeTarget(ctx context.Context, target *Target, successCriteria, oauth2, basicAuth, jwtDelivery []byte) (err error) {
+	if err = checkTargetType(ctx, target); err != nil {
+		return err
+	}
+	target.SuccessCriteria, err = unmarshalSuccessCriteria(successCriteria)
+	if err != nil {
+		return err
+	}
+	credentials, err := unmarshalOAuth2(oauth2)
+	if err != nil {
+		return err
+	}
+	target.OAuth2 = maskOAuth2(credentials)
+	basicAuthCredentials, err := unmarshalBasicAuth(basicAuth)
+	if err != nil {
+		return err
+	}
+	target.BasicAuth = maskBasicAuth(basicAuthCredentials)
+	delivery, err := unmarshalJWTDelivery(jwtDelivery)
+	if err != nil || delivery.IsZero() {
+		return err
+	}
+	target.JWTDelivery = delivery
+	return nil
+}
+
+type strictTargetScanKey struct{}
+
+// WithStrictTargetScan returns a context in which targets with an unknown target type are rejected on scan.
+// By default such targets are returned as they are, the strict mode helps to detect faulty projections.
+func WithStrictTargetScan(ctx context.Context) context.Context {
+	return context.WithValue(ctx, strictTargetScanKey{}, true)
+}
+
+// checkTargetType returns an error identifying the target if its type is unknown and the strict scan is enabled
+func checkTargetType(ctx context.Context, target *Target) error {
+	strict, _ := ctx.Value(strictTargetScanKey{}).(bool)
+	if !strict || target.TargetType.Valid() {
+		return nil
+	}
+	return zerrors.ThrowInternal(
+		fmt.Errorf("target %s of resource owner %s has unknown target type %d", target.ID, target.ResourceOwner, target.TargetType),
+		"QUERY-Tt7yPe",
+		"Errors.Internal",
+	)
+}
+
+// prepareTargetEndpointsQuery groups the IDs of the targets by their normalized endpoint
+func prepareTargetEndpointsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(rows *sql.Rows) (map[string][]string, error)) {
+	return sq.Select(
+			TargetColumnID.identifier(),
+			TargetColumnURL.identifier(),
+		).From(targetTable.identifier()).
+			OrderBy(TargetColumnID.identifier()).
+			PlaceholderFormat(sq.Dollar),
+		func(rows *sql.Rows) (map[string][]string, error) {
+			endpoints := make(map[string][]string)
+			for rows.Next() {
+				var id, endpoint string
+				if err := rows.Scan(&id, &endpoint); err != nil {
+					return nil, err
+				}
+				normalized := normalizeTargetURL(endpoint)
+				endpoints[normalized] = append(endpoints[normalized], id)
+			}
+			if err := rows.Close(); err != nil {
+				return nil, zerrors.ThrowInternal(err, "QUERY-Ue3nVb", "Errors.Query.CloseRows")
+			}
+			return endpoints, nil
+		}
+}
+
+func prepareTargetIDsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(rows *sql.Rows) ([]string, error)) {
+	return sq.Select(
+			TargetColumnID.identifier(),
+		).From(targetTable.identifier()).
+			PlaceholderFormat(sq.Dollar),
+		func(rows *sql.Rows) ([]string, error) {
+			ids := make([]string, 0)
+			for rows.Next() {
+				var id string
+				if err := rows.Scan(&id); err != nil {
+					return nil, err
+				}
+				ids = append(ids, id)
+			}
+			if err := rows.Close(); err != nil {
+				return nil, zerrors.ThrowInternal(err, "QUERY-Ti7dRc", "Errors.Query.CloseRows")
+			}
+			return ids, nil
+		}
+}
+
+func prepareDistinctTargetTypesQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(rows *sql.Rows) ([]domain.TargetType, error)) {
+	return sq.Select(
+			TargetColumnTargetType.identifier(),
+		).Distinct().
+			From(targetTable.identifier()).
+			OrderBy(TargetColumnTargetType.identifier()).
+			PlaceholderFormat(sq.Dollar),
+		func(rows *sql.Rows) ([]domain.TargetType, error) {
+			types := make([]domain.TargetType, 0)
+			for rows.Next() {
+				var targetType domain.TargetType
+				if err := rows.Scan(&targetType); err != nil {
+					return nil, err
+				}
+				types = append(types, targetType)
+			}
+			if err := rows.Close(); err != nil {
+				return nil, zerrors.ThrowInternal(err, "QUERY-Mq4zTe", "Errors.Query.CloseRows")
+			}
+			return types, nil
+		}
+}
+
+func prepareTargetStateCountsQuery(ctx context.Context, db prepareDatabase) (sq.SelectBuilder, func(rows *sql.Rows) (map[domain.TargetState]uint64, error)) {
+	return sq.Select(
+			TargetColumnState.identifier(),
+			"COUNT(*)",
+		).From(targetTable.identifier()).
+			GroupBy(TargetColumnState.identifier()).
+			PlaceholderFormat(sq.Dollar),
+		func(rows *sql.Rows) (map[domain.TargetState]uint64, error) {
+			counts := map[domain.TargetState]uint64{
+				domain.TargetActive:   0,
+				domain.TargetInactive: 0,
+			}
+			for rows.Next() {
+				var (
+					state domain.TargetState
+					count uint64
+				)
+				if err := rows.Scan(&state, &count); err != nil {
+					return nil, err
+				}
+				counts[state] = count
+			}
+			if err := rows.Close(); err != nil {
+				return nil, zerrors.ThrowInternal(err, "QUERY-Ts8cSt", "Errors.Query.CloseRows")
+			}
+			return counts, nil
+		}
+}
+
+// normalizeTargetURL lower cases the scheme and host and removes trailing slashes of the path
+func normalizeTargetURL(endpoint string) string {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return strings.TrimRight(endpoint, "/")
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	parsed.Path = strings.TrimRight(parsed.Path, "/")
+	parsed.RawPath = strings.TrimRight(parsed.RawPath, "/")
+	return parsed.String()
+}
+
+func unmarshalSuccessCriteria(data []byte) (*domain.TargetSuccessCriteria, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	criteria := new(domain.TargetSuccessCriteria)
+	if err := json.Unmarshal(data, criteria); err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Wc9vHd", "Errors.Internal")
+	}
+	return criteria, nil
+}
+
+func unmarshalOAuth2(data []byte) (*domain.TargetOAuth2, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	oauth2 := new(domain.TargetOAuth2)
+	if err := json.Unmarshal(data, oauth2); err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Oa2Um9", "Errors.Internal")
+	}
+	return oauth2, nil
+}
+
+func unmarshalBasicAuth(data []byte) (*domain.TargetBasicAuth, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	basicAuth := new(domain.TargetBasicAuth)
+	if err := json.Unmarshal(data, basicAuth); err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Ba5Um2", "Errors.Internal")
+	}
+	return basicAuth, nil
+}
+
+func unmarshalJWTDelivery(data []byte) (*domain.TargetJWTDelivery, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	delivery := new(domain.TargetJWTDelivery)
+	if err := json.Unmarshal(data, delivery); err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Jw5Um1", "Errors.Internal")
+	}
+	return delivery, nil
+}
+
+func unmarshalSigningKeys(data []byte) (*domain.TargetSigningKeys, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	keys := new(domain.TargetSigningKeys)
+	if err := json.Unmarshal(data, keys); err != nil {
+		return nil, zerrors.ThrowInternal(err, "QUERY-Sk5Um1", "Errors.Internal")
+	}
+	return keys, nil
+}
@@ -0,0 +1,70 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+)
+
+// TargetDefaults are the defaults of the instance applied to targets created without the respective values.
+// The values of the targets are stored after the defaults are applied,
+// so the timeout of a [Target] is always the effective one.
+type TargetDefaults struct {
+	Details *domain.ObjectDetails
+	// Timeout is applied to targets created without timeout, 0 if there is no default
+	Timeout time.Duration
+}
+
+type targetDefaultsReadModel struct {
+	*eventstore.ReadModel
+	defaults *TargetDefaults
+}
+
+func newTargetDefaultsReadModel(ctx context.Context) *targetDefaultsReadModel {
+	instanceID := authz.GetInstance(ctx).InstanceID()
+	return &targetDefaultsReadModel{
+		ReadModel: &eventstore.ReadModel{
+			AggregateID:   instanceID,
+			ResourceOwner: instanceID,
+		},
+		defaults: new(TargetDefaults),
+	}
+}
+
+func (m *targetDefaultsReadModel) Reduce() error {
+	for _, event := range m.Events {
+		if e, ok := event.(*instance.TargetDefaultsSetEvent); ok {
+			m.defaults.Timeout = e.Timeout
+		}
+	}
+	return m.ReadModel.Reduce()
+}
+
+func (m *targetDefaultsReadModel) Query() *eventstore.SearchQueryBuilder {
+	return eventstore.NewSearchQueryBuilder(eventstore.ColumnsEvent).
+		AwaitOpenTransactions().
+		ResourceOwner(m.ResourceOwner).
+		AddQuery().
+		AggregateTypes(instance.AggregateType).
+		AggregateIDs(m.AggregateID).
+		EventTypes(instance.TargetDefaultsSetEventType).
+		Builder()
+}
+
+// GetTargetDefaults returns the target defaults of the instance in the context
+func (q *Queries) GetTargetDefaults(ctx context.Context) (_ *TargetDefaults, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	m := newTargetDefaultsReadModel(ctx)
+	if err = q.eventstore.FilterToQueryReducer(ctx, m); err != nil {
+		return nil, err
+	}
+	m.defaults.Details = readModelToObjectDetails(m.ReadModel)
+	return m.defaults, nil
+}
@@ -0,0 +1,72 @@
+package query
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/repository/instance"
+)
+
+func TestQueries_GetTargetDefaults(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance1")
+	aggregate := &instance.NewAggregate("instance1").Aggregate
+
+	tests := []struct {
+		name       string
+		eventstore func(*testing.T) *eventstore.Eventstore
+		want       *TargetDefaults
+		wantErr    error
+	}{
+		{
+			name: "filter error",
+			eventstore: expectEventstore(
+				expectFilterError(io.ErrClosedPipe),
+			),
+			wantErr: io.ErrClosedPipe,
+		},
+		{
+			name: "no defaults set",
+			eventstore: expectEventstore(
+				expectFilter(),
+			),
+			want: &TargetDefaults{
+				Details: &domain.ObjectDetails{
+					ResourceOwner: "instance1",
+				},
+			},
+		},
+		{
+			name: "latest defaults",
+			eventstore: expectEventstore(
+				expectFilter(
+					eventFromEventPusher(instance.NewTargetDefaultsSetEvent(ctx, aggregate, time.Second)),
+					eventFromEventPusher(instance.NewTargetDefaultsSetEvent(ctx, aggregate, 5*time.Second)),
+				),
+			),
+			want: &TargetDefaults{
+				Details: &domain.ObjectDetails{
+					ResourceOwner: "instance1",
+				},
+				Timeout: 5 * time.Second,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &Queries{
+				eventstore: tt.eventstore(t),
+			}
+			got, err := q.GetTargetDefaults(ctx)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
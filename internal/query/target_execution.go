@@ -0,0 +1,301 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/execution"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+var (
+	targetExecutionTable = table{
+		name:          execution.TargetExecutionTable,
+		instanceIDCol: execution.TargetExecutionInstanceIDCol,
+	}
+	TargetExecutionColumnInstanceID = Column{
+		name:  execution.TargetExecutionInstanceIDCol,
+		table: targetExecutionTable,
+	}
+	TargetExecutionColumnTargetID = Column{
+		name:  execution.TargetExecutionTargetIDCol,
+		table: targetExecutionTable,
+	}
+	TargetExecutionColumnID = Column{
+		name:  execution.TargetExecutionIDCol,
+		table: targetExecutionTable,
+	}
+	TargetExecutionColumnExecutionDate = Column{
+		name:  execution.TargetExecutionDateCol,
+		table: targetExecutionTable,
+	}
+	TargetExecutionColumnStatusCode = Column{
+		name:  execution.TargetExecutionStatusCodeCol,
+		table: targetExecutionTable,
+	}
+	TargetExecutionColumnLatency = Column{
+		name:  execution.TargetExecutionLatencyCol,
+		table: targetExecutionTable,
+	}
+	TargetExecutionColumnError = Column{
+		name:  execution.TargetExecutionErrorCol,
+		table: targetExecutionTable,
+	}
+	TargetExecutionColumnSucceeded = Column{
+		name:  execution.TargetExecutionSucceededCol,
+		table: targetExecutionTable,
+	}
+)
+
+type TargetExecutions struct {
+	SearchResponse
+	TargetExecutions []*TargetExecution
+}
+
+func (t *TargetExecutions) SetState(s *State) {
+	t.State = s
+}
+
+// TargetExecution is a recorded call to a target
+type TargetExecution struct {
+	ID            string
+	TargetID      string
+	ResourceOwner string
+	ExecutionDate time.Time
+	StatusCode    int
+	Latency       time.Duration
+	// Error is the message of the error of a failed call, empty if the call succeeded
+	Error string
+	// Succeeded is true if the target responded according to its success criteria
+	Succeeded bool
+}
+
+type TargetExecutionSearchQueries struct {
+	SearchRequest
+	Queries []SearchQuery
+}
+
+func (q *TargetExecutionSearchQueries) toQuery(query sq.SelectBuilder) sq.SelectBuilder {
+	query = q.SearchRequest.toQuery(query)
+	// the executions are paginated newest first by default,
+	// the id keeps the order of executions with the same sorting value stable across pages
+	direction := " DESC"
+	if q.SortingColumn.isZero() {
+		query = query.OrderBy(TargetExecutionColumnExecutionDate.identifier() + direction)
+	} else if q.Asc {
+		direction = ""
+	}
+	query = query.OrderBy(TargetExecutionColumnID.identifier() + direction)
+	return q.filter(query)
+}
+
+// filter applies the queries without pagination and sorting
+func (q *TargetExecutionSearchQueries) filter(query sq.SelectBuilder) sq.SelectBuilder {
+	for _, q := range q.Queries {
+		query = q.toQuery(query)
+	}
+	return query
+}
+
+// SearchTargetExecutions returns a page of the recorded executions of the target,
+// executions older than the [execution.TargetExecutionRetention] are not kept.
+// The count is the total of executions matching the queries, independent of the requested page.
+func (q *Queries) SearchTargetExecutions(ctx context.Context, targetID, resourceOwner string, queries *TargetExecutionSearchQueries) (executions *TargetExecutions, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	eq := sq.Eq{
+		TargetExecutionColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
+		TargetExecutionColumnTargetID.identifier():   targetID,
+		TargetColumnResourceOwner.identifier():       resourceOwner,
+	}
+	query, scan := prepareTargetExecutionsQuery(ctx, q.client)
+	// the executions are not projected, the state is the one of the targets they are joined with
+	executions, err = genericRowsQueryWithState[*TargetExecutions](ctx, q.client, targetTable, combineToWhereStmt(query, queries.toQuery, eq), scan)
+	if err != nil {
+		return nil, err
+	}
+	// the count is part of the returned rows, a page after the last execution has to count separately
+	if len(executions.TargetExecutions) > 0 || queries.Offset == 0 {
+		return executions, nil
+	}
+	countQuery, scanCount := prepareTargetExecutionsCountQuery(ctx, q.client)
+	executions.Count, err = genericRowQuery[uint64](ctx, q.client, combineToWhereStmt(countQuery, queries.filter, eq), scanCount)
+	if err != nil {
+		return nil, err
+	}
+	return executions, nil
+}
+
+// TargetWithStats is a target with the summary of its recorded executions
+type TargetWithStats struct {
+	*Target
+	// Stats are zero if the target has no recorded executions
+	Stats TargetExecutionStats
+}
+
+// TargetExecutionStats summarizes the recorded executions of a target
+type TargetExecutionStats struct {
+	// Total is the count of the recorded executions
+	Total uint64
+	// Succeeded is the count of the executions the target responded to according to its success criteria
+	Succeeded uint64
+	// LastError is the error of the latest failed execution, empty if no execution failed
+	LastError string
+}
+
+// SuccessRate returns the share of the succeeded executions between 0 and 1, 0 if there are no executions
+func (s TargetExecutionStats) SuccessRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Succeeded) / float64(s.Total)
+}
+
+// GetTargetWithStats returns the target of the resource owner together with the stats of its recorded executions.
+// The executions are aggregated by the database within the same query,
+// executions older than the [execution.TargetExecutionRetention] are not kept.
+func (q *Queries) GetTargetWithStats(ctx context.Context, id, resourceOwner string) (target *TargetWithStats, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	eq := sq.Eq{
+		TargetColumnID.identifier():            id,
+		TargetColumnResourceOwner.identifier(): resourceOwner,
+		TargetColumnInstanceID.identifier():    authz.GetInstance(ctx).InstanceID(),
+	}
+	query, scan := prepareTargetWithStatsQuery(ctx, q.client)
+	return genericRowQuery[*TargetWithStats](ctx, q.client, query.Where(eq), scan)
+}
+
+func NewTargetExecutionStatusCodeSearchQuery(method NumberComparison, code int) (SearchQuery, error) {
+	return NewNumberQuery(TargetExecutionColumnStatusCode, code, method)
+}
+
+// NewTargetExecutionDateFromSearchQuery filters the executions at or after the time
+func NewTargetExecutionDateFromSearchQuery(from time.Time) (SearchQuery, error) {
+	return NewTimestampQuery(TargetExecutionColumnExecutionDate, from, TimestampGreaterOrEquals)
+}
+
+// NewTargetExecutionDateToSearchQuery filters the executions before the time
+func NewTargetExecutionDateToSearchQuery(to time.Time) (SearchQuery, error) {
+	return NewTimestampQuery(TargetExecutionColumnExecutionDate, to, TimestampLess)
+}
+
+func prepareTargetExecutionsQuery(context.Context, prepareDatabase) (sq.SelectBuilder, func(rows *sql.Rows) (*TargetExecutions, error)) {
+	return sq.Select(
+			TargetExecutionColumnID.identifier(),
+			TargetExecutionColumnTargetID.identifier(),
+			TargetColumnResourceOwner.identifier(),
+			TargetExecutionColumnExecutionDate.identifier(),
+			TargetExecutionColumnStatusCode.identifier(),
+			TargetExecutionColumnLatency.identifier(),
+			TargetExecutionColumnError.identifier(),
+			TargetExecutionColumnSucceeded.identifier(),
+			countColumn.identifier(),
+		).From(targetExecutionTable.identifier()).
+			Join(targetExecutionTargetJoin).
+			PlaceholderFormat(sq.Dollar),
+		func(rows *sql.Rows) (*TargetExecutions, error) {
+			executions := make([]*TargetExecution, 0)
+			var count uint64
+			for rows.Next() {
+				execution := new(TargetExecution)
+				err := rows.Scan(
+					&execution.ID,
+					&execution.TargetID,
+					&execution.ResourceOwner,
+					&execution.ExecutionDate,
+					&execution.StatusCode,
+					&execution.Latency,
+					&execution.Error,
+					&execution.Succeeded,
+					&count,
+				)
+				if err != nil {
+					return nil, err
+				}
+				executions = append(executions, execution)
+			}
+
+			if err := rows.Close(); err != nil {
+				return nil, zerrors.ThrowInternal(err, "QUERY-Te8cLs", "Errors.Query.CloseRows")
+			}
+
+			return &TargetExecutions{
+				TargetExecutions: executions,
+				SearchResponse: SearchResponse{
+					Count: count,
+				},
+			}, nil
+		}
+}
+
+// targetExecutionTargetJoin joins the target of the executions, which they are filtered by the resource owner of
+var targetExecutionTargetJoin = targetTable.identifier() + " ON " +
+	TargetColumnInstanceID.identifier() + " = " + TargetExecutionColumnInstanceID.identifier() + " AND " +
+	TargetColumnID.identifier() + " = " + TargetExecutionColumnTargetID.identifier()
+
+// prepareTargetExecutionsCountQuery counts the executions without reading them
+func prepareTargetExecutionsCountQuery(context.Context, prepareDatabase) (sq.SelectBuilder, func(row *sql.Row) (uint64, error)) {
+	return sq.Select("COUNT(*)").
+			From(targetExecutionTable.identifier()).
+			Join(targetExecutionTargetJoin).
+			PlaceholderFormat(sq.Dollar),
+		func(row *sql.Row) (count uint64, err error) {
+			if err := row.Scan(&count); err != nil {
+				return 0, zerrors.ThrowInternal(err, "QUERY-Te9cNt", "Errors.Internal")
+			}
+			return count, nil
+		}
+}
+
+// targetExecutionStatsJoin aggregates the executions of each selected target,
+// an aggregate without grouping always results in a single row, so targets without executions have zero stats
+var targetExecutionStatsJoin = `CROSS JOIN LATERAL (SELECT COUNT(*) AS total,` +
+	` COUNT(*) FILTER (WHERE ` + TargetExecutionColumnSucceeded.identifier() + `) AS succeeded,` +
+	` (ARRAY_AGG(` + TargetExecutionColumnError.identifier() + ` ORDER BY ` + TargetExecutionColumnExecutionDate.identifier() + ` DESC)` +
+	` FILTER (WHERE NOT ` + TargetExecutionColumnSucceeded.identifier() + `))[1] AS last_error` +
+	` FROM ` + targetExecutionTable.identifier() +
+	` WHERE ` + TargetExecutionColumnInstanceID.identifier() + ` = ` + TargetColumnInstanceID.identifier() +
+	` AND ` + TargetExecutionColumnTargetID.identifier() + ` = ` + TargetColumnID.identifier() + `) AS stats`
+
+func prepareTargetWithStatsQuery(ctx context.Context, _ prepareDatabase) (sq.SelectBuilder, func(row *sql.Row) (*TargetWithStats, error)) {
+	return sq.Select(
+			append(targetColumns(), "stats.total", "stats.succeeded", "stats.last_error")...,
+		).From(targetTable.identifier()).
+			JoinClause(targetExecutionStatsJoin).
+			PlaceholderFormat(sq.Dollar),
+		func(row *sql.Row) (*TargetWithStats, error) {
+			target := &TargetWithStats{Target: new(Target)}
+			var (
+				successCriteria, oauth2, basicAuth, jwtDelivery []byte
+				lastError                                       sql.NullString
+			)
+			err := row.Scan(
+				append(
+					targetScanDestinations(target.Target, &successCriteria, &oauth2, &basicAuth, &jwtDelivery),
+					&target.Stats.Total,
+					&target.Stats.Succeeded,
+					&lastError,
+				)...,
+			)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, zerrors.ThrowNotFound(err, "QUERY-Ts4tNf", "Errors.Target.NotFound")
+				}
+				return nil, zerrors.ThrowInternal(err, "QUERY-Ts4tIe", "Errors.Internal")
+			}
+			if err = decodeTarget(ctx, target.Target, successCriteria, oauth2, basicAuth, jwtDelivery); err != nil {
+				return nil, err
+			}
+			target.Stats.LastError = lastError.String
+			return target, nil
+		}
+}
@@ -0,0 +1,447 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/database"
+	db_mock "github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+var (
+	prepareTargetExecutionsStmt = `SELECT execution.target_executions.id,` +
+		` execution.target_executions.target_id,` +
+		` projections.targets2.resource_owner,` +
+		` execution.target_executions.execution_date,` +
+		` execution.target_executions.status_code,` +
+		` execution.target_executions.latency,` +
+		` execution.target_executions.error,` +
+		` execution.target_executions.succeeded,` +
+		` COUNT(*) OVER ()` +
+		` FROM execution.target_executions` +
+		` JOIN projections.targets2 ON projections.targets2.instance_id = execution.target_executions.instance_id` +
+		` AND projections.targets2.id = execution.target_executions.target_id`
+	prepareTargetExecutionsCols = []string{
+		"id",
+		"target_id",
+		"resource_owner",
+		"execution_date",
+		"status_code",
+		"latency",
+		"error",
+		"succeeded",
+		"count",
+	}
+)
+
+func Test_TargetExecutionPrepares(t *testing.T) {
+	type want struct {
+		sqlExpectations sqlExpectation
+		err             checkErr
+	}
+	tests := []struct {
+		name   string
+		want   want
+		object interface{}
+	}{
+		{
+			name: "prepareTargetExecutionsQuery no result",
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(prepareTargetExecutionsStmt),
+					nil,
+					nil,
+				),
+			},
+			object: &TargetExecutions{TargetExecutions: []*TargetExecution{}},
+		},
+		{
+			name: "prepareTargetExecutionsQuery multiple result",
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(prepareTargetExecutionsStmt),
+					prepareTargetExecutionsCols,
+					[][]driver.Value{
+						{
+							"execution-1",
+							"target",
+							"ro",
+							testNow,
+							200,
+							150 * time.Millisecond,
+							"",
+							true,
+						},
+						{
+							"execution-2",
+							"target",
+							"ro",
+							testNow.Add(time.Minute),
+							502,
+							2 * time.Second,
+							"bad gateway",
+							false,
+						},
+					},
+				),
+			},
+			object: &TargetExecutions{
+				SearchResponse: SearchResponse{
+					Count: 2,
+				},
+				TargetExecutions: []*TargetExecution{
+					{
+						ID:            "execution-1",
+						TargetID:      "target",
+						ResourceOwner: "ro",
+						ExecutionDate: testNow,
+						StatusCode:    200,
+						Latency:       150 * time.Millisecond,
+						Succeeded:     true,
+					},
+					{
+						ID:            "execution-2",
+						TargetID:      "target",
+						ResourceOwner: "ro",
+						ExecutionDate: testNow.Add(time.Minute),
+						StatusCode:    502,
+						Latency:       2 * time.Second,
+						Error:         "bad gateway",
+					},
+				},
+			},
+		},
+		{
+			name: "prepareTargetExecutionsQuery sql err",
+			want: want{
+				sqlExpectations: mockQueryErr(
+					regexp.QuoteMeta(prepareTargetExecutionsStmt),
+					sql.ErrConnDone,
+				),
+				err: func(err error) (error, bool) {
+					if !errors.Is(err, sql.ErrConnDone) {
+						return fmt.Errorf("err should be sql.ErrConnDone got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: (*TargetExecutions)(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPrepare(t, prepareTargetExecutionsQuery, tt.object, tt.want.sqlExpectations, tt.want.err, defaultPrepareArgs...)
+		})
+	}
+}
+
+func Test_prepareTargetWithStatsQuery(t *testing.T) {
+	stmt := strings.TrimSuffix(prepareTargetStmt, " FROM projections.targets2") +
+		`, stats.total, stats.succeeded, stats.last_error` +
+		` FROM projections.targets2` +
+		` CROSS JOIN LATERAL (SELECT COUNT(*) AS total,` +
+		` COUNT(*) FILTER (WHERE execution.target_executions.succeeded) AS succeeded,` +
+		` (ARRAY_AGG(execution.target_executions.error ORDER BY execution.target_executions.execution_date DESC)` +
+		` FILTER (WHERE NOT execution.target_executions.succeeded))[1] AS last_error` +
+		` FROM execution.target_executions` +
+		` WHERE execution.target_executions.instance_id = projections.targets2.instance_id` +
+		` AND execution.target_executions.target_id = projections.targets2.id) AS stats`
+	cols := append(prepareTargetCols, "total", "succeeded", "last_error")
+	targetRow := []driver.Value{"id", testNow, "ro", uint64(20211109), "target-name", domain.TargetTypeWebhook, time.Second, "https://example.com", true, nil, nil, "", "", time.Duration(0), time.Duration(0), time.Duration(0), nil, nil, domain.TargetPayloadFormatJSON, nil, int64(0), domain.TargetResponseSizePolicyError, int64(0), time.Duration(0), false, true, "", nil, nil, int64(0), "", domain.TargetActive}
+	target := &Target{
+		ID: "id",
+		ObjectDetails: domain.ObjectDetails{
+			EventDate:     testNow,
+			ResourceOwner: "ro",
+			Sequence:      20211109,
+		},
+		Name:             "target-name",
+		TargetType:       domain.TargetTypeWebhook,
+		Timeout:          time.Second,
+		Endpoint:         "https://example.com",
+		IncludePayload:   true,
+		State:            domain.TargetActive,
+		InterruptOnError: true,
+		PayloadFormat:    domain.TargetPayloadFormatJSON,
+	}
+	tests := []struct {
+		name            string
+		sqlExpectations sqlExpectation
+		err             checkErr
+		object          interface{}
+	}{
+		{
+			name: "not found",
+			sqlExpectations: mockQueriesScanErr(
+				regexp.QuoteMeta(stmt),
+				nil,
+				nil,
+			),
+			err: func(err error) (error, bool) {
+				if !zerrors.IsNotFound(err) {
+					return fmt.Errorf("err should be zitadel.NotFoundError got: %w", err), false
+				}
+				return nil, true
+			},
+			object: (*TargetWithStats)(nil),
+		},
+		{
+			name: "without executions, zero stats",
+			sqlExpectations: mockQuery(
+				regexp.QuoteMeta(stmt),
+				cols,
+				append(targetRow, uint64(0), uint64(0), nil),
+			),
+			object: &TargetWithStats{
+				Target: target,
+			},
+		},
+		{
+			name: "with executions",
+			sqlExpectations: mockQuery(
+				regexp.QuoteMeta(stmt),
+				cols,
+				append(targetRow, uint64(4), uint64(3), "bad gateway"),
+			),
+			object: &TargetWithStats{
+				Target: target,
+				Stats: TargetExecutionStats{
+					Total:     4,
+					Succeeded: 3,
+					LastError: "bad gateway",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPrepare(t, prepareTargetWithStatsQuery, tt.object, tt.sqlExpectations, tt.err, defaultPrepareArgs...)
+		})
+	}
+}
+
+func TestTargetExecutionStats_SuccessRate(t *testing.T) {
+	assert.Zero(t, TargetExecutionStats{}.SuccessRate())
+	assert.Equal(t, 0.75, TargetExecutionStats{Total: 4, Succeeded: 3}.SuccessRate())
+}
+
+func TestTargetExecutionSearchQueries_toQuery(t *testing.T) {
+	from := testNow.Add(-time.Hour)
+	to := testNow
+
+	statusQuery, err := NewTargetExecutionStatusCodeSearchQuery(NumberGreater, 499)
+	require.NoError(t, err)
+	fromQuery, err := NewTargetExecutionDateFromSearchQuery(from)
+	require.NoError(t, err)
+	toQuery, err := NewTargetExecutionDateToSearchQuery(to)
+	require.NoError(t, err)
+
+	queries := &TargetExecutionSearchQueries{
+		Queries: []SearchQuery{statusQuery, fromQuery, toQuery},
+	}
+	query, _ := prepareTargetExecutionsQuery(context.Background(), nil)
+	stmt, args, err := queries.toQuery(query).ToSql()
+	require.NoError(t, err)
+
+	assert.Contains(t, stmt, "execution.target_executions.status_code > $1")
+	assert.Contains(t, stmt, "execution.target_executions.execution_date >= $2")
+	assert.Contains(t, stmt, "execution.target_executions.execution_date < $3")
+	assert.Equal(t, []any{499, from, to}, args)
+}
+
+func TestTargetExecutionSearchQueries_toQuery_sorting(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   SearchRequest
+		wantOrder string
+	}{
+		{
+			name:      "default, newest first",
+			wantOrder: "ORDER BY execution.target_executions.execution_date DESC, execution.target_executions.id DESC",
+		},
+		{
+			name:      "sorting column ascending",
+			request:   SearchRequest{SortingColumn: TargetExecutionColumnStatusCode, Asc: true},
+			wantOrder: "ORDER BY execution.target_executions.status_code, execution.target_executions.id",
+		},
+		{
+			name:      "sorting column descending",
+			request:   SearchRequest{SortingColumn: TargetExecutionColumnLatency},
+			wantOrder: "ORDER BY execution.target_executions.latency DESC, execution.target_executions.id DESC",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queries := &TargetExecutionSearchQueries{SearchRequest: tt.request}
+			query, _ := prepareTargetExecutionsQuery(context.Background(), nil)
+			stmt, _, err := queries.toQuery(query).ToSql()
+			require.NoError(t, err)
+			assert.Contains(t, stmt, tt.wantOrder)
+		})
+	}
+}
+
+func TestQueries_SearchTargetExecutions(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	failed, err := NewTargetExecutionStatusCodeSearchQuery(NumberGreater, 499)
+	require.NoError(t, err)
+	from, err := NewTargetExecutionDateFromSearchQuery(testNow.Add(-time.Hour))
+	require.NoError(t, err)
+	to, err := NewTargetExecutionDateToSearchQuery(testNow)
+	require.NoError(t, err)
+
+	executionRow := func(id string, statusCode int, count uint64) []driver.Value {
+		return []driver.Value{id, "target", "ro", testNow, statusCode, time.Second, "", statusCode < 300, count}
+	}
+	type expectation struct {
+		rows [][]driver.Value
+		// count is the result of the separate count query, nil if not expected
+		count []driver.Value
+	}
+	tests := []struct {
+		name      string
+		queries   *TargetExecutionSearchQueries
+		args      []driver.Value
+		pageStmt  string
+		expect    expectation
+		wantIDs   []string
+		wantCount uint64
+	}{
+		{
+			name: "first page",
+			queries: &TargetExecutionSearchQueries{
+				SearchRequest: SearchRequest{Limit: 2},
+			},
+			args:     []driver.Value{"instance", "target", "ro"},
+			pageStmt: "LIMIT 2$",
+			expect: expectation{
+				rows: [][]driver.Value{executionRow("5", 200, 5), executionRow("4", 200, 5)},
+			},
+			wantIDs:   []string{"5", "4"},
+			wantCount: 5,
+		},
+		{
+			name: "last page",
+			queries: &TargetExecutionSearchQueries{
+				SearchRequest: SearchRequest{Limit: 2, Offset: 4},
+			},
+			args:     []driver.Value{"instance", "target", "ro"},
+			pageStmt: "LIMIT 2 OFFSET 4$",
+			expect: expectation{
+				rows: [][]driver.Value{executionRow("1", 200, 5)},
+			},
+			wantIDs:   []string{"1"},
+			wantCount: 5,
+		},
+		{
+			name: "page after last execution, counted separately",
+			queries: &TargetExecutionSearchQueries{
+				SearchRequest: SearchRequest{Limit: 2, Offset: 6},
+			},
+			args:     []driver.Value{"instance", "target", "ro"},
+			pageStmt: "LIMIT 2 OFFSET 6$",
+			expect: expectation{
+				count: []driver.Value{uint64(5)},
+			},
+			wantIDs:   []string{},
+			wantCount: 5,
+		},
+		{
+			name: "no executions",
+			queries: &TargetExecutionSearchQueries{
+				SearchRequest: SearchRequest{Limit: 2},
+			},
+			args:      []driver.Value{"instance", "target", "ro"},
+			pageStmt:  "LIMIT 2$",
+			wantIDs:   []string{},
+			wantCount: 0,
+		},
+		{
+			name: "filtered by status",
+			queries: &TargetExecutionSearchQueries{
+				SearchRequest: SearchRequest{Limit: 1},
+				Queries:       []SearchQuery{failed},
+			},
+			args:     []driver.Value{499, "instance", "target", "ro"},
+			pageStmt: "LIMIT 1$",
+			expect: expectation{
+				rows: [][]driver.Value{executionRow("3", 502, 2)},
+			},
+			wantIDs:   []string{"3"},
+			wantCount: 2,
+		},
+		{
+			name: "filtered by status and time, page after last execution",
+			queries: &TargetExecutionSearchQueries{
+				SearchRequest: SearchRequest{Limit: 1, Offset: 3},
+				Queries:       []SearchQuery{failed, from, to},
+			},
+			args:     []driver.Value{499, testNow.Add(-time.Hour), testNow, "instance", "target", "ro"},
+			pageStmt: "LIMIT 1 OFFSET 3$",
+			expect: expectation{
+				count: []driver.Value{uint64(2)},
+			},
+			wantIDs:   []string{},
+			wantCount: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+			require.NoError(t, err)
+			defer client.Close()
+
+			rows := sqlmock.NewRows(prepareTargetExecutionsCols)
+			for _, row := range tt.expect.rows {
+				rows.AddRow(row...)
+			}
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(prepareTargetExecutionsStmt) + ".*" + tt.pageStmt).
+				WithArgs(tt.args...).
+				WillReturnRows(rows)
+			mock.ExpectCommit()
+			mock.ExpectBegin()
+			mock.ExpectQuery("projections.current_states").
+				WillReturnRows(sqlmock.NewRows([]string{"event_date", "position", "last_updated"}).AddRow(testNow, float64(1), testNow))
+			mock.ExpectCommit()
+			if tt.expect.count != nil {
+				// the count query neither sorts nor pages
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM execution.target_executions JOIN projections.targets2 ON ") + `.* WHERE .*resource_owner = \$\d+\)?$`).
+					WithArgs(tt.args...).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tt.expect.count...))
+				mock.ExpectCommit()
+			}
+
+			q := &Queries{
+				client: &database.DB{
+					DB:       client,
+					Database: new(prepareDB),
+				},
+			}
+			got, err := q.SearchTargetExecutions(ctx, "target", "ro", tt.queries)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCount, got.Count)
+			ids := make([]string, len(got.TargetExecutions))
+			for i, execution := range got.TargetExecutions {
+				ids[i] = execution.ID
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+)
+
+// TargetExportVersion is the version of the format of [TargetsExport].
+// Fields are only added to the format, a field is never removed, renamed or changed in its meaning within a version.
+const TargetExportVersion = 1
+
+// TargetsExport describes the configuration of targets for infrastructure as code, e.g. to be serialized to JSON or HCL.
+// Secrets are never exported, only whether they are set.
+type TargetsExport struct {
+	Version int             `json:"version"`
+	Targets []*TargetExport `json:"targets"`
+}
+
+// TargetExport is the configuration of a target in the [TargetsExport].
+// Enums are exported by their name and durations in the format of [time.Duration.String],
+// unset optional fields are omitted, so fields added later do not change the export of existing targets.
+type TargetExport struct {
+	ID                     string                         `json:"id"`
+	Name                   string                         `json:"name"`
+	Type                   string                         `json:"type"`
+	Endpoint               string                         `json:"endpoint"`
+	Timeout                string                         `json:"timeout"`
+	InterruptOnError       bool                           `json:"interrupt_on_error"`
+	SuccessCriteria        *TargetSuccessCriteriaExport   `json:"success_criteria,omitempty"`
+	EventTypeFilter        []string                       `json:"event_type_filter,omitempty"`
+	CertificateFingerprint string                         `json:"certificate_fingerprint,omitempty"`
+	CABundle               string                         `json:"ca_bundle,omitempty"`
+	ResponseContentType    string                         `json:"response_content_type,omitempty"`
+	PayloadFormat          string                         `json:"payload_format"`
+	PhaseTimeouts          *TargetPhaseTimeoutsExport     `json:"phase_timeouts,omitempty"`
+	OAuth2                 *TargetOAuth2Export            `json:"oauth2,omitempty"`
+	BasicAuth              *TargetBasicAuthExport         `json:"basic_auth,omitempty"`
+	JWTDelivery            *TargetJWTDeliveryExport       `json:"jwt_delivery,omitempty"`
+	MirrorEndpoints        []string                       `json:"mirror_endpoints,omitempty"`
+	FailoverEndpoints      []string                       `json:"failover_endpoints,omitempty"`
+	ResponseSizeLimit      *TargetResponseSizeLimitExport `json:"response_size_limit,omitempty"`
+	BatchDelivery          *TargetBatchDeliveryExport     `json:"batch_delivery,omitempty"`
+	OrderedDelivery        bool                           `json:"ordered_delivery"`
+	IncludePayload         bool                           `json:"include_payload"`
+	MaxRedirects           int                            `json:"max_redirects"`
+	RequestIDHeader        string                         `json:"request_id_header,omitempty"`
+}
+
+type TargetSuccessCriteriaExport struct {
+	StatusCodes []int  `json:"status_codes,omitempty"`
+	BodyPath    string `json:"body_path,omitempty"`
+	BodyValue   string `json:"body_value,omitempty"`
+}
+
+type TargetPhaseTimeoutsExport struct {
+	Dial           string `json:"dial,omitempty"`
+	TLSHandshake   string `json:"tls_handshake,omitempty"`
+	ResponseHeader string `json:"response_header,omitempty"`
+}
+
+// TargetOAuth2Export are the client credentials of a target without the client secret
+type TargetOAuth2Export struct {
+	TokenEndpoint   string `json:"token_endpoint"`
+	ClientID        string `json:"client_id"`
+	HasClientSecret bool   `json:"has_client_secret"`
+}
+
+// TargetBasicAuthExport is the basic authentication of a target without the password
+type TargetBasicAuthExport struct {
+	Username    string `json:"username"`
+	HasPassword bool   `json:"has_password"`
+}
+
+// TargetJWTDeliveryExport is the JWT delivery of a target, the JWTs are signed with the signing key of the target
+type TargetJWTDeliveryExport struct {
+	Algorithm string `json:"algorithm"`
+	Issuer    string `json:"issuer"`
+}
+
+type TargetResponseSizeLimitExport struct {
+	MaxBytes int64  `json:"max_bytes"`
+	Policy   string `json:"policy"`
+}
+
+type TargetBatchDeliveryExport struct {
+	MaxSize int    `json:"max_size"`
+	MaxWait string `json:"max_wait,omitempty"`
+}
+
+// ExportTargets exports the targets found by [Queries.SearchTargets] in the current [TargetExportVersion]
+func (q *Queries) ExportTargets(ctx context.Context, queries *TargetSearchQueries) (_ *TargetsExport, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	targets, err := q.SearchTargets(ctx, queries)
+	if err != nil {
+		return nil, err
+	}
+	export := &TargetsExport{
+		Version: TargetExportVersion,
+		Targets: make([]*TargetExport, len(targets.Targets)),
+	}
+	for i, target := range targets.Targets {
+		export.Targets[i] = exportTarget(target)
+	}
+	return export, nil
+}
+
+func exportTarget(target *Target) *TargetExport {
+	export := &TargetExport{
+		ID:                     target.ID,
+		Name:                   target.Name,
+		Type:                   exportTargetType(target.TargetType),
+		Endpoint:               target.Endpoint,
+		Timeout:                target.Timeout.String(),
+		InterruptOnError:       target.InterruptOnError,
+		EventTypeFilter:        target.EventTypeFilter,
+		CertificateFingerprint: target.CertificateFingerprint,
+		CABundle:               target.CABundle,
+		ResponseContentType:    target.ResponseContentType,
+		PayloadFormat:          exportPayloadFormat(target.PayloadFormat),
+		MirrorEndpoints:        target.MirrorEndpoints,
+		FailoverEndpoints:      target.FailoverEndpoints,
+		OrderedDelivery:        target.OrderedDelivery,
+		IncludePayload:         target.IncludePayload,
+		MaxRedirects:           target.MaxRedirects,
+		RequestIDHeader:        target.RequestIDHeader,
+	}
+	if criteria := target.SuccessCriteria; criteria != nil {
+		export.SuccessCriteria = &TargetSuccessCriteriaExport{
+			StatusCodes: criteria.StatusCodes,
+			BodyPath:    criteria.BodyPath,
+			BodyValue:   criteria.BodyValue,
+		}
+	}
+	if timeouts := target.PhaseTimeouts; !timeouts.IsZero() {
+		export.PhaseTimeouts = &TargetPhaseTimeoutsExport{
+			Dial:           exportOptionalDuration(timeouts.Dial),
+			TLSHandshake:   exportOptionalDuration(timeouts.TLSHandshake),
+			ResponseHeader: exportOptionalDuration(timeouts.ResponseHeader),
+		}
+	}
+	if oauth2 := target.OAuth2; oauth2 != nil {
+		export.OAuth2 = &TargetOAuth2Export{
+			TokenEndpoint:   oauth2.TokenEndpoint,
+			ClientID:        oauth2.ClientID,
+			HasClientSecret: oauth2.MaskedClientSecret != "",
+		}
+	}
+	if basicAuth := target.BasicAuth; basicAuth != nil {
+		export.BasicAuth = &TargetBasicAuthExport{
+			Username:    basicAuth.Username,
+			HasPassword: basicAuth.MaskedPassword != "",
+		}
+	}
+	if delivery := target.JWTDelivery; delivery != nil {
+		export.JWTDelivery = &TargetJWTDeliveryExport{
+			Algorithm: string(delivery.Algorithm),
+			Issuer:    delivery.Issuer,
+		}
+	}
+	if limit := target.ResponseSizeLimit; !limit.IsZero() {
+		export.ResponseSizeLimit = &TargetResponseSizeLimitExport{
+			MaxBytes: limit.MaxBytes,
+			Policy:   exportResponseSizePolicy(limit.Policy),
+		}
+	}
+	if batch := target.BatchDelivery; !batch.IsZero() {
+		export.BatchDelivery = &TargetBatchDeliveryExport{
+			MaxSize: batch.MaxSize,
+			MaxWait: exportOptionalDuration(batch.MaxWait),
+		}
+	}
+	return export
+}
+
+func exportTargetType(targetType domain.TargetType) string {
+	switch targetType {
+	case domain.TargetTypeWebhook:
+		return "webhook"
+	case domain.TargetTypeCall:
+		return "call"
+	case domain.TargetTypeAsync:
+		return "async"
+	default:
+		return "unspecified"
+	}
+}
+
+func exportPayloadFormat(format domain.TargetPayloadFormat) string {
+	switch format {
+	case domain.TargetPayloadFormatJSON:
+		return "json"
+	case domain.TargetPayloadFormatForm:
+		return "form"
+	case domain.TargetPayloadFormatXML:
+		return "xml"
+	default:
+		return "unspecified"
+	}
+}
+
+func exportResponseSizePolicy(policy domain.TargetResponseSizePolicy) string {
+	switch policy {
+	case domain.TargetResponseSizePolicyError:
+		return "error"
+	case domain.TargetResponseSizePolicyTruncate:
+		return "truncate"
+	default:
+		return "unspecified"
+	}
+}
+
+// exportOptionalDuration returns an empty string for unset durations, so they are omitted
+func exportOptionalDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
@@ -0,0 +1,137 @@
+package query
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/database"
+	"github.com/zitadel/zitadel/internal/domain"
+)
+
+func Test_exportTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   *Target
+		want     *TargetExport
+		wantJSON string
+	}{
+		{
+			name: "minimal",
+			target: &Target{
+				ID:             "id",
+				Name:           "name",
+				TargetType:     domain.TargetTypeWebhook,
+				Endpoint:       "https://example.com",
+				Timeout:        time.Second,
+				IncludePayload: true,
+			},
+			want: &TargetExport{
+				ID:             "id",
+				Name:           "name",
+				Type:           "webhook",
+				Endpoint:       "https://example.com",
+				Timeout:        "1s",
+				PayloadFormat:  "json",
+				IncludePayload: true,
+			},
+			wantJSON: `{"id":"id","name":"name","type":"webhook","endpoint":"https://example.com","timeout":"1s","interrupt_on_error":false,"payload_format":"json","ordered_delivery":false,"include_payload":true,"max_redirects":0}`,
+		},
+		{
+			name: "full",
+			target: &Target{
+				ID:               "id",
+				Name:             "name",
+				TargetType:       domain.TargetTypeAsync,
+				Endpoint:         "https://example.com",
+				Timeout:          10 * time.Second,
+				InterruptOnError: true,
+				SuccessCriteria: &domain.TargetSuccessCriteria{
+					StatusCodes: []int{200, 202},
+					BodyPath:    "$.status",
+					BodyValue:   "ok",
+				},
+				EventTypeFilter:        database.TextArray[string]{"user.*"},
+				CertificateFingerprint: "fingerprint",
+				CABundle:               "bundle",
+				ResponseContentType:    "application/json",
+				PayloadFormat:          domain.TargetPayloadFormatXML,
+				PhaseTimeouts:          domain.TargetPhaseTimeouts{Dial: time.Second},
+				OAuth2: &TargetOAuth2{
+					TokenEndpoint:      "https://auth.example.com/oauth/token",
+					ClientID:           "client",
+					MaskedClientSecret: maskedSecret,
+				},
+				BasicAuth: &TargetBasicAuth{
+					Username: "user",
+				},
+				JWTDelivery: &domain.TargetJWTDelivery{
+					Algorithm: domain.TargetJWTAlgorithmHS256,
+					Issuer:    "zitadel",
+				},
+				MirrorEndpoints:   database.TextArray[string]{"https://mirror.example.com"},
+				FailoverEndpoints: database.TextArray[string]{"https://failover.example.com"},
+				ResponseSizeLimit: domain.TargetResponseSizeLimit{MaxBytes: 1024, Policy: domain.TargetResponseSizePolicyTruncate},
+				BatchDelivery:     domain.TargetBatchDelivery{MaxSize: 100, MaxWait: time.Minute},
+				OrderedDelivery:   true,
+				MaxRedirects:      3,
+				RequestIDHeader:   "X-Request-Id",
+			},
+			want: &TargetExport{
+				ID:               "id",
+				Name:             "name",
+				Type:             "async",
+				Endpoint:         "https://example.com",
+				Timeout:          "10s",
+				InterruptOnError: true,
+				SuccessCriteria: &TargetSuccessCriteriaExport{
+					StatusCodes: []int{200, 202},
+					BodyPath:    "$.status",
+					BodyValue:   "ok",
+				},
+				EventTypeFilter:        []string{"user.*"},
+				CertificateFingerprint: "fingerprint",
+				CABundle:               "bundle",
+				ResponseContentType:    "application/json",
+				PayloadFormat:          "xml",
+				PhaseTimeouts:          &TargetPhaseTimeoutsExport{Dial: "1s"},
+				OAuth2: &TargetOAuth2Export{
+					TokenEndpoint:   "https://auth.example.com/oauth/token",
+					ClientID:        "client",
+					HasClientSecret: true,
+				},
+				BasicAuth: &TargetBasicAuthExport{
+					Username: "user",
+				},
+				JWTDelivery: &TargetJWTDeliveryExport{
+					Algorithm: "HS256",
+					Issuer:    "zitadel",
+				},
+				MirrorEndpoints:   []string{"https://mirror.example.com"},
+				FailoverEndpoints: []string{"https://failover.example.com"},
+				ResponseSizeLimit: &TargetResponseSizeLimitExport{MaxBytes: 1024, Policy: "truncate"},
+				BatchDelivery:     &TargetBatchDeliveryExport{MaxSize: 100, MaxWait: "1m0s"},
+				OrderedDelivery:   true,
+				MaxRedirects:      3,
+				RequestIDHeader:   "X-Request-Id",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := exportTarget(tt.target)
+			assert.Equal(t, tt.want, got)
+
+			data, err := json.Marshal(got)
+			require.NoError(t, err)
+			// the masked secrets are never exported
+			assert.NotContains(t, string(data), maskedSecret)
+			if tt.wantJSON != "" {
+				assert.JSONEq(t, tt.wantJSON, string(data))
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/execution"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// TargetHealthStatus summarizes the health of a target
+type TargetHealthStatus int
+
+const (
+	// TargetHealthStatusUnknown targets have no recorded executions
+	TargetHealthStatusUnknown TargetHealthStatus = iota
+	// TargetHealthStatusHealthy targets succeeded in their latest execution
+	TargetHealthStatusHealthy
+	// TargetHealthStatusFailing targets failed in their latest execution
+	TargetHealthStatusFailing
+	// TargetHealthStatusPaused targets are not called, as they were paused
+	TargetHealthStatusPaused
+)
+
+func (s TargetHealthStatus) String() string {
+	switch s {
+	case TargetHealthStatusHealthy:
+		return "healthy"
+	case TargetHealthStatusFailing:
+		return "failing"
+	case TargetHealthStatusPaused:
+		return "paused"
+	default:
+		return "unknown"
+	}
+}
+
+// TargetHealth is the health of a target for an overview of the status of all targets
+type TargetHealth struct {
+	TargetID string
+	Name     string
+	Status   TargetHealthStatus
+	// Queue is the state of the delivery to the target
+	Queue execution.TargetQueueState
+	// LastExecution is the latest recorded execution or verification of the target, nil if there is none
+	LastExecution *TargetExecution
+}
+
+// targetLastExecutionJoin selects the latest execution of each selected target,
+// targets without executions are kept with NULL values
+var targetLastExecutionJoin = `LEFT JOIN LATERAL (SELECT ` +
+	TargetExecutionColumnID.identifier() + ` AS id, ` +
+	TargetExecutionColumnExecutionDate.identifier() + ` AS execution_date, ` +
+	TargetExecutionColumnStatusCode.identifier() + ` AS status_code, ` +
+	TargetExecutionColumnLatency.identifier() + ` AS latency, ` +
+	TargetExecutionColumnError.identifier() + ` AS error, ` +
+	TargetExecutionColumnSucceeded.identifier() + ` AS succeeded` +
+	` FROM ` + targetExecutionTable.identifier() +
+	` WHERE ` + TargetExecutionColumnInstanceID.identifier() + ` = ` + TargetColumnInstanceID.identifier() +
+	` AND ` + TargetExecutionColumnTargetID.identifier() + ` = ` + TargetColumnID.identifier() +
+	` ORDER BY ` + TargetExecutionColumnExecutionDate.identifier() + ` DESC LIMIT 1) AS last_execution ON TRUE`
+
+// TargetsHealth returns the health of all targets of the resource owner ordered by their name.
+// The latest execution and the queue of each target are selected by the database within a single query,
+// so they are the same on every node.
+// Paused targets are reported as paused, targets without recorded executions as unknown,
+// executions older than the [execution.TargetExecutionRetention] are not kept.
+func (q *Queries) TargetsHealth(ctx context.Context, resourceOwner string) (_ []*TargetHealth, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	eq := sq.Eq{
+		TargetColumnInstanceID.identifier():    authz.GetInstance(ctx).InstanceID(),
+		TargetColumnResourceOwner.identifier(): resourceOwner,
+	}
+	query, scan := prepareTargetsHealthQuery(ctx, q.client)
+	health, err := genericRowsQuery[[]*TargetHealth](ctx, q.client, query.Where(eq), scan)
+	if err != nil {
+		return nil, err
+	}
+	for _, target := range health {
+		target.Status = targetHealthStatus(target.Queue, target.LastExecution)
+	}
+	return health, nil
+}
+
+// targetHealthStatus returns paused for paused targets, otherwise the outcome of the latest execution
+func targetHealthStatus(queue execution.TargetQueueState, lastExecution *TargetExecution) TargetHealthStatus {
+	switch {
+	case queue.Paused:
+		return TargetHealthStatusPaused
+	case lastExecution == nil:
+		return TargetHealthStatusUnknown
+	case !lastExecution.Succeeded:
+		return TargetHealthStatusFailing
+	default:
+		return TargetHealthStatusHealthy
+	}
+}
+
+func prepareTargetsHealthQuery(context.Context, prepareDatabase) (sq.SelectBuilder, func(rows *sql.Rows) ([]*TargetHealth, error)) {
+	return sq.Select(
+			TargetColumnID.identifier(),
+			TargetColumnName.identifier(),
+			TargetColumnResourceOwner.identifier(),
+			TargetColumnState.identifier(),
+			targetQueueDepthColumn,
+			targetQueueDroppedColumn,
+			"last_execution.id",
+			"last_execution.execution_date",
+			"last_execution.status_code",
+			"last_execution.latency",
+			"last_execution.error",
+			"last_execution.succeeded",
+		).From(targetTable.identifier()).
+			JoinClause(targetLastExecutionJoin).
+			OrderBy(TargetColumnName.identifier()).
+			PlaceholderFormat(sq.Dollar),
+		func(rows *sql.Rows) ([]*TargetHealth, error) {
+			health := make([]*TargetHealth, 0)
+			for rows.Next() {
+				var (
+					target        = new(TargetHealth)
+					resourceOwner string
+					state         domain.TargetState
+					executionID   sql.NullString
+					executionDate sql.NullTime
+					statusCode    sql.NullInt64
+					latency       sql.NullInt64
+					executionErr  sql.NullString
+					succeeded     sql.NullBool
+				)
+				err := rows.Scan(
+					&target.TargetID,
+					&target.Name,
+					&resourceOwner,
+					&state,
+					&target.Queue.Depth,
+					&target.Queue.Dropped,
+					&executionID,
+					&executionDate,
+					&statusCode,
+					&latency,
+					&executionErr,
+					&succeeded,
+				)
+				if err != nil {
+					return nil, err
+				}
+				if executionID.Valid {
+					target.LastExecution = &TargetExecution{
+						ID:            executionID.String,
+						TargetID:      target.TargetID,
+						ResourceOwner: resourceOwner,
+						ExecutionDate: executionDate.Time,
+						StatusCode:    int(statusCode.Int64),
+						Latency:       time.Duration(latency.Int64),
+						Error:         executionErr.String,
+						Succeeded:     succeeded.Bool,
+					}
+				}
+				target.Queue.Paused = state == domain.TargetInactive
+				health = append(health, target)
+			}
+
+			if err := rows.Close(); err != nil {
+				return nil, zerrors.ThrowInternal(err, "QUERY-Th7cLs", "Errors.Query.CloseRows")
+			}
+			return health, nil
+		}
+}
@@ -0,0 +1,210 @@
+package query
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/database"
+	db_mock "github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/execution"
+)
+
+var (
+	prepareTargetsHealthStmt = `SELECT projections.targets2.id,` +
+		` projections.targets2.name,` +
+		` projections.targets2.resource_owner,` +
+		` projections.targets2.state,` +
+		` (SELECT COUNT(*) FROM execution.target_deliveries` +
+		` WHERE execution.target_deliveries.instance_id = projections.targets2.instance_id` +
+		` AND execution.target_deliveries.target_id = projections.targets2.id) AS queue_depth,` +
+		` COALESCE((SELECT dropped FROM execution.target_queues` +
+		` WHERE execution.target_queues.instance_id = projections.targets2.instance_id` +
+		` AND execution.target_queues.target_id = projections.targets2.id), 0) AS queue_dropped,` +
+		` last_execution.id,` +
+		` last_execution.execution_date,` +
+		` last_execution.status_code,` +
+		` last_execution.latency,` +
+		` last_execution.error,` +
+		` last_execution.succeeded` +
+		` FROM projections.targets2` +
+		` LEFT JOIN LATERAL (SELECT execution.target_executions.id AS id,` +
+		` execution.target_executions.execution_date AS execution_date,` +
+		` execution.target_executions.status_code AS status_code,` +
+		` execution.target_executions.latency AS latency,` +
+		` execution.target_executions.error AS error,` +
+		` execution.target_executions.succeeded AS succeeded` +
+		` FROM execution.target_executions` +
+		` WHERE execution.target_executions.instance_id = projections.targets2.instance_id` +
+		` AND execution.target_executions.target_id = projections.targets2.id` +
+		` ORDER BY execution.target_executions.execution_date DESC LIMIT 1) AS last_execution ON TRUE` +
+		` WHERE projections.targets2.instance_id = $1 AND projections.targets2.resource_owner = $2` +
+		` ORDER BY projections.targets2.name`
+	prepareTargetsHealthCols = []string{
+		"id",
+		"name",
+		"resource_owner",
+		"state",
+		"queue_depth",
+		"queue_dropped",
+		"id",
+		"execution_date",
+		"status_code",
+		"latency",
+		"error",
+		"succeeded",
+	}
+)
+
+func TestQueries_TargetsHealth(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	tests := []struct {
+		name string
+		rows [][]driver.Value
+		want []*TargetHealth
+	}{
+		{
+			name: "no targets",
+			want: []*TargetHealth{},
+		},
+		{
+			name: "healthy, failing, paused and never invoked",
+			rows: [][]driver.Value{
+				{"failing", "a", "ro", domain.TargetActive, 0, 0, "2", testNow, 502, int64(time.Second), "bad gateway", false},
+				{"healthy", "b", "ro", domain.TargetActive, 0, 0, "1", testNow, 200, int64(time.Second), "", true},
+				{"never", "c", "ro", domain.TargetActive, 0, 0, nil, nil, nil, nil, nil, nil},
+				{"paused", "d", "ro", domain.TargetInactive, 3, 1, "3", testNow, 200, int64(time.Second), "", true},
+			},
+			want: []*TargetHealth{
+				{
+					TargetID: "failing",
+					Name:     "a",
+					Status:   TargetHealthStatusFailing,
+					LastExecution: &TargetExecution{
+						ID:            "2",
+						TargetID:      "failing",
+						ResourceOwner: "ro",
+						ExecutionDate: testNow,
+						StatusCode:    502,
+						Latency:       time.Second,
+						Error:         "bad gateway",
+					},
+				},
+				{
+					TargetID: "healthy",
+					Name:     "b",
+					Status:   TargetHealthStatusHealthy,
+					LastExecution: &TargetExecution{
+						ID:            "1",
+						TargetID:      "healthy",
+						ResourceOwner: "ro",
+						ExecutionDate: testNow,
+						StatusCode:    200,
+						Latency:       time.Second,
+						Succeeded:     true,
+					},
+				},
+				{
+					TargetID: "never",
+					Name:     "c",
+					Status:   TargetHealthStatusUnknown,
+				},
+				{
+					TargetID: "paused",
+					Name:     "d",
+					Status:   TargetHealthStatusPaused,
+					Queue:    execution.TargetQueueState{Paused: true, Depth: 3, Dropped: 1},
+					LastExecution: &TargetExecution{
+						ID:            "3",
+						TargetID:      "paused",
+						ResourceOwner: "ro",
+						ExecutionDate: testNow,
+						StatusCode:    200,
+						Latency:       time.Second,
+						Succeeded:     true,
+					},
+				},
+			},
+		},
+		{
+			name: "paused without executions",
+			rows: [][]driver.Value{
+				{"paused", "a", "ro", domain.TargetInactive, 0, 0, nil, nil, nil, nil, nil, nil},
+			},
+			want: []*TargetHealth{
+				{
+					TargetID: "paused",
+					Name:     "a",
+					Status:   TargetHealthStatusPaused,
+					Queue:    execution.TargetQueueState{Paused: true},
+				},
+			},
+		},
+		{
+			name: "failing by the success criteria",
+			rows: [][]driver.Value{
+				{"failing", "a", "ro", domain.TargetActive, 0, 0, "1", testNow, 404, int64(time.Second), "not found", false},
+			},
+			want: []*TargetHealth{
+				{
+					TargetID: "failing",
+					Name:     "a",
+					Status:   TargetHealthStatusFailing,
+					LastExecution: &TargetExecution{
+						ID:            "1",
+						TargetID:      "failing",
+						ResourceOwner: "ro",
+						ExecutionDate: testNow,
+						StatusCode:    404,
+						Latency:       time.Second,
+						Error:         "not found",
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+			require.NoError(t, err)
+			defer client.Close()
+
+			rows := sqlmock.NewRows(prepareTargetsHealthCols)
+			for _, row := range tt.rows {
+				rows.AddRow(row...)
+			}
+			// a single query for all targets
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(prepareTargetsHealthStmt)).
+				WithArgs("instance", "ro").
+				WillReturnRows(rows)
+			mock.ExpectCommit()
+
+			q := &Queries{
+				client: &database.DB{
+					DB:       client,
+					Database: new(prepareDB),
+				},
+			}
+			got, err := q.TargetsHealth(ctx, "ro")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTargetHealthStatus_String(t *testing.T) {
+	assert.Equal(t, "unknown", TargetHealthStatusUnknown.String())
+	assert.Equal(t, "healthy", TargetHealthStatusHealthy.String())
+	assert.Equal(t, "failing", TargetHealthStatusFailing.String())
+	assert.Equal(t, "paused", TargetHealthStatusPaused.String())
+}
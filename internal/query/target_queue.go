@@ -0,0 +1,66 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/execution"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// targetQueueDepthColumn counts the payloads queued for the selected target
+var targetQueueDepthColumn = `(SELECT COUNT(*) FROM ` + execution.TargetDeliveryTable +
+	` WHERE ` + execution.TargetDeliveryTable + `.` + execution.TargetDeliveryInstanceIDCol + ` = ` + TargetColumnInstanceID.identifier() +
+	` AND ` + execution.TargetDeliveryTable + `.` + execution.TargetDeliveryTargetIDCol + ` = ` + TargetColumnID.identifier() + `) AS queue_depth`
+
+// targetQueueDroppedColumn selects the amount of payloads dropped for the selected target
+var targetQueueDroppedColumn = `COALESCE((SELECT ` + execution.TargetQueueDroppedCol + ` FROM ` + execution.TargetQueueTable +
+	` WHERE ` + execution.TargetQueueTable + `.` + execution.TargetQueueInstanceIDCol + ` = ` + TargetColumnInstanceID.identifier() +
+	` AND ` + execution.TargetQueueTable + `.` + execution.TargetQueueTargetIDCol + ` = ` + TargetColumnID.identifier() + `), 0) AS queue_dropped`
+
+// TargetQueueState returns if the target is paused and the amount of payloads queued and dropped meanwhile,
+// NotFound if the target does not exist.
+// The queue is stored in the database, so the amounts are the same on every node.
+func (q *Queries) TargetQueueState(ctx context.Context, id string) (_ *execution.TargetQueueState, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	eq := sq.Eq{
+		TargetColumnID.identifier():         id,
+		TargetColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
+	}
+	query, scan := prepareTargetQueueStateQuery(ctx, q.client)
+	return genericRowQuery[*execution.TargetQueueState](ctx, q.client, query.Where(eq), scan)
+}
+
+func prepareTargetQueueStateQuery(context.Context, prepareDatabase) (sq.SelectBuilder, func(row *sql.Row) (*execution.TargetQueueState, error)) {
+	return sq.Select(
+			TargetColumnState.identifier(),
+			targetQueueDepthColumn,
+			targetQueueDroppedColumn,
+		).From(targetTable.identifier()).
+			PlaceholderFormat(sq.Dollar),
+		func(row *sql.Row) (*execution.TargetQueueState, error) {
+			state := new(execution.TargetQueueState)
+			var targetState domain.TargetState
+			err := row.Scan(
+				&targetState,
+				&state.Depth,
+				&state.Dropped,
+			)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, zerrors.ThrowNotFound(err, "QUERY-Tq4sNf", "Errors.Target.NotFound")
+				}
+				return nil, zerrors.ThrowInternal(err, "QUERY-Tq4sIe", "Errors.Internal")
+			}
+			state.Paused = targetState == domain.TargetInactive
+			return state, nil
+		}
+}
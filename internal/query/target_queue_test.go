@@ -0,0 +1,76 @@
+package query
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/execution"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+var (
+	prepareTargetQueueStateStmt = `SELECT projections.targets2.state,` +
+		` (SELECT COUNT(*) FROM execution.target_deliveries` +
+		` WHERE execution.target_deliveries.instance_id = projections.targets2.instance_id` +
+		` AND execution.target_deliveries.target_id = projections.targets2.id) AS queue_depth,` +
+		` COALESCE((SELECT dropped FROM execution.target_queues` +
+		` WHERE execution.target_queues.instance_id = projections.targets2.instance_id` +
+		` AND execution.target_queues.target_id = projections.targets2.id), 0) AS queue_dropped` +
+		` FROM projections.targets2`
+	prepareTargetQueueStateCols = []string{
+		"state",
+		"queue_depth",
+		"queue_dropped",
+	}
+)
+
+func Test_prepareTargetQueueStateQuery(t *testing.T) {
+	tests := []struct {
+		name            string
+		sqlExpectations sqlExpectation
+		err             checkErr
+		object          interface{}
+	}{
+		{
+			name: "not found",
+			sqlExpectations: mockQueryScanErr(
+				regexp.QuoteMeta(prepareTargetQueueStateStmt),
+				nil,
+				nil,
+			),
+			err: func(err error) (error, bool) {
+				if !zerrors.IsNotFound(err) {
+					return fmt.Errorf("err should be zitadel.NotFoundError got: %w", err), false
+				}
+				return nil, true
+			},
+			object: (*execution.TargetQueueState)(nil),
+		},
+		{
+			name: "active",
+			sqlExpectations: mockQuery(
+				regexp.QuoteMeta(prepareTargetQueueStateStmt),
+				prepareTargetQueueStateCols,
+				[]driver.Value{domain.TargetActive, 0, 0},
+			),
+			object: &execution.TargetQueueState{},
+		},
+		{
+			name: "paused with queued and dropped payloads",
+			sqlExpectations: mockQuery(
+				regexp.QuoteMeta(prepareTargetQueueStateStmt),
+				prepareTargetQueueStateCols,
+				[]driver.Value{domain.TargetInactive, 2, uint64(1)},
+			),
+			object: &execution.TargetQueueState{Paused: true, Depth: 2, Dropped: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPrepare(t, prepareTargetQueueStateQuery, tt.object, tt.sqlExpectations, tt.err, defaultPrepareArgs...)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/telemetry/tracing"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+// TargetActiveSigningKeys returns the masked signing keys of the target which are currently valid, the primary key first.
+// The secondary key is only returned during its grace window, the result is empty if the requests are not signed.
+func (q *Queries) TargetActiveSigningKeys(ctx context.Context, id string) (_ []*domain.ActiveSigningKey, err error) {
+	ctx, span := tracing.NewSpan(ctx)
+	defer func() { span.EndWithError(err) }()
+
+	eq := sq.Eq{
+		TargetColumnID.identifier():         id,
+		TargetColumnInstanceID.identifier(): authz.GetInstance(ctx).InstanceID(),
+	}
+	query, scan := prepareTargetSigningKeysQuery(ctx, q.client)
+	keys, err := genericRowQuery[*domain.TargetSigningKeys](ctx, q.client, query.Where(eq), scan)
+	if err != nil {
+		return nil, err
+	}
+	return keys.ActiveKeys(time.Now(), q.idpConfigEncryption)
+}
+
+func prepareTargetSigningKeysQuery(context.Context, prepareDatabase) (sq.SelectBuilder, func(row *sql.Row) (*domain.TargetSigningKeys, error)) {
+	return sq.Select(
+			TargetColumnSigningKeys.identifier(),
+		).From(targetTable.identifier()).
+			PlaceholderFormat(sq.Dollar),
+		func(row *sql.Row) (*domain.TargetSigningKeys, error) {
+			var signingKeys []byte
+			if err := row.Scan(&signingKeys); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, zerrors.ThrowNotFound(err, "QUERY-Sk7Nf1", "Errors.Target.NotFound")
+				}
+				return nil, zerrors.ThrowInternal(err, "QUERY-Sk7Sc2", "Errors.Internal")
+			}
+			return unmarshalSigningKeys(signingKeys)
+		}
+}
@@ -0,0 +1,107 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/crypto"
+	"github.com/zitadel/zitadel/internal/database"
+	db_mock "github.com/zitadel/zitadel/internal/database/mock"
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+var prepareTargetSigningKeysStmt = `SELECT projections.targets2.signing_keys` +
+	` FROM projections.targets2` +
+	` WHERE projections.targets2.id = $1 AND projections.targets2.instance_id = $2`
+
+func TestQueries_TargetActiveSigningKeys(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	validUntil := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	tests := []struct {
+		name    string
+		rows    [][]driver.Value
+		want    []*domain.ActiveSigningKey
+		wantErr error
+	}{
+		{
+			name:    "not found",
+			wantErr: zerrors.ThrowNotFound(sql.ErrNoRows, "QUERY-Sk7Nf1", "Errors.Target.NotFound"),
+		},
+		{
+			name: "not signed",
+			rows: [][]driver.Value{{nil}},
+		},
+		{
+			name: "primary",
+			rows: [][]driver.Value{{
+				[]byte(`{"primary":{"CryptoType":0,"Algorithm":"enc","KeyID":"id","Crypted":"cHJpbWFyeS1rZXktMDAwMQ=="}}`),
+			}},
+			want: []*domain.ActiveSigningKey{{MaskedKey: "************0001", Primary: true}},
+		},
+		{
+			name: "rotating",
+			rows: [][]driver.Value{{
+				[]byte(`{"primary":{"CryptoType":0,"Algorithm":"enc","KeyID":"id","Crypted":"cHJpbWFyeS1rZXktMDAwMQ=="},"secondary":{"CryptoType":0,"Algorithm":"enc","KeyID":"id","Crypted":"c2Vjb25kYXJ5LWtleS0wMDAy"},"secondaryValidUntil":"` + validUntil.Format(time.RFC3339) + `"}`),
+			}},
+			want: []*domain.ActiveSigningKey{
+				{MaskedKey: "************0001", Primary: true},
+				{MaskedKey: "**************0002", ValidUntil: validUntil},
+			},
+		},
+		{
+			name: "grace window ended",
+			rows: [][]driver.Value{{
+				[]byte(`{"primary":{"CryptoType":0,"Algorithm":"enc","KeyID":"id","Crypted":"cHJpbWFyeS1rZXktMDAwMQ=="},"secondary":{"CryptoType":0,"Algorithm":"enc","KeyID":"id","Crypted":"c2Vjb25kYXJ5LWtleS0wMDAy"},"secondaryValidUntil":"2024-01-01T00:00:00Z"}`),
+			}},
+			want: []*domain.ActiveSigningKey{{MaskedKey: "************0001", Primary: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+			require.NoError(t, err)
+			defer client.Close()
+
+			rows := sqlmock.NewRows([]string{"signing_keys"})
+			for _, row := range tt.rows {
+				rows.AddRow(row...)
+			}
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(prepareTargetSigningKeysStmt)).
+				WithArgs("target", "instance").
+				WillReturnRows(rows)
+			if tt.wantErr != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			q := &Queries{
+				client: &database.DB{
+					DB:       client,
+					Database: new(prepareDB),
+				},
+				idpConfigEncryption: crypto.CreateMockEncryptionAlg(gomock.NewController(t)),
+			}
+			got, err := q.TargetActiveSigningKeys(ctx, "target")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -1,30 +1,65 @@
 package query
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"errors"
 	"fmt"
+	"reflect"
 	"regexp"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/zitadel/zitadel/internal/api/authz"
+	"github.com/zitadel/zitadel/internal/database"
+	db_mock "github.com/zitadel/zitadel/internal/database/mock"
 	"github.com/zitadel/zitadel/internal/domain"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
 
 var (
-	prepareTargetsStmt = `SELECT projections.targets1.id,` +
-		` projections.targets1.change_date,` +
-		` projections.targets1.resource_owner,` +
-		` projections.targets1.sequence,` +
-		` projections.targets1.name,` +
-		` projections.targets1.target_type,` +
-		` projections.targets1.timeout,` +
-		` projections.targets1.endpoint,` +
-		` projections.targets1.interrupt_on_error,` +
+	prepareTargetsStmt = `SELECT projections.targets2.id,` +
+		` projections.targets2.change_date,` +
+		` projections.targets2.resource_owner,` +
+		` projections.targets2.sequence,` +
+		` projections.targets2.name,` +
+		` projections.targets2.target_type,` +
+		` projections.targets2.timeout,` +
+		` projections.targets2.endpoint,` +
+		` projections.targets2.interrupt_on_error,` +
+		` projections.targets2.success_criteria,` +
+		` projections.targets2.event_type_filter,` +
+		` projections.targets2.certificate_fingerprint,` +
+		` projections.targets2.response_content_type,` +
+		` projections.targets2.dial_timeout,` +
+		` projections.targets2.tls_handshake_timeout,` +
+		` projections.targets2.response_header_timeout,` +
+		` projections.targets2.oauth2,` +
+		` projections.targets2.basic_auth,` +
+		` projections.targets2.payload_format,` +
+		` projections.targets2.mirror_endpoints,` +
+		` projections.targets2.response_size_limit,` +
+		` projections.targets2.response_size_policy,` +
+		` projections.targets2.batch_max_size,` +
+		` projections.targets2.batch_max_wait,` +
+		` projections.targets2.ordered_delivery,` +
+		` projections.targets2.include_payload,` +
+		` projections.targets2.ca_bundle,` +
+		` projections.targets2.jwt_delivery,` +
+		` projections.targets2.failover_endpoints,` +
+		` projections.targets2.max_redirects,` +
+		` projections.targets2.request_id_header,` +
+		` projections.targets2.state,` +
 		` COUNT(*) OVER ()` +
-		` FROM projections.targets1`
+		` FROM projections.targets2`
 	prepareTargetsCols = []string{
 		"id",
 		"change_date",
@@ -35,19 +70,65 @@ var (
 		"timeout",
 		"endpoint",
 		"interrupt_on_error",
+		"success_criteria",
+		"event_type_filter",
+		"certificate_fingerprint",
+		"response_content_type",
+		"dial_timeout",
+		"tls_handshake_timeout",
+		"response_header_timeout",
+		"oauth2",
+		"basic_auth",
+		"payload_format",
+		"mirror_endpoints",
+		"response_size_limit",
+		"response_size_policy",
+		"batch_max_size",
+		"batch_max_wait",
+		"ordered_delivery",
+		"include_payload",
+		"ca_bundle",
+		"jwt_delivery",
+		"failover_endpoints",
+		"max_redirects",
+		"request_id_header",
+		"state",
 		"count",
 	}
 
-	prepareTargetStmt = `SELECT projections.targets1.id,` +
-		` projections.targets1.change_date,` +
-		` projections.targets1.resource_owner,` +
-		` projections.targets1.sequence,` +
-		` projections.targets1.name,` +
-		` projections.targets1.target_type,` +
-		` projections.targets1.timeout,` +
-		` projections.targets1.endpoint,` +
-		` projections.targets1.interrupt_on_error` +
-		` FROM projections.targets1`
+	prepareTargetStmt = `SELECT projections.targets2.id,` +
+		` projections.targets2.change_date,` +
+		` projections.targets2.resource_owner,` +
+		` projections.targets2.sequence,` +
+		` projections.targets2.name,` +
+		` projections.targets2.target_type,` +
+		` projections.targets2.timeout,` +
+		` projections.targets2.endpoint,` +
+		` projections.targets2.interrupt_on_error,` +
+		` projections.targets2.success_criteria,` +
+		` projections.targets2.event_type_filter,` +
+		` projections.targets2.certificate_fingerprint,` +
+		` projections.targets2.response_content_type,` +
+		` projections.targets2.dial_timeout,` +
+		` projections.targets2.tls_handshake_timeout,` +
+		` projections.targets2.response_header_timeout,` +
+		` projections.targets2.oauth2,` +
+		` projections.targets2.basic_auth,` +
+		` projections.targets2.payload_format,` +
+		` projections.targets2.mirror_endpoints,` +
+		` projections.targets2.response_size_limit,` +
+		` projections.targets2.response_size_policy,` +
+		` projections.targets2.batch_max_size,` +
+		` projections.targets2.batch_max_wait,` +
+		` projections.targets2.ordered_delivery,` +
+		` projections.targets2.include_payload,` +
+		` projections.targets2.ca_bundle,` +
+		` projections.targets2.jwt_delivery,` +
+		` projections.targets2.failover_endpoints,` +
+		` projections.targets2.max_redirects,` +
+		` projections.targets2.request_id_header,` +
+		` projections.targets2.state` +
+		` FROM projections.targets2`
 	prepareTargetCols = []string{
 		"id",
 		"change_date",
@@ -58,6 +139,29 @@ var (
 		"timeout",
 		"endpoint",
 		"interrupt_on_error",
+		"success_criteria",
+		"event_type_filter",
+		"certificate_fingerprint",
+		"response_content_type",
+		"dial_timeout",
+		"tls_handshake_timeout",
+		"response_header_timeout",
+		"oauth2",
+		"basic_auth",
+		"payload_format",
+		"mirror_endpoints",
+		"response_size_limit",
+		"response_size_policy",
+		"batch_max_size",
+		"batch_max_wait",
+		"ordered_delivery",
+		"include_payload",
+		"ca_bundle",
+		"jwt_delivery",
+		"failover_endpoints",
+		"max_redirects",
+		"request_id_header",
+		"state",
 	}
 )
 
@@ -102,6 +206,29 @@ func Test_TargetPrepares(t *testing.T) {
 							1 * time.Second,
 							"https://example.com",
 							true,
+							nil,
+							database.TextArray[string]{"user.*"},
+							"",
+							"",
+							time.Duration(0),
+							time.Duration(0),
+							time.Duration(0),
+							nil,
+							nil,
+							domain.TargetPayloadFormatJSON,
+							nil,
+							int64(0),
+							domain.TargetResponseSizePolicyError,
+							int64(0),
+							time.Duration(0),
+							false,
+							true,
+							"",
+							nil,
+							nil,
+							int64(0),
+							"",
+							domain.TargetActive,
 						},
 					},
 				),
@@ -122,7 +249,10 @@ func Test_TargetPrepares(t *testing.T) {
 						TargetType:       domain.TargetTypeWebhook,
 						Timeout:          1 * time.Second,
 						Endpoint:         "https://example.com",
+						IncludePayload:   true,
+						State:            domain.TargetActive,
 						InterruptOnError: true,
+						EventTypeFilter:  database.TextArray[string]{"user.*"},
 					},
 				},
 			},
@@ -145,6 +275,29 @@ func Test_TargetPrepares(t *testing.T) {
 							1 * time.Second,
 							"https://example.com",
 							true,
+							nil,
+							database.TextArray[string]{"user.*"},
+							"",
+							"",
+							time.Duration(0),
+							time.Duration(0),
+							time.Duration(0),
+							nil,
+							nil,
+							domain.TargetPayloadFormatJSON,
+							nil,
+							int64(0),
+							domain.TargetResponseSizePolicyError,
+							int64(0),
+							time.Duration(0),
+							false,
+							true,
+							"",
+							nil,
+							nil,
+							int64(0),
+							"",
+							domain.TargetActive,
 						},
 						{
 							"id-2",
@@ -156,6 +309,29 @@ func Test_TargetPrepares(t *testing.T) {
 							1 * time.Second,
 							"https://example.com",
 							false,
+							nil,
+							database.TextArray[string]{"user.*"},
+							"",
+							"",
+							time.Duration(0),
+							time.Duration(0),
+							time.Duration(0),
+							nil,
+							nil,
+							domain.TargetPayloadFormatJSON,
+							nil,
+							int64(0),
+							domain.TargetResponseSizePolicyError,
+							int64(0),
+							time.Duration(0),
+							false,
+							true,
+							"",
+							nil,
+							nil,
+							int64(0),
+							"",
+							domain.TargetActive,
 						},
 						{
 							"id-3",
@@ -167,6 +343,29 @@ func Test_TargetPrepares(t *testing.T) {
 							1 * time.Second,
 							"https://example.com",
 							false,
+							nil,
+							database.TextArray[string]{"user.*"},
+							"",
+							"",
+							time.Duration(0),
+							time.Duration(0),
+							time.Duration(0),
+							nil,
+							nil,
+							domain.TargetPayloadFormatJSON,
+							nil,
+							int64(0),
+							domain.TargetResponseSizePolicyError,
+							int64(0),
+							time.Duration(0),
+							false,
+							true,
+							"",
+							nil,
+							nil,
+							int64(0),
+							"",
+							domain.TargetActive,
 						},
 					},
 				),
@@ -187,7 +386,10 @@ func Test_TargetPrepares(t *testing.T) {
 						TargetType:       domain.TargetTypeWebhook,
 						Timeout:          1 * time.Second,
 						Endpoint:         "https://example.com",
+						IncludePayload:   true,
+						State:            domain.TargetActive,
 						InterruptOnError: true,
+						EventTypeFilter:  database.TextArray[string]{"user.*"},
 					},
 					{
 						ID: "id-2",
@@ -200,7 +402,10 @@ func Test_TargetPrepares(t *testing.T) {
 						TargetType:       domain.TargetTypeWebhook,
 						Timeout:          1 * time.Second,
 						Endpoint:         "https://example.com",
+						IncludePayload:   true,
+						State:            domain.TargetActive,
 						InterruptOnError: false,
+						EventTypeFilter:  database.TextArray[string]{"user.*"},
 					},
 					{
 						ID: "id-3",
@@ -213,7 +418,10 @@ func Test_TargetPrepares(t *testing.T) {
 						TargetType:       domain.TargetTypeAsync,
 						Timeout:          1 * time.Second,
 						Endpoint:         "https://example.com",
+						IncludePayload:   true,
+						State:            domain.TargetActive,
 						InterruptOnError: false,
+						EventTypeFilter:  database.TextArray[string]{"user.*"},
 					},
 				},
 			},
@@ -270,6 +478,29 @@ func Test_TargetPrepares(t *testing.T) {
 						1 * time.Second,
 						"https://example.com",
 						true,
+						[]byte(`{"statusCodes":[200,202],"bodyPath":"$.status","bodyValue":"ok"}`),
+						database.TextArray[string]{"user.*", "session.added"},
+						"d4:1d:8c:d9:8f:00:b2:04:e9:80:09:98:ec:f8:42:7e:d4:1d:8c:d9:8f:00:b2:04:e9:80:09:98:ec:f8:42:7e",
+						"application/json",
+						1 * time.Second,
+						time.Duration(0),
+						2 * time.Second,
+						[]byte(`{"tokenEndpoint":"https://auth.example.com/oauth/token","clientID":"client","clientSecret":{"CryptoType":0,"Algorithm":"enc","KeyID":"id","Crypted":"c2VjcmV0"}}`),
+						[]byte(`{"username":"user","password":{"CryptoType":0,"Algorithm":"enc","KeyID":"id","Crypted":"cGFzc3dvcmQ="}}`),
+						domain.TargetPayloadFormatXML,
+						database.TextArray[string]{"https://mirror.example.com"},
+						int64(1024),
+						domain.TargetResponseSizePolicyTruncate,
+						int64(100),
+						time.Second,
+						true,
+						false,
+						"bundle",
+						[]byte(`{"algorithm":"HS256","issuer":"zitadel"}`),
+						database.TextArray[string]{"https://failover.example.com"},
+						int64(3),
+						"X-Request-Id",
+						domain.TargetInactive,
 					},
 				),
 			},
@@ -285,6 +516,47 @@ func Test_TargetPrepares(t *testing.T) {
 				Timeout:          1 * time.Second,
 				Endpoint:         "https://example.com",
 				InterruptOnError: true,
+				SuccessCriteria: &domain.TargetSuccessCriteria{
+					StatusCodes: []int{200, 202},
+					BodyPath:    "$.status",
+					BodyValue:   "ok",
+				},
+				EventTypeFilter:        database.TextArray[string]{"user.*", "session.added"},
+				CertificateFingerprint: "d4:1d:8c:d9:8f:00:b2:04:e9:80:09:98:ec:f8:42:7e:d4:1d:8c:d9:8f:00:b2:04:e9:80:09:98:ec:f8:42:7e",
+				CABundle:               "bundle",
+				ResponseContentType:    "application/json",
+				PayloadFormat:          domain.TargetPayloadFormatXML,
+				MirrorEndpoints:        database.TextArray[string]{"https://mirror.example.com"},
+				ResponseSizeLimit: domain.TargetResponseSizeLimit{
+					MaxBytes: 1024,
+					Policy:   domain.TargetResponseSizePolicyTruncate,
+				},
+				BatchDelivery: domain.TargetBatchDelivery{
+					MaxSize: 100,
+					MaxWait: time.Second,
+				},
+				OrderedDelivery: true,
+				PhaseTimeouts: domain.TargetPhaseTimeouts{
+					Dial:           1 * time.Second,
+					ResponseHeader: 2 * time.Second,
+				},
+				OAuth2: &TargetOAuth2{
+					TokenEndpoint:      "https://auth.example.com/oauth/token",
+					ClientID:           "client",
+					MaskedClientSecret: "********",
+				},
+				BasicAuth: &TargetBasicAuth{
+					Username:       "user",
+					MaskedPassword: "********",
+				},
+				JWTDelivery: &domain.TargetJWTDelivery{
+					Algorithm: domain.TargetJWTAlgorithmHS256,
+					Issuer:    "zitadel",
+				},
+				FailoverEndpoints: database.TextArray[string]{"https://failover.example.com"},
+				MaxRedirects:      3,
+				RequestIDHeader:   "X-Request-Id",
+				State:             domain.TargetInactive,
 			},
 		},
 		{
@@ -311,3 +583,1089 @@ func Test_TargetPrepares(t *testing.T) {
 		})
 	}
 }
+
+func Test_TargetPrepares_sameColumns(t *testing.T) {
+	targetsQuery, _ := prepareTargetsQuery(context.Background(), nil)
+	targetQuery, _ := prepareTargetQuery(context.Background(), nil)
+
+	targetsStmt, _, err := targetsQuery.ToSql()
+	require.NoError(t, err)
+	targetStmt, _, err := targetQuery.ToSql()
+	require.NoError(t, err)
+
+	from := " FROM " + targetTable.identifier()
+	targetsColumns := strings.TrimSuffix(targetsStmt, ", "+countColumn.identifier()+from)
+	targetColumns := strings.TrimSuffix(targetStmt, from)
+	assert.Equal(t, targetColumns, targetsColumns)
+}
+
+func Test_prepareTargetEndpointsQuery(t *testing.T) {
+	stmt := `SELECT projections.targets2.id,` +
+		` projections.targets2.endpoint` +
+		` FROM projections.targets2` +
+		` ORDER BY projections.targets2.id`
+	cols := []string{"id", "endpoint"}
+
+	type want struct {
+		sqlExpectations sqlExpectation
+		err             checkErr
+	}
+	tests := []struct {
+		name   string
+		want   want
+		object interface{}
+	}{
+		{
+			name: "no result",
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(stmt),
+					nil,
+					nil,
+				),
+			},
+			object: map[string][]string{},
+		},
+		{
+			name: "grouped by normalized endpoint, exact and near duplicates",
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(stmt),
+					cols,
+					[][]driver.Value{
+						{"id-1", "https://example.com/hook"},
+						{"id-2", "https://example.com/hook"},
+						{"id-3", "https://Example.com/hook/"},
+						{"id-4", "https://example.com/other"},
+					},
+				),
+			},
+			object: map[string][]string{
+				"https://example.com/hook":  {"id-1", "id-2", "id-3"},
+				"https://example.com/other": {"id-4"},
+			},
+		},
+		{
+			name: "sql err",
+			want: want{
+				sqlExpectations: mockQueryErr(
+					regexp.QuoteMeta(stmt),
+					sql.ErrConnDone,
+				),
+				err: func(err error) (error, bool) {
+					if !errors.Is(err, sql.ErrConnDone) {
+						return fmt.Errorf("err should be sql.ErrConnDone got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: (map[string][]string)(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPrepare(t, prepareTargetEndpointsQuery, tt.object, tt.want.sqlExpectations, tt.want.err, defaultPrepareArgs...)
+		})
+	}
+}
+
+func Test_normalizeTargetURL(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{"https://example.com/hook", "https://example.com/hook"},
+		{"https://example.com/hook/", "https://example.com/hook"},
+		{"HTTPS://EXAMPLE.com/hook", "https://example.com/hook"},
+		{"https://example.com/Hook", "https://example.com/Hook"},
+		{"https://example.com/", "https://example.com"},
+		{"https://example.com/hook/?a=b", "https://example.com/hook?a=b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeTargetURL(tt.endpoint))
+		})
+	}
+}
+
+func Test_prepareDistinctTargetTypesQuery(t *testing.T) {
+	stmt := `SELECT DISTINCT projections.targets2.target_type` +
+		` FROM projections.targets2` +
+		` ORDER BY projections.targets2.target_type`
+	cols := []string{"target_type"}
+
+	type want struct {
+		sqlExpectations sqlExpectation
+		err             checkErr
+	}
+	tests := []struct {
+		name   string
+		want   want
+		object interface{}
+	}{
+		{
+			name: "no targets",
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(stmt),
+					nil,
+					nil,
+				),
+			},
+			object: []domain.TargetType{},
+		},
+		{
+			name: "distinct types",
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(stmt),
+					cols,
+					[][]driver.Value{
+						{domain.TargetTypeWebhook},
+						{domain.TargetTypeAsync},
+					},
+				),
+			},
+			object: []domain.TargetType{
+				domain.TargetTypeWebhook,
+				domain.TargetTypeAsync,
+			},
+		},
+		{
+			name: "sql err",
+			want: want{
+				sqlExpectations: mockQueryErr(
+					regexp.QuoteMeta(stmt),
+					sql.ErrConnDone,
+				),
+				err: func(err error) (error, bool) {
+					if !errors.Is(err, sql.ErrConnDone) {
+						return fmt.Errorf("err should be sql.ErrConnDone got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: ([]domain.TargetType)(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPrepare(t, prepareDistinctTargetTypesQuery, tt.object, tt.want.sqlExpectations, tt.want.err, defaultPrepareArgs...)
+		})
+	}
+}
+
+func Test_prepareTargetStateCountsQuery(t *testing.T) {
+	stmt := `SELECT projections.targets2.state, COUNT(*)` +
+		` FROM projections.targets2` +
+		` GROUP BY projections.targets2.state`
+	cols := []string{"state", "count"}
+
+	type want struct {
+		sqlExpectations sqlExpectation
+		err             checkErr
+	}
+	tests := []struct {
+		name   string
+		want   want
+		object interface{}
+	}{
+		{
+			name: "no targets, zero filled",
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(stmt),
+					nil,
+					nil,
+				),
+			},
+			object: map[domain.TargetState]uint64{
+				domain.TargetActive:   0,
+				domain.TargetInactive: 0,
+			},
+		},
+		{
+			name: "active targets",
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(stmt),
+					cols,
+					[][]driver.Value{
+						{domain.TargetActive, uint64(3)},
+					},
+				),
+			},
+			object: map[domain.TargetState]uint64{
+				domain.TargetActive:   3,
+				domain.TargetInactive: 0,
+			},
+		},
+		{
+			name: "inactive targets",
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(stmt),
+					cols,
+					[][]driver.Value{
+						{domain.TargetInactive, uint64(2)},
+					},
+				),
+			},
+			object: map[domain.TargetState]uint64{
+				domain.TargetActive:   0,
+				domain.TargetInactive: 2,
+			},
+		},
+		{
+			name: "all states",
+			want: want{
+				sqlExpectations: mockQueries(
+					regexp.QuoteMeta(stmt),
+					cols,
+					[][]driver.Value{
+						{domain.TargetActive, uint64(3)},
+						{domain.TargetInactive, uint64(2)},
+					},
+				),
+			},
+			object: map[domain.TargetState]uint64{
+				domain.TargetActive:   3,
+				domain.TargetInactive: 2,
+			},
+		},
+		{
+			name: "sql err",
+			want: want{
+				sqlExpectations: mockQueryErr(
+					regexp.QuoteMeta(stmt),
+					sql.ErrConnDone,
+				),
+				err: func(err error) (error, bool) {
+					if !errors.Is(err, sql.ErrConnDone) {
+						return fmt.Errorf("err should be sql.ErrConnDone got: %w", err), false
+					}
+					return nil, true
+				},
+			},
+			object: (map[domain.TargetState]uint64)(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPrepare(t, prepareTargetStateCountsQuery, tt.object, tt.want.sqlExpectations, tt.want.err, defaultPrepareArgs...)
+		})
+	}
+}
+
+func Test_prepareTargetQuery_strict(t *testing.T) {
+	strictPrepareArgs := []reflect.Value{reflect.ValueOf(WithStrictTargetScan(context.Background())), reflect.ValueOf(new(prepareDB))}
+	row := func(targetType domain.TargetType) []driver.Value {
+		return []driver.Value{"id", testNow, "ro", uint64(20211109), "target-name", targetType, 1 * time.Second, "https://example.com", true, nil, nil, "", "", time.Duration(0), time.Duration(0), time.Duration(0), nil, nil, domain.TargetPayloadFormatJSON, nil, int64(0), domain.TargetResponseSizePolicyError, int64(0), time.Duration(0), false, true, "", nil, nil, int64(0), "", domain.TargetActive}
+	}
+	tests := []struct {
+		name    string
+		args    []reflect.Value
+		row     []driver.Value
+		wantErr checkErr
+		object  interface{}
+	}{
+		{
+			name: "valid type",
+			args: strictPrepareArgs,
+			row:  row(domain.TargetTypeAsync),
+			object: &Target{
+				ID: "id",
+				ObjectDetails: domain.ObjectDetails{
+					EventDate:     testNow,
+					ResourceOwner: "ro",
+					Sequence:      20211109,
+				},
+				Name:             "target-name",
+				TargetType:       domain.TargetTypeAsync,
+				Timeout:          1 * time.Second,
+				Endpoint:         "https://example.com",
+				IncludePayload:   true,
+				State:            domain.TargetActive,
+				InterruptOnError: true,
+			},
+		},
+		{
+			name: "unknown type",
+			args: strictPrepareArgs,
+			row:  row(domain.TargetType(42)),
+			wantErr: func(err error) (error, bool) {
+				if !errors.Is(err, zerrors.ThrowInternal(nil, "QUERY-Tt7yPe", "Errors.Internal")) {
+					return fmt.Errorf("err should be internal got: %w", err), false
+				}
+				if !strings.Contains(err.Error(), "target id") {
+					return fmt.Errorf("err should identify the target got: %w", err), false
+				}
+				return nil, true
+			},
+			object: (*Target)(nil),
+		},
+		{
+			name: "unknown type not strict",
+			args: defaultPrepareArgs,
+			row:  row(domain.TargetType(42)),
+			object: &Target{
+				ID: "id",
+				ObjectDetails: domain.ObjectDetails{
+					EventDate:     testNow,
+					ResourceOwner: "ro",
+					Sequence:      20211109,
+				},
+				Name:             "target-name",
+				TargetType:       domain.TargetType(42),
+				Timeout:          1 * time.Second,
+				Endpoint:         "https://example.com",
+				IncludePayload:   true,
+				State:            domain.TargetActive,
+				InterruptOnError: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPrepare(t, prepareTargetQuery, tt.object, mockQuery(regexp.QuoteMeta(prepareTargetStmt), prepareTargetCols, tt.row), tt.wantErr, tt.args...)
+		})
+	}
+}
+
+func Test_prepareTargetsChangedSinceQuery(t *testing.T) {
+	stmt := prepareTargetsStmt + ` ORDER BY projections.targets2.change_date`
+	changed := testNow.Add(time.Minute)
+
+	tests := []struct {
+		name            string
+		sqlExpectations sqlExpectation
+		object          interface{}
+	}{
+		{
+			name: "no changes",
+			sqlExpectations: mockQueries(
+				regexp.QuoteMeta(stmt),
+				nil,
+				nil,
+			),
+			object: &Targets{Targets: []*Target{}},
+		},
+		{
+			name: "changed targets ordered by change date",
+			sqlExpectations: mockQueries(
+				regexp.QuoteMeta(stmt),
+				prepareTargetsCols,
+				[][]driver.Value{
+					{"id-1", testNow, "ro", uint64(20211109), "target-name1", domain.TargetTypeWebhook, 1 * time.Second, "https://example.com", true, nil, database.TextArray[string]{"user.*"}, "", "", time.Duration(0), time.Duration(0), time.Duration(0), nil, nil, domain.TargetPayloadFormatJSON, nil, int64(0), domain.TargetResponseSizePolicyError, int64(0), time.Duration(0), false, true, "", nil, nil, int64(0), "", domain.TargetActive},
+					{"id-2", changed, "ro", uint64(20211110), "target-name2", domain.TargetTypeCall, 1 * time.Second, "https://example.com", false, nil, database.TextArray[string]{"user.*"}, "", "", time.Duration(0), time.Duration(0), time.Duration(0), nil, nil, domain.TargetPayloadFormatJSON, nil, int64(0), domain.TargetResponseSizePolicyError, int64(0), time.Duration(0), false, true, "", nil, nil, int64(0), "", domain.TargetActive},
+				},
+			),
+			object: &Targets{
+				SearchResponse: SearchResponse{
+					Count: 2,
+				},
+				Targets: []*Target{
+					{
+						ID: "id-1",
+						ObjectDetails: domain.ObjectDetails{
+							EventDate:     testNow,
+							ResourceOwner: "ro",
+							Sequence:      20211109,
+						},
+						Name:             "target-name1",
+						TargetType:       domain.TargetTypeWebhook,
+						Timeout:          1 * time.Second,
+						Endpoint:         "https://example.com",
+						IncludePayload:   true,
+						State:            domain.TargetActive,
+						InterruptOnError: true,
+						EventTypeFilter:  database.TextArray[string]{"user.*"},
+					},
+					{
+						ID: "id-2",
+						ObjectDetails: domain.ObjectDetails{
+							EventDate:     changed,
+							ResourceOwner: "ro",
+							Sequence:      20211110,
+						},
+						Name:            "target-name2",
+						TargetType:      domain.TargetTypeCall,
+						Timeout:         1 * time.Second,
+						Endpoint:        "https://example.com",
+						IncludePayload:  true,
+						State:           domain.TargetActive,
+						EventTypeFilter: database.TextArray[string]{"user.*"},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertPrepare(t, prepareTargetsChangedSinceQuery, tt.object, tt.sqlExpectations, nil, defaultPrepareArgs...)
+		})
+	}
+}
+
+func Test_targetsChangedSinceCondition(t *testing.T) {
+	stmt, args, err := targetsChangedSinceCondition("instance", "ro", testNow).ToSql()
+	require.NoError(t, err)
+	assert.Contains(t, stmt, "projections.targets2.instance_id = ?")
+	assert.Contains(t, stmt, "projections.targets2.resource_owner = ?")
+	assert.Contains(t, stmt, "projections.targets2.change_date > ?")
+	assert.ElementsMatch(t, []any{"instance", "ro", testNow}, args)
+}
+
+func Test_targetsByIDsCondition(t *testing.T) {
+	stmt, args, err := targetsByIDsCondition("instance", "ro", []string{"id-1", "id-2"}).ToSql()
+	require.NoError(t, err)
+	assert.Contains(t, stmt, "projections.targets2.id IN (?,?)")
+	assert.Contains(t, stmt, "projections.targets2.instance_id = ?")
+	assert.Contains(t, stmt, "projections.targets2.resource_owner = ?")
+	assert.ElementsMatch(t, []any{"id-1", "id-2", "instance", "ro"}, args)
+}
+
+func Test_targetsOfResourceOwnersCondition(t *testing.T) {
+	stmt, args, err := targetsOfResourceOwnersCondition("instance", []string{"org-1", "org-2"}).ToSql()
+	require.NoError(t, err)
+	assert.Contains(t, stmt, "projections.targets2.instance_id = ?")
+	assert.Contains(t, stmt, "projections.targets2.resource_owner IN (?,?)")
+	assert.ElementsMatch(t, []any{"instance", "org-1", "org-2"}, args)
+}
+
+func Test_targetsByID(t *testing.T) {
+	target1 := &Target{ID: "id-1", Name: "target-name1"}
+	target2 := &Target{ID: "id-2", Name: "target-name2"}
+
+	got := targetsByID([]*Target{target1, target2})
+	assert.Equal(t, map[string]*Target{"id-1": target1, "id-2": target2}, got)
+	assert.NotContains(t, got, "id-3")
+
+	assert.Empty(t, targetsByID(nil))
+}
+
+func Test_targetsAfterIDCondition(t *testing.T) {
+	stmt, args, err := targetsAfterIDCondition("instance", "ro", "id-1").ToSql()
+	require.NoError(t, err)
+	assert.Contains(t, stmt, "projections.targets2.instance_id = ?")
+	assert.Contains(t, stmt, "projections.targets2.resource_owner = ?")
+	assert.Contains(t, stmt, "projections.targets2.id > ?")
+	assert.ElementsMatch(t, []any{"instance", "ro", "id-1"}, args)
+}
+
+// pageTargets returns a fetch for [forEachTarget] which pages through the targets sorted by their ID like the database
+func pageTargets(targets []*Target, fetches *int) func(ctx context.Context, afterID string, limit uint64) ([]*Target, error) {
+	return func(_ context.Context, afterID string, limit uint64) ([]*Target, error) {
+		*fetches++
+		start := sort.Search(len(targets), func(i int) bool { return targets[i].ID > afterID })
+		end := min(start+int(limit), len(targets))
+		return targets[start:end], nil
+	}
+}
+
+func Test_forEachTarget(t *testing.T) {
+	tests := []struct {
+		name        string
+		count       int
+		wantFetches int
+	}{
+		{
+			name:        "no targets",
+			count:       0,
+			wantFetches: 1,
+		},
+		{
+			name:        "last batch partial",
+			count:       1050,
+			wantFetches: 11,
+		},
+		{
+			name:        "last batch full",
+			count:       1000,
+			wantFetches: 11,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets := make([]*Target, tt.count)
+			for i := range targets {
+				targets[i] = &Target{ID: fmt.Sprintf("id-%05d", i)}
+			}
+			var fetches int
+			visited := make(map[string]int, tt.count)
+
+			err := forEachTarget(context.Background(), 100, pageTargets(targets, &fetches), func(target *Target) error {
+				visited[target.ID]++
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Len(t, visited, tt.count)
+			for id, visits := range visited {
+				assert.Equal(t, 1, visits, "target %s visited %d times", id, visits)
+			}
+			assert.Equal(t, tt.wantFetches, fetches)
+		})
+	}
+}
+
+func Test_forEachTarget_errors(t *testing.T) {
+	targets := make([]*Target, 250)
+	for i := range targets {
+		targets[i] = &Target{ID: fmt.Sprintf("id-%05d", i)}
+	}
+
+	t.Run("fn error stops", func(t *testing.T) {
+		errStop := errors.New("stop")
+		var fetches, visits int
+		err := forEachTarget(context.Background(), 100, pageTargets(targets, &fetches), func(target *Target) error {
+			visits++
+			if target.ID == "id-00149" {
+				return errStop
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, errStop)
+		assert.Equal(t, 150, visits)
+		assert.Equal(t, 2, fetches)
+	})
+	t.Run("fetch error", func(t *testing.T) {
+		errFetch := errors.New("fetch")
+		err := forEachTarget(context.Background(), 100, func(context.Context, string, uint64) ([]*Target, error) {
+			return nil, errFetch
+		}, func(*Target) error {
+			t.Fatal("no target expected")
+			return nil
+		})
+		assert.ErrorIs(t, err, errFetch)
+	})
+}
+
+func TestNewTargetInIDsSearchQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		wantStmt string
+		wantArgs []any
+		wantErr  error
+	}{
+		{
+			name:    "empty",
+			values:  []string{},
+			wantErr: ErrEmptyValues,
+		},
+		{
+			name:    "nil",
+			wantErr: ErrEmptyValues,
+		},
+		{
+			name:     "single",
+			values:   []string{"id-1"},
+			wantStmt: "projections.targets2.id IN (?)",
+			wantArgs: []any{"id-1"},
+		},
+		{
+			name:     "multiple",
+			values:   []string{"id-1", "id-2"},
+			wantStmt: "projections.targets2.id IN (?,?)",
+			wantArgs: []any{"id-1", "id-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := NewTargetInIDsSearchQuery(tt.values)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, query)
+				return
+			}
+			require.NoError(t, err)
+			stmt, args, err := query.comp().ToSql()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStmt, stmt)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestNewTargetInTypesSearchQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		types    []domain.TargetType
+		wantStmt string
+		wantArgs []any
+		wantErr  error
+	}{
+		{
+			name:    "empty",
+			types:   []domain.TargetType{},
+			wantErr: ErrEmptyValues,
+		},
+		{
+			name:    "nil",
+			wantErr: ErrEmptyValues,
+		},
+		{
+			name:     "single",
+			types:    []domain.TargetType{domain.TargetTypeAsync},
+			wantStmt: "projections.targets2.target_type IN (?)",
+			wantArgs: []any{domain.TargetTypeAsync},
+		},
+		{
+			name:     "multiple",
+			types:    []domain.TargetType{domain.TargetTypeWebhook, domain.TargetTypeCall},
+			wantStmt: "projections.targets2.target_type IN (?,?)",
+			wantArgs: []any{domain.TargetTypeWebhook, domain.TargetTypeCall},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := NewTargetInTypesSearchQuery(tt.types)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, query)
+				return
+			}
+			require.NoError(t, err)
+			stmt, args, err := query.comp().ToSql()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStmt, stmt)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestNewTargetHasSigningKeySearchQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		has      bool
+		wantStmt string
+	}{
+		{
+			name:     "with signing key",
+			has:      true,
+			wantStmt: "SELECT projections.targets2.id FROM projections.targets2 WHERE projections.targets2.name = $1 AND projections.targets2.signing_keys IS NOT NULL",
+		},
+		{
+			name:     "without signing key",
+			has:      false,
+			wantStmt: "SELECT projections.targets2.id FROM projections.targets2 WHERE projections.targets2.name = $1 AND projections.targets2.signing_keys IS NULL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nameQuery, err := NewTargetNameSearchQuery(TextEquals, "name")
+			require.NoError(t, err)
+			signingKeyQuery, err := NewTargetHasSigningKeySearchQuery(tt.has)
+			require.NoError(t, err)
+			queries := &TargetSearchQueries{Queries: []SearchQuery{nameQuery, signingKeyQuery}}
+
+			stmt, args, err := queries.toQuery(
+				sq.Select(TargetColumnID.identifier()).From(targetTable.identifier()).PlaceholderFormat(sq.Dollar),
+			).ToSql()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStmt, stmt)
+			assert.Equal(t, []any{"name"}, args)
+		})
+	}
+}
+
+func TestNewTargetTimeoutSearchQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   NumberComparison
+		timeout  time.Duration
+		wantStmt string
+		wantArgs []any
+		wantErr  error
+	}{
+		{
+			name:     "greater",
+			method:   NumberGreater,
+			timeout:  30 * time.Second,
+			wantStmt: "projections.targets2.timeout > ?",
+			wantArgs: []any{int64(30 * time.Second)},
+		},
+		{
+			name:     "less",
+			method:   NumberLess,
+			timeout:  time.Second,
+			wantStmt: "projections.targets2.timeout < ?",
+			wantArgs: []any{int64(time.Second)},
+		},
+		{
+			name:     "equal",
+			method:   NumberEquals,
+			timeout:  time.Minute,
+			wantStmt: "projections.targets2.timeout = ?",
+			wantArgs: []any{int64(time.Minute)},
+		},
+		{
+			name:    "list contains",
+			method:  NumberListContains,
+			timeout: time.Minute,
+			wantErr: ErrInvalidCompare,
+		},
+		{
+			name:    "invalid",
+			method:  numberCompareMax,
+			timeout: time.Minute,
+			wantErr: ErrInvalidCompare,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := NewTargetTimeoutSearchQuery(tt.method, tt.timeout)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, query)
+				return
+			}
+			require.NoError(t, err)
+			stmt, args, err := query.comp().ToSql()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStmt, stmt)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestQueries_LatestChangedTarget(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	targetRow := func(id string, changeDate time.Time) []driver.Value {
+		return []driver.Value{id, changeDate, "ro", uint64(20211109), "name-" + id, domain.TargetTypeWebhook, time.Second, "https://example.com", false, nil, nil, "", "", time.Duration(0), time.Duration(0), time.Duration(0), nil, nil, domain.TargetPayloadFormatJSON, nil, int64(0), domain.TargetResponseSizePolicyError, int64(0), time.Duration(0), false, true, "", nil, nil, int64(0), "", domain.TargetActive}
+	}
+	tests := []struct {
+		name    string
+		targets [][]driver.Value
+		wantID  string
+		wantErr func(error) bool
+	}{
+		{
+			name: "several targets, latest change",
+			targets: [][]driver.Value{
+				targetRow("first", testNow.Add(-time.Hour)),
+				targetRow("latest", testNow),
+				targetRow("second", testNow.Add(-time.Minute)),
+			},
+			wantID: "latest",
+		},
+		{
+			name:    "single target",
+			targets: [][]driver.Value{targetRow("only", testNow)},
+			wantID:  "only",
+		},
+		{
+			name:    "no targets, not found",
+			wantErr: zerrors.IsNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+			require.NoError(t, err)
+			defer client.Close()
+
+			// the database returns the target with the latest change date only
+			sort.Slice(tt.targets, func(i, j int) bool {
+				return tt.targets[i][1].(time.Time).After(tt.targets[j][1].(time.Time))
+			})
+			rows := sqlmock.NewRows(prepareTargetCols)
+			if len(tt.targets) > 0 {
+				rows.AddRow(tt.targets[0]...)
+			}
+			stmt := regexp.QuoteMeta(prepareTargetStmt+" WHERE ") +
+				`\(?` + regexp.QuoteMeta("projections.targets2.instance_id = $1 AND projections.targets2.resource_owner = $2") + `\)?` +
+				regexp.QuoteMeta(" ORDER BY projections.targets2.change_date DESC LIMIT 1")
+			mock.ExpectBegin()
+			mock.ExpectQuery(stmt).
+				WithArgs("instance", "ro").
+				WillReturnRows(rows)
+			if tt.wantErr != nil {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			q := &Queries{
+				client: &database.DB{
+					DB:       client,
+					Database: new(prepareDB),
+				},
+			}
+			got, err := q.LatestChangedTarget(ctx, "ro")
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantID, got.ID)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_targetCursor(t *testing.T) {
+	id, err := decodeTargetCursor(encodeTargetCursor("id-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "id-1", id)
+
+	id, err = decodeTargetCursor("")
+	require.NoError(t, err)
+	assert.Empty(t, id, "first page")
+
+	_, err = decodeTargetCursor("not a cursor!")
+	assert.True(t, zerrors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+}
+
+func TestQueries_SearchTargets_keyset(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	targetRow := func(id string, count uint64) []driver.Value {
+		return []driver.Value{id, testNow, "ro", uint64(20211109), "name-" + id, domain.TargetTypeWebhook, time.Second, "https://example.com", false, nil, nil, "", "", time.Duration(0), time.Duration(0), time.Duration(0), nil, nil, domain.TargetPayloadFormatJSON, nil, int64(0), domain.TargetResponseSizePolicyError, int64(0), time.Duration(0), false, true, "", nil, nil, int64(0), "", domain.TargetActive, count}
+	}
+	expectPage := func(mock sqlmock.Sqlmock, where string, args []driver.Value, rows ...[]driver.Value) {
+		result := sqlmock.NewRows(prepareTargetsCols)
+		for _, row := range rows {
+			result.AddRow(row...)
+		}
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(prepareTargetsStmt + " WHERE " + where + " ORDER BY projections.targets2.id LIMIT 2")).
+			WithArgs(args...).
+			WillReturnRows(result)
+		mock.ExpectCommit()
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("FROM projections.current_states")).
+			WillReturnRows(sqlmock.NewRows([]string{"event_date", "position", "last_updated"}).AddRow(testNow, float64(1), testNow))
+		mock.ExpectCommit()
+	}
+
+	client, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+	require.NoError(t, err)
+	defer client.Close()
+	q := &Queries{
+		client: &database.DB{
+			DB:       client,
+			Database: new(prepareDB),
+		},
+	}
+
+	// the first page is limited but not offset, the count includes the following page
+	expectPage(mock, "projections.targets2.instance_id = $1", []driver.Value{"instance"},
+		targetRow("id-1", 3), targetRow("id-2", 3),
+	)
+	first, err := q.SearchTargets(ctx, &TargetSearchQueries{
+		SearchRequest:      SearchRequest{Limit: 2},
+		Keyset:             true,
+		StronglyConsistent: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, first.Targets, 2)
+	require.NotEmpty(t, first.NextCursor)
+
+	// the next page continues after the last target of the first page
+	expectPage(mock, "(projections.targets2.instance_id = $1 AND projections.targets2.id > $2)", []driver.Value{"instance", "id-2"},
+		targetRow("id-3", 1),
+	)
+	last, err := q.SearchTargets(ctx, &TargetSearchQueries{
+		SearchRequest:      SearchRequest{Limit: 2},
+		Cursor:             first.NextCursor,
+		StronglyConsistent: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, last.Targets, 1)
+	assert.Equal(t, "id-3", last.Targets[0].ID)
+	assert.Empty(t, last.NextCursor, "last page")
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = q.SearchTargets(ctx, &TargetSearchQueries{Cursor: "not a cursor!"})
+	assert.True(t, zerrors.IsErrorInvalidArgument(err), "unexpected error: %v", err)
+}
+
+func TestQueries_SearchTargets_consistency(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	tests := []struct {
+		name    string
+		queries *TargetSearchQueries
+		from    string
+	}{
+		{
+			name:    "default, time travel",
+			queries: &TargetSearchQueries{},
+			from:    " FROM projections.targets2" + asOfSystemTime + " WHERE ",
+		},
+		{
+			name:    "strongly consistent, current data",
+			queries: &TargetSearchQueries{StronglyConsistent: true},
+			from:    " FROM projections.targets2 WHERE ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+			require.NoError(t, err)
+			defer client.Close()
+
+			selectColumns := strings.TrimSuffix(prepareTargetsStmt, " FROM projections.targets2")
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta(selectColumns + tt.from)).
+				WithArgs("instance").
+				WillReturnRows(sqlmock.NewRows(prepareTargetsCols))
+			mock.ExpectCommit()
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta("FROM projections.current_states")).
+				WillReturnRows(sqlmock.NewRows([]string{"event_date", "position", "last_updated"}).AddRow(testNow, float64(1), testNow))
+			mock.ExpectCommit()
+
+			q := &Queries{
+				client: &database.DB{
+					DB:       client,
+					Database: new(prepareDB),
+				},
+			}
+			_, err = q.SearchTargets(ctx, tt.queries)
+			require.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestQueries_SearchTargetsOfResourceOwners(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	targetRow := func(id, resourceOwner string) []driver.Value {
+		return []driver.Value{id, testNow, resourceOwner, uint64(20211109), "name-" + id, domain.TargetTypeWebhook, time.Second, "https://example.com", false, nil, nil, "", "", time.Duration(0), time.Duration(0), time.Duration(0), nil, nil, domain.TargetPayloadFormatJSON, nil, int64(0), domain.TargetResponseSizePolicyError, int64(0), time.Duration(0), false, true, "", nil, nil, int64(0), "", domain.TargetActive, uint64(3)}
+	}
+	tests := []struct {
+		name               string
+		resourceOwners     []string
+		targets            [][]driver.Value
+		wantResourceOwners map[string]string
+		wantErr            func(error) bool
+	}{
+		{
+			name:           "targets of several resource owners",
+			resourceOwners: []string{"org-1", "org-2"},
+			targets: [][]driver.Value{
+				targetRow("id-1", "org-1"),
+				targetRow("id-2", "org-2"),
+				targetRow("id-3", "org-1"),
+			},
+			wantResourceOwners: map[string]string{"id-1": "org-1", "id-2": "org-2", "id-3": "org-1"},
+		},
+		{
+			name:           "no resource owners, invalid argument",
+			resourceOwners: []string{},
+			wantErr:        zerrors.IsErrorInvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+			require.NoError(t, err)
+			defer client.Close()
+
+			if tt.wantErr == nil {
+				args := []driver.Value{"instance"}
+				placeholders := make([]string, len(tt.resourceOwners))
+				for i, resourceOwner := range tt.resourceOwners {
+					args = append(args, resourceOwner)
+					placeholders[i] = fmt.Sprintf("$%d", i+2)
+				}
+				rows := sqlmock.NewRows(prepareTargetsCols)
+				for _, target := range tt.targets {
+					rows.AddRow(target...)
+				}
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(prepareTargetsStmt+" WHERE ") +
+					`\(?` + regexp.QuoteMeta("projections.targets2.instance_id = $1 AND projections.targets2.resource_owner IN ("+strings.Join(placeholders, ",")+")") + `\)?`).
+					WithArgs(args...).
+					WillReturnRows(rows)
+				mock.ExpectCommit()
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("FROM projections.current_states")).
+					WillReturnRows(sqlmock.NewRows([]string{"event_date", "position", "last_updated"}).AddRow(testNow, float64(1), testNow))
+				mock.ExpectCommit()
+			}
+
+			q := &Queries{
+				client: &database.DB{
+					DB:       client,
+					Database: new(prepareDB),
+				},
+			}
+			got, err := q.SearchTargetsOfResourceOwners(ctx, tt.resourceOwners, &TargetSearchQueries{StronglyConsistent: true})
+			if tt.wantErr != nil {
+				assert.True(t, tt.wantErr(err), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint64(3), got.Count)
+			resourceOwners := make(map[string]string, len(got.Targets))
+			for _, target := range got.Targets {
+				resourceOwners[target.ID] = target.ResourceOwner
+			}
+			assert.Equal(t, tt.wantResourceOwners, resourceOwners)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestQueries_SearchTargetIDs(t *testing.T) {
+	ctx := authz.WithInstanceID(context.Background(), "instance")
+	targetRow := func(id string) []driver.Value {
+		return []driver.Value{id, testNow, "ro", uint64(20211109), "name-" + id, domain.TargetTypeWebhook, 5 * time.Second, "https://example.com", false, nil, nil, "", "", time.Duration(0), time.Duration(0), time.Duration(0), nil, nil, domain.TargetPayloadFormatJSON, nil, int64(0), domain.TargetResponseSizePolicyError, int64(0), time.Duration(0), false, true, "", nil, nil, int64(0), "", domain.TargetActive, uint64(3)}
+	}
+	ids := []string{"id-1", "id-2", "id-3"}
+	timeoutQuery, err := NewTargetTimeoutSearchQuery(NumberGreater, time.Second)
+	require.NoError(t, err)
+	queries := &TargetSearchQueries{
+		Queries:            []SearchQuery{timeoutQuery},
+		StronglyConsistent: true,
+	}
+	where := regexp.QuoteMeta(" WHERE projections.targets2.timeout > $1 AND ") + `\(?` +
+		regexp.QuoteMeta("projections.targets2.instance_id = $2 AND projections.targets2.resource_owner ")
+
+	client, mock, err := sqlmock.New(sqlmock.ValueConverterOption(new(db_mock.TypeConverter)))
+	require.NoError(t, err)
+	defer client.Close()
+	q := &Queries{
+		client: &database.DB{
+			DB:       client,
+			Database: new(prepareDB),
+		},
+	}
+
+	// the full search of the same targets
+	rows := sqlmock.NewRows(prepareTargetsCols)
+	for _, id := range ids {
+		rows.AddRow(targetRow(id)...)
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(prepareTargetsStmt)+where).
+		WithArgs(int64(time.Second), "instance", "ro").
+		WillReturnRows(rows)
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM projections.current_states")).
+		WillReturnRows(sqlmock.NewRows([]string{"event_date", "position", "last_updated"}).AddRow(testNow, float64(1), testNow))
+	mock.ExpectCommit()
+	targets, err := q.SearchTargetsOfResourceOwners(ctx, []string{"ro"}, queries)
+	require.NoError(t, err)
+	wantIDs := make([]string, len(targets.Targets))
+	for i, target := range targets.Targets {
+		wantIDs[i] = target.ID
+	}
+
+	// only the IDs are selected with the same filters
+	idRows := sqlmock.NewRows([]string{"id"})
+	for _, id := range ids {
+		idRows.AddRow(id)
+	}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT projections.targets2.id FROM projections.targets2")+where).
+		WithArgs(int64(time.Second), "instance", "ro").
+		WillReturnRows(idRows)
+	mock.ExpectCommit()
+	got, err := q.SearchTargetIDs(ctx, queries, "ro")
+	require.NoError(t, err)
+	assert.Equal(t, wantIDs, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
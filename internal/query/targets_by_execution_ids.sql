@@ -38,9 +38,9 @@ WITH RECURSIVE
                                               AND m.instance_id = pos.instance_id
                                      ORDER BY execution_id,
                                               position)
-select e.execution_id, e.instance_id, e.target_id, t.target_type, t.endpoint, t.timeout, t.interrupt_on_error
+select e.execution_id, e.instance_id, e.target_id, t.target_type, t.endpoint, t.timeout, t.interrupt_on_error, t.success_criteria, t.event_type_filter, t.certificate_fingerprint, t.response_content_type, t.dial_timeout, t.tls_handshake_timeout, t.response_header_timeout, t.oauth2, t.basic_auth, t.payload_format, t.mirror_endpoints, t.response_size_limit, t.response_size_policy, t.batch_max_size, t.batch_max_wait, t.ordered_delivery, t.include_payload, t.ca_bundle, t.jwt_delivery, t.failover_endpoints, t.max_redirects, t.request_id_header, t.state, t.signing_keys
 FROM dissolved_execution_targets e
-         JOIN projections.targets1 t
+         JOIN projections.targets2 t
               ON e.instance_id = t.instance_id
                   AND e.target_id = t.id
 WHERE "include" = ''
@@ -0,0 +1,62 @@
+package instance
+
+import (
+	"context"
+
+	"github.com/zitadel/zitadel/internal/domain"
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const (
+	ExecutionSettingsSetEventType = instanceEventTypePrefix + "execution.settings.set"
+)
+
+// ExecutionSettingsSetEvent sets the settings of the instance for the calls of its targets
+type ExecutionSettingsSetEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	// AsyncPoolSize is the amount of async targets called concurrently, 0 if the default pool size is used
+	AsyncPoolSize int `json:"asyncPoolSize,omitempty"`
+	// TargetQueueDepth is the amount of payloads queued per paused async target, 0 if the default depth is used
+	TargetQueueDepth int `json:"targetQueueDepth,omitempty"`
+	// TargetQueueOverflow defines what happens to the events exceeding the queue of a paused target
+	TargetQueueOverflow domain.TargetQueueOverflow `json:"targetQueueOverflow,omitempty"`
+}
+
+func (e *ExecutionSettingsSetEvent) Payload() interface{} {
+	return e
+}
+
+func (e *ExecutionSettingsSetEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func NewExecutionSettingsSetEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	settings *domain.ExecutionSettings,
+) *ExecutionSettingsSetEvent {
+	return &ExecutionSettingsSetEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			ExecutionSettingsSetEventType,
+		),
+		AsyncPoolSize:       settings.AsyncPoolSize,
+		TargetQueueDepth:    settings.TargetQueueDepth,
+		TargetQueueOverflow: settings.TargetQueueOverflow,
+	}
+}
+
+func ExecutionSettingsSetEventMapper(event eventstore.Event) (eventstore.Event, error) {
+	e := &ExecutionSettingsSetEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}
+	err := event.Unmarshal(e)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "INST-Ex5Sv", "unable to unmarshal execution settings set")
+	}
+
+	return e, nil
+}
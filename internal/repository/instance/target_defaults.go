@@ -0,0 +1,56 @@
+package instance
+
+import (
+	"context"
+	"time"
+
+	"github.com/zitadel/zitadel/internal/eventstore"
+	"github.com/zitadel/zitadel/internal/zerrors"
+)
+
+const (
+	TargetDefaultsSetEventType = instanceEventTypePrefix + "target.defaults.set"
+)
+
+// TargetDefaultsSetEvent sets the defaults applied to targets created without the respective values
+type TargetDefaultsSetEvent struct {
+	eventstore.BaseEvent `json:"-"`
+
+	// Timeout is applied to targets created without timeout, 0 if there is no default
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+func (e *TargetDefaultsSetEvent) Payload() interface{} {
+	return e
+}
+
+func (e *TargetDefaultsSetEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func NewTargetDefaultsSetEvent(
+	ctx context.Context,
+	aggregate *eventstore.Aggregate,
+	timeout time.Duration,
+) *TargetDefaultsSetEvent {
+	return &TargetDefaultsSetEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
+			ctx,
+			aggregate,
+			TargetDefaultsSetEventType,
+		),
+		Timeout: timeout,
+	}
+}
+
+func TargetDefaultsSetEventMapper(event eventstore.Event) (eventstore.Event, error) {
+	e := &TargetDefaultsSetEvent{
+		BaseEvent: *eventstore.BaseEventFromRepo(event),
+	}
+	err := event.Unmarshal(e)
+	if err != nil {
+		return nil, zerrors.ThrowInternal(err, "INST-Td4Um", "unable to unmarshal target defaults set")
+	}
+
+	return e, nil
+}
@@ -6,4 +6,6 @@ func init() {
 	eventstore.RegisterFilterEventMapper(AggregateType, AddedEventType, eventstore.GenericEventMapper[AddedEvent])
 	eventstore.RegisterFilterEventMapper(AggregateType, ChangedEventType, eventstore.GenericEventMapper[ChangedEvent])
 	eventstore.RegisterFilterEventMapper(AggregateType, RemovedEventType, eventstore.GenericEventMapper[RemovedEvent])
+	eventstore.RegisterFilterEventMapper(AggregateType, PausedEventType, eventstore.GenericEventMapper[PausedEvent])
+	eventstore.RegisterFilterEventMapper(AggregateType, ResumedEventType, eventstore.GenericEventMapper[ResumedEvent])
 }
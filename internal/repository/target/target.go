@@ -13,6 +13,8 @@ const (
 	AddedEventType                        = eventTypePrefix + "added"
 	ChangedEventType                      = eventTypePrefix + "changed"
 	RemovedEventType                      = eventTypePrefix + "removed"
+	PausedEventType                       = eventTypePrefix + "paused"
+	ResumedEventType                      = eventTypePrefix + "resumed"
 )
 
 type AddedEvent struct {
@@ -23,6 +25,49 @@ type AddedEvent struct {
 	Endpoint         string            `json:"endpoint"`
 	Timeout          time.Duration     `json:"timeout"`
 	InterruptOnError bool              `json:"interruptOnError"`
+
+	SuccessCriteria *domain.TargetSuccessCriteria `json:"successCriteria,omitempty"`
+	EventTypeFilter []string                      `json:"eventTypeFilter,omitempty"`
+	// CertificateFingerprint is the SHA-256 fingerprint of the pinned certificate of the endpoint
+	CertificateFingerprint string `json:"certificateFingerprint,omitempty"`
+	// CABundle are the PEM encoded certificate authorities the certificate of the endpoint is verified against,
+	// empty to verify against the certificate authorities of the system
+	CABundle string `json:"caBundle,omitempty"`
+	// ResponseContentType is the expected content type of the responses, only used for diagnostics
+	ResponseContentType string `json:"responseContentType,omitempty"`
+	// PayloadFormat is the format the payload is serialized in, JSON by default
+	PayloadFormat domain.TargetPayloadFormat `json:"payloadFormat,omitempty"`
+	// PhaseTimeouts limit the phases of the calls, unset phases are limited by the timeout
+	PhaseTimeouts *domain.TargetPhaseTimeouts `json:"phaseTimeouts,omitempty"`
+	// OAuth2 are the client credentials used to authorize the calls, the client secret is encrypted
+	OAuth2 *domain.TargetOAuth2 `json:"oauth2,omitempty"`
+	// BasicAuth are the credentials used to authorize the calls, the password is encrypted
+	BasicAuth *domain.TargetBasicAuth `json:"basicAuth,omitempty"`
+	// MirrorEndpoints additionally receive the payload best-effort, they never interrupt the execution
+	MirrorEndpoints []string `json:"mirrorEndpoints,omitempty"`
+	// ResponseSizeLimit limits the size of the response bodies, nil if not limited
+	ResponseSizeLimit *domain.TargetResponseSizeLimit `json:"responseSizeLimit,omitempty"`
+	// BatchDelivery coalesces the payloads delivered to an async target into batches, nil if every payload is delivered on its own
+	BatchDelivery *domain.TargetBatchDelivery `json:"batchDelivery,omitempty"`
+	// OrderedDelivery delivers the payloads of an async target one after the other
+	OrderedDelivery bool `json:"orderedDelivery,omitempty"`
+	// IncludePayload sends the payload of the events to the target, nil if included
+	IncludePayload *bool `json:"includePayload,omitempty"`
+	// JWTDelivery sends the payload as JWT signed with the signing key
+	JWTDelivery *domain.TargetJWTDelivery `json:"jwtDelivery,omitempty"`
+	// FailoverEndpoints are called in order if the endpoint fails, until one of them succeeds
+	FailoverEndpoints []string `json:"failoverEndpoints,omitempty"`
+	// MaxRedirects is the amount of redirects followed, redirects are not followed if zero
+	MaxRedirects int `json:"maxRedirects,omitempty"`
+	// RequestIDHeader is the name of the header the ID of the originating request is sent in, empty if it is not sent
+	RequestIDHeader string `json:"requestIDHeader,omitempty"`
+	// SigningKeys are the encrypted keys the requests are signed with, nil if they are not signed
+	SigningKeys *domain.TargetSigningKeys `json:"signingKeys,omitempty"`
+}
+
+// PayloadIncluded returns true if the payload of the events is sent to the target, which is the default
+func (e *AddedEvent) PayloadIncluded() bool {
+	return e.IncludePayload == nil || *e.IncludePayload
 }
 
 func (e *AddedEvent) SetBaseEvent(b *eventstore.BaseEvent) {
@@ -37,6 +82,122 @@ func (e *AddedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
 	return []*eventstore.UniqueConstraint{NewAddUniqueConstraint(e.Name)}
 }
 
+type AddedEventOption func(event *AddedEvent)
+
+func WithSuccessCriteria(criteria *domain.TargetSuccessCriteria) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.SuccessCriteria = criteria
+	}
+}
+
+func WithEventTypeFilter(filter []string) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.EventTypeFilter = filter
+	}
+}
+
+func WithCertificateFingerprint(fingerprint string) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.CertificateFingerprint = fingerprint
+	}
+}
+
+func WithCABundle(bundle string) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.CABundle = bundle
+	}
+}
+
+func WithResponseContentType(contentType string) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.ResponseContentType = contentType
+	}
+}
+
+func WithPhaseTimeouts(timeouts *domain.TargetPhaseTimeouts) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.PhaseTimeouts = timeouts
+	}
+}
+
+func WithPayloadFormat(format domain.TargetPayloadFormat) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.PayloadFormat = format
+	}
+}
+
+func WithOAuth2(oauth2 *domain.TargetOAuth2) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.OAuth2 = oauth2
+	}
+}
+
+func WithBasicAuth(basicAuth *domain.TargetBasicAuth) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.BasicAuth = basicAuth
+	}
+}
+
+func WithMirrorEndpoints(endpoints []string) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.MirrorEndpoints = endpoints
+	}
+}
+
+func WithResponseSizeLimit(limit *domain.TargetResponseSizeLimit) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.ResponseSizeLimit = limit
+	}
+}
+
+func WithBatchDelivery(batch *domain.TargetBatchDelivery) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.BatchDelivery = batch
+	}
+}
+
+func WithOrderedDelivery(ordered bool) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.OrderedDelivery = ordered
+	}
+}
+
+func WithIncludePayload(include *bool) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.IncludePayload = include
+	}
+}
+
+func WithJWTDelivery(delivery *domain.TargetJWTDelivery) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.JWTDelivery = delivery
+	}
+}
+
+func WithFailoverEndpoints(endpoints []string) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.FailoverEndpoints = endpoints
+	}
+}
+
+func WithMaxRedirects(maxRedirects int) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.MaxRedirects = maxRedirects
+	}
+}
+
+func WithRequestIDHeader(name string) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.RequestIDHeader = name
+	}
+}
+
+func WithSigningKeys(keys *domain.TargetSigningKeys) AddedEventOption {
+	return func(e *AddedEvent) {
+		e.SigningKeys = keys
+	}
+}
+
 func NewAddedEvent(
 	ctx context.Context,
 	aggregate *eventstore.Aggregate,
@@ -45,12 +206,22 @@ func NewAddedEvent(
 	endpoint string,
 	timeout time.Duration,
 	interruptOnError bool,
+	opts ...AddedEventOption,
 ) *AddedEvent {
-	return &AddedEvent{
-		*eventstore.NewBaseEventForPush(
+	event := &AddedEvent{
+		BaseEvent: *eventstore.NewBaseEventForPush(
 			ctx, aggregate, AddedEventType,
 		),
-		name, targetType, endpoint, timeout, interruptOnError}
+		Name:             name,
+		TargetType:       targetType,
+		Endpoint:         endpoint,
+		Timeout:          timeout,
+		InterruptOnError: interruptOnError,
+	}
+	for _, opt := range opts {
+		opt(event)
+	}
+	return event
 }
 
 type ChangedEvent struct {
@@ -61,6 +232,44 @@ type ChangedEvent struct {
 	Endpoint         *string            `json:"endpoint,omitempty"`
 	Timeout          *time.Duration     `json:"timeout,omitempty"`
 	InterruptOnError *bool              `json:"interruptOnError,omitempty"`
+	// SuccessCriteria are replaced completely, an empty struct removes the criteria
+	SuccessCriteria *domain.TargetSuccessCriteria `json:"successCriteria,omitempty"`
+	// EventTypeFilter is replaced completely, an empty list removes the filter
+	EventTypeFilter *[]string `json:"eventTypeFilter,omitempty"`
+	// CertificateFingerprint set to an empty string removes the pinning
+	CertificateFingerprint *string `json:"certificateFingerprint,omitempty"`
+	// CABundle set to an empty string verifies against the certificate authorities of the system
+	CABundle *string `json:"caBundle,omitempty"`
+	// ResponseContentType set to an empty string removes the expectation
+	ResponseContentType *string `json:"responseContentType,omitempty"`
+	// PayloadFormat is the format the payload is serialized in
+	PayloadFormat *domain.TargetPayloadFormat `json:"payloadFormat,omitempty"`
+	// PhaseTimeouts are replaced completely, an empty struct removes the phase timeouts
+	PhaseTimeouts *domain.TargetPhaseTimeouts `json:"phaseTimeouts,omitempty"`
+	// OAuth2 replaces the client credentials completely, an empty struct removes them
+	OAuth2 *domain.TargetOAuth2 `json:"oauth2,omitempty"`
+	// BasicAuth replaces the basic authentication completely, an empty struct removes it
+	BasicAuth *domain.TargetBasicAuth `json:"basicAuth,omitempty"`
+	// MirrorEndpoints are replaced completely, an empty list removes the mirrors
+	MirrorEndpoints *[]string `json:"mirrorEndpoints,omitempty"`
+	// ResponseSizeLimit is replaced completely, an empty struct removes the limit
+	ResponseSizeLimit *domain.TargetResponseSizeLimit `json:"responseSizeLimit,omitempty"`
+	// BatchDelivery is replaced completely, an empty struct delivers every payload on its own
+	BatchDelivery *domain.TargetBatchDelivery `json:"batchDelivery,omitempty"`
+	// OrderedDelivery delivers the payloads of an async target one after the other
+	OrderedDelivery *bool `json:"orderedDelivery,omitempty"`
+	// IncludePayload sends or omits the payload of the events
+	IncludePayload *bool `json:"includePayload,omitempty"`
+	// JWTDelivery replaces the JWT delivery completely, an empty struct sends the payload in its payload format
+	JWTDelivery *domain.TargetJWTDelivery `json:"jwtDelivery,omitempty"`
+	// FailoverEndpoints are replaced completely, an empty list removes the failover
+	FailoverEndpoints *[]string `json:"failoverEndpoints,omitempty"`
+	// MaxRedirects is the amount of redirects followed, zero stops following redirects
+	MaxRedirects *int `json:"maxRedirects,omitempty"`
+	// RequestIDHeader is the name of the header the request ID is sent in, empty stops sending it
+	RequestIDHeader *string `json:"requestIDHeader,omitempty"`
+	// SigningKeys replaces the signing keys completely, e.g. after a rotation
+	SigningKeys *domain.TargetSigningKeys `json:"signingKeys,omitempty"`
 
 	oldName string
 }
@@ -134,6 +343,120 @@ func ChangeInterruptOnError(interruptOnError bool) func(event *ChangedEvent) {
 	}
 }
 
+func ChangeSuccessCriteria(criteria *domain.TargetSuccessCriteria) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.SuccessCriteria = criteria
+	}
+}
+
+func ChangeEventTypeFilter(filter []string) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.EventTypeFilter = &filter
+	}
+}
+
+func ChangeCertificateFingerprint(fingerprint string) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.CertificateFingerprint = &fingerprint
+	}
+}
+
+func ChangeCABundle(bundle string) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.CABundle = &bundle
+	}
+}
+
+func ChangeResponseContentType(contentType string) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.ResponseContentType = &contentType
+	}
+}
+
+func ChangePhaseTimeouts(timeouts *domain.TargetPhaseTimeouts) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.PhaseTimeouts = timeouts
+	}
+}
+
+func ChangePayloadFormat(format domain.TargetPayloadFormat) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.PayloadFormat = &format
+	}
+}
+
+func ChangeOAuth2(oauth2 *domain.TargetOAuth2) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.OAuth2 = oauth2
+	}
+}
+
+func ChangeBasicAuth(basicAuth *domain.TargetBasicAuth) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.BasicAuth = basicAuth
+	}
+}
+
+func ChangeMirrorEndpoints(endpoints []string) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.MirrorEndpoints = &endpoints
+	}
+}
+
+func ChangeResponseSizeLimit(limit *domain.TargetResponseSizeLimit) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.ResponseSizeLimit = limit
+	}
+}
+
+func ChangeBatchDelivery(batch *domain.TargetBatchDelivery) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.BatchDelivery = batch
+	}
+}
+
+func ChangeOrderedDelivery(ordered bool) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.OrderedDelivery = &ordered
+	}
+}
+
+func ChangeIncludePayload(include bool) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.IncludePayload = &include
+	}
+}
+
+func ChangeJWTDelivery(delivery *domain.TargetJWTDelivery) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.JWTDelivery = delivery
+	}
+}
+
+func ChangeFailoverEndpoints(endpoints []string) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.FailoverEndpoints = &endpoints
+	}
+}
+
+func ChangeMaxRedirects(maxRedirects int) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.MaxRedirects = &maxRedirects
+	}
+}
+
+func ChangeRequestIDHeader(name string) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.RequestIDHeader = &name
+	}
+}
+
+func ChangeSigningKeys(keys *domain.TargetSigningKeys) func(event *ChangedEvent) {
+	return func(e *ChangedEvent) {
+		e.SigningKeys = keys
+	}
+}
+
 type RemovedEvent struct {
 	eventstore.BaseEvent `json:"-"`
 
@@ -155,3 +478,45 @@ func (e *RemovedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
 func NewRemovedEvent(ctx context.Context, aggregate *eventstore.Aggregate, name string) *RemovedEvent {
 	return &RemovedEvent{*eventstore.NewBaseEventForPush(ctx, aggregate, RemovedEventType), name}
 }
+
+// PausedEvent pauses the calls of the target, the payloads of an async target are queued until it is resumed
+type PausedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+}
+
+func (e *PausedEvent) SetBaseEvent(b *eventstore.BaseEvent) {
+	e.BaseEvent = *b
+}
+
+func (e *PausedEvent) Payload() any {
+	return nil
+}
+
+func (e *PausedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func NewPausedEvent(ctx context.Context, aggregate *eventstore.Aggregate) *PausedEvent {
+	return &PausedEvent{*eventstore.NewBaseEventForPush(ctx, aggregate, PausedEventType)}
+}
+
+// ResumedEvent resumes the calls of a paused target, the queued payloads are delivered in the background
+type ResumedEvent struct {
+	eventstore.BaseEvent `json:"-"`
+}
+
+func (e *ResumedEvent) SetBaseEvent(b *eventstore.BaseEvent) {
+	e.BaseEvent = *b
+}
+
+func (e *ResumedEvent) Payload() any {
+	return nil
+}
+
+func (e *ResumedEvent) UniqueConstraints() []*eventstore.UniqueConstraint {
+	return nil
+}
+
+func NewResumedEvent(ctx context.Context, aggregate *eventstore.Aggregate) *ResumedEvent {
+	return &ResumedEvent{*eventstore.NewBaseEventForPush(ctx, aggregate, ResumedEventType)}
+}
@@ -24,6 +24,8 @@ type Metrics interface {
 	AddCount(ctx context.Context, name string, value int64, labels map[string]attribute.Value) error
 	RegisterUpDownSumObserver(name, description string, callbackFunc metric.Int64Callback) error
 	RegisterValueObserver(name, description string, callbackFunc metric.Int64Callback) error
+	RegisterHistogram(name, description, unit string, buckets []float64) error
+	RecordHistogram(ctx context.Context, name string, value float64, labels map[string]attribute.Value) error
 }
 
 var M Metrics
@@ -69,3 +71,17 @@ func RegisterValueObserver(name, description string, callbackFunc metric.Int64Ca
 	}
 	return M.RegisterValueObserver(name, description, callbackFunc)
 }
+
+func RegisterHistogram(name, description, unit string, buckets []float64) error {
+	if M == nil {
+		return nil
+	}
+	return M.RegisterHistogram(name, description, unit, buckets)
+}
+
+func RecordHistogram(ctx context.Context, name string, value float64, labels map[string]attribute.Value) error {
+	if M == nil {
+		return nil
+	}
+	return M.RecordHistogram(ctx, name, value, labels)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/zitadel/logging"
 
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
 	"github.com/zitadel/zitadel/internal/v2/eventstore"
 	"github.com/zitadel/zitadel/internal/zerrors"
 )
@@ -62,3 +63,32 @@ func (p unmarshalPayload) Unmarshal(ptr any) error {
 
 	return nil
 }
+
+var _ eventstore.StoragePayload = (protobufPayload)(nil)
+
+// protobufPayload is a payload stored with [repository.PayloadEncodingProtobuf]
+type protobufPayload []byte
+
+// Unmarshal implements eventstore.StoragePayload.
+func (p protobufPayload) Unmarshal(ptr any) error {
+	if len(p) == 0 {
+		return nil
+	}
+	if err := repository.UnmarshalPayload(repository.PayloadEncodingProtobuf, p, ptr); err != nil {
+		return zerrors.ThrowInternal(err, "POSTG-Pb2kR", "Errors.Internal")
+	}
+	return nil
+}
+
+// decodePayload returns the payload of an event stored with the encoding,
+// payload is the value of the payload column and compressed the value of the compressed_payload column
+func decodePayload(encoding repository.PayloadEncoding, payload, compressed []byte) (eventstore.StoragePayload, error) {
+	decoded, err := repository.DecodePayload(encoding, payload, compressed)
+	if err != nil {
+		return nil, err
+	}
+	if encoding == repository.PayloadEncodingProtobuf {
+		return protobufPayload(decoded), nil
+	}
+	return unmarshalPayload(decoded), nil
+}
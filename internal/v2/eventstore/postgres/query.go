@@ -7,6 +7,7 @@ import (
 
 	"github.com/zitadel/logging"
 
+	"github.com/zitadel/zitadel/internal/eventstore/repository"
 	"github.com/zitadel/zitadel/internal/telemetry/tracing"
 	"github.com/zitadel/zitadel/internal/v2/database"
 	"github.com/zitadel/zitadel/internal/v2/eventstore"
@@ -40,7 +41,11 @@ func executeQuery(ctx context.Context, tx database.Querier, stmt *database.State
 	err = database.MapRowsToObject(rows, func(scan func(dest ...any) error) error {
 		e := new(eventstore.Event[eventstore.StoragePayload])
 
-		var payload sql.Null[[]byte]
+		var (
+			payload    sql.Null[[]byte]
+			encoding   sql.Null[int16]
+			compressed []byte
+		)
 
 		err := scan(
 			&e.CreatedAt,
@@ -55,11 +60,16 @@ func executeQuery(ctx context.Context, tx database.Querier, stmt *database.State
 			&e.Aggregate.Type,
 			&e.Aggregate.ID,
 			&e.Revision,
+			&encoding,
+			&compressed,
 		)
 		if err != nil {
 			return err
 		}
-		e.Payload = unmarshalPayload(payload.V)
+		e.Payload, err = decodePayload(repository.PayloadEncoding(encoding.V), payload.V, compressed)
+		if err != nil {
+			return err
+		}
 		eventCount++
 
 		return reducer.Reduce(e)
@@ -69,7 +79,7 @@ func executeQuery(ctx context.Context, tx database.Querier, stmt *database.State
 }
 
 var (
-	selectColumns = `SELECT created_at, event_type, "sequence", "position", in_tx_order, payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision`
+	selectColumns = `SELECT created_at, event_type, "sequence", "position", in_tx_order, payload, creator, "owner", instance_id, aggregate_type, aggregate_id, revision, payload_encoding, compressed_payload`
 	// TODO: condition must know if it's args are named parameters or not
 	// instancePlaceholder = database.Placeholder("@instance_id")
 )